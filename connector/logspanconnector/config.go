@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logspanconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/logspanconnector"
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+const (
+	defaultDurationAttribute     = "duration"
+	defaultDurationUnit          = "ms"
+	defaultParentSpanIDAttribute = "parent_span_id"
+	defaultSpanKind              = "internal"
+	defaultErrorSeverity         = "error"
+)
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+}
+
+var spanKinds = map[string]ptrace.SpanKind{
+	"internal": ptrace.SpanKindInternal,
+	"server":   ptrace.SpanKindServer,
+	"client":   ptrace.SpanKindClient,
+	"producer": ptrace.SpanKindProducer,
+	"consumer": ptrace.SpanKindConsumer,
+}
+
+var severities = map[string]plog.SeverityNumber{
+	"trace": plog.SeverityNumberTrace,
+	"debug": plog.SeverityNumberDebug,
+	"info":  plog.SeverityNumberInfo,
+	"warn":  plog.SeverityNumberWarn,
+	"error": plog.SeverityNumberError,
+	"fatal": plog.SeverityNumberFatal,
+}
+
+// Config for the connector
+type Config struct {
+	// LogsToTraces configures how log records are converted into spans.
+	LogsToTraces LogsToTracesConfig `mapstructure:"logs_to_traces"`
+	// TracesToLogs configures how span events are converted into log records.
+	TracesToLogs TracesToLogsConfig `mapstructure:"traces_to_logs"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// LogsToTracesConfig defines how spans are synthesized from log records.
+type LogsToTracesConfig struct {
+	// Conditions are OTTL log conditions, a log record is converted if any of them match.
+	// All log records are converted when no conditions are configured. Log records
+	// without a valid trace ID and span ID are never converted.
+	Conditions []string `mapstructure:"conditions"`
+	// SpanNameAttribute is the log attribute holding the span name.
+	// The log body is used when empty or when the attribute is missing.
+	SpanNameAttribute string `mapstructure:"span_name_attribute"`
+	// DurationAttribute is the log attribute holding the duration of the operation.
+	// The span end timestamp is the log timestamp and the start timestamp is derived
+	// from the duration, so a missing attribute results in a span of zero duration.
+	DurationAttribute string `mapstructure:"duration_attribute"`
+	// DurationUnit is the unit of numeric durations, one of ns, us, ms and s.
+	// String durations with a unit suffix, such as "1.5s", are parsed as is.
+	DurationUnit string `mapstructure:"duration_unit"`
+	// ParentSpanIDAttribute is the log attribute holding the hex encoded parent span ID.
+	ParentSpanIDAttribute string `mapstructure:"parent_span_id_attribute"`
+	// SpanKind is the kind of the synthesized spans, one of internal, server, client,
+	// producer and consumer.
+	SpanKind string `mapstructure:"span_kind"`
+	// ErrorSeverity is the lowest severity, one of trace, debug, info, warn, error and fatal,
+	// for which the synthesized span has an error status.
+	ErrorSeverity string `mapstructure:"error_severity"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// TracesToLogsConfig defines how log records are emitted from span events.
+type TracesToLogsConfig struct {
+	// Conditions are OTTL span event conditions, a span event is converted if any of them match.
+	// All span events are converted when no conditions are configured.
+	Conditions []string `mapstructure:"conditions"`
+	// IncludeSpanAttributes adds the attributes of the span to the emitted log records.
+	// Span event attributes take precedence over span attributes with the same key.
+	IncludeSpanAttributes bool `mapstructure:"include_span_attributes"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+func (c *Config) Validate() error {
+	l := c.LogsToTraces
+	if _, err := filterottl.NewBoolExprForLog(l.Conditions, filterottl.StandardLogFuncs(), ottl.PropagateError, component.TelemetrySettings{Logger: zap.NewNop()}); err != nil {
+		return fmt.Errorf("logs_to_traces condition: %w", err)
+	}
+	if _, ok := durationUnits[l.DurationUnit]; !ok {
+		return fmt.Errorf("logs_to_traces: invalid duration_unit %q", l.DurationUnit)
+	}
+	if _, ok := spanKinds[strings.ToLower(l.SpanKind)]; !ok {
+		return fmt.Errorf("logs_to_traces: invalid span_kind %q", l.SpanKind)
+	}
+	if _, ok := severities[strings.ToLower(l.ErrorSeverity)]; !ok {
+		return fmt.Errorf("logs_to_traces: invalid error_severity %q", l.ErrorSeverity)
+	}
+
+	if _, err := filterottl.NewBoolExprForSpanEvent(c.TracesToLogs.Conditions, filterottl.StandardSpanEventFuncs(), ottl.PropagateError, component.TelemetrySettings{Logger: zap.NewNop()}); err != nil {
+		return fmt.Errorf("traces_to_logs condition: %w", err)
+	}
+	return nil
+}
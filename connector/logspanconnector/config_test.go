@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logspanconnector
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/confmap/xconfmap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/logspanconnector/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	testCases := []struct {
+		id     component.ID
+		expect *Config
+	}{
+		{
+			id:     component.NewIDWithName(metadata.Type, ""),
+			expect: createDefaultConfig().(*Config),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "custom"),
+			expect: &Config{
+				LogsToTraces: LogsToTracesConfig{
+					Conditions:            []string{`body == "request finished"`},
+					SpanNameAttribute:     "http.route",
+					DurationAttribute:     "elapsed",
+					DurationUnit:          "us",
+					ParentSpanIDAttribute: "parent.id",
+					SpanKind:              "server",
+					ErrorSeverity:         "warn",
+				},
+				TracesToLogs: TracesToLogsConfig{
+					Conditions:            []string{`name == "exception"`},
+					IncludeSpanAttributes: true,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.id.String(), func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tc.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			assert.NoError(t, xconfmap.Validate(cfg))
+			assert.Equal(t, tc.expect, cfg)
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	testCases := []struct {
+		name   string
+		expect string
+	}{
+		{
+			name:   "invalid_condition",
+			expect: "logs_to_traces condition:",
+		},
+		{
+			name:   "invalid_duration_unit",
+			expect: `logs_to_traces: invalid duration_unit "h"`,
+		},
+		{
+			name:   "invalid_span_kind",
+			expect: `logs_to_traces: invalid span_kind "unknown"`,
+		},
+		{
+			name:   "invalid_error_severity",
+			expect: `logs_to_traces: invalid error_severity "critical"`,
+		},
+		{
+			name:   "invalid_span_event_condition",
+			expect: "traces_to_logs condition:",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(component.NewIDWithName(metadata.Type, tc.name).String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			assert.ErrorContains(t, xconfmap.Validate(cfg), tc.expect)
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logspanconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/logspanconnector"
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
+)
+
+// exceptionEventName is the name of span events recording an exception,
+// see https://opentelemetry.io/docs/specs/semconv/exceptions/exceptions-spans/.
+const exceptionEventName = "exception"
+
+// logsToTraces synthesizes a span from each matching log record and emits
+// the spans onto a traces pipeline.
+type logsToTraces struct {
+	tracesConsumer consumer.Traces
+	logger         *zap.Logger
+	component.StartFunc
+	component.ShutdownFunc
+
+	condition             *ottl.ConditionSequence[*ottllog.TransformContext]
+	spanNameAttribute     string
+	durationAttribute     string
+	durationUnit          time.Duration
+	parentSpanIDAttribute string
+	spanKind              ptrace.SpanKind
+	errorSeverity         plog.SeverityNumber
+}
+
+func (*logsToTraces) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (l *logsToTraces) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	var multiError error
+	td := ptrace.NewTraces()
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		resourceLog := ld.ResourceLogs().At(i)
+		var resourceSpan ptrace.ResourceSpans
+		hasResource := false
+
+		for j := 0; j < resourceLog.ScopeLogs().Len(); j++ {
+			scopeLog := resourceLog.ScopeLogs().At(j)
+			var scopeSpan ptrace.ScopeSpans
+			hasScope := false
+
+			for k := 0; k < scopeLog.LogRecords().Len(); k++ {
+				logRecord := scopeLog.LogRecords().At(k)
+				// A span cannot be synthesized without the IDs that identify it.
+				if logRecord.TraceID().IsEmpty() || logRecord.SpanID().IsEmpty() {
+					continue
+				}
+				if l.condition != nil {
+					lCtx := ottllog.NewTransformContextPtr(resourceLog, scopeLog, logRecord)
+					match, err := l.condition.Eval(ctx, lCtx)
+					lCtx.Close()
+					if err != nil {
+						multiError = errors.Join(multiError, err)
+						continue
+					}
+					if !match {
+						continue
+					}
+				}
+
+				if !hasResource {
+					resourceSpan = td.ResourceSpans().AppendEmpty()
+					resourceLog.Resource().CopyTo(resourceSpan.Resource())
+					resourceSpan.SetSchemaUrl(resourceLog.SchemaUrl())
+					hasResource = true
+				}
+				if !hasScope {
+					scopeSpan = resourceSpan.ScopeSpans().AppendEmpty()
+					scopeLog.Scope().CopyTo(scopeSpan.Scope())
+					scopeSpan.SetSchemaUrl(scopeLog.SchemaUrl())
+					hasScope = true
+				}
+				l.toSpan(logRecord, scopeSpan.Spans().AppendEmpty())
+			}
+		}
+	}
+
+	if td.SpanCount() == 0 {
+		return multiError
+	}
+	return errors.Join(multiError, l.tracesConsumer.ConsumeTraces(ctx, td))
+}
+
+func (l *logsToTraces) toSpan(logRecord plog.LogRecord, span ptrace.Span) {
+	span.SetTraceID(logRecord.TraceID())
+	span.SetSpanID(logRecord.SpanID())
+	span.SetFlags(uint32(logRecord.Flags()))
+	span.SetKind(l.spanKind)
+
+	attrs := span.Attributes()
+	logRecord.Attributes().CopyTo(attrs)
+
+	span.SetName(logRecord.Body().AsString())
+	if l.spanNameAttribute != "" {
+		if name, ok := attrs.Get(l.spanNameAttribute); ok {
+			span.SetName(name.AsString())
+			attrs.Remove(l.spanNameAttribute)
+		}
+	}
+
+	if l.parentSpanIDAttribute != "" {
+		if value, ok := attrs.Get(l.parentSpanIDAttribute); ok {
+			if parentSpanID, ok := parseSpanID(value.AsString()); ok {
+				span.SetParentSpanID(parentSpanID)
+				attrs.Remove(l.parentSpanIDAttribute)
+			} else {
+				l.logger.Debug("invalid parent span ID", zap.String("attribute", l.parentSpanIDAttribute), zap.String("value", value.AsString()))
+			}
+		}
+	}
+
+	end := logRecord.Timestamp()
+	if end == 0 {
+		end = logRecord.ObservedTimestamp()
+	}
+	span.SetEndTimestamp(end)
+	span.SetStartTimestamp(end)
+	if l.durationAttribute != "" {
+		if value, ok := attrs.Get(l.durationAttribute); ok {
+			if duration, ok := l.parseDuration(value); ok {
+				span.SetStartTimestamp(pcommon.NewTimestampFromTime(end.AsTime().Add(-duration)))
+				attrs.Remove(l.durationAttribute)
+			} else {
+				l.logger.Debug("invalid duration", zap.String("attribute", l.durationAttribute), zap.String("value", value.AsString()))
+			}
+		}
+	}
+
+	if logRecord.SeverityNumber() >= l.errorSeverity {
+		span.Status().SetCode(ptrace.StatusCodeError)
+		span.Status().SetMessage(logRecord.Body().AsString())
+	}
+}
+
+// parseDuration interprets numeric values in the configured duration unit,
+// and strings either as a number in the configured unit or as a Go duration.
+func (l *logsToTraces) parseDuration(value pcommon.Value) (time.Duration, bool) {
+	var duration time.Duration
+	switch value.Type() {
+	case pcommon.ValueTypeInt:
+		duration = time.Duration(value.Int()) * l.durationUnit
+	case pcommon.ValueTypeDouble:
+		duration = time.Duration(value.Double() * float64(l.durationUnit))
+	case pcommon.ValueTypeStr:
+		if f, err := strconv.ParseFloat(value.Str(), 64); err == nil {
+			duration = time.Duration(f * float64(l.durationUnit))
+			break
+		}
+		d, err := time.ParseDuration(value.Str())
+		if err != nil {
+			return 0, false
+		}
+		duration = d
+	default:
+		return 0, false
+	}
+	return duration, duration >= 0
+}
+
+func parseSpanID(s string) (pcommon.SpanID, bool) {
+	var id pcommon.SpanID
+	if hex.DecodedLen(len(s)) != len(id) {
+		return id, false
+	}
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return id, false
+	}
+	return id, !id.IsEmpty()
+}
+
+// tracesToLogs emits a log record for each matching span event onto a
+// logs pipeline.
+type tracesToLogs struct {
+	logsConsumer consumer.Logs
+	component.StartFunc
+	component.ShutdownFunc
+
+	condition             *ottl.ConditionSequence[*ottlspanevent.TransformContext]
+	includeSpanAttributes bool
+}
+
+func (*tracesToLogs) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (t *tracesToLogs) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	var multiError error
+	ld := plog.NewLogs()
+	observed := pcommon.NewTimestampFromTime(time.Now())
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		resourceSpan := td.ResourceSpans().At(i)
+		var resourceLog plog.ResourceLogs
+		hasResource := false
+
+		for j := 0; j < resourceSpan.ScopeSpans().Len(); j++ {
+			scopeSpan := resourceSpan.ScopeSpans().At(j)
+			var scopeLog plog.ScopeLogs
+			hasScope := false
+
+			for k := 0; k < scopeSpan.Spans().Len(); k++ {
+				span := scopeSpan.Spans().At(k)
+
+				for l := 0; l < span.Events().Len(); l++ {
+					event := span.Events().At(l)
+					if t.condition != nil {
+						eCtx := ottlspanevent.NewTransformContextPtr(resourceSpan, scopeSpan, span, event)
+						match, err := t.condition.Eval(ctx, eCtx)
+						eCtx.Close()
+						if err != nil {
+							multiError = errors.Join(multiError, err)
+							continue
+						}
+						if !match {
+							continue
+						}
+					}
+
+					if !hasResource {
+						resourceLog = ld.ResourceLogs().AppendEmpty()
+						resourceSpan.Resource().CopyTo(resourceLog.Resource())
+						resourceLog.SetSchemaUrl(resourceSpan.SchemaUrl())
+						hasResource = true
+					}
+					if !hasScope {
+						scopeLog = resourceLog.ScopeLogs().AppendEmpty()
+						scopeSpan.Scope().CopyTo(scopeLog.Scope())
+						scopeLog.SetSchemaUrl(scopeSpan.SchemaUrl())
+						hasScope = true
+					}
+					t.toLogRecord(span, event, observed, scopeLog.LogRecords().AppendEmpty())
+				}
+			}
+		}
+	}
+
+	if ld.LogRecordCount() == 0 {
+		return multiError
+	}
+	return errors.Join(multiError, t.logsConsumer.ConsumeLogs(ctx, ld))
+}
+
+func (t *tracesToLogs) toLogRecord(span ptrace.Span, event ptrace.SpanEvent, observed pcommon.Timestamp, logRecord plog.LogRecord) {
+	logRecord.SetTimestamp(event.Timestamp())
+	logRecord.SetObservedTimestamp(observed)
+	logRecord.SetEventName(event.Name())
+	logRecord.Body().SetStr(event.Name())
+	logRecord.SetTraceID(span.TraceID())
+	logRecord.SetSpanID(span.SpanID())
+	// Only the lower 8 bits of the span flags are W3C trace flags.
+	logRecord.SetFlags(plog.LogRecordFlags(span.Flags() & 0xff))
+
+	if event.Name() == exceptionEventName {
+		logRecord.SetSeverityNumber(plog.SeverityNumberError)
+		logRecord.SetSeverityText("ERROR")
+	} else {
+		logRecord.SetSeverityNumber(plog.SeverityNumberInfo)
+		logRecord.SetSeverityText("INFO")
+	}
+
+	attrs := logRecord.Attributes()
+	if t.includeSpanAttributes {
+		span.Attributes().CopyTo(attrs)
+	}
+	for k, v := range event.Attributes().All() {
+		v.CopyTo(attrs.PutEmpty(k))
+	}
+}
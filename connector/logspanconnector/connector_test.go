@@ -0,0 +1,278 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logspanconnector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/logspanconnector/internal/metadata"
+)
+
+var (
+	testTraceID      = pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	testSpanID       = pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	testParentSpanID = pcommon.SpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1})
+	testTime         = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+)
+
+func TestLogsToTraces(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cfg    func(*LogsToTracesConfig)
+		log    func(plog.LogRecord)
+		verify func(*testing.T, ptrace.Span)
+	}{
+		{
+			name: "defaults",
+			log: func(lr plog.LogRecord) {
+				lr.Attributes().PutInt("duration", 250)
+				lr.Attributes().PutStr("parent_span_id", "0807060504030201")
+				lr.Attributes().PutStr("http.route", "/users")
+				lr.SetSeverityNumber(plog.SeverityNumberInfo)
+			},
+			verify: func(t *testing.T, span ptrace.Span) {
+				assert.Equal(t, "request finished", span.Name())
+				assert.Equal(t, testTraceID, span.TraceID())
+				assert.Equal(t, testSpanID, span.SpanID())
+				assert.Equal(t, testParentSpanID, span.ParentSpanID())
+				assert.Equal(t, ptrace.SpanKindInternal, span.Kind())
+				assert.Equal(t, testTime, span.EndTimestamp().AsTime())
+				assert.Equal(t, testTime.Add(-250*time.Millisecond), span.StartTimestamp().AsTime())
+				assert.Equal(t, ptrace.StatusCodeUnset, span.Status().Code())
+				assert.Equal(t, map[string]any{"http.route": "/users"}, span.Attributes().AsRaw())
+			},
+		},
+		{
+			name: "custom",
+			cfg: func(c *LogsToTracesConfig) {
+				c.SpanNameAttribute = "http.route"
+				c.DurationAttribute = "elapsed"
+				c.DurationUnit = "s"
+				c.SpanKind = "server"
+				c.ErrorSeverity = "warn"
+			},
+			log: func(lr plog.LogRecord) {
+				lr.Attributes().PutDouble("elapsed", 1.5)
+				lr.Attributes().PutStr("http.route", "/users")
+				lr.SetSeverityNumber(plog.SeverityNumberWarn)
+			},
+			verify: func(t *testing.T, span ptrace.Span) {
+				assert.Equal(t, "/users", span.Name())
+				assert.Equal(t, ptrace.SpanKindServer, span.Kind())
+				assert.Equal(t, testTime.Add(-1500*time.Millisecond), span.StartTimestamp().AsTime())
+				assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+				assert.Equal(t, "request finished", span.Status().Message())
+				assert.Empty(t, span.Attributes().AsRaw())
+			},
+		},
+		{
+			name: "string_duration",
+			log: func(lr plog.LogRecord) {
+				lr.Attributes().PutStr("duration", "2s")
+			},
+			verify: func(t *testing.T, span ptrace.Span) {
+				assert.Equal(t, testTime.Add(-2*time.Second), span.StartTimestamp().AsTime())
+				assert.Empty(t, span.Attributes().AsRaw())
+			},
+		},
+		{
+			name: "invalid_duration_and_parent",
+			log: func(lr plog.LogRecord) {
+				lr.Attributes().PutStr("duration", "soon")
+				lr.Attributes().PutStr("parent_span_id", "xyz")
+			},
+			verify: func(t *testing.T, span ptrace.Span) {
+				assert.Equal(t, span.EndTimestamp(), span.StartTimestamp())
+				assert.True(t, span.ParentSpanID().IsEmpty())
+				assert.Equal(t, map[string]any{"duration": "soon", "parent_span_id": "xyz"}, span.Attributes().AsRaw())
+			},
+		},
+		{
+			name: "observed_timestamp",
+			log: func(lr plog.LogRecord) {
+				lr.SetTimestamp(0)
+				lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(testTime.Add(time.Second)))
+			},
+			verify: func(t *testing.T, span ptrace.Span) {
+				assert.Equal(t, testTime.Add(time.Second), span.EndTimestamp().AsTime())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			if tc.cfg != nil {
+				tc.cfg(&cfg.LogsToTraces)
+			}
+			require.NoError(t, cfg.Validate())
+
+			sink := &consumertest.TracesSink{}
+			conn, err := factory.CreateLogsToTraces(t.Context(), connectortest.NewNopSettings(metadata.Type), cfg, sink)
+			require.NoError(t, err)
+
+			ld := plog.NewLogs()
+			rl := ld.ResourceLogs().AppendEmpty()
+			rl.Resource().Attributes().PutStr("service.name", "legacy")
+			sl := rl.ScopeLogs().AppendEmpty()
+			sl.Scope().SetName("scope")
+			lr := sl.LogRecords().AppendEmpty()
+			lr.Body().SetStr("request finished")
+			lr.SetTraceID(testTraceID)
+			lr.SetSpanID(testSpanID)
+			lr.SetTimestamp(pcommon.NewTimestampFromTime(testTime))
+			tc.log(lr)
+
+			require.NoError(t, conn.ConsumeLogs(t.Context(), ld))
+			require.Len(t, sink.AllTraces(), 1)
+			td := sink.AllTraces()[0]
+			require.Equal(t, 1, td.SpanCount())
+			rs := td.ResourceSpans().At(0)
+			assert.Equal(t, map[string]any{"service.name": "legacy"}, rs.Resource().Attributes().AsRaw())
+			assert.Equal(t, "scope", rs.ScopeSpans().At(0).Scope().Name())
+			tc.verify(t, rs.ScopeSpans().At(0).Spans().At(0))
+		})
+	}
+}
+
+func TestLogsToTracesFiltering(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.LogsToTraces.Conditions = []string{`body == "request finished"`}
+	require.NoError(t, cfg.Validate())
+
+	sink := &consumertest.TracesSink{}
+	conn, err := factory.CreateLogsToTraces(t.Context(), connectortest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	lrs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	matching := lrs.AppendEmpty()
+	matching.Body().SetStr("request finished")
+	matching.SetTraceID(testTraceID)
+	matching.SetSpanID(testSpanID)
+	other := lrs.AppendEmpty()
+	other.Body().SetStr("request started")
+	other.SetTraceID(testTraceID)
+	other.SetSpanID(testSpanID)
+	missingIDs := lrs.AppendEmpty()
+	missingIDs.Body().SetStr("request finished")
+
+	// Resources without any matching log record are dropped.
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("request finished")
+
+	require.NoError(t, conn.ConsumeLogs(t.Context(), ld))
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 1, sink.AllTraces()[0].ResourceSpans().Len())
+	assert.Equal(t, 1, sink.AllTraces()[0].SpanCount())
+
+	sink.Reset()
+	require.NoError(t, conn.ConsumeLogs(t.Context(), plog.NewLogs()))
+	assert.Empty(t, sink.AllTraces())
+}
+
+func TestTracesToLogs(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		conditions            []string
+		includeSpanAttributes bool
+		expect                []map[string]any
+	}{
+		{
+			name: "all_events",
+			expect: []map[string]any{
+				{"cache.hit": true},
+				{"exception.type": "timeout"},
+			},
+		},
+		{
+			name:       "conditions",
+			conditions: []string{`name == "exception"`},
+			expect: []map[string]any{
+				{"exception.type": "timeout"},
+			},
+		},
+		{
+			name:                  "include_span_attributes",
+			includeSpanAttributes: true,
+			expect: []map[string]any{
+				{"cache.hit": true, "http.route": "/users", "exception.type": "span"},
+				{"exception.type": "timeout", "http.route": "/users"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.TracesToLogs.Conditions = tc.conditions
+			cfg.TracesToLogs.IncludeSpanAttributes = tc.includeSpanAttributes
+			require.NoError(t, cfg.Validate())
+
+			sink := &consumertest.LogsSink{}
+			conn, err := factory.CreateTracesToLogs(t.Context(), connectortest.NewNopSettings(metadata.Type), cfg, sink)
+			require.NoError(t, err)
+
+			td := ptrace.NewTraces()
+			rs := td.ResourceSpans().AppendEmpty()
+			rs.Resource().Attributes().PutStr("service.name", "frontend")
+			ss := rs.ScopeSpans().AppendEmpty()
+			ss.Scope().SetName("scope")
+			span := ss.Spans().AppendEmpty()
+			span.SetTraceID(testTraceID)
+			span.SetSpanID(testSpanID)
+			span.SetFlags(1)
+			span.Attributes().PutStr("http.route", "/users")
+			span.Attributes().PutStr("exception.type", "span")
+			cacheEvent := span.Events().AppendEmpty()
+			cacheEvent.SetName("cache lookup")
+			cacheEvent.SetTimestamp(pcommon.NewTimestampFromTime(testTime))
+			cacheEvent.Attributes().PutBool("cache.hit", true)
+			exceptionEvent := span.Events().AppendEmpty()
+			exceptionEvent.SetName("exception")
+			exceptionEvent.SetTimestamp(pcommon.NewTimestampFromTime(testTime.Add(time.Second)))
+			exceptionEvent.Attributes().PutStr("exception.type", "timeout")
+			// Spans without events don't produce any log records.
+			td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("no events")
+
+			require.NoError(t, conn.ConsumeTraces(t.Context(), td))
+			require.Len(t, sink.AllLogs(), 1)
+			ld := sink.AllLogs()[0]
+			require.Equal(t, 1, ld.ResourceLogs().Len())
+			rl := ld.ResourceLogs().At(0)
+			assert.Equal(t, map[string]any{"service.name": "frontend"}, rl.Resource().Attributes().AsRaw())
+			assert.Equal(t, "scope", rl.ScopeLogs().At(0).Scope().Name())
+
+			lrs := rl.ScopeLogs().At(0).LogRecords()
+			require.Equal(t, len(tc.expect), lrs.Len())
+			for i, expect := range tc.expect {
+				lr := lrs.At(i)
+				assert.Equal(t, expect, lr.Attributes().AsRaw())
+				assert.Equal(t, testTraceID, lr.TraceID())
+				assert.Equal(t, testSpanID, lr.SpanID())
+				assert.Equal(t, plog.LogRecordFlags(1), lr.Flags())
+				assert.Equal(t, lr.EventName(), lr.Body().Str())
+				assert.NotZero(t, lr.ObservedTimestamp())
+				if lr.EventName() == "exception" {
+					assert.Equal(t, plog.SeverityNumberError, lr.SeverityNumber())
+					assert.Equal(t, testTime.Add(time.Second), lr.Timestamp().AsTime())
+				} else {
+					assert.Equal(t, plog.SeverityNumberInfo, lr.SeverityNumber())
+					assert.Equal(t, testTime, lr.Timestamp().AsTime())
+				}
+			}
+		})
+	}
+}
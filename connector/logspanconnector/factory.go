@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate make mdatagen
+
+package logspanconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/logspanconnector"
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/logspanconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// NewFactory returns a ConnectorFactory.
+func NewFactory() connector.Factory {
+	return connector.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		connector.WithLogsToTraces(createLogsToTraces, metadata.LogsToTracesStability),
+		connector.WithTracesToLogs(createTracesToLogs, metadata.TracesToLogsStability),
+	)
+}
+
+// createDefaultConfig creates the default configuration.
+func createDefaultConfig() component.Config {
+	return &Config{
+		LogsToTraces: LogsToTracesConfig{
+			DurationAttribute:     defaultDurationAttribute,
+			DurationUnit:          defaultDurationUnit,
+			ParentSpanIDAttribute: defaultParentSpanIDAttribute,
+			SpanKind:              defaultSpanKind,
+			ErrorSeverity:         defaultErrorSeverity,
+		},
+	}
+}
+
+// createLogsToTraces creates a logs to traces connector based on provided config.
+func createLogsToTraces(
+	_ context.Context,
+	set connector.Settings,
+	cfg component.Config,
+	nextConsumer consumer.Traces,
+) (connector.Logs, error) {
+	c := cfg.(*Config).LogsToTraces
+
+	l := &logsToTraces{
+		tracesConsumer:        nextConsumer,
+		logger:                set.Logger,
+		spanNameAttribute:     c.SpanNameAttribute,
+		durationAttribute:     c.DurationAttribute,
+		durationUnit:          durationUnits[c.DurationUnit],
+		parentSpanIDAttribute: c.ParentSpanIDAttribute,
+		spanKind:              spanKinds[strings.ToLower(c.SpanKind)],
+		errorSeverity:         severities[strings.ToLower(c.ErrorSeverity)],
+	}
+	if len(c.Conditions) > 0 {
+		// Error checked in Config.Validate()
+		l.condition, _ = filterottl.NewBoolExprForLog(c.Conditions, filterottl.StandardLogFuncs(), ottl.PropagateError, set.TelemetrySettings)
+	}
+	return l, nil
+}
+
+// createTracesToLogs creates a traces to logs connector based on provided config.
+func createTracesToLogs(
+	_ context.Context,
+	set connector.Settings,
+	cfg component.Config,
+	nextConsumer consumer.Logs,
+) (connector.Traces, error) {
+	c := cfg.(*Config).TracesToLogs
+
+	t := &tracesToLogs{
+		logsConsumer:          nextConsumer,
+		includeSpanAttributes: c.IncludeSpanAttributes,
+	}
+	if len(c.Conditions) > 0 {
+		// Error checked in Config.Validate()
+		t.condition, _ = filterottl.NewBoolExprForSpanEvent(c.Conditions, filterottl.StandardSpanEventFuncs(), ottl.PropagateError, set.TelemetrySettings)
+	}
+	return t, nil
+}
@@ -5,6 +5,8 @@ package awss3exporter // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -20,6 +22,17 @@ const (
 	DefaultRetryMaxBackoff  = 20 * time.Second
 )
 
+const (
+	defaultAggregationMaxSize = 128 << 20
+	defaultAggregationMaxAge  = 5 * time.Minute
+	// minAggregationPartSize is the minimum size of the parts of a multipart upload but the last one.
+	minAggregationPartSize = 5 << 20
+	// maxAggregationPartSize is the maximum size of the parts of a multipart upload.
+	maxAggregationPartSize = 5 << 30
+	// maxAggregationParts is the maximum number of parts of a multipart upload.
+	maxAggregationParts = 10000
+)
+
 // S3UploaderConfig contains aws s3 uploader related config to controls things
 // like bucket, prefix, batching, connections, retries, etc.
 type S3UploaderConfig struct {
@@ -34,6 +47,10 @@ type S3UploaderConfig struct {
 	S3PartitionFormat string `mapstructure:"s3_partition_format"`
 	// S3PartitionTimezone is used to provide timezone for partition time. Defaults to Local timezone.
 	S3PartitionTimezone string `mapstructure:"s3_partition_timezone"`
+	// PartitionKeys defines Hive-style partitions, written as `name=value` in the key, built from the
+	// attributes and the time of each record. Records of a batch are split across the partitions.
+	// When set, S3PartitionFormat is ignored.
+	PartitionKeys []PartitionKeyConfig `mapstructure:"partition_keys"`
 	// FilePrefix is the filename prefix used for the file to avoid any potential collisions.
 	FilePrefix string `mapstructure:"file_prefix"`
 	// Endpoint is the URL used for communicated with S3.
@@ -79,6 +96,117 @@ const (
 	Body         MarshalerType = "body"
 )
 
+// PartitionKeySource is where the value of a partition key is taken from.
+type PartitionKeySource string
+
+const (
+	PartitionKeySourceResource PartitionKeySource = "resource"
+	PartitionKeySourceScope    PartitionKeySource = "scope"
+	PartitionKeySourceRecord   PartitionKeySource = "record"
+	PartitionKeySourceTime     PartitionKeySource = "time"
+)
+
+// PartitionKeyConfig defines a key of the Hive-style partitions.
+type PartitionKeyConfig struct {
+	// Name is the name of the partition key.
+	Name string `mapstructure:"name"`
+	// Source is where the value is taken from: the attributes of the `resource`, of the `scope`
+	// or of the `record` (log record, span or metric data point), or the `time` of the record.
+	Source PartitionKeySource `mapstructure:"source"`
+	// Attribute is the attribute holding the value, when Source is `resource`, `scope` or `record`.
+	Attribute string `mapstructure:"attribute"`
+	// TimeFormat formats the time of the record, when Source is `time`.
+	// Uses [strftime](https://www.man7.org/linux/man-pages/man3/strftime.3.html) formatting.
+	TimeFormat string `mapstructure:"time_format"`
+	// Default is the value used when the attribute is missing or empty. Defaults to `__HIVE_DEFAULT_PARTITION__`.
+	Default string `mapstructure:"default"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+func (k *PartitionKeyConfig) validate() error {
+	var errs error
+	if k.Name == "" || strings.ContainsAny(k.Name, "/=") {
+		errs = multierr.Append(errs, fmt.Errorf("invalid partition key name %q", k.Name))
+	}
+	switch k.Source {
+	case PartitionKeySourceResource, PartitionKeySourceScope, PartitionKeySourceRecord:
+		if k.Attribute == "" {
+			errs = multierr.Append(errs, fmt.Errorf("attribute is required for partition key %q", k.Name))
+		}
+	case PartitionKeySourceTime:
+		if k.TimeFormat == "" {
+			errs = multierr.Append(errs, fmt.Errorf("time_format is required for partition key %q", k.Name))
+		}
+	default:
+		errs = multierr.Append(errs, fmt.Errorf("invalid source %q for partition key %q, must be one of 'resource', 'scope', 'record' or 'time'", k.Source, k.Name))
+	}
+	return errs
+}
+
+// AggregationConfig defines how the uploads are aggregated into larger S3 objects.
+type AggregationConfig struct {
+	// Enabled appends the data of a partition to the same object, written as a multipart upload,
+	// until it reaches MaxSize or MaxAge, instead of writing an object per batch.
+	Enabled bool `mapstructure:"enabled"`
+	// MaxSize is the size in bytes above which an object is completed. Defaults to 128 MiB.
+	MaxSize int64 `mapstructure:"max_size"`
+	// MaxAge is the duration after which an object is completed, whatever its size. Defaults to 5 minutes.
+	MaxAge time.Duration `mapstructure:"max_age"`
+	// PartSize is the size in bytes of the data buffered before uploading a part of an object.
+	// Must be at least 5 MiB, which is the default.
+	PartSize int64 `mapstructure:"part_size"`
+	// Storage is the storage extension used to persist the objects being built, so that they are
+	// resumed after a restart. When unset, the data buffered before uploading a part is lost on a crash.
+	Storage *component.ID `mapstructure:"storage"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+func (c *AggregationConfig) validate() error {
+	var errs error
+	if c.MaxSize < 0 {
+		errs = multierr.Append(errs, errors.New("aggregation max_size must not be negative"))
+	}
+	if c.MaxAge < 0 {
+		errs = multierr.Append(errs, errors.New("aggregation max_age must not be negative"))
+	}
+	if c.PartSize != 0 && c.PartSize < minAggregationPartSize {
+		errs = multierr.Append(errs, fmt.Errorf("aggregation part_size must be at least %d", minAggregationPartSize))
+	}
+	if c.PartSize > maxAggregationPartSize {
+		errs = multierr.Append(errs, fmt.Errorf("aggregation part_size must be at most %d", maxAggregationPartSize))
+	}
+	if maxSize, partSize := c.maxSize(), c.partSize(); partSize > 0 && maxSize/partSize >= maxAggregationParts {
+		errs = multierr.Append(errs, fmt.Errorf("aggregation max_size must be less than %d times part_size", maxAggregationParts))
+	}
+	return errs
+}
+
+// maxSize returns MaxSize, or its default value when unset.
+func (c *AggregationConfig) maxSize() int64 {
+	if c.MaxSize == 0 {
+		return defaultAggregationMaxSize
+	}
+	return c.MaxSize
+}
+
+// maxAge returns MaxAge, or its default value when unset.
+func (c *AggregationConfig) maxAge() time.Duration {
+	if c.MaxAge == 0 {
+		return defaultAggregationMaxAge
+	}
+	return c.MaxAge
+}
+
+// partSize returns PartSize, or its default value when unset.
+func (c *AggregationConfig) partSize() int64 {
+	if c.PartSize == 0 {
+		return minAggregationPartSize
+	}
+	return c.PartSize
+}
+
 // ResourceAttrsToS3 defines the mapping of S3 uploading configuration values to resource attribute values.
 type ResourceAttrsToS3 struct {
 	// S3Bucket indicates the mapping of the bucket name used for uploading to a specific resource attribute value.
@@ -100,6 +228,8 @@ type Config struct {
 	Encoding              *component.ID     `mapstructure:"encoding"`
 	EncodingFileExtension string            `mapstructure:"encoding_file_extension"`
 	ResourceAttrsToS3     ResourceAttrsToS3 `mapstructure:"resource_attrs_to_s3"`
+	// Aggregation aggregates the uploads into larger objects.
+	Aggregation AggregationConfig `mapstructure:"aggregation"`
 }
 
 func (c *Config) Validate() error {
@@ -156,5 +286,22 @@ func (c *Config) Validate() error {
 	if c.S3Uploader.UniqueKeyFuncName != "" && !validUniqueKeyFuncs[c.S3Uploader.UniqueKeyFuncName] {
 		errs = multierr.Append(errs, errors.New("invalid UniqueKeyFuncName"))
 	}
+
+	names := make(map[string]bool, len(c.S3Uploader.PartitionKeys))
+	for _, key := range c.S3Uploader.PartitionKeys {
+		errs = multierr.Append(errs, key.validate())
+		if names[key.Name] {
+			errs = multierr.Append(errs, fmt.Errorf("duplicate partition key %q", key.Name))
+		}
+		names[key.Name] = true
+	}
+
+	if c.Aggregation.Enabled {
+		errs = multierr.Append(errs, c.Aggregation.validate())
+		// The output of an encoding extension may not be valid once concatenated.
+		if c.Encoding != nil {
+			errs = multierr.Append(errs, errors.New("aggregation can't be used with an encoding extension"))
+		}
+	}
 	return errs
 }
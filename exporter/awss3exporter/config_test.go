@@ -320,6 +320,55 @@ func TestConfig_Validate(t *testing.T) {
 			}(),
 			errExpected: errors.New("region is required"),
 		},
+		{
+			name: "invalid aggregation",
+			config: func() *Config {
+				c := createDefaultConfig().(*Config)
+				c.S3Uploader.S3Bucket = "foo"
+				c.Aggregation.Enabled = true
+				c.Aggregation.MaxSize = 1 << 40
+				c.Aggregation.MaxAge = -time.Second
+				c.Aggregation.PartSize = 1 << 20
+				return c
+			}(),
+			errExpected: multierr.Combine(
+				errors.New("aggregation max_age must not be negative"),
+				errors.New("aggregation part_size must be at least 5242880"),
+				errors.New("aggregation max_size must be less than 10000 times part_size"),
+			),
+		},
+		{
+			name: "aggregation with encoding",
+			config: func() *Config {
+				c := createDefaultConfig().(*Config)
+				c.S3Uploader.S3Bucket = "foo"
+				c.Aggregation.Enabled = true
+				c.Encoding = &component.ID{}
+				return c
+			}(),
+			errExpected: errors.New("aggregation can't be used with an encoding extension"),
+		},
+		{
+			name: "invalid partition keys",
+			config: func() *Config {
+				c := createDefaultConfig().(*Config)
+				c.S3Uploader.S3Bucket = "foo"
+				c.S3Uploader.PartitionKeys = []PartitionKeyConfig{
+					{Name: "tenant", Source: PartitionKeySourceResource},
+					{Name: "dt", Source: PartitionKeySourceTime},
+					{Name: "dt", Source: "body"},
+					{Name: "a/b", Source: PartitionKeySourceRecord, Attribute: "foo"},
+				}
+				return c
+			}(),
+			errExpected: multierr.Combine(
+				errors.New(`attribute is required for partition key "tenant"`),
+				errors.New(`time_format is required for partition key "dt"`),
+				errors.New(`invalid source "body" for partition key "dt", must be one of 'resource', 'scope', 'record' or 'time'`),
+				errors.New(`duplicate partition key "dt"`),
+				errors.New(`invalid partition key name "a/b"`),
+			),
+		},
 	}
 
 	for _, tt := range tests {
@@ -646,3 +695,49 @@ func TestConfigS3BasePrefixWithResourceAttrs(t *testing.T) {
 	}, e,
 	)
 }
+
+func TestConfigS3PartitionKeys(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Exporters[factory.Type()] = factory
+	cfg, err := otelcoltest.LoadConfigAndValidate(
+		filepath.Join("testdata", "config-s3_partition_keys.yaml"), factories)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	e := cfg.Exporters[component.MustNewID("awss3")].(*Config)
+
+	assert.Equal(t, []PartitionKeyConfig{
+		{Name: "tenant", Source: PartitionKeySourceResource, Attribute: "tenant.id", Default: "unknown"},
+		{Name: "service", Source: PartitionKeySourceResource, Attribute: "service.name"},
+		{Name: "dt", Source: PartitionKeySourceTime, TimeFormat: "%Y-%m-%d"},
+		{Name: "hour", Source: PartitionKeySourceTime, TimeFormat: "%H"},
+	}, e.S3Uploader.PartitionKeys)
+}
+
+func TestConfigS3Aggregation(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Exporters[factory.Type()] = factory
+	cfg, err := otelcoltest.LoadConfigAndValidate(
+		filepath.Join("testdata", "config-s3_aggregation.yaml"), factories)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	e := cfg.Exporters[component.MustNewID("awss3")].(*Config)
+	storage := component.MustNewID("file_storage")
+
+	assert.Equal(t, AggregationConfig{
+		Enabled:  true,
+		MaxSize:  64 << 20,
+		MaxAge:   10 * time.Minute,
+		PartSize: 8 << 20,
+		Storage:  &storage,
+	}, e.Aggregation)
+}
@@ -5,11 +5,15 @@ package awss3exporter // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/tilinna/clock"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -20,11 +24,14 @@ import (
 )
 
 type s3Exporter struct {
-	config     *Config
-	signalType string
-	uploader   upload.Manager
-	logger     *zap.Logger
-	marshaler  marshaler
+	config      *Config
+	id          component.ID
+	signalType  string
+	uploader    upload.Manager
+	aggregator  *upload.Aggregator
+	logger      *zap.Logger
+	marshaler   marshaler
+	partitioner *partitioner
 }
 
 func newS3Exporter(
@@ -34,6 +41,7 @@ func newS3Exporter(
 ) *s3Exporter {
 	s3Exporter := &s3Exporter{
 		config:     config,
+		id:         params.ID,
 		signalType: signalType,
 		logger:     params.Logger,
 	}
@@ -75,6 +83,22 @@ func (e *s3Exporter) start(ctx context.Context, host component.Host) error {
 
 	e.marshaler = m
 
+	if len(e.config.S3Uploader.PartitionKeys) > 0 {
+		location, err := partitionTimeLocation(e.config.S3Uploader.S3PartitionTimezone)
+		if err != nil {
+			return err
+		}
+		e.partitioner = &partitioner{
+			keys:       e.config.S3Uploader.PartitionKeys,
+			location:   location,
+			uploadOpts: e.getUploadOpts,
+		}
+	}
+
+	if e.config.Aggregation.Enabled {
+		return e.startAggregator(ctx, host, m)
+	}
+
 	up, err := newUploadManager(ctx, e.config, e.signalType, m.format(), m.compressed())
 	if err != nil {
 		return err
@@ -83,11 +107,53 @@ func (e *s3Exporter) start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
+// startAggregator starts an upload.Aggregator writing its state to the configured storage extension, if any.
+func (e *s3Exporter) startAggregator(ctx context.Context, host component.Host, m marshaler) error {
+	var client storage.Client = storage.NewNopClient()
+	if id := e.config.Aggregation.Storage; id != nil {
+		ext, ok := host.GetExtensions()[*id]
+		if !ok {
+			return fmt.Errorf("storage extension '%s' not found", id)
+		}
+		storageExt, ok := ext.(storage.Extension)
+		if !ok {
+			return fmt.Errorf("non-storage extension '%s' found", id)
+		}
+		var err error
+		if client, err = storageExt.GetClient(ctx, component.KindExporter, e.id, e.signalType); err != nil {
+			return err
+		}
+	}
+
+	aggregator, err := newAggregator(ctx, e.config, e.signalType, m.format(), m.compressed(), client, e.logger)
+	if err != nil {
+		return errors.Join(err, client.Close(ctx))
+	}
+	if err := aggregator.Start(ctx); err != nil {
+		return errors.Join(err, client.Close(ctx))
+	}
+	e.aggregator = aggregator
+	e.uploader = aggregator
+	return nil
+}
+
+// shutdown completes the objects being aggregated.
+func (e *s3Exporter) shutdown(ctx context.Context) error {
+	if e.aggregator == nil {
+		return nil
+	}
+	return e.aggregator.Shutdown(ctx)
+}
+
 func (*s3Exporter) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
 func (e *s3Exporter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	if e.partitioner != nil {
+		return e.consumePartitionedMetrics(ctx, md)
+	}
+
 	buf, err := e.marshaler.MarshalMetrics(md)
 	if err != nil {
 		return err
@@ -98,6 +164,10 @@ func (e *s3Exporter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) err
 }
 
 func (e *s3Exporter) ConsumeLogs(ctx context.Context, logs plog.Logs) error {
+	if e.partitioner != nil {
+		return e.consumePartitionedLogs(ctx, logs)
+	}
+
 	buf, err := e.marshaler.MarshalLogs(logs)
 	if err != nil {
 		return err
@@ -109,6 +179,10 @@ func (e *s3Exporter) ConsumeLogs(ctx context.Context, logs plog.Logs) error {
 }
 
 func (e *s3Exporter) ConsumeTraces(ctx context.Context, traces ptrace.Traces) error {
+	if e.partitioner != nil {
+		return e.consumePartitionedTraces(ctx, traces)
+	}
+
 	buf, err := e.marshaler.MarshalTraces(traces)
 	if err != nil {
 		return err
@@ -118,3 +192,87 @@ func (e *s3Exporter) ConsumeTraces(ctx context.Context, traces ptrace.Traces) er
 
 	return e.uploader.Upload(ctx, buf, uploadOpts)
 }
+
+// consumePartitionedLogs uploads an object per partition of the logs. When some uploads fail,
+// only the logs of the failed partitions are returned to be retried.
+func (e *s3Exporter) consumePartitionedLogs(ctx context.Context, logs plog.Logs) error {
+	var uploadErrs, marshalErrs []error
+	failed := plog.NewLogs()
+	for _, partition := range e.partitioner.partitionLogs(logs, clock.Now(ctx)) {
+		buf, err := e.marshaler.MarshalLogs(partition.logs)
+		if err != nil {
+			marshalErrs = append(marshalErrs, err)
+			continue
+		}
+		if err := e.uploader.Upload(ctx, buf, &partition.opts); err != nil {
+			uploadErrs = append(uploadErrs, err)
+			partition.logs.ResourceLogs().MoveAndAppendTo(failed.ResourceLogs())
+		}
+	}
+	if len(uploadErrs) == 0 {
+		return marshalError(marshalErrs)
+	}
+	e.logMarshalErrors(marshalErrs)
+	return consumererror.NewLogs(errors.Join(uploadErrs...), failed)
+}
+
+// consumePartitionedTraces is the equivalent of consumePartitionedLogs for traces.
+func (e *s3Exporter) consumePartitionedTraces(ctx context.Context, traces ptrace.Traces) error {
+	var uploadErrs, marshalErrs []error
+	failed := ptrace.NewTraces()
+	for _, partition := range e.partitioner.partitionTraces(traces, clock.Now(ctx)) {
+		buf, err := e.marshaler.MarshalTraces(partition.traces)
+		if err != nil {
+			marshalErrs = append(marshalErrs, err)
+			continue
+		}
+		if err := e.uploader.Upload(ctx, buf, &partition.opts); err != nil {
+			uploadErrs = append(uploadErrs, err)
+			partition.traces.ResourceSpans().MoveAndAppendTo(failed.ResourceSpans())
+		}
+	}
+	if len(uploadErrs) == 0 {
+		return marshalError(marshalErrs)
+	}
+	e.logMarshalErrors(marshalErrs)
+	return consumererror.NewTraces(errors.Join(uploadErrs...), failed)
+}
+
+// consumePartitionedMetrics is the equivalent of consumePartitionedLogs for metrics.
+func (e *s3Exporter) consumePartitionedMetrics(ctx context.Context, md pmetric.Metrics) error {
+	var uploadErrs, marshalErrs []error
+	failed := pmetric.NewMetrics()
+	for _, partition := range e.partitioner.partitionMetrics(md, clock.Now(ctx)) {
+		buf, err := e.marshaler.MarshalMetrics(partition.metrics)
+		if err != nil {
+			marshalErrs = append(marshalErrs, err)
+			continue
+		}
+		if err := e.uploader.Upload(ctx, buf, &partition.opts); err != nil {
+			uploadErrs = append(uploadErrs, err)
+			partition.metrics.ResourceMetrics().MoveAndAppendTo(failed.ResourceMetrics())
+		}
+	}
+	if len(uploadErrs) == 0 {
+		return marshalError(marshalErrs)
+	}
+	e.logMarshalErrors(marshalErrs)
+	return consumererror.NewMetrics(errors.Join(uploadErrs...), failed)
+}
+
+// marshalError returns the errors of the partitions which failed to be marshaled as a
+// permanent error, since retrying the batch would upload the other partitions again.
+func marshalError(marshalErrs []error) error {
+	if len(marshalErrs) == 0 {
+		return nil
+	}
+	return consumererror.NewPermanent(errors.Join(marshalErrs...))
+}
+
+// logMarshalErrors logs the errors of the partitions which failed to be marshaled, when
+// the error returned for the failed uploads must not be permanent for them to be retried.
+func (e *s3Exporter) logMarshalErrors(marshalErrs []error) {
+	if len(marshalErrs) > 0 {
+		e.logger.Error("dropping partitions which failed to be marshaled", zap.Error(errors.Join(marshalErrs...)))
+	}
+}
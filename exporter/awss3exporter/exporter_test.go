@@ -9,10 +9,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter/internal/upload"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 )
 
 var (
@@ -96,3 +100,18 @@ func TestLogWithBucketAndPrefixAttrs(t *testing.T) {
 	exporter := getLogExporterWithBucketAndPrefixAttrs(t)
 	assert.NoError(t, exporter.ConsumeLogs(t.Context(), logs))
 }
+
+func TestStartAggregationStorage(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Aggregation.Enabled = true
+
+	storageID := storagetest.NewStorageID("file_storage")
+	config.Aggregation.Storage = &storageID
+	exporter := newS3Exporter(config, "logs", exportertest.NewNopSettings(metadata.Type))
+	assert.EqualError(t, exporter.start(t.Context(), componenttest.NewNopHost()), "storage extension 'test_storage/file_storage' not found")
+
+	nonStorageID := storagetest.NewNonStorageID("non_storage")
+	config.Aggregation.Storage = &nonStorageID
+	host := storagetest.NewStorageHost().WithNonStorageExtension("non_storage")
+	assert.EqualError(t, exporter.start(t.Context(), host), "non-storage extension 'non_storage/non_storage' found")
+}
@@ -12,6 +12,7 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/itchyny/timefmt-go v0.1.7
 	github.com/klauspost/compress v1.18.4
+	github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage v0.145.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchperresourceattr v0.145.0
 	github.com/stretchr/testify v1.11.1
 	github.com/tilinna/clock v1.1.0
@@ -21,9 +22,11 @@ require (
 	go.opentelemetry.io/collector/config/configoptional v1.51.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/confmap v1.51.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/consumer v1.51.1-0.20260212054546-f0da990367b6
+	go.opentelemetry.io/collector/consumer/consumererror v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/exporter v1.51.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/exporter/exporterhelper v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/exporter/exportertest v0.145.1-0.20260212054546-f0da990367b6
+	go.opentelemetry.io/collector/extension/xextension v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/otelcol/otelcoltest v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/pdata v1.51.1-0.20260212054546-f0da990367b6
 	go.uber.org/goleak v1.3.0
@@ -96,14 +99,12 @@ require (
 	go.opentelemetry.io/collector/connector v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/connector/connectortest v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/connector/xconnector v0.145.1-0.20260212054546-f0da990367b6 // indirect
-	go.opentelemetry.io/collector/consumer/consumererror v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/consumer/consumertest v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/consumer/xconsumer v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/exporter/xexporter v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/extension v1.51.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/extension/extensioncapabilities v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/extension/extensiontest v0.145.1-0.20260212054546-f0da990367b6 // indirect
-	go.opentelemetry.io/collector/extension/xextension v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/featuregate v1.51.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/internal/componentalias v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/internal/fanoutconsumer v0.145.1-0.20260212054546-f0da990367b6 // indirect
@@ -162,3 +163,5 @@ retract (
 )
 
 replace github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchperresourceattr => ../../pkg/batchperresourceattr
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage => ../../extension/storage
@@ -0,0 +1,546 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package upload // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter/internal/upload"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/tilinna/clock"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	objectsKey      = "objects"
+	objectKeyPrefix = "object_"
+	// flushInterval is how often the age of the objects is checked.
+	flushInterval = time.Second
+)
+
+// ObjectAPI is the part of the S3 API used by the Aggregator.
+type ObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+}
+
+var _ ObjectAPI = (*s3.Client)(nil)
+
+type AggregatorConfig struct {
+	// MaxSize is the size in bytes above which an object is completed.
+	MaxSize int64
+	// MaxAge is the duration after which an object is completed, whatever its size.
+	MaxAge time.Duration
+	// PartSize is the size in bytes of the data buffered before uploading a part of an object.
+	// It must be at least 5 MiB, the minimum size of the parts of a multipart upload but the last one.
+	PartSize int64
+	// LineDelimited appends a newline to the uploads which don't end with one,
+	// so that JSON documents are written one per line.
+	LineDelimited bool
+}
+
+// Aggregator is a Manager which appends the uploads of a partition to the same object
+// until it reaches MaxSize or MaxAge, instead of uploading an object per upload.
+// Compressed uploads are appended as separate gzip members or zstd frames, which
+// decompress as a single stream.
+//
+// The data of an object is buffered until there is PartSize of it, and then uploaded
+// as a part of the multipart upload of the object. The state of the objects and the
+// buffered data are written to a storage client, so that the objects are resumed after
+// a restart. The storage holds the IDs of the open objects under the key objects, the
+// state of each object under object_<id> and its buffered data under object_<id>_<n>.
+//
+// The uploads to an object are serialized, but the calls to S3 for an object don't
+// block the uploads to the other objects.
+type Aggregator struct {
+	bucket       string
+	builder      *PartitionKeyBuilder
+	service      ObjectAPI
+	storageClass s3types.StorageClass
+	acl          s3types.ObjectCannedACL
+	cfg          AggregatorConfig
+	client       storage.Client
+	logger       *zap.Logger
+
+	// mu protects objects, and orders the writes of their IDs to the storage.
+	mu sync.Mutex
+	// objects are the open objects by the bucket and directory they are written to.
+	objects map[string]*aggregatedObject
+	// completed are the completed objects whose state failed to be removed from
+	// the storage. The removal is retried along with the completion of the objects.
+	completed []*aggregatedObject
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+var _ Manager = (*Aggregator)(nil)
+
+// aggregatedObject is the state of an object built by an Aggregator.
+type aggregatedObject struct {
+	Group   string    `json:"group"`
+	Bucket  string    `json:"bucket"`
+	Key     string    `json:"key"`
+	Created time.Time `json:"created"`
+	// Size is the size of the data written to the object so far.
+	Size int64 `json:"size"`
+	// UploadID is the ID of the multipart upload, created along with the first part.
+	UploadID string         `json:"upload_id,omitempty"`
+	Parts    []uploadedPart `json:"parts,omitempty"`
+	// Chunks is the number of uploads buffered until the next part.
+	Chunks int `json:"chunks"`
+
+	buffered [][]byte
+	// bufferedSize is the size of the buffered uploads.
+	bufferedSize int64
+
+	// mu serializes the uploads to the object. It is held while calling S3.
+	mu sync.Mutex
+	// closed is set once the object is removed from the open objects.
+	closed bool
+}
+
+type uploadedPart struct {
+	Number int32  `json:"number"`
+	ETag   string `json:"etag"`
+}
+
+func (o *aggregatedObject) id() string {
+	return o.Bucket + "/" + o.Key
+}
+
+func (o *aggregatedObject) stateKey() string {
+	return objectKeyPrefix + o.id()
+}
+
+func (o *aggregatedObject) chunkKey(n int) string {
+	return fmt.Sprintf("%s%s_%d", objectKeyPrefix, o.id(), n)
+}
+
+// NewAggregator creates an Aggregator writing its state to the given storage client,
+// which is closed on Shutdown.
+func NewAggregator(
+	bucket string,
+	builder *PartitionKeyBuilder,
+	service ObjectAPI,
+	storageClass s3types.StorageClass,
+	cfg AggregatorConfig,
+	client storage.Client,
+	logger *zap.Logger,
+	opts ...ManagerOpt,
+) *Aggregator {
+	a := &Aggregator{
+		bucket:       bucket,
+		builder:      builder,
+		service:      service,
+		storageClass: storageClass,
+		cfg:          cfg,
+		client:       client,
+		logger:       logger,
+		objects:      map[string]*aggregatedObject{},
+		stop:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(a)
+		}
+	}
+	return a
+}
+
+// Start loads the objects left open by a previous run and starts completing the objects
+// which reach MaxAge.
+func (a *Aggregator) Start(ctx context.Context) error {
+	if err := a.load(ctx); err != nil {
+		return fmt.Errorf("failed to load the aggregated objects: %w", err)
+	}
+
+	ticker := clock.NewTicker(ctx, min(a.cfg.MaxAge, flushInterval))
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.stop:
+				return
+			case now := <-ticker.C:
+				a.completeExpired(context.Background(), now)
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown completes all the open objects and closes the storage client. The objects which
+// fail to be completed are kept in the storage, to be completed after a restart.
+func (a *Aggregator) Shutdown(ctx context.Context) error {
+	a.stopOnce.Do(func() {
+		close(a.stop)
+	})
+	a.wg.Wait()
+
+	var errs []error
+	for _, obj := range a.openObjects() {
+		obj.mu.Lock()
+		if !obj.closed {
+			errs = append(errs, a.complete(ctx, obj))
+		}
+		obj.mu.Unlock()
+	}
+	errs = append(errs, a.removeCompleted(ctx))
+	return errors.Join(append(errs, a.client.Close(ctx))...)
+}
+
+func (a *Aggregator) openObjects() []*aggregatedObject {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Collect(maps.Values(a.objects))
+}
+
+func (a *Aggregator) load(ctx context.Context) error {
+	value, err := a.client.Get(ctx, objectsKey)
+	if err != nil || value == nil {
+		return err
+	}
+	var ids []string
+	if err := json.Unmarshal(value, &ids); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		value, err := a.client.Get(ctx, objectKeyPrefix+id)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			continue
+		}
+		obj := &aggregatedObject{}
+		if err := json.Unmarshal(value, obj); err != nil {
+			return err
+		}
+		for n := range obj.Chunks {
+			chunk, err := a.client.Get(ctx, obj.chunkKey(n))
+			if err != nil {
+				return err
+			}
+			obj.buffered = append(obj.buffered, chunk)
+			obj.bufferedSize += int64(len(chunk))
+		}
+		a.objects[obj.Group] = obj
+	}
+	return nil
+}
+
+func (a *Aggregator) Upload(ctx context.Context, data []byte, opts *UploadOptions) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if a.cfg.LineDelimited && data[len(data)-1] != '\n' {
+		data = append(data[:len(data):len(data)], '\n')
+	}
+
+	content, err := contentBuffer(a.builder.Compression, data)
+	if err != nil {
+		return err
+	}
+
+	now := clock.Now(ctx)
+
+	overridePrefix := ""
+	bucket := a.bucket
+	partition := ""
+	if opts != nil {
+		overridePrefix = opts.OverridePrefix
+		if opts.OverrideBucket != "" {
+			bucket = opts.OverrideBucket
+		}
+		partition = opts.Partition
+	}
+	prefix := a.builder.keyPrefix(now, overridePrefix, partition)
+
+	for {
+		obj, created := a.object(bucket, prefix, now)
+		obj.mu.Lock()
+		if obj.closed {
+			// The object was completed meanwhile, the data is written to a new one.
+			obj.mu.Unlock()
+			continue
+		}
+		err := a.upload(ctx, obj, content.Bytes(), created)
+		obj.mu.Unlock()
+		return err
+	}
+}
+
+// object returns the open object of the bucket and prefix, creating it if there is none.
+func (a *Aggregator) object(bucket, prefix string, now time.Time) (*aggregatedObject, bool) {
+	group := bucket + "/" + prefix
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if obj, ok := a.objects[group]; ok {
+		return obj, false
+	}
+	obj := &aggregatedObject{
+		Group:   group,
+		Bucket:  bucket,
+		Key:     path.Join(prefix, a.builder.fileName()),
+		Created: now,
+	}
+	a.objects[group] = obj
+	return obj, true
+}
+
+// upload appends the data to the object, and completes it once it reaches MaxSize.
+func (a *Aggregator) upload(ctx context.Context, obj *aggregatedObject, data []byte, created bool) error {
+	if err := a.append(ctx, obj, data, created); err != nil {
+		if created {
+			// The object is only kept once data was written to it.
+			a.remove(obj)
+		}
+		return err
+	}
+
+	if obj.Size >= a.cfg.MaxSize {
+		// The data is written to the object, so it must not be retried when the object fails to be completed.
+		if err := a.complete(ctx, obj); err != nil {
+			a.logger.Error("failed to complete the S3 object, retrying later", zap.String("key", obj.Key), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// removeLocked removes the object from the open objects. a.mu must be held.
+func (a *Aggregator) removeLocked(obj *aggregatedObject) {
+	if a.objects[obj.Group] == obj {
+		delete(a.objects, obj.Group)
+	}
+	obj.closed = true
+}
+
+func (a *Aggregator) remove(obj *aggregatedObject) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.removeLocked(obj)
+}
+
+// append appends the data to the object, uploading a part when there is enough buffered data.
+// The object is left unchanged when its state fails to be persisted, so that the data can be
+// retried without being written twice to it.
+func (a *Aggregator) append(ctx context.Context, obj *aggregatedObject, data []byte, created bool) error {
+	parts, buffered, bufferedSize, chunks, size := obj.Parts, obj.buffered, obj.bufferedSize, obj.Chunks, obj.Size
+
+	var ops []*storage.Operation
+	if obj.bufferedSize+int64(len(data)) >= a.cfg.PartSize {
+		part, err := a.uploadPart(ctx, obj, append(obj.buffered, data))
+		if err != nil {
+			return err
+		}
+		for n := range obj.Chunks {
+			ops = append(ops, storage.DeleteOperation(obj.chunkKey(n)))
+		}
+		obj.Parts = append(obj.Parts, part)
+		obj.buffered = nil
+		obj.bufferedSize = 0
+		obj.Chunks = 0
+	} else {
+		ops = append(ops, storage.SetOperation(obj.chunkKey(obj.Chunks), data))
+		obj.buffered = append(obj.buffered, data)
+		obj.bufferedSize += int64(len(data))
+		obj.Chunks++
+	}
+	obj.Size += int64(len(data))
+
+	if err := a.persist(ctx, obj, created, ops...); err != nil {
+		// An uploaded part is replaced when the next part is uploaded with the same number.
+		obj.Parts, obj.buffered, obj.bufferedSize, obj.Chunks, obj.Size = parts, buffered, bufferedSize, chunks, size
+		return fmt.Errorf("failed to persist the state of the S3 object: %w", err)
+	}
+	return nil
+}
+
+// uploadPart uploads the chunks as the next part of the object, creating its multipart upload if needed.
+func (a *Aggregator) uploadPart(ctx context.Context, obj *aggregatedObject, chunks [][]byte) (uploadedPart, error) {
+	if obj.UploadID == "" {
+		input := &s3.CreateMultipartUploadInput{
+			Bucket:       aws.String(obj.Bucket),
+			Key:          aws.String(obj.Key),
+			StorageClass: a.storageClass,
+			ACL:          a.acl,
+		}
+		if encoding := a.builder.contentEncoding(); encoding != "" {
+			input.ContentEncoding = aws.String(encoding)
+		}
+		out, err := a.service.CreateMultipartUpload(ctx, input)
+		if err != nil {
+			return uploadedPart{}, err
+		}
+		obj.UploadID = aws.ToString(out.UploadId)
+	}
+
+	number := int32(len(obj.Parts) + 1)
+	out, err := a.service.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(obj.Bucket),
+		Key:        aws.String(obj.Key),
+		UploadId:   aws.String(obj.UploadID),
+		PartNumber: aws.Int32(number),
+		Body:       bytes.NewReader(bytes.Join(chunks, nil)),
+	})
+	if err != nil {
+		return uploadedPart{}, err
+	}
+	return uploadedPart{Number: number, ETag: aws.ToString(out.ETag)}, nil
+}
+
+// complete uploads the buffered data of the object and completes it. Objects without
+// any uploaded part are uploaded at once instead.
+func (a *Aggregator) complete(ctx context.Context, obj *aggregatedObject) error {
+	if obj.UploadID == "" {
+		input := &s3.PutObjectInput{
+			Bucket:       aws.String(obj.Bucket),
+			Key:          aws.String(obj.Key),
+			Body:         bytes.NewReader(bytes.Join(obj.buffered, nil)),
+			StorageClass: a.storageClass,
+			ACL:          a.acl,
+		}
+		if encoding := a.builder.contentEncoding(); encoding != "" {
+			input.ContentEncoding = aws.String(encoding)
+		}
+		if _, err := a.service.PutObject(ctx, input); err != nil {
+			return err
+		}
+	} else {
+		parts := obj.Parts
+		if len(obj.buffered) > 0 {
+			// The last part may be smaller than the part size.
+			part, err := a.uploadPart(ctx, obj, obj.buffered)
+			if err != nil {
+				return err
+			}
+			parts = append(parts[:len(parts):len(parts)], part)
+		}
+		completed := make([]s3types.CompletedPart, 0, len(parts))
+		for _, part := range parts {
+			completed = append(completed, s3types.CompletedPart{
+				ETag:       aws.String(part.ETag),
+				PartNumber: aws.Int32(part.Number),
+			})
+		}
+		_, err := a.service.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(obj.Bucket),
+			Key:             aws.String(obj.Key),
+			UploadId:        aws.String(obj.UploadID),
+			MultipartUpload: &s3types.CompletedMultipartUpload{Parts: completed},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.removeLocked(obj)
+	a.completed = append(a.completed, obj)
+	return a.removeCompletedLocked(ctx)
+}
+
+// removeCompleted removes the state of the completed objects from the storage.
+func (a *Aggregator) removeCompleted(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.removeCompletedLocked(ctx)
+}
+
+// removeCompletedLocked removes the state of the completed objects from the storage, so that
+// they aren't completed again after a restart. The objects are kept in a.completed until it
+// succeeds. a.mu must be held.
+func (a *Aggregator) removeCompletedLocked(ctx context.Context) error {
+	if len(a.completed) == 0 {
+		return nil
+	}
+	var ops []*storage.Operation
+	for _, obj := range a.completed {
+		ops = append(ops, storage.DeleteOperation(obj.stateKey()))
+		for n := range obj.Chunks {
+			ops = append(ops, storage.DeleteOperation(obj.chunkKey(n)))
+		}
+	}
+	index, err := a.indexOperation()
+	if err == nil {
+		err = a.client.Batch(ctx, append(ops, index)...)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove the state of the completed S3 objects: %w", err)
+	}
+	a.completed = nil
+	return nil
+}
+
+// completeExpired completes the objects which reached MaxAge, and retries removing the
+// state of the completed objects.
+func (a *Aggregator) completeExpired(ctx context.Context, now time.Time) {
+	if err := a.removeCompleted(ctx); err != nil {
+		a.logger.Error("failed to remove the state of the completed S3 objects, retrying later", zap.Error(err))
+	}
+	for _, obj := range a.openObjects() {
+		if now.Sub(obj.Created) < a.cfg.MaxAge {
+			continue
+		}
+		obj.mu.Lock()
+		if !obj.closed {
+			if err := a.complete(ctx, obj); err != nil {
+				a.logger.Error("failed to complete the S3 object, retrying later", zap.String("key", obj.Key), zap.Error(err))
+			}
+		}
+		obj.mu.Unlock()
+	}
+}
+
+// persist writes the state of the object along with the given operations,
+// and the IDs of the open objects when the object was created.
+func (a *Aggregator) persist(ctx context.Context, obj *aggregatedObject, created bool, ops ...*storage.Operation) error {
+	state, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	ops = append(ops, storage.SetOperation(obj.stateKey(), state))
+	if !created {
+		return a.client.Batch(ctx, ops...)
+	}
+	// The IDs are written under the lock, so that they aren't overwritten by older ones.
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	index, err := a.indexOperation()
+	if err != nil {
+		return err
+	}
+	return a.client.Batch(ctx, append(ops, index)...)
+}
+
+// indexOperation returns the operation writing the IDs of the open objects. a.mu must be held.
+func (a *Aggregator) indexOperation() (*storage.Operation, error) {
+	ids := make([]string, 0, len(a.objects))
+	for _, obj := range a.objects {
+		ids = append(ids, obj.id())
+	}
+	value, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+	return storage.SetOperation(objectsKey, value), nil
+}
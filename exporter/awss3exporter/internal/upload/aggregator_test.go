@@ -0,0 +1,379 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilinna/clock"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+// fakeObjectAPI keeps the objects and the parts of the multipart uploads in memory.
+type fakeObjectAPI struct {
+	mu             sync.Mutex
+	objects        map[string]string
+	uploads        map[string]string
+	parts          map[string][]string
+	failUploadPart bool
+	// blocked blocks the calls to the bucket until the channel is closed.
+	blocked map[string]chan struct{}
+}
+
+func newFakeObjectAPI() *fakeObjectAPI {
+	return &fakeObjectAPI{
+		objects: map[string]string{},
+		uploads: map[string]string{},
+		parts:   map[string][]string{},
+		blocked: map[string]chan struct{}{},
+	}
+}
+
+func (f *fakeObjectAPI) wait(bucket *string) {
+	f.mu.Lock()
+	blocked := f.blocked[aws.ToString(bucket)]
+	f.mu.Unlock()
+	if blocked != nil {
+		<-blocked
+	}
+}
+
+func (f *fakeObjectAPI) getObjects() map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return maps.Clone(f.objects)
+}
+
+func (f *fakeObjectAPI) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.wait(params.Bucket)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key)] = string(body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeObjectAPI) CreateMultipartUpload(_ context.Context, params *s3.CreateMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	f.wait(params.Bucket)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := "upload-" + strconv.Itoa(len(f.uploads))
+	f.uploads[id] = aws.ToString(params.Bucket) + "/" + aws.ToString(params.Key)
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String(id)}, nil
+}
+
+func (f *fakeObjectAPI) UploadPart(_ context.Context, params *s3.UploadPartInput, _ ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	f.wait(params.Bucket)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failUploadPart {
+		return nil, errors.New("upload part failed")
+	}
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	id := aws.ToString(params.UploadId)
+	number := int(aws.ToInt32(params.PartNumber))
+	for len(f.parts[id]) < number {
+		f.parts[id] = append(f.parts[id], "")
+	}
+	f.parts[id][number-1] = string(body)
+	return &s3.UploadPartOutput{ETag: aws.String("etag-" + strconv.Itoa(number))}, nil
+}
+
+func (f *fakeObjectAPI) CompleteMultipartUpload(_ context.Context, params *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	f.wait(params.Bucket)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := aws.ToString(params.UploadId)
+	var content string
+	for _, part := range params.MultipartUpload.Parts {
+		content += f.parts[id][aws.ToInt32(part.PartNumber)-1]
+	}
+	f.objects[f.uploads[id]] = content
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+// failingClient is a storage client whose batches fail when fail returns an error.
+type failingClient struct {
+	storage.Client
+	mu   sync.Mutex
+	fail func(ops []*storage.Operation) error
+}
+
+func newFailingClient() *failingClient {
+	return &failingClient{Client: storage.NewNopClient()}
+}
+
+func (c *failingClient) setFail(fail func(ops []*storage.Operation) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fail = fail
+}
+
+func (c *failingClient) Batch(ctx context.Context, ops ...*storage.Operation) error {
+	c.mu.Lock()
+	fail := c.fail
+	c.mu.Unlock()
+	if fail != nil {
+		if err := fail(ops); err != nil {
+			return err
+		}
+	}
+	return c.Client.Batch(ctx, ops...)
+}
+
+func newTestAggregator(t *testing.T, service ObjectAPI, client storage.Client) *Aggregator {
+	key := 0
+	a := NewAggregator(
+		"my-bucket",
+		&PartitionKeyBuilder{
+			PartitionPrefix: "telemetry",
+			PartitionFormat: "year=%Y/month=%m/day=%d",
+			FilePrefix:      "signal-data-",
+			Metadata:        "noop",
+			FileFormat:      "json",
+			UniqueKeyFunc: func() string {
+				key++
+				return strconv.Itoa(key)
+			},
+		},
+		service,
+		"STANDARD",
+		AggregatorConfig{
+			MaxSize:       30,
+			MaxAge:        time.Minute,
+			PartSize:      16,
+			LineDelimited: true,
+		},
+		client,
+		zap.NewNop(),
+	)
+	// The objects are only completed by age when the tests call completeExpired.
+	require.NoError(t, a.Start(clock.Context(t.Context(), clock.NewMock(time.Time{}))))
+	return a
+}
+
+func TestAggregatorUpload(t *testing.T) {
+	service := newFakeObjectAPI()
+	a := newTestAggregator(t, service, storage.NewNopClient())
+	ctx := clock.Context(t.Context(), clock.NewMock(time.Date(2024, 1, 10, 10, 30, 40, 0, time.UTC)))
+
+	// The first upload is buffered, the second one uploads a part with both of them
+	// and the third one completes the object when it reaches the max size.
+	require.NoError(t, a.Upload(ctx, []byte("hello world"), nil))
+	assert.Empty(t, service.uploads)
+	require.NoError(t, a.Upload(ctx, []byte("hello world"), nil))
+	assert.Len(t, service.parts["upload-0"], 1)
+	assert.Empty(t, service.objects)
+	require.NoError(t, a.Upload(ctx, []byte("hello world\n"), nil))
+
+	assert.Equal(t, map[string]string{
+		"my-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_1.json": "hello world\nhello world\nhello world\n",
+	}, service.objects)
+	assert.Empty(t, a.objects)
+	require.NoError(t, a.Shutdown(t.Context()))
+}
+
+func TestAggregatorPartitions(t *testing.T) {
+	service := newFakeObjectAPI()
+	a := newTestAggregator(t, service, storage.NewNopClient())
+	ctx := clock.Context(t.Context(), clock.NewMock(time.Date(2024, 1, 10, 10, 30, 40, 0, time.UTC)))
+
+	require.NoError(t, a.Upload(ctx, []byte("a"), &UploadOptions{Partition: "tenant=a"}))
+	require.NoError(t, a.Upload(ctx, []byte("b"), &UploadOptions{Partition: "tenant=b"}))
+	require.NoError(t, a.Upload(ctx, []byte("a"), &UploadOptions{Partition: "tenant=a"}))
+	require.NoError(t, a.Upload(ctx, []byte("c"), &UploadOptions{OverrideBucket: "custom-bucket"}))
+	require.NoError(t, a.Shutdown(t.Context()))
+
+	assert.Equal(t, map[string]string{
+		"my-bucket/telemetry/tenant=a/signal-data-noop_1.json":                      "a\na\n",
+		"my-bucket/telemetry/tenant=b/signal-data-noop_2.json":                      "b\n",
+		"custom-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_3.json": "c\n",
+	}, service.objects)
+}
+
+func TestAggregatorMaxAge(t *testing.T) {
+	service := newFakeObjectAPI()
+	a := newTestAggregator(t, service, storage.NewNopClient())
+	now := time.Date(2024, 1, 10, 10, 30, 40, 0, time.UTC)
+
+	require.NoError(t, a.Upload(clock.Context(t.Context(), clock.NewMock(now)), []byte("hello"), nil))
+	a.completeExpired(t.Context(), now.Add(time.Minute-time.Second))
+	assert.Empty(t, service.objects)
+	a.completeExpired(t.Context(), now.Add(time.Minute))
+	assert.Equal(t, map[string]string{
+		"my-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_1.json": "hello\n",
+	}, service.objects)
+	require.NoError(t, a.Shutdown(t.Context()))
+}
+
+func TestAggregatorConcurrentUploads(t *testing.T) {
+	service := newFakeObjectAPI()
+	service.blocked["blocked-bucket"] = make(chan struct{})
+	a := newTestAggregator(t, service, storage.NewNopClient())
+	ctx := clock.Context(t.Context(), clock.NewMock(time.Date(2024, 1, 10, 10, 30, 40, 0, time.UTC)))
+
+	// The upload to the blocked bucket completes its object, and is blocked in the call to S3.
+	done := make(chan error)
+	go func() {
+		done <- a.Upload(ctx, []byte("hello world, this is a long line"), &UploadOptions{OverrideBucket: "blocked-bucket"})
+	}()
+	require.Eventually(t, func() bool {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return len(a.objects) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The uploads to the other buckets, and the completion of their objects, aren't blocked.
+	require.NoError(t, a.Upload(ctx, []byte("hello world, this is a long line"), nil))
+	assert.Equal(t, map[string]string{
+		"my-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_2.json": "hello world, this is a long line\n",
+	}, service.getObjects())
+
+	close(service.blocked["blocked-bucket"])
+	require.NoError(t, <-done)
+	require.NoError(t, a.Shutdown(t.Context()))
+	assert.Equal(t, map[string]string{
+		"blocked-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_1.json": "hello world, this is a long line\n",
+		"my-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_2.json":      "hello world, this is a long line\n",
+	}, service.getObjects())
+}
+
+func TestAggregatorUploadPartFailure(t *testing.T) {
+	service := newFakeObjectAPI()
+	a := newTestAggregator(t, service, storage.NewNopClient())
+	ctx := clock.Context(t.Context(), clock.NewMock(time.Date(2024, 1, 10, 10, 30, 40, 0, time.UTC)))
+
+	require.NoError(t, a.Upload(ctx, []byte("hello world"), nil))
+	service.failUploadPart = true
+	require.EqualError(t, a.Upload(ctx, []byte("hello world"), nil), "upload part failed")
+
+	// The failed upload isn't written to the object, so that it can be retried.
+	service.failUploadPart = false
+	require.NoError(t, a.Upload(ctx, []byte("hello world"), nil))
+	require.NoError(t, a.Shutdown(t.Context()))
+	assert.Equal(t, map[string]string{
+		"my-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_1.json": "hello world\nhello world\n",
+	}, service.objects)
+}
+
+func TestAggregatorPersistFailure(t *testing.T) {
+	service := newFakeObjectAPI()
+	client := newFailingClient()
+	a := newTestAggregator(t, service, client)
+	ctx := clock.Context(t.Context(), clock.NewMock(time.Date(2024, 1, 10, 10, 30, 40, 0, time.UTC)))
+
+	failed := errors.New("storage failed")
+	client.setFail(func([]*storage.Operation) error { return failed })
+	require.ErrorIs(t, a.Upload(ctx, []byte("hello"), nil), failed)
+	assert.Empty(t, a.objects)
+
+	client.setFail(nil)
+	require.NoError(t, a.Upload(ctx, []byte("hello world"), nil))
+	// The uploaded part isn't kept when the state fails to be persisted.
+	client.setFail(func([]*storage.Operation) error { return failed })
+	require.ErrorIs(t, a.Upload(ctx, []byte("hello world"), nil), failed)
+
+	// The failed upload isn't written to the object, so that it can be retried.
+	client.setFail(nil)
+	require.NoError(t, a.Upload(ctx, []byte("hello world"), nil))
+	require.NoError(t, a.Shutdown(t.Context()))
+	assert.Equal(t, map[string]string{
+		"my-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_2.json": "hello world\nhello world\n",
+	}, service.getObjects())
+}
+
+func TestAggregatorRemoveStateFailure(t *testing.T) {
+	service := newFakeObjectAPI()
+	client := newFailingClient()
+	a := newTestAggregator(t, service, client)
+	now := time.Date(2024, 1, 10, 10, 30, 40, 0, time.UTC)
+	ctx := clock.Context(t.Context(), clock.NewMock(now))
+
+	var deletes []string
+	client.setFail(func(ops []*storage.Operation) error {
+		for _, op := range ops {
+			if op.Type == storage.Delete {
+				return errors.New("storage failed")
+			}
+		}
+		return nil
+	})
+	// The object is completed, so the upload succeeds even though its state isn't removed.
+	require.NoError(t, a.Upload(ctx, []byte("hello world, this is a long line"), nil))
+	assert.Len(t, service.getObjects(), 1)
+	assert.Len(t, a.completed, 1)
+
+	client.setFail(func(ops []*storage.Operation) error {
+		for _, op := range ops {
+			if op.Type == storage.Delete {
+				deletes = append(deletes, op.Key)
+			}
+		}
+		return nil
+	})
+	a.completeExpired(ctx, now)
+	assert.Empty(t, a.completed)
+	assert.Equal(t, []string{objectKeyPrefix + "my-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_1.json"}, deletes)
+
+	require.NoError(t, a.Shutdown(t.Context()))
+	// Shutting down again doesn't panic.
+	require.NoError(t, a.Shutdown(t.Context()))
+}
+
+func TestAggregatorResume(t *testing.T) {
+	dir := t.TempDir()
+	id := component.MustNewID("awss3")
+	service := newFakeObjectAPI()
+	ctx := clock.Context(t.Context(), clock.NewMock(time.Date(2024, 1, 10, 10, 30, 40, 0, time.UTC)))
+
+	client := storagetest.NewFileBackedClient(component.KindExporter, id, "logs", dir)
+	a := newTestAggregator(t, service, client)
+	require.NoError(t, a.Upload(ctx, []byte("hello world"), nil))
+	require.NoError(t, a.Upload(ctx, []byte("hello world"), nil))
+	require.NoError(t, a.Upload(ctx, []byte("bye"), nil))
+	// Closing the client without shutting down the aggregator persists the
+	// state of the objects as if the collector had crashed.
+	require.NoError(t, client.Close(t.Context()))
+	assert.Empty(t, service.objects)
+
+	resumed := newTestAggregator(t, service, storagetest.NewFileBackedClient(component.KindExporter, id, "logs", dir))
+	require.Len(t, resumed.objects, 1)
+	require.NoError(t, resumed.Upload(ctx, []byte("again"), nil))
+	require.NoError(t, resumed.Shutdown(t.Context()))
+
+	assert.Equal(t, map[string]string{
+		"my-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_1.json": "hello world\nhello world\nbye\nagain\n",
+	}, service.objects)
+
+	// The state of the completed object is removed.
+	client = storagetest.NewFileBackedClient(component.KindExporter, id, "logs", dir)
+	value, err := client.Get(t.Context(), objectsKey)
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(value))
+	value, err = client.Get(t.Context(), objectKeyPrefix+"my-bucket/telemetry/year=2024/month=01/day=10/signal-data-noop_1.json_0")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	require.NoError(t, client.Close(t.Context()))
+}
@@ -60,7 +60,46 @@ func (pki *PartitionKeyBuilder) Build(ts time.Time, overridePrefix string) strin
 	return path.Join(pki.bucketKeyPrefix(ts, overridePrefix), pki.fileName())
 }
 
+// BuildPartition builds the key of a file of the given Hive-style partition,
+// which replaces the time partition built from the PartitionFormat.
+func (pki *PartitionKeyBuilder) BuildPartition(partition, overridePrefix string) string {
+	pathParts := append(pki.prefixParts(overridePrefix), partition)
+	return path.Join(strings.Join(pathParts, "/"), pki.fileName())
+}
+
+// contentEncoding returns the ContentEncoding of the files, if any.
+func (pki *PartitionKeyBuilder) contentEncoding() string {
+	// Only use ContentEncoding for non-archive formats
+	// Archive formats store files compressed permanently (like .tar.gz)
+	// while ContentEncoding is for HTTP transfer compression
+	if pki.Compression.IsCompressed() && !pki.IsCompressed {
+		return string(pki.Compression)
+	}
+	return ""
+}
+
+// keyPrefix returns the directory of the file, built from the given Hive-style
+// partition if any, or else from the time partition.
+func (pki *PartitionKeyBuilder) keyPrefix(ts time.Time, overridePrefix, partition string) string {
+	if partition != "" {
+		return strings.Join(append(pki.prefixParts(overridePrefix), partition), "/")
+	}
+	return pki.bucketKeyPrefix(ts, overridePrefix)
+}
+
 func (pki *PartitionKeyBuilder) bucketKeyPrefix(ts time.Time, overridePrefix string) string {
+	pathParts := pki.prefixParts(overridePrefix)
+
+	location := pki.PartitionTimeLocation
+	if location == nil {
+		location = time.Local
+	}
+	pathParts = append(pathParts, timefmt.Format(ts.In(location), pki.PartitionFormat))
+
+	return strings.Join(pathParts, "/")
+}
+
+func (pki *PartitionKeyBuilder) prefixParts(overridePrefix string) []string {
 	// Don't want to overwrite the actual value
 	prefix := pki.PartitionPrefix
 	// Only override when it's not empty string
@@ -78,13 +117,7 @@ func (pki *PartitionKeyBuilder) bucketKeyPrefix(ts time.Time, overridePrefix str
 		pathParts = append(pathParts, prefix)
 	}
 
-	location := pki.PartitionTimeLocation
-	if location == nil {
-		location = time.Local
-	}
-	pathParts = append(pathParts, timefmt.Format(ts.In(location), pki.PartitionFormat))
-
-	return strings.Join(pathParts, "/")
+	return pathParts
 }
 
 func (pki *PartitionKeyBuilder) fileName() string {
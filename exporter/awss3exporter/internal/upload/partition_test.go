@@ -240,6 +240,24 @@ func TestPartitionKeyInputsBucketPrefix(t *testing.T) {
 	}
 }
 
+func TestPartitionKeyBuildPartition(t *testing.T) {
+	t.Parallel()
+
+	builder := &PartitionKeyBuilder{
+		PartitionBasePrefix: "archive",
+		PartitionPrefix:     "logs",
+		PartitionFormat:     "year=%Y/month=%m/day=%d/hour=%H/minute=%M",
+		Metadata:            "logs",
+		FileFormat:          "json",
+		UniqueKeyFunc: func() string {
+			return "fixed"
+		},
+	}
+
+	assert.Equal(t, "archive/logs/tenant=a/dt=2024-01-24/logs_fixed.json", builder.BuildPartition("tenant=a/dt=2024-01-24", ""))
+	assert.Equal(t, "archive/host/tenant=a/dt=2024-01-24/logs_fixed.json", builder.BuildPartition("tenant=a/dt=2024-01-24", "host"))
+}
+
 func TestPartitionKeyInputsFilename(t *testing.T) {
 	t.Parallel()
 
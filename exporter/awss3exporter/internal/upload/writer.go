@@ -27,6 +27,9 @@ type ManagerOpt func(Manager)
 type UploadOptions struct {
 	OverrideBucket string
 	OverridePrefix string
+	// Partition is the Hive-style partition of the data, such as `tenant=a/dt=2024-01-01`.
+	// When set, it replaces the time partition built from the PartitionFormat.
+	Partition string
 }
 
 type s3manager struct {
@@ -60,33 +63,34 @@ func (sw *s3manager) Upload(ctx context.Context, data []byte, opts *UploadOption
 		return nil
 	}
 
-	content, err := sw.contentBuffer(data)
+	content, err := contentBuffer(sw.builder.Compression, data)
 	if err != nil {
 		return err
 	}
 
-	encoding := ""
-	// Only use ContentEncoding for non-archive formats
-	// Archive formats store files compressed permanently (like .tar.gz)
-	// while ContentEncoding is for HTTP transfer compression
-	if sw.builder.Compression.IsCompressed() && !sw.builder.IsCompressed {
-		encoding = string(sw.builder.Compression)
-	}
+	encoding := sw.builder.contentEncoding()
 
 	now := clock.Now(ctx)
 
 	overridePrefix := ""
 	overrideBucket := sw.bucket
+	partition := ""
 	if opts != nil {
 		overridePrefix = opts.OverridePrefix
 		if opts.OverrideBucket != "" {
 			overrideBucket = opts.OverrideBucket
 		}
+		partition = opts.Partition
+	}
+
+	key := sw.builder.Build(now, overridePrefix)
+	if partition != "" {
+		key = sw.builder.BuildPartition(partition, overridePrefix)
 	}
 
 	uploadInput := &transfermanager.UploadObjectInput{
 		Bucket:       aws.String(overrideBucket),
-		Key:          aws.String(sw.builder.Build(now, overridePrefix)),
+		Key:          aws.String(key),
 		Body:         content,
 		StorageClass: transfermanagertypes.StorageClass(sw.storageClass),
 		ACL:          transfermanagertypes.ObjectCannedACL(sw.acl),
@@ -101,8 +105,8 @@ func (sw *s3manager) Upload(ctx context.Context, data []byte, opts *UploadOption
 	return err
 }
 
-func (sw *s3manager) contentBuffer(raw []byte) (*bytes.Buffer, error) {
-	switch sw.builder.Compression {
+func contentBuffer(compression configcompression.Type, raw []byte) (*bytes.Buffer, error) {
+	switch compression {
 	case configcompression.TypeGzip:
 		content := bytes.NewBuffer(nil)
 
@@ -138,10 +142,11 @@ func (sw *s3manager) contentBuffer(raw []byte) (*bytes.Buffer, error) {
 
 func WithACL(acl s3types.ObjectCannedACL) func(Manager) {
 	return func(m Manager) {
-		s3m, ok := m.(*s3manager)
-		if !ok {
-			return
+		switch m := m.(type) {
+		case *s3manager:
+			m.acl = acl
+		case *Aggregator:
+			m.acl = acl
 		}
-		s3m.acl = acl
 	}
 }
@@ -288,6 +288,26 @@ func TestS3ManagerUpload(t *testing.T) {
 			storageClass: "STANDARD_IA",
 			uploadOpts:   &UploadOptions{OverrideBucket: "custom-bucket"},
 		},
+		{
+			name: "upload with hive partition",
+			handler: func(t *testing.T) http.Handler {
+				return http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+					_, _ = io.Copy(io.Discard, r.Body)
+					_ = r.Body.Close()
+
+					assert.Equal(
+						t,
+						"/my-bucket/telemetry/tenant=a/dt=2024-01-10/signal-data-noop_random.metrics",
+						r.URL.Path,
+						"Must replace the time partition with the hive partition",
+					)
+				})
+			},
+			compression: configcompression.Type(""),
+			data:        []byte("hello world"),
+			errVal:      "",
+			uploadOpts:  &UploadOptions{Partition: "tenant=a/dt=2024-01-10"},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awss3exporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter"
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/itchyny/timefmt-go"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter/internal/upload"
+)
+
+// hiveDefaultPartition is the value Hive uses for the partitions of missing values.
+const hiveDefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+// partitioner splits the records of a batch across the Hive-style partitions
+// built from their attributes and time, keeping the order of the partitions.
+type partitioner struct {
+	keys     []PartitionKeyConfig
+	location *time.Location
+	// uploadOpts returns the bucket and prefix overrides of a resource.
+	uploadOpts func(pcommon.Resource) *upload.UploadOptions
+}
+
+type logsPartition struct {
+	opts upload.UploadOptions
+	logs plog.Logs
+}
+
+type tracesPartition struct {
+	opts   upload.UploadOptions
+	traces ptrace.Traces
+}
+
+type metricsPartition struct {
+	opts    upload.UploadOptions
+	metrics pmetric.Metrics
+}
+
+// path returns the partition of a record, such as `tenant=a/dt=2024-01-01`.
+func (p *partitioner) path(resource, scope, record pcommon.Map, ts time.Time) string {
+	var sb strings.Builder
+	for i, key := range p.keys {
+		if i > 0 {
+			sb.WriteByte('/')
+		}
+		var value string
+		switch key.Source {
+		case PartitionKeySourceResource:
+			value = attributeValue(resource, key.Attribute)
+		case PartitionKeySourceScope:
+			value = attributeValue(scope, key.Attribute)
+		case PartitionKeySourceRecord:
+			value = attributeValue(record, key.Attribute)
+		case PartitionKeySourceTime:
+			value = timefmt.Format(ts.In(p.location), key.TimeFormat)
+		}
+		if value == "" {
+			value = key.Default
+		}
+		if value == "" {
+			value = hiveDefaultPartition
+		}
+		sb.WriteString(key.Name)
+		sb.WriteByte('=')
+		sb.WriteString(url.PathEscape(value))
+	}
+	return sb.String()
+}
+
+func attributeValue(attrs pcommon.Map, name string) string {
+	if value, ok := attrs.Get(name); ok {
+		return value.AsString()
+	}
+	return ""
+}
+
+// recordTime returns ts, or now when ts isn't set.
+func recordTime(ts pcommon.Timestamp, now time.Time) time.Time {
+	if ts == 0 {
+		return now
+	}
+	return ts.AsTime()
+}
+
+func (p *partitioner) partitionLogs(ld plog.Logs, now time.Time) []logsPartition {
+	var partitions []logsPartition
+	indexes := map[upload.UploadOptions]int{}
+	for _, rl := range ld.ResourceLogs().All() {
+		opts := *p.uploadOpts(rl.Resource())
+		resources := map[int]plog.ResourceLogs{}
+		for _, sl := range rl.ScopeLogs().All() {
+			scopes := map[int]plog.ScopeLogs{}
+			for _, lr := range sl.LogRecords().All() {
+				ts := lr.Timestamp()
+				if ts == 0 {
+					ts = lr.ObservedTimestamp()
+				}
+				opts.Partition = p.path(rl.Resource().Attributes(), sl.Scope().Attributes(), lr.Attributes(), recordTime(ts, now))
+				n, ok := indexes[opts]
+				if !ok {
+					n = len(partitions)
+					indexes[opts] = n
+					partitions = append(partitions, logsPartition{opts: opts, logs: plog.NewLogs()})
+				}
+				dstScope, ok := scopes[n]
+				if !ok {
+					dstResource, ok := resources[n]
+					if !ok {
+						dstResource = partitions[n].logs.ResourceLogs().AppendEmpty()
+						rl.Resource().CopyTo(dstResource.Resource())
+						dstResource.SetSchemaUrl(rl.SchemaUrl())
+						resources[n] = dstResource
+					}
+					dstScope = dstResource.ScopeLogs().AppendEmpty()
+					sl.Scope().CopyTo(dstScope.Scope())
+					dstScope.SetSchemaUrl(sl.SchemaUrl())
+					scopes[n] = dstScope
+				}
+				lr.CopyTo(dstScope.LogRecords().AppendEmpty())
+			}
+		}
+	}
+	return partitions
+}
+
+func (p *partitioner) partitionTraces(td ptrace.Traces, now time.Time) []tracesPartition {
+	var partitions []tracesPartition
+	indexes := map[upload.UploadOptions]int{}
+	for _, rs := range td.ResourceSpans().All() {
+		opts := *p.uploadOpts(rs.Resource())
+		resources := map[int]ptrace.ResourceSpans{}
+		for _, ss := range rs.ScopeSpans().All() {
+			scopes := map[int]ptrace.ScopeSpans{}
+			for _, span := range ss.Spans().All() {
+				opts.Partition = p.path(rs.Resource().Attributes(), ss.Scope().Attributes(), span.Attributes(), recordTime(span.StartTimestamp(), now))
+				n, ok := indexes[opts]
+				if !ok {
+					n = len(partitions)
+					indexes[opts] = n
+					partitions = append(partitions, tracesPartition{opts: opts, traces: ptrace.NewTraces()})
+				}
+				dstScope, ok := scopes[n]
+				if !ok {
+					dstResource, ok := resources[n]
+					if !ok {
+						dstResource = partitions[n].traces.ResourceSpans().AppendEmpty()
+						rs.Resource().CopyTo(dstResource.Resource())
+						dstResource.SetSchemaUrl(rs.SchemaUrl())
+						resources[n] = dstResource
+					}
+					dstScope = dstResource.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(dstScope.Scope())
+					dstScope.SetSchemaUrl(ss.SchemaUrl())
+					scopes[n] = dstScope
+				}
+				span.CopyTo(dstScope.Spans().AppendEmpty())
+			}
+		}
+	}
+	return partitions
+}
+
+// partitionMetrics splits the metrics by data point, so a metric can be
+// split across several partitions.
+func (p *partitioner) partitionMetrics(md pmetric.Metrics, now time.Time) []metricsPartition {
+	var partitions []metricsPartition
+	indexes := map[upload.UploadOptions]int{}
+	for _, rm := range md.ResourceMetrics().All() {
+		opts := *p.uploadOpts(rm.Resource())
+		resources := map[int]pmetric.ResourceMetrics{}
+		for _, sm := range rm.ScopeMetrics().All() {
+			scopes := map[int]pmetric.ScopeMetrics{}
+			for _, m := range sm.Metrics().All() {
+				metrics := map[int]pmetric.Metric{}
+				// target returns the metric of the partition of a data point,
+				// where the data point is copied.
+				target := func(attrs pcommon.Map, ts pcommon.Timestamp) pmetric.Metric {
+					opts.Partition = p.path(rm.Resource().Attributes(), sm.Scope().Attributes(), attrs, recordTime(ts, now))
+					n, ok := indexes[opts]
+					if !ok {
+						n = len(partitions)
+						indexes[opts] = n
+						partitions = append(partitions, metricsPartition{opts: opts, metrics: pmetric.NewMetrics()})
+					}
+					if dst, ok := metrics[n]; ok {
+						return dst
+					}
+					dstScope, ok := scopes[n]
+					if !ok {
+						dstResource, ok := resources[n]
+						if !ok {
+							dstResource = partitions[n].metrics.ResourceMetrics().AppendEmpty()
+							rm.Resource().CopyTo(dstResource.Resource())
+							dstResource.SetSchemaUrl(rm.SchemaUrl())
+							resources[n] = dstResource
+						}
+						dstScope = dstResource.ScopeMetrics().AppendEmpty()
+						sm.Scope().CopyTo(dstScope.Scope())
+						dstScope.SetSchemaUrl(sm.SchemaUrl())
+						scopes[n] = dstScope
+					}
+					dst := dstScope.Metrics().AppendEmpty()
+					copyMetricDescriptor(m, dst)
+					metrics[n] = dst
+					return dst
+				}
+
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					for _, dp := range m.Gauge().DataPoints().All() {
+						dp.CopyTo(target(dp.Attributes(), dp.Timestamp()).Gauge().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeSum:
+					for _, dp := range m.Sum().DataPoints().All() {
+						dp.CopyTo(target(dp.Attributes(), dp.Timestamp()).Sum().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeHistogram:
+					for _, dp := range m.Histogram().DataPoints().All() {
+						dp.CopyTo(target(dp.Attributes(), dp.Timestamp()).Histogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					for _, dp := range m.ExponentialHistogram().DataPoints().All() {
+						dp.CopyTo(target(dp.Attributes(), dp.Timestamp()).ExponentialHistogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeSummary:
+					for _, dp := range m.Summary().DataPoints().All() {
+						dp.CopyTo(target(dp.Attributes(), dp.Timestamp()).Summary().DataPoints().AppendEmpty())
+					}
+				}
+			}
+		}
+	}
+	return partitions
+}
+
+// copyMetricDescriptor copies everything but the data points of src to dst.
+func copyMetricDescriptor(src, dst pmetric.Metric) {
+	dst.SetName(src.Name())
+	dst.SetDescription(src.Description())
+	dst.SetUnit(src.Unit())
+	src.Metadata().CopyTo(dst.Metadata())
+	switch src.Type() {
+	case pmetric.MetricTypeGauge:
+		dst.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		sum := dst.SetEmptySum()
+		sum.SetAggregationTemporality(src.Sum().AggregationTemporality())
+		sum.SetIsMonotonic(src.Sum().IsMonotonic())
+	case pmetric.MetricTypeHistogram:
+		dst.SetEmptyHistogram().SetAggregationTemporality(src.Histogram().AggregationTemporality())
+	case pmetric.MetricTypeExponentialHistogram:
+		dst.SetEmptyExponentialHistogram().SetAggregationTemporality(src.ExponentialHistogram().AggregationTemporality())
+	case pmetric.MetricTypeSummary:
+		dst.SetEmptySummary()
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awss3exporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter/internal/upload"
+)
+
+var testPartitionKeys = []PartitionKeyConfig{
+	{Name: "tenant", Source: PartitionKeySourceResource, Attribute: "tenant.id"},
+	{Name: "level", Source: PartitionKeySourceRecord, Attribute: "level", Default: "none"},
+	{Name: "dt", Source: PartitionKeySourceTime, TimeFormat: "%Y-%m-%d"},
+}
+
+func newTestPartitioner(keys []PartitionKeyConfig) *partitioner {
+	e := &s3Exporter{config: createDefaultConfig().(*Config)}
+	return &partitioner{keys: keys, location: time.UTC, uploadOpts: e.getUploadOpts}
+}
+
+func TestPartitionerPath(t *testing.T) {
+	p := newTestPartitioner([]PartitionKeyConfig{
+		{Name: "tenant", Source: PartitionKeySourceResource, Attribute: "tenant.id"},
+		{Name: "lib", Source: PartitionKeySourceScope, Attribute: "library"},
+		{Name: "service", Source: PartitionKeySourceRecord, Attribute: "service.name"},
+		{Name: "hour", Source: PartitionKeySourceTime, TimeFormat: "%H"},
+	})
+	resource := pcommon.NewMap()
+	resource.PutStr("tenant.id", "a/b")
+	scope := pcommon.NewMap()
+	record := pcommon.NewMap()
+	record.PutInt("service.name", 42)
+	ts := time.Date(2024, 1, 24, 6, 40, 20, 0, time.UTC)
+
+	assert.Equal(t, "tenant=a%2Fb/lib=__HIVE_DEFAULT_PARTITION__/service=42/hour=06", p.path(resource, scope, record, ts))
+}
+
+func TestPartitionLogs(t *testing.T) {
+	ld := plog.NewLogs()
+	for _, tenant := range []string{"a", "b"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("tenant.id", tenant)
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName("scope")
+		for _, level := range []string{"info", "error", "info", ""} {
+			lr := sl.LogRecords().AppendEmpty()
+			lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Date(2024, 1, 24, 6, 40, 20, 0, time.UTC)))
+			if level != "" {
+				lr.Attributes().PutStr("level", level)
+			}
+		}
+	}
+	// Without timestamp, the time the batch is exported is used.
+	ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().AppendEmpty().Attributes().PutStr("level", "info")
+
+	now := time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC)
+	partitions := newTestPartitioner(testPartitionKeys).partitionLogs(ld, now)
+
+	var paths []string
+	counts := map[string]int{}
+	for _, partition := range partitions {
+		paths = append(paths, partition.opts.Partition)
+		counts[partition.opts.Partition] = partition.logs.LogRecordCount()
+		assert.Equal(t, 1, partition.logs.ResourceLogs().Len())
+		assert.Equal(t, "scope", partition.logs.ResourceLogs().At(0).ScopeLogs().At(0).Scope().Name())
+	}
+	assert.Equal(t, []string{
+		"tenant=a/level=info/dt=2024-01-24",
+		"tenant=a/level=error/dt=2024-01-24",
+		"tenant=a/level=none/dt=2024-01-24",
+		"tenant=a/level=info/dt=2024-01-25",
+		"tenant=b/level=info/dt=2024-01-24",
+		"tenant=b/level=error/dt=2024-01-24",
+		"tenant=b/level=none/dt=2024-01-24",
+	}, paths)
+	assert.Equal(t, 2, counts["tenant=a/level=info/dt=2024-01-24"])
+	assert.Equal(t, 1, counts["tenant=a/level=info/dt=2024-01-25"])
+}
+
+func TestPartitionTraces(t *testing.T) {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("tenant.id", "a")
+	ss := rs.ScopeSpans().AppendEmpty()
+	for _, level := range []string{"info", "error"} {
+		span := ss.Spans().AppendEmpty()
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Date(2024, 1, 24, 6, 40, 20, 0, time.UTC)))
+		span.Attributes().PutStr("level", level)
+	}
+
+	partitions := newTestPartitioner(testPartitionKeys).partitionTraces(td, time.Now())
+	require.Len(t, partitions, 2)
+	assert.Equal(t, "tenant=a/level=info/dt=2024-01-24", partitions[0].opts.Partition)
+	assert.Equal(t, "tenant=a/level=error/dt=2024-01-24", partitions[1].opts.Partition)
+	assert.Equal(t, 1, partitions[1].traces.SpanCount())
+}
+
+func TestPartitionMetrics(t *testing.T) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("tenant.id", "a")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("requests")
+	m.SetUnit("1")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	for _, level := range []string{"info", "error", "info"} {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Date(2024, 1, 24, 6, 40, 20, 0, time.UTC)))
+		dp.Attributes().PutStr("level", level)
+	}
+
+	partitions := newTestPartitioner(testPartitionKeys).partitionMetrics(md, time.Now())
+	require.Len(t, partitions, 2)
+	assert.Equal(t, "tenant=a/level=info/dt=2024-01-24", partitions[0].opts.Partition)
+	assert.Equal(t, 2, partitions[0].metrics.DataPointCount())
+	assert.Equal(t, "tenant=a/level=error/dt=2024-01-24", partitions[1].opts.Partition)
+
+	dst := partitions[1].metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "requests", dst.Name())
+	assert.Equal(t, "1", dst.Unit())
+	assert.True(t, dst.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, dst.Sum().AggregationTemporality())
+	assert.Equal(t, 1, dst.Sum().DataPoints().Len())
+}
+
+type partitionWriter struct {
+	failing    string
+	partitions []string
+}
+
+func (w *partitionWriter) Upload(_ context.Context, _ []byte, opts *upload.UploadOptions) error {
+	if opts.Partition == w.failing {
+		return errors.New("upload failed")
+	}
+	w.partitions = append(w.partitions, opts.Partition)
+	return nil
+}
+
+func TestConsumePartitionedLogs(t *testing.T) {
+	marshaler, _ := newMarshaler("otlp_json", zap.NewNop())
+	writer := &partitionWriter{failing: "tenant=a/level=error/dt=2024-01-24"}
+	exporter := &s3Exporter{
+		config:      createDefaultConfig().(*Config),
+		uploader:    writer,
+		logger:      zap.NewNop(),
+		marshaler:   marshaler,
+		partitioner: newTestPartitioner(testPartitionKeys),
+	}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("tenant.id", "a")
+	sl := rl.ScopeLogs().AppendEmpty()
+	for _, level := range []string{"info", "error"} {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Date(2024, 1, 24, 6, 40, 20, 0, time.UTC)))
+		lr.Attributes().PutStr("level", level)
+	}
+
+	err := exporter.ConsumeLogs(t.Context(), ld)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, []string{"tenant=a/level=info/dt=2024-01-24"}, writer.partitions)
+
+	// Only the logs of the failed partition are returned.
+	var logsErr consumererror.Logs
+	require.ErrorAs(t, err, &logsErr)
+	require.Equal(t, 1, logsErr.Data().LogRecordCount())
+	level, _ := logsErr.Data().ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().Get("level")
+	assert.Equal(t, "error", level.Str())
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awss3exporter/internal/upload"
 )
@@ -26,6 +28,63 @@ func newUploadManager(
 	format string,
 	isCompressed bool,
 ) (upload.Manager, error) {
+	service, err := newS3Client(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	builder, err := newPartitionKeyBuilder(conf, metadata, format, isCompressed)
+	if err != nil {
+		return nil, err
+	}
+
+	return upload.NewS3Manager(
+		conf.S3Uploader.S3Bucket,
+		builder,
+		service,
+		s3types.StorageClass(conf.S3Uploader.StorageClass),
+		managerOpts(conf)...,
+	), nil
+}
+
+// newAggregator creates an upload.Aggregator, which writes its state to the given storage client.
+func newAggregator(
+	ctx context.Context,
+	conf *Config,
+	metadata string,
+	format string,
+	isCompressed bool,
+	client storage.Client,
+	logger *zap.Logger,
+) (*upload.Aggregator, error) {
+	service, err := newS3Client(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	builder, err := newPartitionKeyBuilder(conf, metadata, format, isCompressed)
+	if err != nil {
+		return nil, err
+	}
+
+	return upload.NewAggregator(
+		conf.S3Uploader.S3Bucket,
+		builder,
+		service,
+		s3types.StorageClass(conf.S3Uploader.StorageClass),
+		upload.AggregatorConfig{
+			MaxSize:       conf.Aggregation.maxSize(),
+			MaxAge:        conf.Aggregation.maxAge(),
+			PartSize:      conf.Aggregation.partSize(),
+			LineDelimited: format == "json",
+		},
+		client,
+		logger,
+		managerOpts(conf)...,
+	), nil
+}
+
+func newS3Client(ctx context.Context, conf *Config) (*s3.Client, error) {
 	configOpts := []func(*config.LoadOptions) error{}
 
 	if region := conf.S3Uploader.Region; region != "" {
@@ -75,12 +134,19 @@ func newUploadManager(
 		})
 	}
 
+	return s3.NewFromConfig(cfg, s3Opts...), nil
+}
+
+func managerOpts(conf *Config) []upload.ManagerOpt {
 	var managerOpts []upload.ManagerOpt
 	if conf.S3Uploader.ACL != "" {
 		managerOpts = append(managerOpts,
 			upload.WithACL(s3types.ObjectCannedACL(conf.S3Uploader.ACL)))
 	}
+	return managerOpts
+}
 
+func newPartitionKeyBuilder(conf *Config, metadata, format string, isCompressed bool) (*upload.PartitionKeyBuilder, error) {
 	var uniqueKeyFunc func() string
 	switch conf.S3Uploader.UniqueKeyFuncName {
 	case "uuidv7":
@@ -89,32 +155,33 @@ func newUploadManager(
 		uniqueKeyFunc = nil
 	}
 
-	var s3PartitionTimeLocation *time.Location
-	if conf.S3Uploader.S3PartitionTimezone != "" {
-		s3PartitionTimeLocation, err = time.LoadLocation(conf.S3Uploader.S3PartitionTimezone)
-		if err != nil {
-			return nil, fmt.Errorf("invalid S3 partition timezone: %w", err)
-		}
-	} else {
-		s3PartitionTimeLocation = time.Local
+	s3PartitionTimeLocation, err := partitionTimeLocation(conf.S3Uploader.S3PartitionTimezone)
+	if err != nil {
+		return nil, err
 	}
 
-	return upload.NewS3Manager(
-		conf.S3Uploader.S3Bucket,
-		&upload.PartitionKeyBuilder{
-			PartitionBasePrefix:   conf.S3Uploader.S3BasePrefix,
-			PartitionPrefix:       conf.S3Uploader.S3Prefix,
-			PartitionFormat:       conf.S3Uploader.S3PartitionFormat,
-			PartitionTimeLocation: s3PartitionTimeLocation,
-			FilePrefix:            conf.S3Uploader.FilePrefix,
-			FileFormat:            format,
-			Metadata:              metadata,
-			Compression:           conf.S3Uploader.Compression,
-			UniqueKeyFunc:         uniqueKeyFunc,
-			IsCompressed:          isCompressed,
-		},
-		s3.NewFromConfig(cfg, s3Opts...),
-		s3types.StorageClass(conf.S3Uploader.StorageClass),
-		managerOpts...,
-	), nil
+	return &upload.PartitionKeyBuilder{
+		PartitionBasePrefix:   conf.S3Uploader.S3BasePrefix,
+		PartitionPrefix:       conf.S3Uploader.S3Prefix,
+		PartitionFormat:       conf.S3Uploader.S3PartitionFormat,
+		PartitionTimeLocation: s3PartitionTimeLocation,
+		FilePrefix:            conf.S3Uploader.FilePrefix,
+		FileFormat:            format,
+		Metadata:              metadata,
+		Compression:           conf.S3Uploader.Compression,
+		UniqueKeyFunc:         uniqueKeyFunc,
+		IsCompressed:          isCompressed,
+	}, nil
+}
+
+// partitionTimeLocation returns the location of the given timezone, or the Local one when it's empty.
+func partitionTimeLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 partition timezone: %w", err)
+	}
+	return location, nil
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 // Config defines configuration for clickhouse exporter.
@@ -69,6 +70,20 @@ type Config struct {
 	AsyncInsert bool `mapstructure:"async_insert"`
 	// MetricsTables defines the table names for metric types.
 	MetricsTables MetricTablesConfig `mapstructure:"metrics_tables"`
+	// Migrations configures the migrations which bring existing tables up to date with the layout of the exporter.
+	Migrations MigrationsConfig `mapstructure:"migrations"`
+	// DeadLetter configures the storage extension where the data which is rejected by
+	// ClickHouse is written, along with the reason of the rejection.
+	DeadLetter deadletter.Config `mapstructure:"dead_letter"`
+}
+
+// MigrationsConfig defines how the schema migrations are handled.
+type MigrationsConfig struct {
+	// Mode is one of `apply` (default), which applies the pending migrations on start when CreateSchema is true,
+	// `dry_run`, which only logs them, or `disabled`.
+	Mode string `mapstructure:"mode"`
+	// TableName is the table recording the applied migrations. default is `otel_schema_migrations`.
+	TableName string `mapstructure:"table_name"`
 }
 
 type MetricTablesConfig struct {
@@ -99,6 +114,10 @@ const (
 	defaultSummarySuffix      = "_summary"
 	defaultHistogramSuffix    = "_histogram"
 	defaultExpHistogramSuffix = "_exponential_histogram"
+
+	migrationModeApply    = "apply"
+	migrationModeDryRun   = "dry_run"
+	migrationModeDisabled = "disabled"
 )
 
 var (
@@ -127,6 +146,11 @@ func createDefaultConfig() component.Config {
 			Histogram:            metrics.MetricTypeConfig{Name: defaultMetricTableName + defaultHistogramSuffix},
 			ExponentialHistogram: metrics.MetricTypeConfig{Name: defaultMetricTableName + defaultExpHistogramSuffix},
 		},
+		Migrations: MigrationsConfig{
+			Mode:      migrationModeApply,
+			TableName: "otel_schema_migrations",
+		},
+		DeadLetter: deadletter.NewDefaultConfig(),
 	}
 }
 
@@ -143,6 +167,16 @@ func (cfg *Config) Validate() (err error) {
 
 	cfg.buildMetricTableNames()
 
+	switch cfg.Migrations.Mode {
+	case migrationModeApply, migrationModeDryRun, migrationModeDisabled:
+	default:
+		err = errors.Join(err, fmt.Errorf("migrations::mode must be one of %q, %q or %q, got %q",
+			migrationModeApply, migrationModeDryRun, migrationModeDisabled, cfg.Migrations.Mode))
+	}
+	if cfg.Migrations.Mode != migrationModeDisabled && cfg.Migrations.TableName == "" {
+		err = errors.Join(err, errors.New("migrations::table_name must be specified"))
+	}
+
 	// Validate DSN with clickhouse driver.
 	// Last chance to catch invalid config.
 	if _, e := clickhouse.ParseDSN(dsn); e != nil {
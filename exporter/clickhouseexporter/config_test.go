@@ -25,6 +25,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 const defaultEndpoint = "clickhouse://127.0.0.1:9000"
@@ -39,6 +40,7 @@ func TestLoadConfig(t *testing.T) {
 	defaultCfg.(*Config).Endpoint = defaultEndpoint
 
 	storageID := component.MustNewIDWithName("file_storage", "clickhouse")
+	deadLetterStorageID := component.MustNewIDWithName("file_storage", "dead_letter")
 
 	tests := []struct {
 		id       component.ID
@@ -78,6 +80,14 @@ func TestLoadConfig(t *testing.T) {
 					Histogram:            metrics.MetricTypeConfig{Name: "otel_metrics_custom_histogram"},
 					ExponentialHistogram: metrics.MetricTypeConfig{Name: "otel_metrics_custom_exp_histogram"},
 				},
+				Migrations: MigrationsConfig{
+					Mode:      migrationModeDryRun,
+					TableName: "otel_migrations",
+				},
+				DeadLetter: deadletter.Config{
+					Storage:  &deadLetterStorageID,
+					MaxItems: 1000,
+				},
 				ConnectionParams: map[string]string{},
 				QueueSettings: configoptional.Some(func() exporterhelper.QueueBatchConfig {
 					queue := exporterhelper.NewDefaultQueueConfig()
@@ -787,3 +797,18 @@ func TestBuildClickHouseOptions_WithCAFileOnly(t *testing.T) {
 	// No panic, but options may be nil since TLS setup failed early.
 	require.Nil(t, opt, "expected nil options when TLS setup fails cleanly")
 }
+
+func TestValidateMigrations(t *testing.T) {
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.Endpoint = defaultEndpoint
+		cfg.Migrations.Mode = "auto"
+	})
+	assert.ErrorContains(t, cfg.Validate(), `migrations::mode must be one of "apply", "dry_run" or "disabled", got "auto"`)
+
+	cfg.Migrations.Mode = migrationModeDryRun
+	cfg.Migrations.TableName = ""
+	assert.ErrorContains(t, cfg.Validate(), "migrations::table_name must be specified")
+
+	cfg.Migrations.Mode = migrationModeDisabled
+	assert.NoError(t, cfg.Validate())
+}
@@ -52,10 +52,11 @@ func (e *logsExporter) start(ctx context.Context, _ component.Host) error {
 		if createDBErr := internal.CreateDatabase(ctx, e.db, e.cfg.database(), e.cfg.clusterString()); createDBErr != nil {
 			return createDBErr
 		}
+	}
 
-		if createTableErr := createLogsTable(ctx, e.cfg, e.db); createTableErr != nil {
-			return createTableErr
-		}
+	tables := map[string][]internal.Migration{e.cfg.LogsTableName: logsMigrations}
+	if createTableErr := createAndMigrateTables(ctx, e.cfg, e.db, e.logger, tables, createLogsTable); createTableErr != nil {
+		return createTableErr
 	}
 
 	err = e.detectSchemaFeatures(ctx)
@@ -59,10 +59,11 @@ func (e *logsJSONExporter) start(ctx context.Context, _ component.Host) error {
 		if createDBErr := internal.CreateDatabase(ctx, e.db, e.cfg.database(), e.cfg.clusterString()); createDBErr != nil {
 			return createDBErr
 		}
+	}
 
-		if createTableErr := createLogsJSONTable(ctx, e.cfg, e.db); createTableErr != nil {
-			return createTableErr
-		}
+	tables := map[string][]internal.Migration{e.cfg.LogsTableName: logsJSONMigrations}
+	if createTableErr := createAndMigrateTables(ctx, e.cfg, e.db, e.logger, tables, createLogsJSONTable); createTableErr != nil {
+		return createTableErr
 	}
 
 	err = e.detectSchemaFeatures(ctx)
@@ -48,20 +48,21 @@ func (e *metricsExporter) start(ctx context.Context, _ component.Host) error {
 	}
 
 	if e.cfg.shouldCreateSchema() {
-		database := e.cfg.database()
-		clusterStr := e.cfg.clusterString()
-		if err := internal.CreateDatabase(ctx, e.db, database, clusterStr); err != nil {
+		if err := internal.CreateDatabase(ctx, e.db, e.cfg.database(), e.cfg.clusterString()); err != nil {
 			return err
 		}
+	}
 
-		ttlExpr := internal.GenerateTTLExpr(e.cfg.TTL, "toDateTime(TimeUnix)")
-		err := metrics.NewMetricsTable(ctx, e.tablesConfig, database, clusterStr, e.cfg.tableEngineString(), ttlExpr, e.db)
-		if err != nil {
-			return err
-		}
+	tables := make(map[string][]internal.Migration, len(e.tablesConfig))
+	for metricType, tableConfig := range e.tablesConfig {
+		tables[tableConfig.Name] = metricsMigrations[metricType]
 	}
+	return createAndMigrateTables(ctx, e.cfg, e.db, e.logger, tables, e.createMetricsTables)
+}
 
-	return nil
+func (e *metricsExporter) createMetricsTables(ctx context.Context, cfg *Config, db driver.Conn) error {
+	ttlExpr := internal.GenerateTTLExpr(cfg.TTL, "toDateTime(TimeUnix)")
+	return metrics.NewMetricsTable(ctx, e.tablesConfig, cfg.database(), cfg.clusterString(), cfg.tableEngineString(), ttlExpr, db)
 }
 
 func generateMetricTablesConfigMapper(cfg *Config) metrics.MetricTablesConfigMapper {
@@ -50,13 +50,10 @@ func (e *tracesExporter) start(ctx context.Context, _ component.Host) error {
 		if err := internal.CreateDatabase(ctx, e.db, e.cfg.database(), e.cfg.clusterString()); err != nil {
 			return err
 		}
-
-		if err := createTraceTables(ctx, e.cfg, e.db); err != nil {
-			return err
-		}
 	}
 
-	return nil
+	tables := map[string][]internal.Migration{e.cfg.TracesTableName: tracesMigrations}
+	return createAndMigrateTables(ctx, e.cfg, e.db, e.logger, tables, createTraceTables)
 }
 
 func (e *tracesExporter) shutdown(_ context.Context) error {
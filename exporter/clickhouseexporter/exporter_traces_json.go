@@ -58,10 +58,11 @@ func (e *tracesJSONExporter) start(ctx context.Context, _ component.Host) error
 		if createDBErr := internal.CreateDatabase(ctx, e.db, e.cfg.database(), e.cfg.clusterString()); createDBErr != nil {
 			return createDBErr
 		}
+	}
 
-		if createTableErr := createTraceJSONTables(ctx, e.cfg, e.db); createTableErr != nil {
-			return createTableErr
-		}
+	tables := map[string][]internal.Migration{e.cfg.TracesTableName: tracesJSONMigrations}
+	if createTableErr := createAndMigrateTables(ctx, e.cfg, e.db, e.logger, tables, createTraceJSONTables); createTableErr != nil {
+		return createTableErr
 	}
 
 	err = e.detectSchemaFeatures(ctx)
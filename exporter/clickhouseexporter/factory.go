@@ -7,13 +7,20 @@ package clickhouseexporter // import "github.com/open-telemetry/opentelemetry-co
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 var featureGateJSON = featuregate.GlobalRegistry().MustRegister("clickhouse.json", featuregate.StageAlpha)
@@ -43,14 +50,15 @@ func createLogsExporter(
 	} else {
 		exp = newLogsExporter(set.Logger, c)
 	}
+	deadLetter := deadletter.NewWriter(c.DeadLetter, set.ID, set.Logger)
 
 	return exporterhelper.NewLogs(
 		ctx,
 		set,
 		cfg,
-		exp.pushLogsData,
-		exporterhelper.WithStart(exp.start),
-		exporterhelper.WithShutdown(exp.shutdown),
+		deadLetter.PushLogs(rejectLogs(c, exp.pushLogsData)),
+		exporterhelper.WithStart(startWithDeadLetter(deadLetter, exp.start)),
+		exporterhelper.WithShutdown(shutdownWithDeadLetter(deadLetter, exp.shutdown)),
 		exporterhelper.WithTimeout(c.TimeoutSettings),
 		exporterhelper.WithQueue(c.QueueSettings),
 		exporterhelper.WithRetry(c.BackOffConfig),
@@ -71,14 +79,15 @@ func createTracesExporter(
 	} else {
 		exp = newTracesExporter(set.Logger, c)
 	}
+	deadLetter := deadletter.NewWriter(c.DeadLetter, set.ID, set.Logger)
 
 	return exporterhelper.NewTraces(
 		ctx,
 		set,
 		cfg,
-		exp.pushTraceData,
-		exporterhelper.WithStart(exp.start),
-		exporterhelper.WithShutdown(exp.shutdown),
+		deadLetter.PushTraces(rejectTraces(c, exp.pushTraceData)),
+		exporterhelper.WithStart(startWithDeadLetter(deadLetter, exp.start)),
+		exporterhelper.WithShutdown(shutdownWithDeadLetter(deadLetter, exp.shutdown)),
 		exporterhelper.WithTimeout(c.TimeoutSettings),
 		exporterhelper.WithQueue(c.QueueSettings),
 		exporterhelper.WithRetry(c.BackOffConfig),
@@ -93,16 +102,76 @@ func createMetricExporter(
 	c := cfg.(*Config)
 	c.collectorVersion = set.BuildInfo.Version
 	exp := newMetricsExporter(set.Logger, c)
+	deadLetter := deadletter.NewWriter(c.DeadLetter, set.ID, set.Logger)
 
 	return exporterhelper.NewMetrics(
 		ctx,
 		set,
 		cfg,
-		exp.pushMetricsData,
-		exporterhelper.WithStart(exp.start),
-		exporterhelper.WithShutdown(exp.shutdown),
+		deadLetter.PushMetrics(rejectMetrics(c, exp.pushMetricsData)),
+		exporterhelper.WithStart(startWithDeadLetter(deadLetter, exp.start)),
+		exporterhelper.WithShutdown(shutdownWithDeadLetter(deadLetter, exp.shutdown)),
 		exporterhelper.WithTimeout(c.TimeoutSettings),
 		exporterhelper.WithQueue(c.QueueSettings),
 		exporterhelper.WithRetry(c.BackOffConfig),
 	)
 }
+
+// rejectLogs marks the errors caused by the logs themselves as permanent, so that the
+// insert isn't retried and the logs are written to the dead-letter storage. Without
+// dead-letter storage, the inserts are retried as usual.
+func rejectLogs(cfg *Config, push func(context.Context, plog.Logs) error) func(context.Context, plog.Logs) error {
+	if cfg.DeadLetter.Storage == nil {
+		return push
+	}
+	return func(ctx context.Context, ld plog.Logs) error {
+		err := push(ctx, ld)
+		if internal.IsRejectedData(err) {
+			return consumererror.NewLogs(consumererror.NewPermanent(err), ld)
+		}
+		return err
+	}
+}
+
+// rejectTraces is the equivalent of rejectLogs for traces.
+func rejectTraces(cfg *Config, push func(context.Context, ptrace.Traces) error) func(context.Context, ptrace.Traces) error {
+	if cfg.DeadLetter.Storage == nil {
+		return push
+	}
+	return func(ctx context.Context, td ptrace.Traces) error {
+		err := push(ctx, td)
+		if internal.IsRejectedData(err) {
+			return consumererror.NewTraces(consumererror.NewPermanent(err), td)
+		}
+		return err
+	}
+}
+
+// rejectMetrics is the equivalent of rejectLogs for metrics.
+func rejectMetrics(cfg *Config, push func(context.Context, pmetric.Metrics) error) func(context.Context, pmetric.Metrics) error {
+	if cfg.DeadLetter.Storage == nil {
+		return push
+	}
+	return func(ctx context.Context, md pmetric.Metrics) error {
+		err := push(ctx, md)
+		if internal.IsRejectedData(err) {
+			return consumererror.NewMetrics(consumererror.NewPermanent(err), md)
+		}
+		return err
+	}
+}
+
+func startWithDeadLetter(deadLetter *deadletter.Writer, start component.StartFunc) component.StartFunc {
+	return func(ctx context.Context, host component.Host) error {
+		if err := deadLetter.Start(ctx, host); err != nil {
+			return err
+		}
+		return start(ctx, host)
+	}
+}
+
+func shutdownWithDeadLetter(deadLetter *deadletter.Writer, shutdown component.ShutdownFunc) component.ShutdownFunc {
+	return func(ctx context.Context) error {
+		return errors.Join(shutdown(ctx), deadLetter.Shutdown(ctx))
+	}
+}
@@ -4,13 +4,20 @@
 package clickhouseexporter
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/plog"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal/metadata"
 )
@@ -92,3 +99,27 @@ func TestFactory_CreateMetrics(t *testing.T) {
 
 	require.NoError(t, exporter.Shutdown(t.Context()))
 }
+
+func TestRejectLogs(t *testing.T) {
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("rejected")
+
+	typeMismatch := fmt.Errorf("logs insert failed: %w", &clickhouse.Exception{Code: 53, Name: "DB::Exception"})
+	storageID := component.MustNewID("file_storage")
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.DeadLetter.Storage = &storageID
+	})
+	err := rejectLogs(cfg, func(context.Context, plog.Logs) error { return typeMismatch })(t.Context(), ld)
+	assert.True(t, consumererror.IsPermanent(err))
+	var logsErr consumererror.Logs
+	require.ErrorAs(t, err, &logsErr)
+	assert.Equal(t, ld, logsErr.Data())
+
+	connErr := errors.New("connection refused")
+	err = rejectLogs(cfg, func(context.Context, plog.Logs) error { return connErr })(t.Context(), ld)
+	assert.Equal(t, connErr, err)
+
+	// Without dead-letter storage, the inserts are retried.
+	err = rejectLogs(withDefaultConfig(), func(context.Context, plog.Logs) error { return typeMismatch })(t.Context(), ld)
+	assert.Equal(t, typeMismatch, err)
+}
@@ -5,6 +5,7 @@ package internal // import "github.com/open-telemetry/opentelemetry-collector-co
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,6 +15,29 @@ import (
 
 const DefaultDatabase = "default"
 
+// rejectedDataCodes are the codes of the ClickHouse exceptions caused by the inserted
+// data itself, which fail again however many times the insert is retried. The exceptions
+// caused by the schema, such as unknown tables or columns, are retried, since they're
+// fixed by migrating the schema.
+var rejectedDataCodes = map[int32]struct{}{
+	6:   {}, // CANNOT_PARSE_TEXT
+	41:  {}, // CANNOT_PARSE_DATETIME
+	53:  {}, // TYPE_MISMATCH
+	70:  {}, // CANNOT_CONVERT_TYPE
+	117: {}, // INCORRECT_DATA
+}
+
+// IsRejectedData reports whether err is a ClickHouse exception caused by the inserted data,
+// rather than by the connection or the load of the server.
+func IsRejectedData(err error) bool {
+	var exception *clickhouse.Exception
+	if !errors.As(err, &exception) {
+		return false
+	}
+	_, ok := rejectedDataCodes[exception.Code]
+	return ok
+}
+
 // DatabaseFromDSN returns the database specified in the DSN. Empty if unset.
 func DatabaseFromDSN(dsn string) (string, error) {
 	opt, err := clickhouse.ParseDSN(dsn)
@@ -4,9 +4,12 @@
 package internal
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -176,3 +179,43 @@ func TestGenerateTTLExpr(t *testing.T) {
 		})
 	}
 }
+
+func TestIsRejectedData(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "type mismatch",
+			err:      fmt.Errorf("logs insert failed: %w", &clickhouse.Exception{Code: 53, Name: "DB::Exception"}),
+			expected: true,
+		},
+		{
+			name:     "unknown table",
+			err:      &clickhouse.Exception{Code: 60, Name: "DB::Exception"},
+			expected: false,
+		},
+		{
+			name:     "too many parts",
+			err:      &clickhouse.Exception{Code: 252, Name: "DB::Exception"},
+			expected: false,
+		},
+		{
+			name:     "not an exception",
+			err:      errors.New("connection refused"),
+			expected: false,
+		},
+		{
+			name:     "nil",
+			err:      nil,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, IsRejectedData(tt.err))
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal/sqltemplates"
+)
+
+// Migration is a versioned change of the layout of a table.
+// Tables may have been changed by hand or by an earlier migration run which
+// wasn't recorded, so the statements must be idempotent, e.g. use
+// `ADD COLUMN IF NOT EXISTS`.
+type Migration struct {
+	Version     uint32
+	Description string
+	// Statements are formatted with the database, the table and the ON CLUSTER string.
+	Statements []string
+}
+
+// Migrator applies the migrations of tables, and records the applied versions in a table.
+type Migrator struct {
+	db       driver.Conn
+	database string
+	table    string
+	cluster  string
+	engine   string
+	dryRun   bool
+	logger   *zap.Logger
+}
+
+// NewMigrator creates a Migrator recording the applied migrations in the given table.
+// In dry run, the pending migrations are logged instead of being applied.
+func NewMigrator(db driver.Conn, database, table, clusterStr, engine string, dryRun bool, logger *zap.Logger) *Migrator {
+	return &Migrator{
+		db:       db,
+		database: database,
+		table:    table,
+		cluster:  clusterStr,
+		engine:   engine,
+		dryRun:   dryRun,
+		logger:   logger,
+	}
+}
+
+// Baseline records the migrations of a table which was just created with the latest layout as applied.
+func (m *Migrator) Baseline(ctx context.Context, table string, migrations []Migration) error {
+	if m.dryRun || len(migrations) == 0 {
+		return nil
+	}
+	if err := m.createTable(ctx); err != nil {
+		return err
+	}
+	applied, err := m.appliedVersions(ctx, table)
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if applied[migration.Version] {
+			continue
+		}
+		if err := m.record(ctx, table, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Migrate applies the migrations of a table which weren't applied yet, by order of version.
+func (m *Migrator) Migrate(ctx context.Context, table string, migrations []Migration) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+	if !m.dryRun {
+		if err := m.createTable(ctx); err != nil {
+			return err
+		}
+	}
+	applied, err := m.appliedVersions(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	pending := slices.SortedFunc(slices.Values(migrations), func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	for _, migration := range pending {
+		if applied[migration.Version] {
+			continue
+		}
+		statements := make([]string, 0, len(migration.Statements))
+		for _, statement := range migration.Statements {
+			statements = append(statements, fmt.Sprintf(statement, m.database, table, m.cluster))
+		}
+		if m.dryRun {
+			m.logger.Info("pending schema migration",
+				zap.String("table", table),
+				zap.Uint32("version", migration.Version),
+				zap.String("description", migration.Description),
+				zap.Strings("statements", statements),
+			)
+			continue
+		}
+
+		m.logger.Info("applying schema migration",
+			zap.String("table", table),
+			zap.Uint32("version", migration.Version),
+			zap.String("description", migration.Description),
+		)
+		for _, statement := range statements {
+			if err := m.db.Exec(ctx, statement); err != nil {
+				return fmt.Errorf("apply migration %d of table %q: %w", migration.Version, table, err)
+			}
+		}
+		if err := m.record(ctx, table, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) createTable(ctx context.Context) error {
+	ddl := fmt.Sprintf(sqltemplates.SchemaMigrationsCreateTable, m.database, m.table, m.cluster, m.engine)
+	if err := m.db.Exec(ctx, ddl); err != nil {
+		return fmt.Errorf("exec create schema migrations table sql: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, table string) (map[uint32]bool, error) {
+	applied := map[uint32]bool{}
+	if m.dryRun {
+		// The migrations table isn't created in dry run.
+		exists, err := TableExists(ctx, m.db, m.database, m.table)
+		if err != nil || !exists {
+			return applied, err
+		}
+	}
+
+	query := fmt.Sprintf("SELECT Version FROM %q.%q WHERE TableName = ?", m.database, m.table)
+	rows, err := m.db.Query(ctx, query, table)
+	if err != nil {
+		return nil, fmt.Errorf("get applied migrations: %w", err)
+	}
+	for rows.Next() {
+		var version uint32
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("get applied migrations rows close: %w", err)
+	}
+	return applied, nil
+}
+
+func (m *Migrator) record(ctx context.Context, table string, migration Migration) error {
+	insert := fmt.Sprintf("INSERT INTO %q.%q (TableName, Version, Description) VALUES (?, ?, ?)", m.database, m.table)
+	if err := m.db.Exec(ctx, insert, table, migration.Version, migration.Description); err != nil {
+		return fmt.Errorf("record migration %d of table %q: %w", migration.Version, table, err)
+	}
+	return nil
+}
+
+// TableExists returns whether a table exists.
+func TableExists(ctx context.Context, db driver.Conn, database, table string) (bool, error) {
+	var exists uint8
+	if err := db.QueryRow(ctx, fmt.Sprintf("EXISTS TABLE %q.%q", database, table)).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check table exists: %w", err)
+	}
+	return exists == 1, nil
+}
@@ -69,3 +69,8 @@ var MetricsSummaryCreateTable string
 
 //go:embed metrics_summary_insert.sql
 var MetricsSummaryInsert string
+
+// MIGRATIONS
+
+//go:embed schema_migrations_table.sql
+var SchemaMigrationsCreateTable string
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS %q.%q %s (
+    TableName String CODEC(ZSTD(1)),
+    Version UInt32,
+    Description String CODEC(ZSTD(1)),
+    AppliedAt DateTime64(3) DEFAULT now64(3)
+) ENGINE = %s
+ORDER BY (TableName, Version)
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter"
+
+import (
+	"context"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
+)
+
+// The migrations below bring the tables created by earlier versions of the exporter up to date
+// with the CREATE TABLE statements in internal/sqltemplates. When changing the layout of a table,
+// update its CREATE TABLE statement and append a migration with the next version to its list.
+// Released migrations must not be changed.
+
+var logsMigrations = []internal.Migration{
+	{
+		Version:     1,
+		Description: "add EventName column",
+		Statements: []string{
+			`ALTER TABLE %q.%q %s ADD COLUMN IF NOT EXISTS EventName String CODEC(ZSTD(1)) AFTER LogAttributes`,
+		},
+	},
+}
+
+var logsJSONMigrations = []internal.Migration{
+	{
+		Version:     1,
+		Description: "add attribute keys columns",
+		Statements: []string{
+			`ALTER TABLE %q.%q %s
+    ADD COLUMN IF NOT EXISTS ResourceAttributesKeys Array(LowCardinality(String)) CODEC(ZSTD(1)) AFTER ResourceAttributes,
+    ADD COLUMN IF NOT EXISTS ScopeAttributesKeys Array(LowCardinality(String)) CODEC(ZSTD(1)) AFTER ScopeAttributes,
+    ADD COLUMN IF NOT EXISTS LogAttributesKeys Array(LowCardinality(String)) CODEC(ZSTD(1)) AFTER LogAttributes`,
+			`ALTER TABLE %q.%q %s
+    ADD INDEX IF NOT EXISTS idx_res_attr_keys ResourceAttributesKeys TYPE bloom_filter(0.01) GRANULARITY 1,
+    ADD INDEX IF NOT EXISTS idx_scope_attr_keys ScopeAttributesKeys TYPE bloom_filter(0.01) GRANULARITY 1,
+    ADD INDEX IF NOT EXISTS idx_log_attr_keys LogAttributesKeys TYPE bloom_filter(0.01) GRANULARITY 1`,
+		},
+	},
+	{
+		Version:     2,
+		Description: "add EventName column",
+		Statements: []string{
+			`ALTER TABLE %q.%q %s ADD COLUMN IF NOT EXISTS EventName String CODEC(ZSTD(1)) AFTER LogAttributesKeys`,
+		},
+	},
+}
+
+var tracesMigrations []internal.Migration
+
+var tracesJSONMigrations = []internal.Migration{
+	{
+		Version:     1,
+		Description: "add attribute keys columns",
+		Statements: []string{
+			`ALTER TABLE %q.%q %s
+    ADD COLUMN IF NOT EXISTS ResourceAttributesKeys Array(LowCardinality(String)) CODEC(ZSTD(1)) AFTER ResourceAttributes,
+    ADD COLUMN IF NOT EXISTS SpanAttributesKeys Array(LowCardinality(String)) CODEC(ZSTD(1)) AFTER SpanAttributes`,
+			`ALTER TABLE %q.%q %s
+    ADD INDEX IF NOT EXISTS idx_res_attr_keys ResourceAttributesKeys TYPE bloom_filter(0.01) GRANULARITY 1,
+    ADD INDEX IF NOT EXISTS idx_span_attr_keys SpanAttributesKeys TYPE bloom_filter(0.01) GRANULARITY 1`,
+		},
+	},
+}
+
+var metricsMigrations = map[pmetric.MetricType][]internal.Migration{}
+
+// newMigrator returns the migrator of the tables, or nil if migrations are disabled.
+func (cfg *Config) newMigrator(db driver.Conn, logger *zap.Logger) *internal.Migrator {
+	if cfg.Migrations.Mode == migrationModeDisabled {
+		return nil
+	}
+	return internal.NewMigrator(db, cfg.database(), cfg.Migrations.TableName,
+		cfg.clusterString(), cfg.tableEngineString(),
+		cfg.Migrations.Mode == migrationModeDryRun, logger)
+}
+
+// createAndMigrateTables runs create if CreateSchema is true, then handles the migrations of the given tables:
+// the pending migrations of the tables which already existed are applied, or only logged in dry run, and the
+// migrations of the tables which were just created with the latest layout are recorded as applied.
+// Without CreateSchema, the tables are managed outside the exporter, so their migrations are only logged in dry run.
+func createAndMigrateTables(ctx context.Context, cfg *Config, db driver.Conn, logger *zap.Logger,
+	tables map[string][]internal.Migration, create func(context.Context, *Config, driver.Conn) error,
+) error {
+	createSchema := cfg.shouldCreateSchema()
+	migrator := cfg.newMigrator(db, logger)
+	if migrator == nil || (!createSchema && cfg.Migrations.Mode != migrationModeDryRun) {
+		if createSchema {
+			return create(ctx, cfg, db)
+		}
+		return nil
+	}
+
+	existing := make(map[string]bool, len(tables))
+	for table, migrations := range tables {
+		if len(migrations) == 0 {
+			continue
+		}
+		exists, err := internal.TableExists(ctx, db, cfg.database(), table)
+		if err != nil {
+			return err
+		}
+		existing[table] = exists
+	}
+
+	if createSchema {
+		if err := create(ctx, cfg, db); err != nil {
+			return err
+		}
+	}
+
+	for table, migrations := range tables {
+		var err error
+		switch {
+		case existing[table]:
+			err = migrator.Migrate(ctx, table, migrations)
+		case createSchema:
+			err = migrator.Baseline(ctx, table, migrations)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package clickhouseexporter
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
+)
+
+func testSchemaMigrations(t *testing.T, endpoint string) {
+	cfg := withTestExporterConfig(func(cfg *Config) {
+		cfg.LogsTableName = "otel_logs_migrations"
+		cfg.Migrations.TableName = "otel_schema_migrations_test"
+	})(endpoint)
+
+	// Create the table as an earlier version of the exporter did, without the EventName column.
+	opt, err := cfg.buildClickHouseOptions()
+	require.NoError(t, err)
+	db, err := internal.NewClickhouseClientFromOptions(opt)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, internal.CreateDatabase(t.Context(), db, cfg.database(), cfg.clusterString()))
+	for _, table := range []string{cfg.LogsTableName, cfg.Migrations.TableName} {
+		require.NoError(t, db.Exec(t.Context(), fmt.Sprintf("DROP TABLE IF EXISTS %q.%q", cfg.database(), table)))
+	}
+	require.NoError(t, createLogsTable(t.Context(), cfg, db))
+	require.NoError(t, db.Exec(t.Context(), fmt.Sprintf("ALTER TABLE %q.%q DROP COLUMN EventName", cfg.database(), cfg.LogsTableName)))
+
+	hasEventName := func() bool {
+		columns, err := internal.GetTableColumns(t.Context(), db, cfg.database(), cfg.LogsTableName)
+		require.NoError(t, err)
+		return slices.Contains(columns, logsColumnEventName)
+	}
+	appliedVersions := func() []uint32 {
+		var versions []uint32
+		exists, err := internal.TableExists(t.Context(), db, cfg.database(), cfg.Migrations.TableName)
+		require.NoError(t, err)
+		if !exists {
+			return nil
+		}
+		rows, err := db.Query(t.Context(), fmt.Sprintf("SELECT Version FROM %q.%q WHERE TableName = ? ORDER BY Version", cfg.database(), cfg.Migrations.TableName), cfg.LogsTableName)
+		require.NoError(t, err)
+		for rows.Next() {
+			var version uint32
+			require.NoError(t, rows.Scan(&version))
+			versions = append(versions, version)
+		}
+		require.NoError(t, rows.Close())
+		return versions
+	}
+
+	// In dry run, the pending migrations are only logged.
+	dryRunCfg := *cfg
+	dryRunCfg.Migrations.Mode = migrationModeDryRun
+	exporter := newLogsExporter(zaptest.NewLogger(t), &dryRunCfg)
+	require.NoError(t, exporter.start(t.Context(), nil))
+	require.NoError(t, exporter.shutdown(t.Context()))
+	require.False(t, hasEventName())
+	require.Empty(t, appliedVersions())
+
+	exporter = newLogsExporter(zaptest.NewLogger(t), cfg)
+	require.NoError(t, exporter.start(t.Context(), nil))
+	require.True(t, exporter.schemaFeatures.EventName)
+	require.NoError(t, exporter.shutdown(t.Context()))
+	require.True(t, hasEventName())
+	require.Equal(t, []uint32{1}, appliedVersions())
+
+	// Applied migrations are not run again.
+	exporter = newLogsExporter(zaptest.NewLogger(t), cfg)
+	require.NoError(t, exporter.start(t.Context(), nil))
+	require.NoError(t, exporter.shutdown(t.Context()))
+	require.Equal(t, []uint32{1}, appliedVersions())
+}
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -30,6 +31,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/logging"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 type bulkIndexer interface {
@@ -76,9 +78,11 @@ func newBulkIndexer(
 	config *Config,
 	requireDataStream bool,
 	tb *metadata.TelemetryBuilder,
+	deadLetter *deadletter.Writer,
+	flowControl *flowController,
 	logger *zap.Logger,
 ) bulkIndexer {
-	return newSyncBulkIndexer(client, config, requireDataStream, tb, logger)
+	return newSyncBulkIndexer(client, config, requireDataStream, tb, deadLetter, flowControl, logger)
 }
 
 func bulkIndexerConfig(client elastictransport.Interface, config *Config, requireDataStream bool, logger *zap.Logger) docappender.BulkIndexerConfig {
@@ -100,7 +104,7 @@ func bulkIndexerConfig(client elastictransport.Interface, config *Config, requir
 		RetryOnDocumentStatus:   config.Retry.RetryOnStatus,
 		RequireDataStream:       requireDataStream,
 		CompressionLevel:        compressionLevel,
-		PopulateFailedDocsInput: config.LogFailedDocsInput,
+		PopulateFailedDocsInput: config.LogFailedDocsInput || config.DeadLetter.Storage != nil,
 		IncludeSourceOnError:    bulkIndexerIncludeSourceOnError(config.IncludeSourceOnError),
 		QueryParams:             getQueryParamsFromEndpoint(config, logger),
 	}
@@ -142,6 +146,8 @@ func newSyncBulkIndexer(
 	config *Config,
 	requireDataStream bool,
 	tb *metadata.TelemetryBuilder,
+	deadLetter *deadletter.Writer,
+	flowControl *flowController,
 	logger *zap.Logger,
 ) *syncBulkIndexer {
 	var maxFlushBytes int64
@@ -158,6 +164,8 @@ func newSyncBulkIndexer(
 		retryConfig:           config.Retry,
 		metadataKeys:          config.MetadataKeys,
 		telemetryBuilder:      tb,
+		deadLetter:            deadLetter,
+		flowControl:           flowControl,
 		logger:                logger,
 		failedDocsInputLogger: newFailedDocsInputLogger(logger, config),
 	}
@@ -170,6 +178,8 @@ type syncBulkIndexer struct {
 	retryConfig           RetrySettings
 	metadataKeys          []string
 	telemetryBuilder      *metadata.TelemetryBuilder
+	deadLetter            *deadletter.Writer
+	flowControl           *flowController
 	logger                *zap.Logger
 	failedDocsInputLogger *zap.Logger
 }
@@ -185,7 +195,11 @@ func (s *syncBulkIndexer) StartSession(context.Context) bulkIndexerSession {
 		// always be valid at this point.
 		return errBulkIndexerSession{err: err}
 	}
-	return &syncBulkIndexerSession{s: s, bi: bi}
+	session := &syncBulkIndexerSession{s: s, bi: bi}
+	if s.flowControl != nil {
+		session.indices = make(map[string]struct{})
+	}
+	return session
 }
 
 // Close is a no-op.
@@ -196,6 +210,10 @@ func (*syncBulkIndexer) Close(context.Context) error {
 type syncBulkIndexerSession struct {
 	s  *syncBulkIndexer
 	bi *docappender.BulkIndexer
+
+	// indices holds the indices of the documents buffered by bi,
+	// when the flow control is enabled.
+	indices map[string]struct{}
 }
 
 // Add adds an item to the sync bulk indexer session.
@@ -208,10 +226,24 @@ func (s *syncBulkIndexerSession) Add(ctx context.Context, index, docID, pipeline
 		Action:           action,
 		Pipeline:         pipeline,
 	}
-	err := s.bi.Add(doc)
+	delayed, err := s.s.flowControl.wait(ctx, index)
+	if delayed {
+		s.s.telemetryBuilder.ElasticsearchDocsThrottled.Add(
+			ctx, 1,
+			metric.WithAttributeSet(attribute.NewSet(
+				getAttributesFromMetadataKeys(ctx, s.s.metadataKeys)...),
+			),
+		)
+	}
 	if err != nil {
 		return err
 	}
+	if err := s.bi.Add(doc); err != nil {
+		return err
+	}
+	if s.indices != nil {
+		s.indices[index] = struct{}{}
+	}
 	s.s.telemetryBuilder.ElasticsearchDocsReceived.Add(
 		ctx, 1,
 		metric.WithAttributeSet(attribute.NewSet(
@@ -234,21 +266,38 @@ func (*syncBulkIndexerSession) End() {
 
 // Flush flushes documents added to the bulk indexer session.
 func (s *syncBulkIndexerSession) Flush(ctx context.Context) error {
+	if s.bi.Items() == 0 {
+		return nil
+	}
 	var retryBackoff func(int) time.Duration
 	for attempts := 0; ; attempts++ {
-		if err := flushBulkIndexer(
+		if err := s.s.flowControl.acquire(ctx); err != nil {
+			return err
+		}
+		start := time.Now()
+		stat, err := flushBulkIndexer(
 			ctx,
 			s.bi,
 			s.s.flushTimeout,
 			s.s.metadataKeys,
 			s.s.telemetryBuilder,
+			s.s.deadLetter,
 			s.s.logger,
 			s.s.failedDocsInputLogger,
-		); err != nil {
+		)
+		s.s.flowControl.release(ctx, bulkRequestResult{
+			start:   start,
+			latency: time.Since(start),
+			stat:    stat,
+			err:     err,
+			indices: s.indices,
+		})
+		if err != nil {
 			return err
 		}
 		if s.bi.Items() == 0 {
 			// No documents in buffer waiting for per-document retry, exit retry loop.
+			clear(s.indices)
 			return nil
 		}
 		if retryBackoff == nil {
@@ -277,23 +326,26 @@ func flushBulkIndexer(
 	timeout time.Duration,
 	tMetaKeys []string,
 	tb *metadata.TelemetryBuilder,
+	deadLetter *deadletter.Writer,
 	logger *zap.Logger,
 	failedDocsInputLogger *zap.Logger,
-) error {
+) (docappender.BulkIndexerResponseStat, error) {
 	itemsCount := bi.Items()
 	if itemsCount == 0 {
-		return nil
+		return docappender.BulkIndexerResponseStat{}, nil
 	}
 	if timeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
+	defaultMetaAttrs := getAttributesFromMetadataKeys(ctx, tMetaKeys)
+	defaultAttrsSet := attribute.NewSet(defaultMetaAttrs...)
+	tb.ElasticsearchBulkRequestsInflight.Add(ctx, 1, metric.WithAttributeSet(defaultAttrsSet))
 	startTime := time.Now()
 	stat, err := bi.Flush(ctx)
 	latency := time.Since(startTime).Seconds()
-	defaultMetaAttrs := getAttributesFromMetadataKeys(ctx, tMetaKeys)
-	defaultAttrsSet := attribute.NewSet(defaultMetaAttrs...)
+	tb.ElasticsearchBulkRequestsInflight.Add(ctx, -1, metric.WithAttributeSet(defaultAttrsSet))
 	if flushed := bi.BytesFlushed(); flushed > 0 {
 		tb.ElasticsearchFlushedBytes.Add(ctx, int64(flushed), metric.WithAttributeSet(defaultAttrsSet))
 	}
@@ -361,6 +413,7 @@ func flushBulkIndexer(
 		tb.ElasticsearchBulkRequestsLatency.Record(ctx, latency, successAttrSet)
 	}
 
+	var deadLetterItems []deadletter.Item
 	for _, resp := range stat.FailedDocs {
 		// Collect telemetry
 		outcome := statusToOutcome(resp.Status)
@@ -399,6 +452,26 @@ func flushBulkIndexer(
 			fields = append(fields, zap.String("input", resp.Input))
 		}
 		failedDocsInputLogger.Debug("failed to index document; input may contain sensitive data", fields...)
+
+		if resp.Input != "" {
+			reason := resp.Error.Type
+			if resp.Error.Reason != "" {
+				reason += ": " + resp.Error.Reason
+			}
+			deadLetterItems = append(deadLetterItems, deadletter.Item{
+				Reason: reason,
+				Attributes: map[string]string{
+					"index":                     resp.Index,
+					"error.type":                resp.Error.Type,
+					"http.response.status_code": strconv.Itoa(resp.Status),
+				},
+				ContentType: deadletter.ContentTypeNDJSON,
+				Body:        []byte(resp.Input),
+			})
+		}
+	}
+	if err := deadLetter.Write(ctx, deadLetterItems...); err != nil {
+		logger.Error("failed to write documents to the dead-letter storage", zap.Error(err))
 	}
 	if stat.Indexed > 0 {
 		tb.ElasticsearchDocsProcessed.Add(
@@ -451,7 +524,7 @@ func flushBulkIndexer(
 			metric.WithAttributeSet(defaultAttrsSet),
 		)
 	}
-	return err
+	return stat, err
 }
 
 func getAttributesFromMetadataKeys(ctx context.Context, keys []string) []attribute.KeyValue {
@@ -501,6 +574,8 @@ type bulkIndexers struct {
 	profilingExecutables bulkIndexer // For profiling-executables
 
 	telemetryBuilder *metadata.TelemetryBuilder
+	deadLetter       *deadletter.Writer
+	flowControl      *flowController
 }
 
 func (b *bulkIndexers) start(
@@ -523,21 +598,22 @@ func (b *bulkIndexers) start(
 		return err
 	}
 
+	b.flowControl = newFlowController(cfg, b.telemetryBuilder, set.Logger)
 	for _, mode := range allowedMappingModes {
-		bi := newBulkIndexer(esClient, cfg, mode == MappingOTel, b.telemetryBuilder, set.Logger)
+		bi := newBulkIndexer(esClient, cfg, mode == MappingOTel, b.telemetryBuilder, b.deadLetter, b.flowControl, set.Logger)
 		b.modes[mode] = &wgTrackingBulkIndexer{bulkIndexer: bi, wg: &b.wg}
 	}
 
-	profilingEvents := newBulkIndexer(esClient, cfg, true, b.telemetryBuilder, set.Logger)
+	profilingEvents := newBulkIndexer(esClient, cfg, true, b.telemetryBuilder, b.deadLetter, b.flowControl, set.Logger)
 	b.profilingEvents = &wgTrackingBulkIndexer{bulkIndexer: profilingEvents, wg: &b.wg}
 
-	profilingStackTraces := newBulkIndexer(esClient, cfg, false, b.telemetryBuilder, set.Logger)
+	profilingStackTraces := newBulkIndexer(esClient, cfg, false, b.telemetryBuilder, b.deadLetter, b.flowControl, set.Logger)
 	b.profilingStackTraces = &wgTrackingBulkIndexer{bulkIndexer: profilingStackTraces, wg: &b.wg}
 
-	profilingStackFrames := newBulkIndexer(esClient, cfg, false, b.telemetryBuilder, set.Logger)
+	profilingStackFrames := newBulkIndexer(esClient, cfg, false, b.telemetryBuilder, b.deadLetter, b.flowControl, set.Logger)
 	b.profilingStackFrames = &wgTrackingBulkIndexer{bulkIndexer: profilingStackFrames, wg: &b.wg}
 
-	profilingExecutables := newBulkIndexer(esClient, cfg, false, b.telemetryBuilder, set.Logger)
+	profilingExecutables := newBulkIndexer(esClient, cfg, false, b.telemetryBuilder, b.deadLetter, b.flowControl, set.Logger)
 	b.profilingExecutables = &wgTrackingBulkIndexer{bulkIndexer: profilingExecutables, wg: &b.wg}
 	return nil
 }
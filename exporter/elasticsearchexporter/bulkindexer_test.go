@@ -4,6 +4,8 @@
 package elasticsearchexporter
 
 import (
+	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
@@ -16,6 +18,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configoptional"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -29,6 +32,8 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadatatest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 var defaultRoundTripFunc = func(*http.Request) (*http.Response, error) {
@@ -113,7 +118,7 @@ func TestSyncBulkIndexer(t *testing.T) {
 			require.NoError(t, err)
 
 			core, observed := observer.New(zap.NewAtomicLevelAt(zapcore.DebugLevel))
-			bi := newSyncBulkIndexer(esClient, &cfg, false, tb, zap.New(core))
+			bi := newSyncBulkIndexer(esClient, &cfg, false, tb, nil, nil, zap.New(core))
 
 			info := client.Info{Metadata: client.NewMetadata(map[string][]string{"x-test": {"test"}})}
 			ctx := client.NewContext(t.Context(), info)
@@ -193,6 +198,55 @@ func TestSyncBulkIndexer(t *testing.T) {
 	}
 }
 
+func TestSyncBulkIndexerDeadLetter(t *testing.T) {
+	storageExt := storagetest.NewFileBackedStorageExtension("dead_letter", t.TempDir())
+	cfg := createDefaultConfig().(*Config)
+	cfg.DeadLetter.Storage = &storageExt.ID
+	esClient, err := elastictransport.New(elastictransport.Config{
+		URLs: []*url.URL{{Scheme: "http", Host: "localhost:9200"}},
+		Transport: &mockTransport{
+			RoundTripFunc: func(*http.Request) (*http.Response, error) {
+				return &http.Response{
+					Header:     http.Header{"X-Elastic-Product": []string{"Elasticsearch"}},
+					Body:       io.NopCloser(strings.NewReader(`{"items":[{"create":{"_index":"foo","status":400,"error":{"type":"document_parsing_exception","reason":"failed to parse field [foo]"}}}]}`)),
+					StatusCode: http.StatusOK,
+				}, nil
+			},
+		},
+	})
+	require.NoError(t, err)
+	tb, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	exporterID := component.MustNewID("elasticsearch")
+	deadLetter := deadletter.NewWriter(cfg.DeadLetter, exporterID, zap.NewNop())
+	require.NoError(t, deadLetter.Start(t.Context(), storagetest.NewStorageHost().WithExtension(storageExt.ID, storageExt)))
+
+	bi := newSyncBulkIndexer(esClient, cfg, false, tb, deadLetter, nil, zap.NewNop())
+	session := bi.StartSession(t.Context())
+	require.NoError(t, session.Add(t.Context(), "foo", "", "", strings.NewReader(`{"foo": "bar"}`), nil, docappender.ActionCreate))
+	require.NoError(t, session.Flush(t.Context()))
+	session.End()
+	require.NoError(t, bi.Close(t.Context()))
+	require.NoError(t, deadLetter.Shutdown(t.Context()))
+
+	client, err := storageExt.GetClient(t.Context(), component.KindExporter, exporterID, "dead_letter")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, client.Close(context.Background())) }()
+	value, err := client.Get(t.Context(), "item_0")
+	require.NoError(t, err)
+	var item deadletter.Item
+	require.NoError(t, json.Unmarshal(value, &item))
+	assert.Equal(t, "document_parsing_exception: failed to parse field [foo]", item.Reason)
+	assert.Equal(t, map[string]string{
+		"index":                     "foo",
+		"error.type":                "document_parsing_exception",
+		"http.response.status_code": "400",
+	}, item.Attributes)
+	assert.Equal(t, deadletter.ContentTypeNDJSON, item.ContentType)
+	assert.Contains(t, string(item.Body), `{"foo": "bar"}`)
+}
+
 func TestQueryParamsParsedFromEndpoints(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.Endpoints = []string{"http://localhost:9200?pipeline=test-pipeline"}
@@ -213,7 +267,7 @@ func TestNewBulkIndexer(t *testing.T) {
 	client, err := newElasticsearchClient(t.Context(), cfg, componenttest.NewNopHost(), componenttest.NewTelemetry().NewTelemetrySettings(), "")
 	require.NoError(t, err)
 
-	bi := newBulkIndexer(client, cfg, true, nil, nil)
+	bi := newBulkIndexer(client, cfg, true, nil, nil, nil, nil)
 	t.Cleanup(func() { bi.Close(t.Context()) })
 }
 
@@ -20,6 +20,8 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 // Config defines configuration for Elastic exporter.
@@ -83,6 +85,10 @@ type Config struct {
 	Discovery               DiscoverySettings      `mapstructure:"discover"`
 	Retry                   RetrySettings          `mapstructure:"retry"`
 
+	// FlowControl configures the adaptive concurrency of the bulk requests,
+	// and the backpressure applied to the indices rejecting documents.
+	FlowControl FlowControlSettings `mapstructure:"flow_control"`
+
 	// Deprecated: [v0.136.0] This config is now deprecated. Use `sending_queue::batch` instead.
 	// If this config is defined then it will be used to configure sending queue's batch provided
 	// sending queue's config are not explicitly defined.
@@ -120,6 +126,10 @@ type Config struct {
 	//
 	// Keys are case-insensitive and duplicates will trigger a validation error.
 	MetadataKeys []string `mapstructure:"metadata_keys"`
+
+	// DeadLetter configures the storage extension where the documents which
+	// failed to be indexed are written, along with the reason of the failure.
+	DeadLetter deadletter.Config `mapstructure:"dead_letter"`
 }
 
 type TelemetrySettings struct {
@@ -256,6 +266,33 @@ type RetrySettings struct {
 	RetryOnStatus []int `mapstructure:"retry_on_status"`
 }
 
+// FlowControlSettings defines settings for the adaptive flow control of the bulk requests.
+// The number of concurrent bulk requests is increased additively while Elasticsearch keeps
+// up, and decreased multiplicatively when it rejects documents with a status configured in
+// `retry::retry_on_status`, or when the bulk requests get slower than LatencyThreshold.
+type FlowControlSettings struct {
+	// Enabled enables the adaptive flow control.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MinConcurrency configures the minimum number of concurrent bulk requests.
+	MinConcurrency int `mapstructure:"min_concurrency"`
+
+	// MaxConcurrency configures the maximum number of concurrent bulk requests.
+	// Defaults to `sending_queue::num_consumers` if unset.
+	MaxConcurrency int `mapstructure:"max_concurrency"`
+
+	// DecreaseFactor configures the factor the concurrency is multiplied by
+	// when Elasticsearch is under pressure.
+	DecreaseFactor float64 `mapstructure:"decrease_factor"`
+
+	// LatencyThreshold configures the latency of a bulk request above which the
+	// concurrency is decreased. The latency isn't considered if it is zero.
+	LatencyThreshold time.Duration `mapstructure:"latency_threshold"`
+
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
 type MappingsSettings struct {
 	// Mode configures the default document mapping mode.
 	//
@@ -371,6 +408,12 @@ func (cfg *Config) Validate() error {
 		return errors.New("retry::max_retries should be non-negative")
 	}
 
+	if cfg.FlowControl.Enabled {
+		if err := cfg.FlowControl.validate(); err != nil {
+			return err
+		}
+	}
+
 	if cfg.LogsIndex != "" && cfg.LogsDynamicIndex.Enabled {
 		return errors.New("must not specify both logs_index and logs_dynamic_index; logs_index should be empty unless all documents should be sent to the same index")
 	}
@@ -395,6 +438,37 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+func (s *FlowControlSettings) validate() error {
+	if s.MinConcurrency < 1 {
+		return errors.New("flow_control::min_concurrency should be greater than 0")
+	}
+	if s.MaxConcurrency < 0 {
+		return errors.New("flow_control::max_concurrency should be non-negative")
+	}
+	if s.MaxConcurrency != 0 && s.MaxConcurrency < s.MinConcurrency {
+		return errors.New("flow_control::max_concurrency should not be less than flow_control::min_concurrency")
+	}
+	if s.DecreaseFactor <= 0 || s.DecreaseFactor >= 1 {
+		return errors.New("flow_control::decrease_factor should be between 0 and 1 exclusive")
+	}
+	if s.LatencyThreshold < 0 {
+		return errors.New("flow_control::latency_threshold should be non-negative")
+	}
+	return nil
+}
+
+// flowControlMaxConcurrency returns the maximum number of concurrent bulk requests
+// of the flow control, which defaults to the number of consumers of the sending queue.
+func (cfg *Config) flowControlMaxConcurrency() int {
+	if cfg.FlowControl.MaxConcurrency > 0 {
+		return cfg.FlowControl.MaxConcurrency
+	}
+	if cfg.QueueBatchConfig.HasValue() && cfg.QueueBatchConfig.Get().NumConsumers > 0 {
+		return max(cfg.FlowControl.MinConcurrency, cfg.QueueBatchConfig.Get().NumConsumers)
+	}
+	return max(cfg.FlowControl.MinConcurrency, exporterhelper.NewDefaultQueueConfig().NumConsumers)
+}
+
 // allowedMappingModes returns a map from canonical mapping mode names to MappingModes.
 func (cfg *Config) allowedMappingModes() map[string]MappingMode {
 	modes := make(map[string]MappingMode)
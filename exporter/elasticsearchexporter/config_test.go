@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 func TestConfig(t *testing.T) {
@@ -139,6 +140,11 @@ func TestConfig(t *testing.T) {
 				TelemetrySettings: TelemetrySettings{
 					LogFailedDocsInputRateLimit: time.Second,
 				},
+				FlowControl: FlowControlSettings{
+					MinConcurrency: 1,
+					DecreaseFactor: 0.5,
+				},
+				DeadLetter: deadletter.NewDefaultConfig(),
 			},
 		},
 		{
@@ -215,6 +221,11 @@ func TestConfig(t *testing.T) {
 				TelemetrySettings: TelemetrySettings{
 					LogFailedDocsInputRateLimit: time.Second,
 				},
+				FlowControl: FlowControlSettings{
+					MinConcurrency: 1,
+					DecreaseFactor: 0.5,
+				},
+				DeadLetter: deadletter.NewDefaultConfig(),
 			},
 		},
 		{
@@ -291,6 +302,11 @@ func TestConfig(t *testing.T) {
 				TelemetrySettings: TelemetrySettings{
 					LogFailedDocsInputRateLimit: time.Second,
 				},
+				FlowControl: FlowControlSettings{
+					MinConcurrency: 1,
+					DecreaseFactor: 0.5,
+				},
+				DeadLetter: deadletter.NewDefaultConfig(),
 			},
 		},
 		{
@@ -344,6 +360,30 @@ func TestConfig(t *testing.T) {
 				cfg.IncludeSourceOnError = &includeSource
 			}),
 		},
+		{
+			id:         component.NewIDWithName(metadata.Type, "dead_letter"),
+			configFile: "config.yaml",
+			expected: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = "https://elastic.example.com:9200"
+
+				storageID := component.MustNewID("file_storage")
+				cfg.DeadLetter.Storage = &storageID
+				cfg.DeadLetter.MaxItems = 100
+			}),
+		},
+		{
+			id:         component.NewIDWithName(metadata.Type, "flow_control"),
+			configFile: "config.yaml",
+			expected: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = "https://elastic.example.com:9200"
+
+				cfg.FlowControl.Enabled = true
+				cfg.FlowControl.MinConcurrency = 2
+				cfg.FlowControl.MaxConcurrency = 20
+				cfg.FlowControl.DecreaseFactor = 0.7
+				cfg.FlowControl.LatencyThreshold = 5 * time.Second
+			}),
+		},
 		{
 			id:         component.NewIDWithName(metadata.Type, "metadata_keys"),
 			configFile: "config.yaml",
@@ -538,6 +578,31 @@ func TestConfig_Validate(t *testing.T) {
 			}),
 			err: `metadata_keys must be case-insenstive and unique, found duplicate: x-test-1`,
 		},
+		"flow_control min_concurrency not positive": {
+			config: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoints = []string{"http://test:9200"}
+				cfg.FlowControl.Enabled = true
+				cfg.FlowControl.MinConcurrency = 0
+			}),
+			err: `flow_control::min_concurrency should be greater than 0`,
+		},
+		"flow_control max_concurrency less than min_concurrency": {
+			config: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoints = []string{"http://test:9200"}
+				cfg.FlowControl.Enabled = true
+				cfg.FlowControl.MinConcurrency = 4
+				cfg.FlowControl.MaxConcurrency = 2
+			}),
+			err: `flow_control::max_concurrency should not be less than flow_control::min_concurrency`,
+		},
+		"flow_control decrease_factor out of range": {
+			config: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoints = []string{"http://test:9200"}
+				cfg.FlowControl.Enabled = true
+				cfg.FlowControl.DecreaseFactor = 1
+			}),
+			err: `flow_control::decrease_factor should be between 0 and 1 exclusive`,
+		},
 	}
 
 	for name, tt := range tests {
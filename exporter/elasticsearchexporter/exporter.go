@@ -28,6 +28,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metricgroup"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/pool"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/serializer/otelserializer"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 type elasticsearchExporter struct {
@@ -55,6 +56,7 @@ func newExporter(cfg *Config, set exporter.Settings, index string) (*elasticsear
 
 	allowedMappingModes := cfg.allowedMappingModes()
 	defaultMappingMode := allowedMappingModes[canonicalMappingModeName(cfg.Mapping.Mode)]
+	deadLetter := deadletter.NewWriter(cfg.DeadLetter, set.ID, set.Logger)
 	exporter := &elasticsearchExporter{
 		set:                 set,
 		config:              cfg,
@@ -63,7 +65,7 @@ func newExporter(cfg *Config, set exporter.Settings, index string) (*elasticsear
 		allowedMappingModes: allowedMappingModes,
 		defaultMappingMode:  defaultMappingMode,
 		bufferPool:          pool.NewBufferPool(),
-		bulkIndexers:        bulkIndexers{telemetryBuilder: telemetryBuilder},
+		bulkIndexers:        bulkIndexers{telemetryBuilder: telemetryBuilder, deadLetter: deadLetter},
 		telemetryBuilder:    telemetryBuilder,
 	}
 	for mappingMode := range NumMappingModes {
@@ -79,6 +81,9 @@ func newExporter(cfg *Config, set exporter.Settings, index string) (*elasticsear
 }
 
 func (e *elasticsearchExporter) Start(ctx context.Context, host component.Host) error {
+	if err := e.bulkIndexers.deadLetter.Start(ctx, host); err != nil {
+		return fmt.Errorf("error starting dead-letter storage: %w", err)
+	}
 	if err := e.bulkIndexers.start(ctx, e.config, e.set, host, e.allowedMappingModes); err != nil {
 		return fmt.Errorf("error starting bulk indexers: %w", err)
 	}
@@ -89,6 +94,9 @@ func (e *elasticsearchExporter) Shutdown(ctx context.Context) error {
 	if err := e.bulkIndexers.shutdown(ctx); err != nil {
 		return fmt.Errorf("error shutting down bulk indexers: %w", err)
 	}
+	if err := e.bulkIndexers.deadLetter.Shutdown(ctx); err != nil {
+		return fmt.Errorf("error shutting down dead-letter storage: %w", err)
+	}
 	if e.telemetryBuilder != nil {
 		e.telemetryBuilder.Shutdown()
 		e.telemetryBuilder = nil
@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/xexporter"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 // NewFactory creates a factory for Elastic exporter.
@@ -71,6 +72,11 @@ func createDefaultConfig() component.Config {
 				http.StatusTooManyRequests,
 			},
 		},
+		FlowControl: FlowControlSettings{
+			Enabled:        false,
+			MinConcurrency: 1,
+			DecreaseFactor: 0.5,
+		},
 		Mapping: MappingsSettings{
 			Mode:         "otel",
 			AllowedModes: slices.Sorted(maps.Keys(canonicalMappingModes)),
@@ -87,6 +93,7 @@ func createDefaultConfig() component.Config {
 			LogFailedDocsInputRateLimit: time.Second,
 		},
 		IncludeSourceOnError: nil,
+		DeadLetter:           deadletter.NewDefaultConfig(),
 	}
 }
 
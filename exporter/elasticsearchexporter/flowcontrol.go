@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-docappender/v2"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
+)
+
+const (
+	defaultIndexBackoffInitialInterval = 100 * time.Millisecond
+	defaultIndexBackoffMaxInterval     = time.Minute
+)
+
+// flowController adapts the number of concurrent bulk requests to the pressure on
+// Elasticsearch, and delays the documents of the indices rejecting documents. It is
+// shared by all the bulk indexers of the exporter, as they send to the same cluster.
+//
+// A nil *flowController doesn't limit the bulk requests.
+type flowController struct {
+	minConcurrency   float64
+	maxConcurrency   float64
+	decreaseFactor   float64
+	latencyThreshold time.Duration
+	rejectStatus     []int
+	backoffInitial   time.Duration
+	backoffMax       time.Duration
+	telemetryBuilder *metadata.TelemetryBuilder
+	logger           *zap.Logger
+
+	mu sync.Mutex
+	// limit is the number of concurrent bulk requests allowed.
+	limit    float64
+	inflight int
+	// lastDecrease is when limit was last decreased. The bulk requests started
+	// before are ignored when decreasing limit, so that the concurrent requests
+	// rejected for the same reason decrease it once.
+	lastDecrease time.Time
+	// released is closed when a bulk request completes, or limit changes.
+	released chan struct{}
+	// indices holds the backoff of the indices which rejected documents.
+	indices map[string]*indexBackoff
+}
+
+type indexBackoff struct {
+	delay time.Duration
+	until time.Time
+}
+
+// bulkRequestResult is the outcome of a bulk request, used to adapt the flow control.
+type bulkRequestResult struct {
+	start   time.Time
+	latency time.Duration
+	stat    docappender.BulkIndexerResponseStat
+	err     error
+	// indices are the indices of the documents of the request.
+	indices map[string]struct{}
+}
+
+func newFlowController(config *Config, tb *metadata.TelemetryBuilder, logger *zap.Logger) *flowController {
+	if !config.FlowControl.Enabled {
+		return nil
+	}
+	rejectStatus := config.Retry.RetryOnStatus
+	if !slices.Contains(rejectStatus, http.StatusTooManyRequests) {
+		rejectStatus = append(slices.Clone(rejectStatus), http.StatusTooManyRequests)
+	}
+	backoffInitial := config.Retry.InitialInterval
+	if backoffInitial <= 0 {
+		backoffInitial = defaultIndexBackoffInitialInterval
+	}
+	backoffMax := config.Retry.MaxInterval
+	if backoffMax <= 0 {
+		backoffMax = defaultIndexBackoffMaxInterval
+	}
+	maxConcurrency := float64(config.flowControlMaxConcurrency())
+	return &flowController{
+		minConcurrency:   float64(config.FlowControl.MinConcurrency),
+		maxConcurrency:   maxConcurrency,
+		decreaseFactor:   config.FlowControl.DecreaseFactor,
+		latencyThreshold: config.FlowControl.LatencyThreshold,
+		rejectStatus:     rejectStatus,
+		backoffInitial:   backoffInitial,
+		backoffMax:       max(backoffInitial, backoffMax),
+		telemetryBuilder: tb,
+		logger:           logger,
+		limit:            maxConcurrency,
+		released:         make(chan struct{}),
+		indices:          make(map[string]*indexBackoff),
+	}
+}
+
+// acquire waits until a bulk request can be sent, within the concurrency limit.
+func (fc *flowController) acquire(ctx context.Context) error {
+	if fc == nil {
+		return nil
+	}
+	for {
+		fc.mu.Lock()
+		if fc.inflight < int(fc.limit) {
+			fc.inflight++
+			fc.mu.Unlock()
+			return nil
+		}
+		released := fc.released
+		fc.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// release completes a bulk request acquired with acquire, and adapts the
+// concurrency limit and the backoff of the indices to its result.
+func (fc *flowController) release(ctx context.Context, result bulkRequestResult) {
+	if fc == nil {
+		return
+	}
+	rejected, rejectedIndices := fc.rejected(result)
+	now := time.Now()
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.inflight--
+	previous := fc.limit
+	switch {
+	case rejected || (fc.latencyThreshold > 0 && result.latency > fc.latencyThreshold):
+		if result.start.After(fc.lastDecrease) {
+			fc.limit = max(fc.minConcurrency, fc.limit*fc.decreaseFactor)
+			fc.lastDecrease = now
+		}
+	case result.err == nil:
+		// Increase the limit by one once a limit worth of requests succeeded.
+		fc.limit = min(fc.maxConcurrency, fc.limit+1/fc.limit)
+	}
+	if int(previous) != int(fc.limit) {
+		fc.logger.Debug("bulk request concurrency limit changed",
+			zap.Int("from", int(previous)), zap.Int("to", int(fc.limit)),
+		)
+	}
+	fc.telemetryBuilder.ElasticsearchBulkRequestsConcurrencyLimit.Record(ctx, int64(fc.limit))
+
+	for index := range rejectedIndices {
+		b, ok := fc.indices[index]
+		if !ok {
+			b = &indexBackoff{}
+			fc.indices[index] = b
+		}
+		b.delay = min(fc.backoffMax, max(fc.backoffInitial, 2*b.delay))
+		b.until = now.Add(b.delay)
+	}
+	if result.err == nil && result.stat.RetriedDocs == 0 {
+		// The indices which didn't reject documents recovered. The retried
+		// documents can't be attributed to their index, so the backoff of
+		// the indices is kept until the request is fully indexed.
+		for index := range result.indices {
+			if _, ok := rejectedIndices[index]; !ok {
+				delete(fc.indices, index)
+			}
+		}
+	}
+
+	close(fc.released)
+	fc.released = make(chan struct{})
+}
+
+// rejected returns whether Elasticsearch is under pressure according to the result,
+// and the indices which rejected documents.
+func (fc *flowController) rejected(result bulkRequestResult) (bool, map[string]struct{}) {
+	var bulkFailedErr docappender.ErrorFlushFailed
+	switch {
+	case errors.As(result.err, &bulkFailedErr):
+		if !slices.Contains(fc.rejectStatus, bulkFailedErr.StatusCode()) {
+			return false, nil
+		}
+		// The whole request was rejected.
+		return true, result.indices
+	case errors.Is(result.err, context.DeadlineExceeded):
+		return true, nil
+	}
+
+	var rejectedIndices map[string]struct{}
+	for _, doc := range result.stat.FailedDocs {
+		if slices.Contains(fc.rejectStatus, doc.Status) {
+			if rejectedIndices == nil {
+				rejectedIndices = make(map[string]struct{})
+			}
+			rejectedIndices[doc.Index] = struct{}{}
+		}
+	}
+	// The documents are retried on the statuses of retry::retry_on_status.
+	return result.stat.RetriedDocs > 0 || len(rejectedIndices) > 0, rejectedIndices
+}
+
+// wait waits for the backoff of the index, if it rejected documents. It returns
+// whether the document was delayed.
+func (fc *flowController) wait(ctx context.Context, index string) (bool, error) {
+	if fc == nil {
+		return false, nil
+	}
+	fc.mu.Lock()
+	var delay time.Duration
+	if b, ok := fc.indices[index]; ok {
+		delay = time.Until(b.until)
+	}
+	fc.mu.Unlock()
+	if delay <= 0 {
+		return false, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return true, ctx.Err()
+	case <-timer.C:
+		return true, nil
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearchexporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-docappender/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/metadata"
+)
+
+func newTestFlowController(t *testing.T, fns ...func(*Config)) *flowController {
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.FlowControl.Enabled = true
+		cfg.FlowControl.MaxConcurrency = 4
+		cfg.Retry.InitialInterval = 10 * time.Millisecond
+		cfg.Retry.MaxInterval = 40 * time.Millisecond
+	})
+	for _, fn := range fns {
+		fn(cfg)
+	}
+	tb, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	return newFlowController(cfg, tb, zap.NewNop())
+}
+
+func TestFlowController_Disabled(t *testing.T) {
+	fc := newFlowController(withDefaultConfig(), nil, zap.NewNop())
+	require.Nil(t, fc)
+
+	// A nil flow controller doesn't limit the requests.
+	require.NoError(t, fc.acquire(t.Context()))
+	fc.release(t.Context(), bulkRequestResult{})
+	delayed, err := fc.wait(t.Context(), "logs-generic-default")
+	require.NoError(t, err)
+	assert.False(t, delayed)
+}
+
+func TestFlowController_Limit(t *testing.T) {
+	fc := newTestFlowController(t)
+	for range 4 {
+		require.NoError(t, fc.acquire(t.Context()))
+	}
+
+	// The limit is reached.
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, fc.acquire(ctx), context.DeadlineExceeded)
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- fc.acquire(t.Context())
+	}()
+	fc.release(t.Context(), bulkRequestResult{start: time.Now()})
+	select {
+	case err := <-acquired:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("bulk request not acquired after release")
+	}
+}
+
+func TestFlowController_AdaptsLimit(t *testing.T) {
+	fc := newTestFlowController(t, func(cfg *Config) {
+		cfg.FlowControl.LatencyThreshold = time.Second
+	})
+	release := func(result bulkRequestResult) {
+		require.NoError(t, fc.acquire(t.Context()))
+		fc.release(t.Context(), result)
+	}
+	retried := docappender.BulkIndexerResponseStat{RetriedDocs: 1}
+
+	// The concurrent requests rejected for the same reason decrease the limit once.
+	start := time.Now()
+	release(bulkRequestResult{start: start, stat: retried})
+	assert.Equal(t, 2.0, fc.limit)
+	release(bulkRequestResult{start: start, stat: retried})
+	assert.Equal(t, 2.0, fc.limit)
+
+	// The limit doesn't go below the min concurrency.
+	release(bulkRequestResult{start: time.Now(), stat: retried})
+	release(bulkRequestResult{start: time.Now(), latency: 2 * time.Second})
+	assert.Equal(t, 1.0, fc.limit)
+
+	// The limit increases by one once a limit worth of requests succeeded.
+	release(bulkRequestResult{start: time.Now()})
+	assert.Equal(t, 2.0, fc.limit)
+	for range 3 {
+		release(bulkRequestResult{start: time.Now()})
+	}
+	assert.Equal(t, 3, int(fc.limit))
+
+	// The failed requests which aren't rejected don't change the limit.
+	limit := fc.limit
+	release(bulkRequestResult{start: time.Now(), err: errors.New("connection refused")})
+	assert.Equal(t, limit, fc.limit)
+
+	// The limit doesn't go above the max concurrency.
+	for range 10 {
+		release(bulkRequestResult{start: time.Now()})
+	}
+	assert.Equal(t, 4.0, fc.limit)
+}
+
+func TestFlowController_Rejected(t *testing.T) {
+	indices := map[string]struct{}{"logs-a": {}, "logs-b": {}}
+	tests := map[string]struct {
+		result       bulkRequestResult
+		wantRejected bool
+		wantIndices  map[string]struct{}
+	}{
+		"success": {
+			result: bulkRequestResult{indices: indices},
+		},
+		"retried documents": {
+			result:       bulkRequestResult{indices: indices, stat: docappender.BulkIndexerResponseStat{RetriedDocs: 2}},
+			wantRejected: true,
+		},
+		"rejected documents": {
+			result: bulkRequestResult{indices: indices, stat: docappender.BulkIndexerResponseStat{
+				FailedDocs: []docappender.BulkIndexerResponseItem{
+					{Index: "logs-a", Status: http.StatusTooManyRequests},
+					{Index: "logs-b", Status: http.StatusBadRequest},
+				},
+			}},
+			wantRejected: true,
+			wantIndices:  map[string]struct{}{"logs-a": {}},
+		},
+		"failed documents": {
+			result: bulkRequestResult{indices: indices, stat: docappender.BulkIndexerResponseStat{
+				FailedDocs: []docappender.BulkIndexerResponseItem{{Index: "logs-a", Status: http.StatusBadRequest}},
+			}},
+		},
+		"timeout": {
+			result:       bulkRequestResult{indices: indices, err: context.DeadlineExceeded},
+			wantRejected: true,
+		},
+	}
+	fc := newTestFlowController(t)
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rejected, rejectedIndices := fc.rejected(tt.result)
+			assert.Equal(t, tt.wantRejected, rejected)
+			assert.Equal(t, tt.wantIndices, rejectedIndices)
+		})
+	}
+}
+
+func TestFlowController_IndexBackoff(t *testing.T) {
+	fc := newTestFlowController(t)
+	rejected := bulkRequestResult{
+		start:   time.Now(),
+		indices: map[string]struct{}{"logs-a": {}, "logs-b": {}},
+		stat: docappender.BulkIndexerResponseStat{
+			FailedDocs: []docappender.BulkIndexerResponseItem{{Index: "logs-a", Status: http.StatusTooManyRequests}},
+		},
+	}
+	require.NoError(t, fc.acquire(t.Context()))
+	fc.release(t.Context(), rejected)
+
+	delayed, err := fc.wait(t.Context(), "logs-b")
+	require.NoError(t, err)
+	assert.False(t, delayed)
+
+	start := time.Now()
+	delayed, err = fc.wait(t.Context(), "logs-a")
+	require.NoError(t, err)
+	assert.True(t, delayed)
+	assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+
+	// The backoff grows exponentially, up to retry::max_interval.
+	for range 3 {
+		require.NoError(t, fc.acquire(t.Context()))
+		fc.release(t.Context(), rejected)
+	}
+	assert.Equal(t, 40*time.Millisecond, fc.indices["logs-a"].delay)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	delayed, err = fc.wait(ctx, "logs-a")
+	assert.True(t, delayed)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The backoff is reset once the index accepts its documents.
+	require.NoError(t, fc.acquire(t.Context()))
+	fc.release(t.Context(), bulkRequestResult{start: time.Now(), indices: map[string]struct{}{"logs-a": {}}})
+	delayed, err = fc.wait(t.Context(), "logs-a")
+	require.NoError(t, err)
+	assert.False(t, delayed)
+}
@@ -22,16 +22,19 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 // TelemetryBuilder provides an interface for components to report telemetry
 // as defined in metadata and user config.
 type TelemetryBuilder struct {
-	meter                                 metric.Meter
-	mu                                    sync.Mutex
-	registrations                         []metric.Registration
-	ElasticsearchBulkRequestsCount        metric.Int64Counter
-	ElasticsearchBulkRequestsLatency      metric.Float64Histogram
-	ElasticsearchDocsProcessed            metric.Int64Counter
-	ElasticsearchDocsReceived             metric.Int64Counter
-	ElasticsearchDocsRetried              metric.Int64Counter
-	ElasticsearchFlushedBytes             metric.Int64Counter
-	ElasticsearchFlushedUncompressedBytes metric.Int64Counter
+	meter                                     metric.Meter
+	mu                                        sync.Mutex
+	registrations                             []metric.Registration
+	ElasticsearchBulkRequestsConcurrencyLimit metric.Int64Gauge
+	ElasticsearchBulkRequestsCount            metric.Int64Counter
+	ElasticsearchBulkRequestsInflight         metric.Int64UpDownCounter
+	ElasticsearchBulkRequestsLatency          metric.Float64Histogram
+	ElasticsearchDocsProcessed                metric.Int64Counter
+	ElasticsearchDocsReceived                 metric.Int64Counter
+	ElasticsearchDocsRetried                  metric.Int64Counter
+	ElasticsearchDocsThrottled                metric.Int64Counter
+	ElasticsearchFlushedBytes                 metric.Int64Counter
+	ElasticsearchFlushedUncompressedBytes     metric.Int64Counter
 }
 
 // TelemetryBuilderOption applies changes to default builder.
@@ -63,12 +66,24 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 	}
 	builder.meter = Meter(settings)
 	var err, errs error
+	builder.ElasticsearchBulkRequestsConcurrencyLimit, err = builder.meter.Int64Gauge(
+		"otelcol.elasticsearch.bulk_requests.concurrency_limit",
+		metric.WithDescription("Maximum number of concurrent bulk requests allowed by the adaptive flow control. [Alpha]"),
+		metric.WithUnit("{request}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ElasticsearchBulkRequestsCount, err = builder.meter.Int64Counter(
 		"otelcol.elasticsearch.bulk_requests.count",
 		metric.WithDescription("Count of the completed bulk requests. [Alpha]"),
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ElasticsearchBulkRequestsInflight, err = builder.meter.Int64UpDownCounter(
+		"otelcol.elasticsearch.bulk_requests.inflight",
+		metric.WithDescription("Number of bulk requests in flight to Elasticsearch. [Alpha]"),
+		metric.WithUnit("{request}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ElasticsearchBulkRequestsLatency, err = builder.meter.Float64Histogram(
 		"otelcol.elasticsearch.bulk_requests.latency",
 		metric.WithDescription("Latency of Elasticsearch bulk operations in seconds. [Alpha]"),
@@ -94,6 +109,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ElasticsearchDocsThrottled, err = builder.meter.Int64Counter(
+		"otelcol.elasticsearch.docs.throttled",
+		metric.WithDescription("Count of documents delayed because their index rejected documents. [Alpha]"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ElasticsearchFlushedBytes, err = builder.meter.Int64Counter(
 		"otelcol.elasticsearch.flushed.bytes",
 		metric.WithDescription("Number of bytes flushed by the indexer. [Alpha]"),
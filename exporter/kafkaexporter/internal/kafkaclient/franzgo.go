@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -17,17 +18,26 @@ import (
 // the Producer interface. Allowing us to use the franz-go client while
 // maintaining compatibility with the existing Kafka exporter code.
 type FranzSyncProducer struct {
-	client       *kgo.Client
-	metadataKeys []string
+	client        *kgo.Client
+	metadataKeys  []string
+	transactional bool
+
+	// txMu serializes the transactions, since a producer can only have one
+	// open transaction at a time.
+	txMu sync.Mutex
 }
 
 // NewFranzSyncProducer Franz-go producer from a kgo.Client and a Messenger.
+// If transactional is true, each batch is produced in its own transaction,
+// which requires the client to be configured with a transactional ID.
 func NewFranzSyncProducer(client *kgo.Client,
 	metadataKeys []string,
+	transactional bool,
 ) *FranzSyncProducer {
 	return &FranzSyncProducer{
-		client:       client,
-		metadataKeys: metadataKeys,
+		client:        client,
+		metadataKeys:  metadataKeys,
+		transactional: transactional,
 	}
 }
 
@@ -41,9 +51,45 @@ func (p *FranzSyncProducer) ExportData(ctx context.Context, msgs Messages) error
 		func(m *kgo.Record) []kgo.RecordHeader { return m.Headers },
 		func(m *kgo.Record, h []kgo.RecordHeader) { m.Headers = h },
 	)
-	result := p.client.ProduceSync(ctx, messages...)
+	if p.transactional {
+		return p.produceTransaction(ctx, messages)
+	}
+	return produceError(p.client.ProduceSync(ctx, messages...))
+}
+
+// produceTransaction produces the records in a transaction, which is only
+// committed if all of them were written, and aborted otherwise.
+func (p *FranzSyncProducer) produceTransaction(ctx context.Context, records []*kgo.Record) error {
+	p.txMu.Lock()
+	defer p.txMu.Unlock()
+
+	if err := p.client.BeginTransaction(); err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	if err := produceError(p.client.ProduceSync(ctx, records...)); err != nil {
+		return errors.Join(err, p.abortTransaction(ctx))
+	}
+	if err := p.client.EndTransaction(ctx, kgo.TryCommit); err != nil {
+		err = fmt.Errorf("error committing transaction: %w", err)
+		return errors.Join(err, p.abortTransaction(ctx))
+	}
+	return nil
+}
+
+func (p *FranzSyncProducer) abortTransaction(ctx context.Context) error {
+	if err := p.client.AbortBufferedRecords(ctx); err != nil {
+		return fmt.Errorf("error aborting transaction: %w", err)
+	}
+	if err := p.client.EndTransaction(ctx, kgo.TryAbort); err != nil {
+		return fmt.Errorf("error aborting transaction: %w", err)
+	}
+	return nil
+}
+
+// produceError returns the errors of the records which couldn't be produced.
+func produceError(results kgo.ProduceResults) error {
 	var errs []error
-	for _, r := range result {
+	for _, r := range results {
 		if r.Err != nil {
 			err := fmt.Errorf("error exporting to topic %q: %w", r.Record.Topic, r.Err)
 			// check if its defined as a non-retriable error by franzgo
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"strings"
 
 	"github.com/twmb/franz-go/pkg/kgo"
 	"go.opentelemetry.io/collector/client"
@@ -56,6 +57,7 @@ type kafkaExporter[T any] struct {
 	set          exporter.Settings
 	tb           *metadata.TelemetryBuilder
 	logger       *zap.Logger
+	signal       string
 	newMessenger func(host component.Host) (messenger[T], error)
 	messenger    messenger[T]
 	producer     producer
@@ -64,12 +66,14 @@ type kafkaExporter[T any] struct {
 func newKafkaExporter[T any](
 	config Config,
 	set exporter.Settings,
+	signal string,
 	newMessenger func(component.Host) (messenger[T], error),
 ) *kafkaExporter[T] {
 	return &kafkaExporter[T]{
 		cfg:          config,
 		set:          set,
 		logger:       set.Logger,
+		signal:       signal,
 		newMessenger: newMessenger,
 	}
 }
@@ -85,6 +89,13 @@ func (e *kafkaExporter[T]) Start(ctx context.Context, host component.Host) (err
 		return err
 	}
 
+	opts := []kgo.Opt{kgo.WithHooks(kafkaclient.NewFranzProducerMetrics(tb))}
+	transactional := e.cfg.Producer.Transaction.Enable
+	if transactional {
+		id := transactionalID(e.cfg.Producer.Transaction.ID, e.set, e.signal)
+		e.logger.Info("producing batches in transactions", zap.String("transactional_id", id))
+		opts = append(opts, kgo.TransactionalID(id))
+	}
 	producer, err := kafka.NewFranzSyncProducer(
 		ctx,
 		host,
@@ -92,17 +103,26 @@ func (e *kafkaExporter[T]) Start(ctx context.Context, host component.Host) (err
 		e.cfg.Producer,
 		e.cfg.TimeoutSettings.Timeout,
 		e.logger,
-		kgo.WithHooks(kafkaclient.NewFranzProducerMetrics(tb)),
+		opts...,
 	)
 	if err != nil {
 		return err
 	}
 	e.producer = kafkaclient.NewFranzSyncProducer(producer,
 		e.cfg.IncludeMetadataKeys,
+		transactional,
 	)
 	return nil
 }
 
+// transactionalID derives the transactional ID of the producer from the
+// configured transaction ID, the exporter ID and the signal, so that the
+// producers of different exporters don't fence each other out, while a
+// restarted collector fences out the producers of its previous run.
+func transactionalID(id string, set exporter.Settings, signal string) string {
+	return strings.Join([]string{id, set.ID.String(), signal}, "-")
+}
+
 func (e *kafkaExporter[T]) Close(context.Context) (err error) {
 	if e.producer == nil {
 		return nil
@@ -171,7 +191,7 @@ func newTracesExporter(config Config, set exporter.Settings) *kafkaExporter[ptra
 	case "jaeger_proto", "jaeger_json":
 		config.PartitionTracesByID = false
 	}
-	return newKafkaExporter(config, set, func(host component.Host) (messenger[ptrace.Traces], error) {
+	return newKafkaExporter(config, set, "traces", func(host component.Host) (messenger[ptrace.Traces], error) {
 		marshaler, err := getTracesMarshaler(config.Traces.Encoding, host)
 		if err != nil {
 			return nil, err
@@ -216,7 +236,7 @@ func (e *kafkaTracesMessenger) partitionData(td ptrace.Traces) iter.Seq2[[]byte,
 }
 
 func newLogsExporter(config Config, set exporter.Settings) *kafkaExporter[plog.Logs] {
-	return newKafkaExporter(config, set, func(host component.Host) (messenger[plog.Logs], error) {
+	return newKafkaExporter(config, set, "logs", func(host component.Host) (messenger[plog.Logs], error) {
 		marshaler, err := getLogsMarshaler(config.Logs.Encoding, host)
 		if err != nil {
 			return nil, err
@@ -272,7 +292,7 @@ func (e *kafkaLogsMessenger) partitionData(ld plog.Logs) iter.Seq2[[]byte, plog.
 }
 
 func newMetricsExporter(config Config, set exporter.Settings) *kafkaExporter[pmetric.Metrics] {
-	return newKafkaExporter(config, set, func(host component.Host) (messenger[pmetric.Metrics], error) {
+	return newKafkaExporter(config, set, "metrics", func(host component.Host) (messenger[pmetric.Metrics], error) {
 		marshaler, err := getMetricsMarshaler(config.Metrics.Encoding, host)
 		if err != nil {
 			return nil, err
@@ -315,7 +335,7 @@ func (e *kafkaMetricsMessenger) partitionData(md pmetric.Metrics) iter.Seq2[[]by
 }
 
 func newProfilesExporter(config Config, set exporter.Settings) *kafkaExporter[pprofile.Profiles] {
-	return newKafkaExporter(config, set, func(host component.Host) (messenger[pprofile.Profiles], error) {
+	return newKafkaExporter(config, set, "profiles", func(host component.Host) (messenger[pprofile.Profiles], error) {
 		marshaler, err := getProfilesMarshaler(config.Profiles.Encoding, host)
 		if err != nil {
 			return nil, err
@@ -31,6 +31,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/kafkatest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/kafka/configkafka"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/kafka/topic"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/ptracetest"
 )
@@ -324,6 +325,42 @@ func TestMetricsDataPusher_Kgo(t *testing.T) {
 	assert.Nil(t, record.Key, "expected nil key for default config")
 }
 
+func TestMetricsDataPusher_transaction_Kgo(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Producer.RequiredAcks = configkafka.WaitForAll
+	config.Producer.Transaction.Enable = true
+	config.Producer.Transaction.ID = "otelcol-0"
+
+	exp, fakeCluster := newKgoMockMetricsExporter(t, *config,
+		componenttest.NewNopHost(), config.Metrics.Topic,
+	)
+
+	// Each batch is produced in its own transaction.
+	for range 2 {
+		require.NoError(t, exp.exportData(t.Context(), testdata.GenerateMetrics(2)))
+	}
+
+	records := fetchKgoRecords(t,
+		fakeCluster.ListenAddrs(), config.Metrics.Topic, 2,
+	)
+	fakeCluster.Close()
+
+	require.Len(t, records, 2, "expected one message to be produced per metrics batch")
+	for _, record := range records {
+		assert.NotEmpty(t, record.Value)
+	}
+}
+
+func TestTransactionalID(t *testing.T) {
+	set := exportertest.NewNopSettings(metadata.Type)
+	assert.Equal(t, "otelcol-0-kafka-logs", transactionalID("otelcol-0", set, "logs"))
+
+	// The service.instance.id is random by default, so it isn't used.
+	set.ID = component.NewIDWithName(metadata.Type, "primary")
+	set.Resource.Attributes().PutStr("service.instance.id", "627cc493-f310-47de-96bd-71410b7dec09")
+	assert.Equal(t, "otelcol-0-kafka/primary-metrics", transactionalID("otelcol-0", set, "metrics"))
+}
+
 func TestMetricsDataPusher_attr_Kgo(t *testing.T) {
 	config := createDefaultConfig().(*Config)
 	attributeKey := "my_custom_topic_key_metrics"
@@ -948,6 +985,11 @@ func configureExporter[T any](tb testing.TB,
 		kgo.SeedBrokers(kcfg.Brokers...),
 		kgo.ClientID(cfg.ClientID),
 	}
+	if cfg.Producer.Transaction.Enable {
+		kgoClientOpts = append(kgoClientOpts, kgo.TransactionalID(
+			transactionalID(cfg.Producer.Transaction.ID, exp.set, exp.signal),
+		))
+	}
 
 	client, err := kafka.NewFranzSyncProducer(tb.Context(), host, kcfg,
 		cfg.Producer, 1*time.Second, zap.NewNop(), kgoClientOpts...)
@@ -957,7 +999,7 @@ func configureExporter[T any](tb testing.TB,
 	require.NoError(tb, err, "failed to create messenger for metrics")
 
 	exp.messenger = messenger
-	exp.producer = kafkaclient.NewFranzSyncProducer(client, cfg.IncludeMetadataKeys)
+	exp.producer = kafkaclient.NewFranzSyncProducer(client, cfg.IncludeMetadataKeys, cfg.Producer.Transaction.Enable)
 
 	tb.Cleanup(func() { assert.NoError(tb, exp.Close(tb.Context())) })
 	return cluster
@@ -11,6 +11,8 @@ import (
 	"go.opentelemetry.io/collector/config/configoptional"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 const (
@@ -58,6 +60,10 @@ type Config struct {
 	// BulkAction configures the action for ingesting data. Only `create` and `index` are allowed here.
 	// If not specified, the default value `create` will be used.
 	BulkAction string `mapstructure:"bulk_action"`
+
+	// DeadLetter configures the storage extension where the documents which are
+	// rejected by OpenSearch are written, along with the reason of the rejection.
+	DeadLetter deadletter.Config `mapstructure:"dead_letter"`
 }
 
 var (
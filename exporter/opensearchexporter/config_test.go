@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opensearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -82,6 +83,7 @@ func TestLoadConfig(t *testing.T) {
 					Mode: "ss4o",
 				},
 				QueueConfig: configoptional.Default(exporterhelper.NewDefaultQueueConfig()),
+				DeadLetter:  deadletter.NewDefaultConfig(),
 			},
 			configValidateAssert: assert.NoError,
 		},
@@ -227,6 +229,16 @@ func TestLoadConfig(t *testing.T) {
 				return assert.ErrorContains(t, err, errTracesIndexTimeFormatInvalid.Error())
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "dead_letter"),
+			expected: withDefaultConfig(func(config *Config) {
+				config.Endpoint = sampleEndpoint
+				storageID := component.MustNewID("file_storage")
+				config.DeadLetter.Storage = &storageID
+				config.DeadLetter.MaxItems = 100
+			}),
+			configValidateAssert: assert.NoError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -17,6 +17,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/opensearchexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 )
 
 // NewFactory creates a factory for OpenSearch exporter.
@@ -38,6 +39,7 @@ func newDefaultConfig() component.Config {
 		BackOffConfig:    configretry.NewDefaultBackOffConfig(),
 		MappingsSettings: MappingsSettings{Mode: defaultMappingMode},
 		QueueConfig:      configoptional.Default(exporterhelper.NewDefaultQueueConfig()),
+		DeadLetter:       deadletter.NewDefaultConfig(),
 	}
 }
 
@@ -48,9 +50,17 @@ func createTracesExporter(ctx context.Context,
 	c := cfg.(*Config)
 	te := newSSOTracesExporter(c, set)
 
+	deadLetter := deadletter.NewWriter(c.DeadLetter, set.ID, set.Logger)
+
 	return exporterhelper.NewTraces(ctx, set, cfg,
-		te.pushTraceData,
-		exporterhelper.WithStart(te.Start),
+		deadLetter.PushTraces(te.pushTraceData),
+		exporterhelper.WithStart(func(ctx context.Context, host component.Host) error {
+			if err := deadLetter.Start(ctx, host); err != nil {
+				return err
+			}
+			return te.Start(ctx, host)
+		}),
+		exporterhelper.WithShutdown(deadLetter.Shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		exporterhelper.WithRetry(c.BackOffConfig),
 		exporterhelper.WithQueue(c.QueueConfig),
@@ -64,9 +74,17 @@ func createLogsExporter(ctx context.Context,
 	c := cfg.(*Config)
 	le := newLogExporter(c, set)
 
+	deadLetter := deadletter.NewWriter(c.DeadLetter, set.ID, set.Logger)
+
 	return exporterhelper.NewLogs(ctx, set, cfg,
-		le.pushLogData,
-		exporterhelper.WithStart(le.Start),
+		deadLetter.PushLogs(le.pushLogData),
+		exporterhelper.WithStart(func(ctx context.Context, host component.Host) error {
+			if err := deadLetter.Start(ctx, host); err != nil {
+				return err
+			}
+			return le.Start(ctx, host)
+		}),
+		exporterhelper.WithShutdown(deadLetter.Shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
 		exporterhelper.WithRetry(c.BackOffConfig),
 		exporterhelper.WithQueue(c.QueueConfig),
@@ -58,6 +58,11 @@ func (lbi *logBulkIndexer) appendRetryLogError(err error, log plog.Logs) {
 	lbi.errs = append(lbi.errs, consumererror.NewLogs(err, log))
 }
 
+// appendPermanentLogError keeps the log along with the error, so that it can be written to the dead-letter storage.
+func (lbi *logBulkIndexer) appendPermanentLogError(err error, log plog.Logs) {
+	lbi.errs = append(lbi.errs, consumererror.NewLogs(consumererror.NewPermanent(err), log))
+}
+
 func (lbi *logBulkIndexer) submit(ctx context.Context, ld plog.Logs, ir *indexResolver, cfg *Config, timestamp time.Time) {
 	keys := ir.extractPlaceholderKeys(cfg.LogsIndex)
 	timeSuffix := ir.calculateTimeSuffix(cfg.LogsIndexTimeFormat, timestamp)
@@ -125,10 +130,10 @@ func (lbi *logBulkIndexer) processItemFailure(resp opensearchapi.BulkRespItem, i
 		lbi.appendRetryLogError(responseAsError(resp), logs)
 	case resp.Status != 0 && itemErr == nil:
 		// Non-recoverable OpenSearch error while indexing document
-		lbi.appendPermanentError(responseAsError(resp))
+		lbi.appendPermanentLogError(responseAsError(resp), logs)
 	default:
 		// Encoding error. We didn't even attempt to send the event
-		lbi.appendPermanentError(itemErr)
+		lbi.appendPermanentLogError(itemErr, logs)
 	}
 }
 
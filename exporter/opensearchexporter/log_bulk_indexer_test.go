@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 )
@@ -60,6 +61,25 @@ func TestProcessItemFailure(t *testing.T) {
 	}
 }
 
+func TestProcessItemFailureKeepsRejectedLog(t *testing.T) {
+	lbi := &logBulkIndexer{}
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("rejected")
+	lbi.processItemFailure(opensearchapi.BulkRespItem{Status: 400}, nil, logs)
+
+	err := lbi.joinedError()
+	if !consumererror.IsPermanent(err) {
+		t.Fatalf("expected a permanent error, got %v", err)
+	}
+	var logsErr consumererror.Logs
+	if !errors.As(err, &logsErr) {
+		t.Fatalf("expected the error to carry the rejected log, got %v", err)
+	}
+	if logsErr.Data().LogRecordCount() != 1 {
+		t.Errorf("expected 1 rejected log record, got %d", logsErr.Data().LogRecordCount())
+	}
+}
+
 func TestNewBulkIndexerItem(t *testing.T) {
 	lbi := &logBulkIndexer{bulkAction: "index"}
 	payload := []byte(`{"test": "data"}`)
@@ -60,6 +60,11 @@ func (tbi *traceBulkIndexer) appendRetryTraceError(err error, trace ptrace.Trace
 	tbi.errs = append(tbi.errs, consumererror.NewTraces(err, trace))
 }
 
+// appendPermanentTraceError keeps the trace along with the error, so that it can be written to the dead-letter storage.
+func (tbi *traceBulkIndexer) appendPermanentTraceError(err error, trace ptrace.Traces) {
+	tbi.errs = append(tbi.errs, consumererror.NewTraces(consumererror.NewPermanent(err), trace))
+}
+
 func (tbi *traceBulkIndexer) submit(ctx context.Context, td ptrace.Traces, ir *indexResolver, cfg *Config, timestamp time.Time) {
 	keys := ir.extractPlaceholderKeys(cfg.TracesIndex)
 	timeSuffix := ir.calculateTimeSuffix(cfg.TracesIndexTimeFormat, timestamp)
@@ -127,10 +132,10 @@ func (tbi *traceBulkIndexer) processItemFailure(resp opensearchapi.BulkRespItem,
 		tbi.appendRetryTraceError(responseAsError(resp), traces)
 	case resp.Status != 0 && itemErr == nil:
 		// Non-recoverable OpenSearch error while indexing document
-		tbi.appendPermanentError(responseAsError(resp))
+		tbi.appendPermanentTraceError(responseAsError(resp), traces)
 	default:
 		// Encoding error. We didn't even attempt to send the event
-		tbi.appendPermanentError(itemErr)
+		tbi.appendPermanentTraceError(itemErr, traces)
 	}
 }
 
@@ -120,6 +120,12 @@ func (cfg *Config) Validate() error {
 		cfg.MaxBatchSizeBytes = 3000000
 	}
 
+	if wal := cfg.WAL.Get(); wal != nil {
+		if err := wal.Queue.validate(); err != nil {
+			return err
+		}
+	}
+
 	if len(cfg.ClientConfig.Compression) > 0 && cfg.ClientConfig.Compression != "snappy" {
 		return errors.New("compression type must be snappy")
 	}
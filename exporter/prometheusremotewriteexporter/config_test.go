@@ -101,6 +101,10 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(metadata.Type, "non_snappy_compression_type"),
 			errorMessage: "compression type must be snappy",
 		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_wal_queue_shards"),
+			errorMessage: "wal queue min_shards can't be greater than max_shards",
+		},
 		{
 			id:           component.NewIDWithName(metadata.Type, "unknown_protobuf_message"),
 			errorMessage: "unknown type for remote write protobuf message io.prometheus.write.v4.Request, supported: prometheus.WriteRequest, io.prometheus.write.v2.Request",
@@ -152,6 +156,31 @@ func TestDisabledTargetInfo(t *testing.T) {
 	assert.False(t, cfg.(*Config).TargetInfo.Enabled)
 }
 
+func TestWALQueue(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "wal_queue").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	require.NoError(t, xconfmap.Validate(cfg))
+
+	wal := cfg.(*Config).WAL.Get()
+	require.NotNil(t, wal)
+	queue := wal.Queue
+	assert.True(t, queue.Enabled)
+	assert.Equal(t, 2, queue.minShards())
+	assert.Equal(t, 10, queue.maxShards())
+	assert.Equal(t, defaultWALQueueCapacity, queue.capacity())
+	assert.Equal(t, 500, queue.maxSamplesPerSend())
+	assert.Equal(t, 2*time.Second, queue.batchSendDeadline())
+	assert.Equal(t, defaultWALQueueMinBackoff, queue.minBackoff())
+	assert.Equal(t, defaultWALQueueMaxBackoff, queue.maxBackoff())
+	assert.Equal(t, defaultWALQueueSeriesTTL, queue.seriesTTL())
+}
+
 func toPtr[T any](val T) *T {
 	return &val
 }
@@ -228,6 +228,9 @@ func newPRWExporter(cfg *Config, set exporter.Settings) (*prwExporter, error) {
 	if err != nil {
 		return nil, err
 	}
+	if prwe.wal != nil {
+		prwe.wal.sendSink = prwe.sendFromWAL
+	}
 	return prwe, nil
 }
 
@@ -422,90 +425,7 @@ func (prwe *prwExporter) execute(ctx context.Context, buf []byte) error {
 	// executeFunc can be used for backoff and non backoff scenarios.
 	executeFunc := func() (int, error) {
 		retryCount++
-		// check there was no timeout in the component level to avoid retries
-		// to continue to run after a timeout
-		select {
-		case <-ctx.Done():
-			return http.StatusGatewayTimeout, backoff.Permanent(ctx.Err())
-		default:
-			// continue
-		}
-
-		// Create the HTTP POST request to send to the endpoint
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, prwe.endpointURL.String(), bytes.NewReader(buf))
-		if err != nil {
-			return http.StatusBadRequest, backoff.Permanent(consumererror.NewPermanent(err))
-		}
-
-		// Add necessary headers specified by:
-		// https://cortexmetrics.io/docs/apis/#remote-api
-		req.Header.Add("Content-Encoding", "snappy")
-		req.Header.Set("User-Agent", prwe.userAgentHeader)
-
-		switch {
-		// If feature flag not enabled support only RW1
-		case !enableSendingRW2FeatureGate.IsEnabled(), prwe.RemoteWriteProtoMsg == remoteapi.WriteV1MessageType:
-			req.Header.Set("Content-Type", "application/x-protobuf")
-			req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
-		case prwe.RemoteWriteProtoMsg == remoteapi.WriteV2MessageType:
-			req.Header.Set("Content-Type", "application/x-protobuf;proto=io.prometheus.write.v2.Request")
-			req.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
-		default:
-			return http.StatusBadRequest, fmt.Errorf("unsupported remote-write protobuf message: %v (should be validated earlier)", prwe.RemoteWriteProtoMsg)
-		}
-
-		resp, err := prwe.client.Do(req)
-		prwe.telemetry.recordRemoteWriteSentBatch(ctx)
-		if err != nil {
-			return http.StatusBadRequest, err
-		}
-		defer func() {
-			_, _ = io.Copy(io.Discard, resp.Body)
-			resp.Body.Close()
-		}()
-
-		// Per the Prometheus remote write 2.0 specification, the response should contain
-		// X-Prometheus-Remote-Write-Samples-Written header.
-		// If the header is missing, it suggests that the endpoint does not support RW2 or the
-		// implementation is not compliant with the specification. Reference:
-		// https://prometheus.io/docs/specs/prw/remote_write_spec_2_0/#required-written-response-headers
-		if enableSendingRW2FeatureGate.IsEnabled() && prwe.RemoteWriteProtoMsg == remoteapi.WriteV2MessageType {
-			prwe.handleWrittenHeaders(ctx, resp)
-		}
-
-		// 2xx status code is considered a success
-		// 5xx errors are recoverable and the exporter should retry
-		// Reference for different behavior according to status code:
-		// https://github.com/prometheus/prometheus/pull/2552/files#diff-ae8db9d16d8057358e49d694522e7186
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			prwe.settings.Logger.Debug("remote write request successful",
-				zap.Int("status_code", resp.StatusCode),
-				zap.String("status", resp.Status),
-				zap.String("endpoint", prwe.endpointURL.String()),
-			)
-			return resp.StatusCode, nil
-		}
-
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		prwe.settings.Logger.Error("failed to send WriteRequest to remote endpoint",
-			zap.Int("status_code", resp.StatusCode),
-			zap.String("status", resp.Status),
-			zap.String("endpoint", prwe.endpointURL.String()),
-			zap.Int("retry_attempt", retryCount),
-			zap.String("error", string(body)),
-		)
-		rerr := errors.New("remote write request failed")
-		if resp.StatusCode >= 500 && resp.StatusCode < 600 {
-			return resp.StatusCode, rerr
-		}
-
-		// 429 errors are recoverable and the exporter should retry if RetryOnHTTP429 enabled
-		// Reference: https://github.com/prometheus/prometheus/pull/12677
-		if prwe.retryOnHTTP429 && resp.StatusCode == http.StatusTooManyRequests {
-			return resp.StatusCode, rerr
-		}
-
-		return resp.StatusCode, backoff.Permanent(consumererror.NewPermanent(rerr))
+		return prwe.send(ctx, buf, retryCount)
 	}
 
 	var err error
@@ -528,6 +448,108 @@ func (prwe *prwExporter) execute(ctx context.Context, buf []byte) error {
 	return nil
 }
 
+// sendFromWAL makes a single attempt to send a request replayed by the WAL queue,
+// which retries it unless the returned error is a backoff.PermanentError.
+func (prwe *prwExporter) sendFromWAL(ctx context.Context, req *prompb.WriteRequest, attempt int) error {
+	buf := bufferPool.Get().(*buffer)
+	defer bufferPool.Put(buf)
+	reqBuf, err := buf.MarshalAndEncode(req)
+	if err != nil {
+		return backoff.Permanent(consumererror.NewPermanent(err))
+	}
+	_, err = prwe.send(ctx, reqBuf, attempt)
+	return err
+}
+
+// send makes a single attempt to send buf to the remote write endpoint. The errors
+// which can't be retried are wrapped with backoff.Permanent.
+func (prwe *prwExporter) send(ctx context.Context, buf []byte, attempt int) (int, error) {
+	// check there was no timeout in the component level to avoid retries
+	// to continue to run after a timeout
+	select {
+	case <-ctx.Done():
+		return http.StatusGatewayTimeout, backoff.Permanent(ctx.Err())
+	default:
+		// continue
+	}
+
+	// Create the HTTP POST request to send to the endpoint
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, prwe.endpointURL.String(), bytes.NewReader(buf))
+	if err != nil {
+		return http.StatusBadRequest, backoff.Permanent(consumererror.NewPermanent(err))
+	}
+
+	// Add necessary headers specified by:
+	// https://cortexmetrics.io/docs/apis/#remote-api
+	req.Header.Add("Content-Encoding", "snappy")
+	req.Header.Set("User-Agent", prwe.userAgentHeader)
+
+	switch {
+	// If feature flag not enabled support only RW1
+	case !enableSendingRW2FeatureGate.IsEnabled(), prwe.RemoteWriteProtoMsg == remoteapi.WriteV1MessageType:
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	case prwe.RemoteWriteProtoMsg == remoteapi.WriteV2MessageType:
+		req.Header.Set("Content-Type", "application/x-protobuf;proto=io.prometheus.write.v2.Request")
+		req.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
+	default:
+		return http.StatusBadRequest, fmt.Errorf("unsupported remote-write protobuf message: %v (should be validated earlier)", prwe.RemoteWriteProtoMsg)
+	}
+
+	resp, err := prwe.client.Do(req)
+	prwe.telemetry.recordRemoteWriteSentBatch(ctx)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	// Per the Prometheus remote write 2.0 specification, the response should contain
+	// X-Prometheus-Remote-Write-Samples-Written header.
+	// If the header is missing, it suggests that the endpoint does not support RW2 or the
+	// implementation is not compliant with the specification. Reference:
+	// https://prometheus.io/docs/specs/prw/remote_write_spec_2_0/#required-written-response-headers
+	if enableSendingRW2FeatureGate.IsEnabled() && prwe.RemoteWriteProtoMsg == remoteapi.WriteV2MessageType {
+		prwe.handleWrittenHeaders(ctx, resp)
+	}
+
+	// 2xx status code is considered a success
+	// 5xx errors are recoverable and the exporter should retry
+	// Reference for different behavior according to status code:
+	// https://github.com/prometheus/prometheus/pull/2552/files#diff-ae8db9d16d8057358e49d694522e7186
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		prwe.settings.Logger.Debug("remote write request successful",
+			zap.Int("status_code", resp.StatusCode),
+			zap.String("status", resp.Status),
+			zap.String("endpoint", prwe.endpointURL.String()),
+		)
+		return resp.StatusCode, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	prwe.settings.Logger.Error("failed to send WriteRequest to remote endpoint",
+		zap.Int("status_code", resp.StatusCode),
+		zap.String("status", resp.Status),
+		zap.String("endpoint", prwe.endpointURL.String()),
+		zap.Int("retry_attempt", attempt),
+		zap.String("error", string(body)),
+	)
+	rerr := errors.New("remote write request failed")
+	if resp.StatusCode >= 500 && resp.StatusCode < 600 {
+		return resp.StatusCode, rerr
+	}
+
+	// 429 errors are recoverable and the exporter should retry if RetryOnHTTP429 enabled
+	// Reference: https://github.com/prometheus/prometheus/pull/12677
+	if prwe.retryOnHTTP429 && resp.StatusCode == http.StatusTooManyRequests {
+		return resp.StatusCode, rerr
+	}
+
+	return resp.StatusCode, backoff.Permanent(consumererror.NewPermanent(rerr))
+}
+
 func (prwe *prwExporter) walEnabled() bool { return prwe.wal != nil }
 
 func (prwe *prwExporter) turnOnWALIfEnabled(ctx context.Context) error {
@@ -31,10 +31,14 @@ type TelemetryBuilder struct {
 	ExporterPrometheusremotewriteTranslatedTimeSeries metric.Int64Counter
 	ExporterPrometheusremotewriteWalBytesRead         metric.Int64Counter
 	ExporterPrometheusremotewriteWalBytesWritten      metric.Int64Counter
+	ExporterPrometheusremotewriteWalDroppedSamples    metric.Int64Counter
 	ExporterPrometheusremotewriteWalLag               metric.Int64Gauge
 	ExporterPrometheusremotewriteWalReadLatency       metric.Int64Histogram
 	ExporterPrometheusremotewriteWalReads             metric.Int64Counter
 	ExporterPrometheusremotewriteWalReadsFailures     metric.Int64Counter
+	ExporterPrometheusremotewriteWalShards            metric.Int64Gauge
+	ExporterPrometheusremotewriteWalSize              metric.Int64Gauge
+	ExporterPrometheusremotewriteWalTimestampLag      metric.Int64Gauge
 	ExporterPrometheusremotewriteWalWriteLatency      metric.Int64Histogram
 	ExporterPrometheusremotewriteWalWrites            metric.Int64Counter
 	ExporterPrometheusremotewriteWalWritesFailures    metric.Int64Counter
@@ -108,6 +112,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("By"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteWalDroppedSamples, err = builder.meter.Int64Counter(
+		"otelcol_exporter_prometheusremotewrite_wal_dropped_samples",
+		metric.WithDescription("Number of samples read from the WAL which were dropped because they were rejected by the remote write endpoint, or their series is unknown [Development]"),
+		metric.WithUnit("{sample}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ExporterPrometheusremotewriteWalLag, err = builder.meter.Int64Gauge(
 		"otelcol_exporter_prometheusremotewrite_wal_lag",
 		metric.WithDescription("WAL lag [Development]"),
@@ -133,6 +143,24 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteWalShards, err = builder.meter.Int64Gauge(
+		"otelcol_exporter_prometheusremotewrite_wal_shards",
+		metric.WithDescription("Number of shards sending the samples read from the WAL [Development]"),
+		metric.WithUnit("{shard}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteWalSize, err = builder.meter.Int64Gauge(
+		"otelcol_exporter_prometheusremotewrite_wal_size",
+		metric.WithDescription("Size of the WAL on disk [Development]"),
+		metric.WithUnit("By"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterPrometheusremotewriteWalTimestampLag, err = builder.meter.Int64Gauge(
+		"otelcol_exporter_prometheusremotewrite_wal_timestamp_lag",
+		metric.WithDescription("Difference between the highest timestamp written to the WAL and the highest timestamp sent to the remote write endpoint [Development]"),
+		metric.WithUnit("s"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ExporterPrometheusremotewriteWalWriteLatency, err = builder.meter.Int64Histogram(
 		"otelcol_exporter_prometheusremotewrite_wal_write_latency",
 		metric.WithDescription("Response latency in ms for the WAL writes. [Development]"),
@@ -7,6 +7,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"sync"
 	"sync/atomic"
@@ -34,6 +36,10 @@ type prwWalTelemetry interface {
 	recordWALBytesWritten(ctx context.Context, bytes int)
 	recordWALBytesRead(ctx context.Context, bytes int)
 	recordWALLag(ctx context.Context, lag int64)
+	recordWALSize(ctx context.Context, bytes int64)
+	recordWALTimestampLag(ctx context.Context, seconds int64)
+	recordWALShards(ctx context.Context, shards int64)
+	recordWALDroppedSamples(ctx context.Context, samples int64)
 }
 
 type prwWalTelemetryOTel struct {
@@ -77,6 +83,22 @@ func (p *prwWalTelemetryOTel) recordWALLag(ctx context.Context, lag int64) {
 	p.telemetryBuilder.ExporterPrometheusremotewriteWalLag.Record(ctx, lag, metric.WithAttributes(p.otelAttrs...))
 }
 
+func (p *prwWalTelemetryOTel) recordWALSize(ctx context.Context, bytes int64) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteWalSize.Record(ctx, bytes, metric.WithAttributes(p.otelAttrs...))
+}
+
+func (p *prwWalTelemetryOTel) recordWALTimestampLag(ctx context.Context, seconds int64) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteWalTimestampLag.Record(ctx, seconds, metric.WithAttributes(p.otelAttrs...))
+}
+
+func (p *prwWalTelemetryOTel) recordWALShards(ctx context.Context, shards int64) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteWalShards.Record(ctx, shards, metric.WithAttributes(p.otelAttrs...))
+}
+
+func (p *prwWalTelemetryOTel) recordWALDroppedSamples(ctx context.Context, samples int64) {
+	p.telemetryBuilder.ExporterPrometheusremotewriteWalDroppedSamples.Add(ctx, samples, metric.WithAttributes(p.otelAttrs...))
+}
+
 func newPRWWalTelemetry(set exporter.Settings) (prwWalTelemetry, error) {
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
 	if err != nil {
@@ -96,6 +118,12 @@ type prweWAL struct {
 	walPath   string
 
 	exportSink func(ctx context.Context, reqL []*prompb.WriteRequest) error
+	// sendSink makes a single attempt to send a request replayed by the queue.
+	sendSink func(ctx context.Context, req *prompb.WriteRequest, attempt int) error
+
+	// series and queue are only set when the queue is enabled.
+	series *walSeriesWriter
+	queue  *walQueue
 
 	stopOnce  sync.Once
 	stopChan  chan struct{}
@@ -117,6 +145,119 @@ type WALConfig struct {
 	BufferSize         int           `mapstructure:"buffer_size"`
 	TruncateFrequency  time.Duration `mapstructure:"truncate_frequency"`
 	LagRecordFrequency time.Duration `mapstructure:"lag_record_frequency"`
+
+	// Queue replays the WAL into shards, as the Prometheus agent does, instead of
+	// exporting the requests read from the WAL in batches.
+	Queue WALQueueConfig `mapstructure:"queue"`
+}
+
+// WALQueueConfig configures the shards the WAL is replayed into. The labels of the series
+// are only written once to the WAL, followed by the samples referencing them.
+type WALQueueConfig struct {
+	// Enabled replays the WAL into shards. The WAL written with the queue enabled
+	// can't be read with the queue disabled.
+	Enabled bool `mapstructure:"enabled"`
+	// MinShards is the minimum number of shards sending concurrently. Defaults to 1.
+	MinShards int `mapstructure:"min_shards"`
+	// MaxShards is the maximum number of shards sending concurrently. Defaults to 50.
+	MaxShards int `mapstructure:"max_shards"`
+	// Capacity is the number of series buffered by each shard. Defaults to 10000.
+	Capacity int `mapstructure:"capacity"`
+	// MaxSamplesPerSend is the maximum number of samples per request. Defaults to 2000.
+	MaxSamplesPerSend int `mapstructure:"max_samples_per_send"`
+	// BatchSendDeadline is the maximum time the samples wait in a shard. Defaults to 5s.
+	BatchSendDeadline time.Duration `mapstructure:"batch_send_deadline"`
+	// MinBackoff is the initial retry delay of a shard. Defaults to 30ms.
+	MinBackoff time.Duration `mapstructure:"min_backoff"`
+	// MaxBackoff is the maximum retry delay of a shard. Defaults to 5s.
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+	// SeriesTTL is the time after which the series which weren't written anymore are
+	// removed from the WAL checkpoint. Defaults to 1h.
+	SeriesTTL time.Duration `mapstructure:"series_ttl"`
+
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+const (
+	defaultWALQueueMinShards         = 1
+	defaultWALQueueMaxShards         = 50
+	defaultWALQueueCapacity          = 10000
+	defaultWALQueueMaxSamplesPerSend = 2000
+	defaultWALQueueBatchSendDeadline = 5 * time.Second
+	defaultWALQueueMinBackoff        = 30 * time.Millisecond
+	defaultWALQueueMaxBackoff        = 5 * time.Second
+	defaultWALQueueSeriesTTL         = 1 * time.Hour
+)
+
+func (qc *WALQueueConfig) minShards() int {
+	if qc.MinShards > 0 {
+		return qc.MinShards
+	}
+	return defaultWALQueueMinShards
+}
+
+func (qc *WALQueueConfig) maxShards() int {
+	if qc.MaxShards > 0 {
+		return qc.MaxShards
+	}
+	return max(defaultWALQueueMaxShards, qc.minShards())
+}
+
+func (qc *WALQueueConfig) capacity() int {
+	if qc.Capacity > 0 {
+		return qc.Capacity
+	}
+	return defaultWALQueueCapacity
+}
+
+func (qc *WALQueueConfig) maxSamplesPerSend() int {
+	if qc.MaxSamplesPerSend > 0 {
+		return qc.MaxSamplesPerSend
+	}
+	return defaultWALQueueMaxSamplesPerSend
+}
+
+func (qc *WALQueueConfig) batchSendDeadline() time.Duration {
+	if qc.BatchSendDeadline > 0 {
+		return qc.BatchSendDeadline
+	}
+	return defaultWALQueueBatchSendDeadline
+}
+
+func (qc *WALQueueConfig) minBackoff() time.Duration {
+	if qc.MinBackoff > 0 {
+		return qc.MinBackoff
+	}
+	return defaultWALQueueMinBackoff
+}
+
+func (qc *WALQueueConfig) maxBackoff() time.Duration {
+	if qc.MaxBackoff > 0 {
+		return qc.MaxBackoff
+	}
+	return max(defaultWALQueueMaxBackoff, qc.minBackoff())
+}
+
+func (qc *WALQueueConfig) seriesTTL() time.Duration {
+	if qc.SeriesTTL > 0 {
+		return qc.SeriesTTL
+	}
+	return defaultWALQueueSeriesTTL
+}
+
+func (qc *WALQueueConfig) validate() error {
+	if qc.MinShards < 0 || qc.MaxShards < 0 || qc.Capacity < 0 || qc.MaxSamplesPerSend < 0 ||
+		qc.BatchSendDeadline < 0 || qc.MinBackoff < 0 || qc.MaxBackoff < 0 || qc.SeriesTTL < 0 {
+		return errors.New("wal queue settings can't be negative")
+	}
+	if qc.MaxShards > 0 && qc.minShards() > qc.MaxShards {
+		return errors.New("wal queue min_shards can't be greater than max_shards")
+	}
+	if qc.MaxBackoff > 0 && qc.minBackoff() > qc.MaxBackoff {
+		return errors.New("wal queue min_backoff can't be greater than max_backoff")
+	}
+	return nil
 }
 
 func (wc *WALConfig) bufferSize() int {
@@ -152,15 +293,22 @@ func newWAL(walConfig *WALConfig, set exporter.Settings, exportSink func(context
 		return nil, err
 	}
 
-	return &prweWAL{
+	pwal := &prweWAL{
 		exportSink: exportSink,
 		walConfig:  walConfig,
 		stopChan:   make(chan struct{}),
-		rNotify:    make(chan struct{}),
-		rWALIndex:  &atomic.Uint64{},
-		wWALIndex:  &atomic.Uint64{},
-		telemetry:  telemetryPRWWal,
-	}, nil
+		// The notification is buffered so that it isn't lost when the
+		// reader isn't waiting yet.
+		rNotify:   make(chan struct{}, 1),
+		rWALIndex: &atomic.Uint64{},
+		wWALIndex: &atomic.Uint64{},
+		telemetry: telemetryPRWWal,
+	}
+	if walConfig.Queue.Enabled {
+		pwal.series = newWALSeriesWriter()
+		pwal.queue = newWALQueue(pwal, set.Logger)
+	}
+	return pwal, nil
 }
 
 func (wc *WALConfig) createWAL() (*wal.Log, string, error) {
@@ -236,6 +384,30 @@ func (prweWAL *prweWAL) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	if prweWAL.queue != nil {
+		runCtx, cancel := context.WithCancel(ctx)
+		prweWAL.wg.Add(2)
+		go func() {
+			defer prweWAL.wg.Done()
+			defer cancel()
+			select {
+			case <-runCtx.Done():
+			case <-prweWAL.stopChan:
+			}
+		}()
+		go func() {
+			defer prweWAL.wg.Done()
+			defer cancel()
+			prweWAL.recordLagLoop(runCtx)
+		}()
+		if err = prweWAL.queue.start(runCtx, &prweWAL.wg); err != nil {
+			cancel()
+			logger.Error("unable to start write-ahead log queue", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
 	runCtx, cancel := context.WithCancel(ctx)
 
 	// Start the process of exporting but wait until the exporting has started.
@@ -292,8 +464,44 @@ func (prweWAL *prweWAL) recordLagLoop(ctx context.Context) {
 			// In normal state, wIndex and rIndex will differ by one. To avoid having -1 as a final value, we set it to 0 as minimum.
 			lag := max(0, int64(prweWAL.wWALIndex.Load()-prweWAL.rWALIndex.Load()))
 			prweWAL.telemetry.recordWALLag(ctx, lag)
+			if size, err := prweWAL.size(); err == nil {
+				prweWAL.telemetry.recordWALSize(ctx, size)
+			}
+			if prweWAL.queue != nil {
+				prweWAL.telemetry.recordWALTimestampLag(ctx, prweWAL.queue.timestampLag())
+			}
+		}
+	}
+}
+
+// size returns the size in bytes of the WAL on disk, including its checkpoint.
+func (prweWAL *prweWAL) size() (int64, error) {
+	prweWAL.mu.Lock()
+	walPath := prweWAL.walPath
+	prweWAL.mu.Unlock()
+
+	var size int64
+	err := filepath.WalkDir(walPath, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
 		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if info, err := os.Stat(walCheckpointPath(walPath)); err == nil {
+		size += info.Size()
 	}
+	return size, nil
 }
 
 // continuallyPopWALThenExport reads a prompb.WriteRequest proto encoded blob from the WAL, and moves
@@ -422,15 +630,30 @@ func (prweWAL *prweWAL) persistToWAL(ctx context.Context, requests []*prompb.Wri
 
 	// Write all the requests to the WAL in a batch.
 	batch := new(wal.Batch)
-	for _, req := range requests {
-		protoBlob, err := proto.Marshal(req)
-		if err != nil {
-			return err
-		}
+	write := func(protoBlob []byte) {
 		prweWAL.telemetry.recordWALBytesWritten(ctx, len(protoBlob))
 		wIndex := prweWAL.wWALIndex.Add(1)
 		batch.Write(wIndex, protoBlob)
 	}
+	now := time.Now()
+	for _, req := range requests {
+		if prweWAL.series == nil {
+			protoBlob, err := proto.Marshal(req)
+			if err != nil {
+				return err
+			}
+			write(protoBlob)
+			continue
+		}
+		for _, record := range prweWAL.series.records(req, now) {
+			blob, err := record.encode()
+			if err != nil {
+				return err
+			}
+			write(blob)
+		}
+		prweWAL.queue.appended(req)
+	}
 
 	// Notify reader go routine that is possibly waiting for writes.
 	select {
@@ -438,7 +661,59 @@ func (prweWAL *prweWAL) persistToWAL(ctx context.Context, requests []*prompb.Wri
 	default:
 	}
 
-	return prweWAL.wal.WriteBatch(batch)
+	err := prweWAL.wal.WriteBatch(batch)
+	if err != nil && prweWAL.series != nil {
+		// The series written by the failed batch are unknown to the reader,
+		// write all of them again from now on.
+		prweWAL.series.reset()
+	}
+	return err
+}
+
+// writeTombstones removes the series which weren't written for the configured TTL,
+// so that they are removed from the checkpoint.
+func (prweWAL *prweWAL) writeTombstones(ctx context.Context) error {
+	prweWAL.mu.Lock()
+	defer prweWAL.mu.Unlock()
+
+	if prweWAL.wal == nil {
+		return errNilWAL
+	}
+	record, ok := prweWAL.series.gc(time.Now(), prweWAL.walConfig.Queue.seriesTTL())
+	if !ok {
+		return nil
+	}
+	blob, err := record.encode()
+	if err != nil {
+		return err
+	}
+	prweWAL.telemetry.recordWALBytesWritten(ctx, len(blob))
+	if err := prweWAL.wal.Write(prweWAL.wWALIndex.Add(1), blob); err != nil {
+		prweWAL.series.reset()
+		return err
+	}
+	return nil
+}
+
+// truncateFrontTo removes the entries before index, keeping at least the last entry of the WAL.
+func (prweWAL *prweWAL) truncateFrontTo(index uint64) error {
+	prweWAL.mu.Lock()
+	defer prweWAL.mu.Unlock()
+
+	if prweWAL.wal == nil {
+		return errNilWAL
+	}
+	if err := prweWAL.wal.Sync(); err != nil {
+		return err
+	}
+	lastIndex, err := prweWAL.wal.LastIndex()
+	if err != nil {
+		return err
+	}
+	if err := prweWAL.wal.TruncateFront(min(index, lastIndex)); err != nil && !errors.Is(err, wal.ErrOutOfRange) {
+		return err
+	}
+	return nil
 }
 
 func (prweWAL *prweWAL) readPrompbFromWAL(ctx context.Context, index uint64) (wreq *prompb.WriteRequest, err error) {
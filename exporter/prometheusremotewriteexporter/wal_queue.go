@@ -0,0 +1,629 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/tidwall/wal"
+	"go.uber.org/zap"
+)
+
+const (
+	// walShardUpdateInterval is how often the number of shards is computed.
+	walShardUpdateInterval = 10 * time.Second
+	// walShardTolerance is the relative difference with the current number of shards
+	// under which the shards aren't changed.
+	walShardTolerance = 0.3
+	// walBacklogCatchUp is the share of the backlog added to the rate of the samples to send.
+	walBacklogCatchUp = 0.05
+	// walRateSmoothing is the weight of the last interval in the smoothed rates.
+	walRateSmoothing = 0.2
+)
+
+// walQueue replays the records of the WAL into shards, as the remote write queue of the
+// Prometheus agent does. The samples of a series are always sent by the same shard, in
+// the order they were written, and each shard retries its requests independently. The
+// entries of the WAL are truncated once all their samples were sent, after the labels of
+// the series they reference are persisted to a checkpoint.
+type walQueue struct {
+	wal    *prweWAL
+	cfg    *WALQueueConfig
+	logger *zap.Logger
+
+	// series are the labels of the series references, as of the last record read.
+	series    map[uint64]walSeries
+	reshardCh chan int
+
+	mu sync.Mutex // mu protects the fields below.
+	// readIndex is the index of the next record to read.
+	readIndex uint64
+	// checkpoint are the labels of the series references as of checkpointIndex, and
+	// changes the records read since then which change them.
+	checkpoint      map[uint64][]prompb.Label
+	checkpointIndex uint64
+	changes         []walSeriesChange
+	shards          []*walShard
+	shardsWG        sync.WaitGroup
+
+	// Throughput, used to compute the number of shards.
+	samplesIn       atomic.Int64
+	samplesOut      atomic.Int64
+	sendDuration    atomic.Int64
+	highestIn       atomic.Int64
+	highestSent     atomic.Int64
+	lastSendFailure atomic.Int64
+	inRate          float64
+	outRate         float64
+	sendRate        float64
+}
+
+type walSeries struct {
+	labels []prompb.Label
+	hash   uint64
+}
+
+type walSeriesChange struct {
+	index  uint64
+	record walRecord
+}
+
+// walQueueItem is a time series, or a metadata, read from the WAL entry at index.
+type walQueueItem struct {
+	index    uint64
+	series   prompb.TimeSeries
+	metadata *prompb.MetricMetadata
+}
+
+func (item *walQueueItem) samples() int {
+	return len(item.series.Samples) + len(item.series.Histograms)
+}
+
+func newWALQueue(pwal *prweWAL, logger *zap.Logger) *walQueue {
+	return &walQueue{
+		wal:       pwal,
+		cfg:       &pwal.walConfig.Queue,
+		logger:    logger,
+		reshardCh: make(chan int, 1),
+	}
+}
+
+// appended records the samples written to the WAL.
+func (q *walQueue) appended(req *prompb.WriteRequest) {
+	var samples int64
+	for i := range req.Timeseries {
+		ts := &req.Timeseries[i]
+		samples += int64(len(ts.Samples) + len(ts.Histograms))
+		q.highestIn.Store(max(q.highestIn.Load(), highestTimestamp(ts)))
+	}
+	q.samplesIn.Add(samples)
+}
+
+// timestampLag returns the difference, in seconds, between the highest timestamps written to the WAL and sent.
+func (q *walQueue) timestampLag() int64 {
+	return max(0, (q.highestIn.Load()-q.highestSent.Load())/1000)
+}
+
+func highestTimestamp(ts *prompb.TimeSeries) int64 {
+	var highest int64
+	for _, s := range ts.Samples {
+		highest = max(highest, s.Timestamp)
+	}
+	for _, h := range ts.Histograms {
+		highest = max(highest, h.Timestamp)
+	}
+	return highest
+}
+
+// start loads the checkpoint and starts replaying the WAL from it.
+func (q *walQueue) start(ctx context.Context, wg *sync.WaitGroup) error {
+	index, checkpoint, err := readWALCheckpoint(walCheckpointPath(q.wal.walPath))
+	if err != nil {
+		return err
+	}
+	// The entries before the checkpoint may not have been truncated yet, but they were sent.
+	firstIndex := max(q.wal.rWALIndex.Load(), 1)
+	index = max(index, firstIndex)
+	if index > q.wal.wWALIndex.Load()+1 {
+		// The WAL was removed, but not its checkpoint.
+		index = firstIndex
+	}
+	q.wal.rWALIndex.Store(index)
+
+	q.series = make(map[uint64]walSeries, len(checkpoint))
+	for ref, labels := range checkpoint {
+		q.series[ref] = walSeries{labels: labels, hash: labelsHash(labels)}
+	}
+	q.mu.Lock()
+	q.readIndex = index
+	q.checkpoint = checkpoint
+	q.checkpointIndex = index
+	q.changes = nil
+	q.mu.Unlock()
+	q.startShards(ctx, q.cfg.minShards())
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		q.readLoop(ctx)
+		q.stopShards()
+	}()
+	go func() {
+		defer wg.Done()
+		q.truncateLoop(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		q.updateShardsLoop(ctx)
+	}()
+	return nil
+}
+
+// readLoop reads the records of the WAL, in order, into the shards.
+func (q *walQueue) readLoop(ctx context.Context) {
+	for {
+		select {
+		case n := <-q.reshardCh:
+			if !q.reshard(ctx, n) {
+				return
+			}
+		default:
+		}
+
+		index := q.wal.rWALIndex.Load()
+		record, err := q.read(ctx, index)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, errInvalidWALRecord):
+			q.logger.Error("dropping invalid WAL record", zap.Uint64("index", index), zap.Error(err))
+		case err != nil:
+			q.logger.Error("failed to read WAL record", zap.Uint64("index", index), zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(q.cfg.maxBackoff()):
+			}
+			continue
+		default:
+			if !q.process(ctx, index, record) {
+				return
+			}
+		}
+		q.mu.Lock()
+		q.readIndex = index + 1
+		q.mu.Unlock()
+		q.wal.rWALIndex.Store(index + 1)
+	}
+}
+
+// read reads the record at index, waiting for it to be written.
+func (q *walQueue) read(ctx context.Context, index uint64) (walRecord, error) {
+	for {
+		q.wal.mu.Lock()
+		if q.wal.wal == nil {
+			q.wal.mu.Unlock()
+			return walRecord{}, errors.New("attempt to read from closed WAL")
+		}
+		q.wal.telemetry.recordWALReads(ctx)
+		start := time.Now()
+		blob, err := q.wal.wal.Read(index)
+		q.wal.telemetry.recordWALReadLatency(ctx, time.Since(start).Milliseconds())
+		q.wal.telemetry.recordWALBytesRead(ctx, len(blob))
+		var record walRecord
+		if err == nil {
+			// The blob is only valid until the next operation on the WAL.
+			record, err = decodeWALRecord(blob)
+			if err != nil {
+				err = errors.Join(errInvalidWALRecord, err)
+			}
+		}
+		q.wal.mu.Unlock()
+
+		if !errors.Is(err, wal.ErrNotFound) {
+			if err != nil {
+				q.wal.telemetry.recordWALReadsFailures(ctx)
+			}
+			return record, err
+		}
+		// Wait for the record to be written.
+		select {
+		case <-q.wal.rNotify:
+		case <-ctx.Done():
+			return walRecord{}, ctx.Err()
+		}
+	}
+}
+
+// process applies the record to the series, or enqueues its samples into the shards.
+// It returns false if the queue was stopped.
+func (q *walQueue) process(ctx context.Context, index uint64, record walRecord) bool {
+	switch record.typ {
+	case walRecordSeries, walRecordTombstones, walRecordReset:
+		q.applySeriesRecord(record)
+		q.mu.Lock()
+		q.changes = append(q.changes, walSeriesChange{index: index, record: record})
+		q.mu.Unlock()
+		return true
+	case walRecordSamples:
+		var dropped int64
+		for i, ref := range record.refs {
+			ts := record.req.Timeseries[i]
+			series, ok := q.series[ref]
+			if !ok {
+				dropped += int64(len(ts.Samples) + len(ts.Histograms))
+				continue
+			}
+			ts.Labels = series.labels
+			if !q.enqueue(ctx, series.hash, walQueueItem{index: index, series: ts}) {
+				return false
+			}
+		}
+		if dropped > 0 {
+			q.logger.Warn("dropping samples of unknown series", zap.Uint64("index", index), zap.Int64("samples", dropped))
+			q.wal.telemetry.recordWALDroppedSamples(ctx, dropped)
+		}
+	default:
+		for _, ts := range record.req.Timeseries {
+			if !q.enqueue(ctx, labelsHash(ts.Labels), walQueueItem{index: index, series: ts}) {
+				return false
+			}
+		}
+	}
+	for i := range record.req.Metadata {
+		metadata := &record.req.Metadata[i]
+		hash := labelsHash([]prompb.Label{{Value: metadata.MetricFamilyName}})
+		if !q.enqueue(ctx, hash, walQueueItem{index: index, metadata: metadata}) {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *walQueue) applySeriesRecord(record walRecord) {
+	switch record.typ {
+	case walRecordSeries:
+		for i, ref := range record.refs {
+			labels := record.req.Timeseries[i].Labels
+			q.series[ref] = walSeries{labels: labels, hash: labelsHash(labels)}
+		}
+	case walRecordTombstones:
+		for _, ref := range record.refs {
+			delete(q.series, ref)
+		}
+	case walRecordReset:
+		clear(q.series)
+	}
+}
+
+// enqueue enqueues the item into the shard of its series, resharding while the shard is full
+// if needed. It returns false if the queue was stopped.
+func (q *walQueue) enqueue(ctx context.Context, hash uint64, item walQueueItem) bool {
+	for {
+		q.mu.Lock()
+		shard := q.shards[hash%uint64(len(q.shards))]
+		q.mu.Unlock()
+		shard.add(item.index)
+		select {
+		case shard.queue <- item:
+			return true
+		case n := <-q.reshardCh:
+			// The item is enqueued again into the new shards.
+			if !q.reshard(ctx, n) {
+				return false
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// reshard waits for the shards to send their samples, then replaces them with n shards,
+// so that the samples of a series are still sent in order. It returns false if the queue
+// was stopped.
+func (q *walQueue) reshard(ctx context.Context, n int) bool {
+	q.logger.Info("resharding the WAL queue", zap.Int("from", len(q.shards)), zap.Int("to", n))
+	q.stopShards()
+	if ctx.Err() != nil {
+		return false
+	}
+	q.startShards(ctx, n)
+	return true
+}
+
+func (q *walQueue) startShards(ctx context.Context, n int) {
+	shards := make([]*walShard, n)
+	for i := range shards {
+		shards[i] = &walShard{queue: make(chan walQueueItem, q.cfg.capacity())}
+	}
+	q.mu.Lock()
+	q.shards = shards
+	q.mu.Unlock()
+
+	q.shardsWG.Add(n)
+	for _, shard := range shards {
+		go func() {
+			defer q.shardsWG.Done()
+			q.runShard(ctx, shard)
+		}()
+	}
+	q.wal.telemetry.recordWALShards(ctx, int64(n))
+}
+
+// stopShards waits for the shards to send the samples they buffer, unless the queue is stopped.
+// The shards are kept until they're replaced, so that truncate doesn't remove the entries
+// whose samples they're still sending. It may be called again once they're stopped.
+func (q *walQueue) stopShards() {
+	q.mu.Lock()
+	for _, shard := range q.shards {
+		if !shard.stopped {
+			shard.stopped = true
+			close(shard.queue)
+		}
+	}
+	q.mu.Unlock()
+	q.shardsWG.Wait()
+}
+
+func (q *walQueue) runShard(ctx context.Context, shard *walShard) {
+	deadline := q.cfg.batchSendDeadline()
+	maxSamples := q.cfg.maxSamplesPerSend()
+	timer := time.NewTimer(deadline)
+	defer timer.Stop()
+
+	var batch []walQueueItem
+	samples := 0
+	flush := func() bool {
+		if len(batch) > 0 {
+			if !q.sendBatch(ctx, batch) {
+				return false
+			}
+			shard.done(len(batch))
+			batch = batch[:0]
+			samples = 0
+		}
+		timer.Reset(deadline)
+		return true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// The samples which weren't sent are kept in the WAL.
+			return
+		case item, ok := <-shard.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, item)
+			samples += item.samples()
+			if samples >= maxSamples && !flush() {
+				return
+			}
+		case <-timer.C:
+			if !flush() {
+				return
+			}
+		}
+	}
+}
+
+// sendBatch sends the batch, retrying with backoff until it is sent, rejected, or the
+// queue is stopped. It returns false if the queue was stopped before the batch was sent.
+func (q *walQueue) sendBatch(ctx context.Context, batch []walQueueItem) bool {
+	req := &prompb.WriteRequest{}
+	var samples int
+	var highest int64
+	for i := range batch {
+		item := &batch[i]
+		if item.metadata != nil {
+			req.Metadata = append(req.Metadata, *item.metadata)
+			continue
+		}
+		req.Timeseries = append(req.Timeseries, item.series)
+		samples += item.samples()
+		highest = max(highest, highestTimestamp(&item.series))
+	}
+
+	bo := backoff.ExponentialBackOff{
+		InitialInterval:     q.cfg.minBackoff(),
+		RandomizationFactor: backoff.DefaultRandomizationFactor,
+		Multiplier:          backoff.DefaultMultiplier,
+		MaxInterval:         q.cfg.maxBackoff(),
+	}
+	bo.Reset()
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := q.wal.sendSink(ctx, req, attempt)
+		if ctx.Err() != nil {
+			return false
+		}
+		if err == nil {
+			q.samplesOut.Add(int64(samples))
+			q.sendDuration.Add(int64(time.Since(start)))
+			q.highestSent.Store(max(q.highestSent.Load(), highest))
+			return true
+		}
+		var permanent *backoff.PermanentError
+		if errors.As(err, &permanent) {
+			q.logger.Error("dropping samples rejected by the remote write endpoint", zap.Int("samples", samples), zap.Error(err))
+			q.wal.telemetry.recordWALDroppedSamples(ctx, int64(samples))
+			return true
+		}
+		q.lastSendFailure.Store(time.Now().UnixNano())
+		q.logger.Debug("failed to send samples, retrying", zap.Int("attempt", attempt), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(bo.NextBackOff()):
+		}
+	}
+}
+
+// truncateLoop periodically checkpoints and truncates the WAL.
+func (q *walQueue) truncateLoop(ctx context.Context) {
+	ticker := time.NewTicker(q.wal.walConfig.truncateFrequency())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := q.wal.writeTombstones(ctx); err != nil {
+				q.logger.Error("failed to write WAL tombstones", zap.Error(err))
+			}
+			if err := q.truncate(); err != nil {
+				q.logger.Error("failed to truncate WAL", zap.Error(err))
+			}
+		}
+	}
+}
+
+// truncate persists the checkpoint of the first entry whose samples weren't all sent,
+// then truncates the entries before it.
+func (q *walQueue) truncate() error {
+	q.mu.Lock()
+	index := q.readIndex
+	for _, shard := range q.shards {
+		if pending, ok := shard.firstPending(); ok {
+			index = min(index, pending)
+		}
+	}
+	if index <= q.checkpointIndex {
+		q.mu.Unlock()
+		return nil
+	}
+	applied := 0
+	for _, change := range q.changes {
+		if change.index >= index {
+			break
+		}
+		applySeriesRecord(q.checkpoint, change.record)
+		applied++
+	}
+	q.changes = append(q.changes[:0], q.changes[applied:]...)
+	q.checkpointIndex = index
+	checkpoint := maps.Clone(q.checkpoint)
+	q.mu.Unlock()
+
+	if err := writeWALCheckpoint(walCheckpointPath(q.wal.walPath), index, checkpoint); err != nil {
+		return err
+	}
+	return q.wal.truncateFrontTo(index)
+}
+
+// updateShardsLoop periodically computes the number of shards needed to keep up with the
+// samples written to the WAL, and reshards if it changed.
+func (q *walQueue) updateShardsLoop(ctx context.Context) {
+	ticker := time.NewTicker(walShardUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.mu.Lock()
+			current := len(q.shards)
+			q.mu.Unlock()
+			desired := q.desiredShards(current, walShardUpdateInterval)
+			if desired == current {
+				continue
+			}
+			select {
+			case q.reshardCh <- desired:
+			default:
+			}
+		}
+	}
+}
+
+// desiredShards updates the rates of the samples over the last interval, and returns the
+// number of shards needed to send the samples written to the WAL and catch up with the backlog.
+func (q *walQueue) desiredShards(current int, interval time.Duration) int {
+	seconds := interval.Seconds()
+	q.inRate = smoothRate(q.inRate, float64(q.samplesIn.Swap(0))/seconds)
+	q.outRate = smoothRate(q.outRate, float64(q.samplesOut.Swap(0))/seconds)
+	q.sendRate = smoothRate(q.sendRate, time.Duration(q.sendDuration.Swap(0)).Seconds()/seconds)
+
+	// Adding shards doesn't help when the remote write endpoint is failing.
+	if time.Since(time.Unix(0, q.lastSendFailure.Load())) < 2*interval || q.outRate == 0 {
+		return current
+	}
+	timePerSample := q.sendRate / q.outRate
+	backlog := float64(q.timestampLag()) * q.inRate
+	return computeDesiredShards(current, q.cfg.minShards(), q.cfg.maxShards(), timePerSample*(q.inRate+walBacklogCatchUp*backlog))
+}
+
+func smoothRate(rate, last float64) float64 {
+	return walRateSmoothing*last + (1-walRateSmoothing)*rate
+}
+
+// computeDesiredShards returns the number of shards within [minShards, maxShards] closest to
+// desired, unless it is within the tolerance of the current number of shards.
+func computeDesiredShards(current, minShards, maxShards int, desired float64) int {
+	if desired >= float64(current)*(1-walShardTolerance) && desired <= float64(current)*(1+walShardTolerance) {
+		return max(minShards, min(maxShards, current))
+	}
+	return max(minShards, min(maxShards, int(math.Ceil(desired))))
+}
+
+// walShard sends the samples of a subset of the series, in order.
+type walShard struct {
+	queue chan walQueueItem
+	// stopped is set once queue is closed. It is protected by walQueue.mu.
+	stopped bool
+
+	mu sync.Mutex
+	// pending are the indices of the entries of the items enqueued but not sent yet.
+	pending []walPendingEntry
+}
+
+type walPendingEntry struct {
+	index uint64
+	items int
+}
+
+// add records an item of the entry at index as pending, before it is enqueued.
+func (s *walShard) add(index uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.pending); n > 0 && s.pending[n-1].index == index {
+		s.pending[n-1].items++
+		return
+	}
+	s.pending = append(s.pending, walPendingEntry{index: index, items: 1})
+}
+
+// done records the first n pending items as sent.
+func (s *walShard) done(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for n > 0 && len(s.pending) > 0 {
+		sent := min(n, s.pending[0].items)
+		s.pending[0].items -= sent
+		n -= sent
+		if s.pending[0].items == 0 {
+			s.pending = s.pending[1:]
+		}
+	}
+}
+
+// firstPending returns the index of the first entry whose items weren't all sent.
+func (s *walShard) firstPending() (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 {
+		return 0, false
+	}
+	return s.pending[0].index, true
+}
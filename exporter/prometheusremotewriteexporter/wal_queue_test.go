@@ -0,0 +1,320 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter/internal/metadata"
+)
+
+// walQueueSink records the samples sent by the WAL queue, by series name.
+type walQueueSink struct {
+	mu      sync.Mutex
+	samples map[string][]int64
+	// fail returns the error of the request, if any.
+	fail func(req *prompb.WriteRequest, attempt int) error
+}
+
+func newWALQueueSink() *walQueueSink {
+	return &walQueueSink{samples: map[string][]int64{}}
+}
+
+func (s *walQueueSink) send(_ context.Context, req *prompb.WriteRequest, attempt int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail != nil {
+		if err := s.fail(req, attempt); err != nil {
+			return err
+		}
+	}
+	for _, ts := range req.Timeseries {
+		name := seriesName(ts.Labels)
+		for _, sample := range ts.Samples {
+			s.samples[name] = append(s.samples[name], sample.Timestamp)
+		}
+	}
+	return nil
+}
+
+func (s *walQueueSink) setFail(fail func(req *prompb.WriteRequest, attempt int) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = fail
+}
+
+func (s *walQueueSink) received(name string) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int64(nil), s.samples[name]...)
+}
+
+func seriesName(labels []prompb.Label) string {
+	for _, l := range labels {
+		if l.Name == "__name__" {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+func seriesRequest(timestamp int64, names ...string) *prompb.WriteRequest {
+	req := &prompb.WriteRequest{}
+	for _, name := range names {
+		req.Timeseries = append(req.Timeseries, prompb.TimeSeries{
+			Labels:  []prompb.Label{{Name: "__name__", Value: name}},
+			Samples: []prompb.Sample{{Value: 1, Timestamp: timestamp}},
+		})
+	}
+	return req
+}
+
+func startWALQueue(t *testing.T, dir string, sink *walQueueSink, fns ...func(*WALQueueConfig)) *prweWAL {
+	config := &WALConfig{
+		Directory:         dir,
+		TruncateFrequency: 10 * time.Millisecond,
+		Queue: WALQueueConfig{
+			Enabled:           true,
+			MinShards:         2,
+			MaxShards:         2,
+			MaxSamplesPerSend: 10,
+			BatchSendDeadline: 10 * time.Millisecond,
+			MinBackoff:        time.Millisecond,
+			MaxBackoff:        5 * time.Millisecond,
+		},
+	}
+	for _, fn := range fns {
+		fn(&config.Queue)
+	}
+	pwal, err := newWAL(config, exportertest.NewNopSettings(metadata.Type), doNothingExportSink)
+	require.NoError(t, err)
+	pwal.sendSink = sink.send
+	require.NoError(t, pwal.run(contextWithLogger(t.Context(), zap.NewNop())))
+	return pwal
+}
+
+// waitForCheckpoint waits for the WAL to be checkpointed after all its entries.
+func waitForCheckpoint(t *testing.T, pwal *prweWAL) {
+	require.Eventually(t, func() bool {
+		index, _, err := readWALCheckpoint(walCheckpointPath(pwal.walPath))
+		return err == nil && index == pwal.wWALIndex.Load()+1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWALQueue_SendsInOrder(t *testing.T) {
+	sink := newWALQueueSink()
+	// The first attempts of the requests fail, and are retried.
+	sink.fail = func(_ *prompb.WriteRequest, attempt int) error {
+		if attempt == 1 {
+			return errors.New("remote write request failed")
+		}
+		return nil
+	}
+	pwal := startWALQueue(t, t.TempDir(), sink)
+	t.Cleanup(func() {
+		assert.NoError(t, pwal.stop())
+	})
+
+	names := []string{"a", "b", "c", "d", "e"}
+	var want []int64
+	for ts := int64(1); ts <= 50; ts++ {
+		require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(ts, names...)}))
+		want = append(want, ts)
+	}
+
+	for _, name := range names {
+		assert.Eventually(t, func() bool {
+			return len(sink.received(name)) == len(want)
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, want, sink.received(name), "series %s", name)
+	}
+	waitForCheckpoint(t, pwal)
+}
+
+func TestWALQueue_DropsRejectedSamples(t *testing.T) {
+	sink := newWALQueueSink()
+	sink.fail = func(req *prompb.WriteRequest, _ int) error {
+		for _, ts := range req.Timeseries {
+			if seriesName(ts.Labels) == "rejected" {
+				return backoff.Permanent(errors.New("remote write request failed"))
+			}
+		}
+		return nil
+	}
+	pwal := startWALQueue(t, t.TempDir(), sink)
+	t.Cleanup(func() {
+		assert.NoError(t, pwal.stop())
+	})
+
+	// The rejected samples aren't retried, so they don't hold back the truncation.
+	require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(1, "rejected")}))
+	waitForCheckpoint(t, pwal)
+	assert.Empty(t, sink.received("rejected"))
+
+	require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(2, "accepted")}))
+	assert.Eventually(t, func() bool {
+		return len(sink.received("accepted")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	waitForCheckpoint(t, pwal)
+}
+
+func TestWALQueue_ResumesFromCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	sink := newWALQueueSink()
+	pwal := startWALQueue(t, dir, sink)
+
+	require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(1, "a", "b")}))
+	assert.Eventually(t, func() bool {
+		return len(sink.received("a")) == 1 && len(sink.received("b")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	waitForCheckpoint(t, pwal)
+
+	// The samples can't be sent, so they are kept in the WAL. The series were
+	// written before, so only the samples referencing them are written.
+	sink.setFail(func(*prompb.WriteRequest, int) error {
+		return errors.New("remote write request failed")
+	})
+	require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(2, "a", "b")}))
+	// Let the truncation happen while the samples are pending.
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, pwal.stop())
+
+	// The labels of the series are read from the checkpoint.
+	sink = newWALQueueSink()
+	pwal = startWALQueue(t, dir, sink)
+	t.Cleanup(func() {
+		assert.NoError(t, pwal.stop())
+	})
+	assert.Eventually(t, func() bool {
+		return len(sink.received("a")) == 1 && len(sink.received("b")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int64{2}, sink.received("a"))
+	assert.Equal(t, []int64{2}, sink.received("b"))
+}
+
+func TestWALQueue_TruncateWhileResharding(t *testing.T) {
+	sink := newWALQueueSink()
+	release := make(chan struct{})
+	sink.fail = func(*prompb.WriteRequest, int) error {
+		<-release
+		return nil
+	}
+	pwal := startWALQueue(t, t.TempDir(), sink, func(cfg *WALQueueConfig) {
+		cfg.MinShards = 1
+		cfg.MaxShards = 1
+		cfg.Capacity = 1
+		cfg.MaxSamplesPerSend = 1
+	})
+	t.Cleanup(func() {
+		assert.NoError(t, pwal.stop())
+	})
+
+	// The first sample is being sent, the second one is buffered by the shard, and the
+	// third one waits for the shard to have room.
+	require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(1, "a")}))
+	firstSamples := pwal.wWALIndex.Load()
+	require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(2, "a")}))
+	require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(3, "a")}))
+	require.Eventually(t, func() bool {
+		pwal.queue.mu.Lock()
+		defer pwal.queue.mu.Unlock()
+		return pwal.queue.readIndex == pwal.wWALIndex.Load()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The shard is waited for while it sends its samples.
+	pwal.queue.reshardCh <- 1
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, pwal.queue.truncate())
+	index, _, err := readWALCheckpoint(walCheckpointPath(pwal.walPath))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, index, firstSamples)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return len(sink.received("a")) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int64{1, 2, 3}, sink.received("a"))
+	waitForCheckpoint(t, pwal)
+}
+
+func TestWALQueue_StopWhileResharding(t *testing.T) {
+	sink := newWALQueueSink()
+	sink.fail = func(*prompb.WriteRequest, int) error {
+		return errors.New("remote write request failed")
+	}
+	pwal := startWALQueue(t, t.TempDir(), sink, func(cfg *WALQueueConfig) {
+		cfg.MinShards = 1
+		cfg.MaxShards = 1
+	})
+
+	// The shard retries its samples, so the reshard waits for it until the queue is stopped.
+	require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(1, "a")}))
+	pwal.queue.reshardCh <- 1
+	require.NoError(t, pwal.persistToWAL(t.Context(), []*prompb.WriteRequest{seriesRequest(2, "a")}))
+	require.Eventually(t, func() bool {
+		return len(pwal.queue.reshardCh) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	// The shards stopped by the reshard aren't stopped again.
+	require.NoError(t, pwal.stop())
+	assert.Empty(t, sink.received("a"))
+}
+
+func TestComputeDesiredShards(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int
+		desired float64
+		want    int
+	}{
+		{name: "within tolerance", current: 10, desired: 12, want: 10},
+		{name: "scale up", current: 10, desired: 15.5, want: 16},
+		{name: "scale down", current: 10, desired: 4.2, want: 5},
+		{name: "above max shards", current: 10, desired: 100, want: 50},
+		{name: "below min shards", current: 10, desired: 0.1, want: 2},
+		{name: "current below min shards", current: 1, desired: 1, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, computeDesiredShards(tt.current, 2, 50, tt.desired))
+		})
+	}
+}
+
+func TestWALShard_Pending(t *testing.T) {
+	shard := &walShard{}
+	_, ok := shard.firstPending()
+	assert.False(t, ok)
+
+	shard.add(3)
+	shard.add(3)
+	shard.add(5)
+	index, ok := shard.firstPending()
+	require.True(t, ok)
+	assert.Equal(t, uint64(3), index)
+
+	// The entry is pending until all its items are sent.
+	shard.done(1)
+	index, _ = shard.firstPending()
+	assert.Equal(t, uint64(3), index)
+	shard.done(1)
+	index, _ = shard.firstPending()
+	assert.Equal(t, uint64(5), index)
+
+	shard.done(1)
+	_, ok = shard.firstPending()
+	assert.False(t, ok)
+}
@@ -0,0 +1,277 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// walRecordType is the first byte of the records written to the WAL when the queue is enabled.
+// The records written when the queue is disabled are proto encoded prompb.WriteRequests, whose
+// first byte is the tag of a field with a non-zero number, so never one of the types below.
+type walRecordType byte
+
+const (
+	// walRecordWriteRequest is a prompb.WriteRequest, written with the queue disabled.
+	walRecordWriteRequest walRecordType = iota
+	// walRecordSeries defines the labels of series references.
+	walRecordSeries
+	// walRecordSamples holds the samples, histograms and exemplars of series references, and metadata.
+	walRecordSamples
+	// walRecordTombstones removes series references which aren't written anymore.
+	walRecordTombstones
+	// walRecordReset removes all the series references, when the series are written again from scratch.
+	walRecordReset
+)
+
+var errInvalidWALRecord = errors.New("invalid WAL record")
+
+// walRecord is a record of the WAL. Its refs are the series references of the time series of req.
+type walRecord struct {
+	typ  walRecordType
+	refs []uint64
+	req  *prompb.WriteRequest
+}
+
+// encode encodes the record as its type, the number of references, the references
+// as uvarints, and the proto encoded request.
+func (r walRecord) encode() ([]byte, error) {
+	if r.typ == walRecordWriteRequest {
+		return proto.Marshal(r.req)
+	}
+	buf := make([]byte, 0, 1+binary.MaxVarintLen64*(len(r.refs)+1))
+	buf = append(buf, byte(r.typ))
+	buf = binary.AppendUvarint(buf, uint64(len(r.refs)))
+	for _, ref := range r.refs {
+		buf = binary.AppendUvarint(buf, ref)
+	}
+	if r.req == nil {
+		return buf, nil
+	}
+	protoBlob, err := proto.Marshal(r.req)
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, protoBlob...), nil
+}
+
+func decodeWALRecord(blob []byte) (walRecord, error) {
+	record := walRecord{req: new(prompb.WriteRequest)}
+	if len(blob) == 0 || blob[0] == byte(walRecordWriteRequest) || blob[0] > byte(walRecordReset) {
+		// Written with the queue disabled.
+		return record, proto.Unmarshal(blob, record.req)
+	}
+	record.typ = walRecordType(blob[0])
+	blob = blob[1:]
+	n, size := binary.Uvarint(blob)
+	if size <= 0 || n > uint64(len(blob)) {
+		return walRecord{}, errInvalidWALRecord
+	}
+	blob = blob[size:]
+	record.refs = make([]uint64, n)
+	for i := range record.refs {
+		record.refs[i], size = binary.Uvarint(blob)
+		if size <= 0 {
+			return walRecord{}, errInvalidWALRecord
+		}
+		blob = blob[size:]
+	}
+	if err := proto.Unmarshal(blob, record.req); err != nil {
+		return walRecord{}, err
+	}
+	if (record.typ == walRecordSeries || record.typ == walRecordSamples) && len(record.refs) != len(record.req.Timeseries) {
+		return walRecord{}, errInvalidWALRecord
+	}
+	return record, nil
+}
+
+// walSeriesWriter assigns references to the series written to the WAL, so that their
+// labels are only written once.
+type walSeriesWriter struct {
+	refs    map[string]*walSeriesRef
+	nextRef uint64
+	// started is false until the reset record is written.
+	started bool
+}
+
+type walSeriesRef struct {
+	ref       uint64
+	lastWrite time.Time
+}
+
+func newWALSeriesWriter() *walSeriesWriter {
+	return &walSeriesWriter{refs: map[string]*walSeriesRef{}}
+}
+
+// records returns the records writing req: the series which weren't written yet, then their samples.
+func (w *walSeriesWriter) records(req *prompb.WriteRequest, now time.Time) []walRecord {
+	var records []walRecord
+	if !w.started {
+		// The series written before are unknown, so are the references they were assigned.
+		records = append(records, walRecord{typ: walRecordReset})
+		w.started = true
+	}
+
+	series := walRecord{typ: walRecordSeries, req: &prompb.WriteRequest{}}
+	samples := walRecord{
+		typ:  walRecordSamples,
+		refs: make([]uint64, 0, len(req.Timeseries)),
+		req: &prompb.WriteRequest{
+			Timeseries: make([]prompb.TimeSeries, 0, len(req.Timeseries)),
+			Metadata:   req.Metadata,
+		},
+	}
+	for i := range req.Timeseries {
+		ts := &req.Timeseries[i]
+		key := labelsKey(ts.Labels)
+		ref, ok := w.refs[key]
+		if !ok {
+			w.nextRef++
+			ref = &walSeriesRef{ref: w.nextRef}
+			w.refs[key] = ref
+			series.refs = append(series.refs, ref.ref)
+			series.req.Timeseries = append(series.req.Timeseries, prompb.TimeSeries{Labels: ts.Labels})
+		}
+		ref.lastWrite = now
+		samples.refs = append(samples.refs, ref.ref)
+		samples.req.Timeseries = append(samples.req.Timeseries, prompb.TimeSeries{
+			Samples:    ts.Samples,
+			Exemplars:  ts.Exemplars,
+			Histograms: ts.Histograms,
+		})
+	}
+	if len(series.refs) > 0 {
+		records = append(records, series)
+	}
+	return append(records, samples)
+}
+
+// gc returns the tombstones of the series which weren't written since ttl.
+func (w *walSeriesWriter) gc(now time.Time, ttl time.Duration) (walRecord, bool) {
+	tombstones := walRecord{typ: walRecordTombstones}
+	for key, ref := range w.refs {
+		if now.Sub(ref.lastWrite) >= ttl {
+			tombstones.refs = append(tombstones.refs, ref.ref)
+			delete(w.refs, key)
+		}
+	}
+	return tombstones, len(tombstones.refs) > 0
+}
+
+// reset forgets the series written so far, so that they are written again.
+func (w *walSeriesWriter) reset() {
+	clear(w.refs)
+	w.started = false
+}
+
+func labelsKey(labels []prompb.Label) string {
+	var sb strings.Builder
+	for _, l := range labels {
+		sb.WriteString(l.Name)
+		sb.WriteByte(0xff)
+		sb.WriteString(l.Value)
+		sb.WriteByte(0xff)
+	}
+	return sb.String()
+}
+
+func labelsHash(labels []prompb.Label) uint64 {
+	h := fnv.New64a()
+	for _, l := range labels {
+		_, _ = h.Write([]byte(l.Name))
+		_, _ = h.Write([]byte{0xff})
+		_, _ = h.Write([]byte(l.Value))
+		_, _ = h.Write([]byte{0xff})
+	}
+	return h.Sum64()
+}
+
+// applySeriesRecord applies the series, tombstones and reset records to the labels of the series, by reference.
+func applySeriesRecord(series map[uint64][]prompb.Label, record walRecord) {
+	switch record.typ {
+	case walRecordSeries:
+		for i, ref := range record.refs {
+			series[ref] = record.req.Timeseries[i].Labels
+		}
+	case walRecordTombstones:
+		for _, ref := range record.refs {
+			delete(series, ref)
+		}
+	case walRecordReset:
+		clear(series)
+	}
+}
+
+func walCheckpointPath(walPath string) string {
+	return walPath + ".checkpoint"
+}
+
+// writeWALCheckpoint persists the labels of the series referenced by the entries of the WAL
+// from index, so that the entries before index can be truncated.
+func writeWALCheckpoint(path string, index uint64, series map[uint64][]prompb.Label) error {
+	record := walRecord{
+		typ:  walRecordSeries,
+		refs: slices.Sorted(maps.Keys(series)),
+		req:  &prompb.WriteRequest{Timeseries: make([]prompb.TimeSeries, 0, len(series))},
+	}
+	for _, ref := range record.refs {
+		record.req.Timeseries = append(record.req.Timeseries, prompb.TimeSeries{Labels: series[ref]})
+	}
+	blob, err := record.encode()
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(binary.AppendUvarint(nil, index)); err == nil {
+		_, err = f.Write(blob)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if errC := f.Close(); err == nil {
+		err = errC
+	}
+	if err != nil {
+		return fmt.Errorf("prometheusremotewriteexporter: failed to write WAL checkpoint: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// readWALCheckpoint returns the index and the series of the checkpoint, or 0 when there is no checkpoint.
+func readWALCheckpoint(path string) (uint64, map[uint64][]prompb.Label, error) {
+	series := map[uint64][]prompb.Label{}
+	blob, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, series, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	index, size := binary.Uvarint(blob)
+	if size <= 0 {
+		return 0, nil, fmt.Errorf("prometheusremotewriteexporter: failed to read WAL checkpoint: %w", errInvalidWALRecord)
+	}
+	record, err := decodeWALRecord(blob[size:])
+	if err != nil {
+		return 0, nil, fmt.Errorf("prometheusremotewriteexporter: failed to read WAL checkpoint: %w", err)
+	}
+	applySeriesRecord(series, record)
+	return index, series, nil
+}
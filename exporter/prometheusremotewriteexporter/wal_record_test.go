@@ -0,0 +1,230 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewriteexporter
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWALRecordEncodeDecode(t *testing.T) {
+	labels := []prompb.Label{{Name: "__name__", Value: "test_metric"}}
+	tests := []struct {
+		name   string
+		record walRecord
+	}{
+		{
+			name:   "reset",
+			record: walRecord{typ: walRecordReset, req: &prompb.WriteRequest{}},
+		},
+		{
+			name: "series",
+			record: walRecord{
+				typ:  walRecordSeries,
+				refs: []uint64{1, 300},
+				req: &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+					{Labels: labels},
+					{Labels: []prompb.Label{{Name: "__name__", Value: "other_metric"}}},
+				}},
+			},
+		},
+		{
+			name: "samples",
+			record: walRecord{
+				typ:  walRecordSamples,
+				refs: []uint64{300},
+				req: &prompb.WriteRequest{
+					Timeseries: []prompb.TimeSeries{{Samples: []prompb.Sample{{Value: 1, Timestamp: 100}}}},
+					Metadata:   []prompb.MetricMetadata{{MetricFamilyName: "test_metric", Type: prompb.MetricMetadata_GAUGE}},
+				},
+			},
+		},
+		{
+			name:   "tombstones",
+			record: walRecord{typ: walRecordTombstones, refs: []uint64{1}, req: &prompb.WriteRequest{}},
+		},
+		{
+			name: "write request",
+			record: walRecord{
+				typ: walRecordWriteRequest,
+				req: &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+					{Labels: labels, Samples: []prompb.Sample{{Value: 1, Timestamp: 100}}},
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blob, err := tt.record.encode()
+			require.NoError(t, err)
+			got, err := decodeWALRecord(blob)
+			require.NoError(t, err)
+			assert.Equal(t, tt.record.typ, got.typ)
+			assert.Equal(t, len(tt.record.refs), len(got.refs))
+			for i, ref := range tt.record.refs {
+				assert.Equal(t, ref, got.refs[i])
+			}
+			assert.Equal(t, tt.record.req, got.req)
+		})
+	}
+}
+
+func TestDecodeWALRecord_WrittenWithoutQueue(t *testing.T) {
+	req := &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+		{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "test_metric"}},
+			Samples: []prompb.Sample{{Value: 1, Timestamp: 100}},
+		},
+	}}
+	blob, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	record, err := decodeWALRecord(blob)
+	require.NoError(t, err)
+	assert.Equal(t, walRecordWriteRequest, record.typ)
+	assert.Equal(t, req, record.req)
+}
+
+func TestDecodeWALRecord_Invalid(t *testing.T) {
+	mismatched, err := walRecord{
+		typ:  walRecordSamples,
+		refs: []uint64{1, 2},
+		req:  &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{{Samples: []prompb.Sample{{Value: 1}}}}},
+	}.encode()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		blob []byte
+	}{
+		{name: "missing refs", blob: []byte{byte(walRecordSeries)}},
+		{name: "truncated refs", blob: []byte{byte(walRecordTombstones), 3, 1}},
+		{name: "refs mismatch", blob: mismatched},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeWALRecord(tt.blob)
+			assert.ErrorIs(t, err, errInvalidWALRecord)
+		})
+	}
+}
+
+func TestWALSeriesWriter(t *testing.T) {
+	seriesA := []prompb.Label{{Name: "__name__", Value: "a"}}
+	seriesB := []prompb.Label{{Name: "__name__", Value: "b"}}
+	req := func(series ...[]prompb.Label) *prompb.WriteRequest {
+		wr := &prompb.WriteRequest{}
+		for i, labels := range series {
+			wr.Timeseries = append(wr.Timeseries, prompb.TimeSeries{
+				Labels:  labels,
+				Samples: []prompb.Sample{{Value: float64(i), Timestamp: 100}},
+			})
+		}
+		return wr
+	}
+	types := func(records []walRecord) []walRecordType {
+		var typs []walRecordType
+		for _, record := range records {
+			typs = append(typs, record.typ)
+		}
+		return typs
+	}
+
+	w := newWALSeriesWriter()
+	now := time.Unix(1000, 0)
+
+	// The first records reset the series.
+	records := w.records(req(seriesA), now)
+	require.Equal(t, []walRecordType{walRecordReset, walRecordSeries, walRecordSamples}, types(records))
+	assert.Equal(t, []uint64{1}, records[1].refs)
+	assert.Equal(t, seriesA, records[1].req.Timeseries[0].Labels)
+	assert.Equal(t, []uint64{1}, records[2].refs)
+	assert.Nil(t, records[2].req.Timeseries[0].Labels)
+	assert.Equal(t, []prompb.Sample{{Value: 0, Timestamp: 100}}, records[2].req.Timeseries[0].Samples)
+
+	// Only the new series are written.
+	records = w.records(req(seriesA, seriesB), now.Add(time.Minute))
+	require.Equal(t, []walRecordType{walRecordSeries, walRecordSamples}, types(records))
+	assert.Equal(t, []uint64{2}, records[0].refs)
+	assert.Equal(t, seriesB, records[0].req.Timeseries[0].Labels)
+	assert.Equal(t, []uint64{1, 2}, records[1].refs)
+
+	records = w.records(req(seriesB), now.Add(2*time.Minute))
+	require.Equal(t, []walRecordType{walRecordSamples}, types(records))
+	assert.Equal(t, []uint64{2}, records[0].refs)
+
+	// The series which weren't written since the TTL are removed.
+	_, ok := w.gc(now.Add(3*time.Minute), 5*time.Minute)
+	assert.False(t, ok)
+	tombstones, ok := w.gc(now.Add(3*time.Minute), 90*time.Second)
+	require.True(t, ok)
+	assert.Equal(t, walRecordTombstones, tombstones.typ)
+	assert.Equal(t, []uint64{1}, tombstones.refs)
+
+	// A removed series is written again with a new reference.
+	records = w.records(req(seriesA), now.Add(3*time.Minute))
+	require.Equal(t, []walRecordType{walRecordSeries, walRecordSamples}, types(records))
+	assert.Equal(t, []uint64{3}, records[0].refs)
+
+	// All the series are written again after a reset.
+	w.reset()
+	records = w.records(req(seriesB), now.Add(4*time.Minute))
+	require.Equal(t, []walRecordType{walRecordReset, walRecordSeries, walRecordSamples}, types(records))
+	assert.Equal(t, seriesB, records[1].req.Timeseries[0].Labels)
+}
+
+func TestApplySeriesRecord(t *testing.T) {
+	series := map[uint64][]prompb.Label{}
+	labels := []prompb.Label{{Name: "__name__", Value: "a"}}
+
+	applySeriesRecord(series, walRecord{
+		typ:  walRecordSeries,
+		refs: []uint64{1, 2},
+		req:  &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{{Labels: labels}, {Labels: labels}}},
+	})
+	assert.Len(t, series, 2)
+
+	applySeriesRecord(series, walRecord{typ: walRecordTombstones, refs: []uint64{1}})
+	assert.Equal(t, map[uint64][]prompb.Label{2: labels}, series)
+
+	// The samples don't change the series.
+	applySeriesRecord(series, walRecord{typ: walRecordSamples, refs: []uint64{3}, req: &prompb.WriteRequest{}})
+	assert.Len(t, series, 1)
+
+	applySeriesRecord(series, walRecord{typ: walRecordReset})
+	assert.Empty(t, series)
+}
+
+func TestWALCheckpoint(t *testing.T) {
+	path := walCheckpointPath(filepath.Join(t.TempDir(), "prom_remotewrite"))
+
+	index, series, err := readWALCheckpoint(path)
+	require.NoError(t, err)
+	assert.Zero(t, index)
+	assert.Empty(t, series)
+
+	want := map[uint64][]prompb.Label{
+		1: {{Name: "__name__", Value: "a"}},
+		7: {{Name: "__name__", Value: "b"}, {Name: "job", Value: "test"}},
+	}
+	require.NoError(t, writeWALCheckpoint(path, 42, want))
+	index, series, err = readWALCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), index)
+	assert.Equal(t, want, series)
+
+	// The checkpoint is replaced.
+	require.NoError(t, writeWALCheckpoint(path, 43, map[uint64][]prompb.Label{}))
+	index, series, err = readWALCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(43), index)
+	assert.Empty(t, series)
+	assert.NoFileExists(t, path+".tmp")
+}
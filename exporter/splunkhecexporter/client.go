@@ -24,6 +24,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
 	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
 )
@@ -57,6 +58,7 @@ type client struct {
 	bufferPool        bufferPool
 	exporterName      string
 	meter             metric.Meter
+	deadLetter        *deadletter.Writer
 }
 
 func newClient(set exporter.Settings, cfg *Config, maxContentLength uint) *client {
@@ -68,6 +70,7 @@ func newClient(set exporter.Settings, cfg *Config, maxContentLength uint) *clien
 		bufferPool:        newBufferPool(maxContentLength, !cfg.DisableCompression),
 		exporterName:      set.ID.String(),
 		meter:             metadata.Meter(set.TelemetrySettings),
+		deadLetter:        deadletter.NewWriter(cfg.DeadLetter, set.ID, set.Logger),
 	}
 }
 
@@ -604,15 +607,19 @@ func subTraces(src ptrace.Traces, state iterState) ptrace.Traces {
 	return dst
 }
 
-func (c *client) stop(context.Context) error {
+func (c *client) stop(ctx context.Context) error {
 	c.wg.Wait()
 	if c.heartbeater != nil {
 		c.heartbeater.shutdown()
 	}
-	return nil
+	return c.deadLetter.Shutdown(ctx)
 }
 
 func (c *client) start(ctx context.Context, host component.Host) (err error) {
+	if err = c.deadLetter.Start(ctx, host); err != nil {
+		return fmt.Errorf("%s: failed to start the dead-letter storage: %w", c.exporterName, err)
+	}
+
 	httpClient, err := buildHTTPClient(ctx, c.config, host, c.telemetrySettings)
 	if err != nil {
 		return err
@@ -6,6 +6,7 @@ package splunkhecexporter
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -36,6 +37,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
 )
 
@@ -1704,6 +1707,38 @@ func Test_pushLogData_ShouldReturnUnsentLogsOnly(t *testing.T) {
 	assert.Equal(t, logs.ResourceLogs().At(1), logsErr.Data().ResourceLogs().At(0))
 }
 
+func Test_pushLogData_DeadLetter(t *testing.T) {
+	storageExt := storagetest.NewFileBackedStorageExtension("dead_letter", t.TempDir())
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.DeadLetter.Storage = &storageExt.ID
+
+	url := &url.URL{Scheme: "http", Host: "splunk"}
+	set := exportertest.NewNopSettings(metadata.Type)
+	c := newLogsClient(set, config)
+	require.NoError(t, c.deadLetter.Start(t.Context(), storagetest.NewStorageHost().WithExtension(storageExt.ID, storageExt)))
+
+	httpClient, _ := newTestClient(400, "NOK")
+	c.hecWorker = &defaultHecWorker{url, httpClient, buildHTTPHeaders(config, component.NewDefaultBuildInfo()), zap.NewNop()}
+
+	logs := createLogData(1, 1, 1)
+	err := c.deadLetter.PushLogs(c.pushLogData)(t.Context(), logs)
+	require.True(t, consumererror.IsPermanent(err), "Expecting permanent error")
+	require.NoError(t, c.stop(t.Context()))
+
+	client, err := storageExt.GetClient(t.Context(), component.KindExporter, set.ID, "dead_letter")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, client.Close(context.Background())) }()
+	value, err := client.Get(t.Context(), "item_0")
+	require.NoError(t, err)
+	var item deadletter.Item
+	require.NoError(t, json.Unmarshal(value, &item))
+	assert.Equal(t, "logs", item.Signal)
+	assert.Contains(t, item.Reason, "400")
+	rejected, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(item.Body)
+	require.NoError(t, err)
+	assert.Equal(t, logs, rejected)
+}
+
 func Test_pushLogData_ShouldAddHeadersForProfilingData(t *testing.T) {
 	config := NewFactory().CreateDefaultConfig().(*Config)
 
@@ -17,6 +17,7 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
 )
 
@@ -140,6 +141,10 @@ type Config struct {
 
 	// Telemetry is the configuration for splunk hec exporter telemetry
 	Telemetry HecTelemetry `mapstructure:"telemetry"`
+
+	// DeadLetter configures the storage extension where the data which is rejected by
+	// Splunk HEC is written, along with the reason of the rejection.
+	DeadLetter deadletter.Config `mapstructure:"dead_letter"`
 }
 
 func (cfg *Config) Unmarshal(conf *confmap.Conf) error {
@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
 )
 
@@ -34,6 +35,7 @@ func TestLoadConfig(t *testing.T) {
 	defaultCfg := createDefaultConfig().(*Config)
 	defaultCfg.Token = "00000000-0000-0000-0000-0000000000000"
 	defaultCfg.Endpoint = "https://splunk:8088/services/collector"
+	storageID := component.MustNewID("file_storage")
 
 	hundred := 100
 	idleConnTimeout := 10 * time.Second
@@ -124,6 +126,10 @@ func TestLoadConfig(t *testing.T) {
 						"customKey": "customVal",
 					},
 				},
+				DeadLetter: deadletter.Config{
+					Storage:  &storageID,
+					MaxItems: 100,
+				},
 			},
 		},
 	}
@@ -17,6 +17,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchperresourceattr"
 	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
@@ -93,6 +94,7 @@ func createDefaultConfig() component.Config {
 			OverrideMetricsNames: map[string]string{},
 			ExtraAttributes:      map[string]string{},
 		},
+		DeadLetter: deadletter.NewDefaultConfig(),
 	}
 }
 
@@ -109,7 +111,7 @@ func createTracesExporter(
 		ctx,
 		set,
 		cfg,
-		c.pushTraceData,
+		c.deadLetter.PushTraces(c.pushTraceData),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
 		exporterhelper.WithRetry(cfg.BackOffConfig),
@@ -142,7 +144,7 @@ func createMetricsExporter(
 		ctx,
 		set,
 		cfg,
-		c.pushMetricsData,
+		c.deadLetter.PushMetrics(c.pushMetricsData),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
 		exporterhelper.WithRetry(cfg.BackOffConfig),
@@ -175,7 +177,7 @@ func createLogsExporter(
 		ctx,
 		set,
 		cfg,
-		c.pushLogData,
+		c.deadLetter.PushLogs(c.pushLogData),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
 		exporterhelper.WithRetry(cfg.BackOffConfig),
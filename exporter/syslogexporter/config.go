@@ -5,13 +5,16 @@ package syslogexporter // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configoptional"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
 )
 
 var (
@@ -20,6 +23,8 @@ var (
 	errUnsupportedNetwork  = errors.New("unsupported network: network is required, only tcp/udp/unix supported")
 	errUnsupportedProtocol = errors.New("unsupported protocol: Only rfc5424 and rfc3164 supported")
 	errOctetCounting       = errors.New("octet counting is only supported for rfc5424 protocol")
+	errStructuredData      = errors.New("structured data is only supported for rfc5424 protocol")
+	errMessageAndCEF       = errors.New("message and cef mappings are mutually exclusive")
 )
 
 // Config defines configuration for Syslog exporter.
@@ -41,6 +46,10 @@ type Config struct {
 	// TLS struct exposes TLS client configuration.
 	TLS configtls.ClientConfig `mapstructure:"tls"`
 
+	// Mapping defines how the fields of the syslog messages are built from the log records.
+	// By default, they are read from the attributes of the log records.
+	Mapping MappingConfig `mapstructure:"mapping"`
+
 	QueueSettings             configoptional.Optional[exporterhelper.QueueBatchConfig] `mapstructure:"sending_queue"`
 	configretry.BackOffConfig `mapstructure:"retry_on_failure"`
 	TimeoutSettings           exporterhelper.TimeoutConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -76,6 +85,14 @@ func (cfg *Config) Validate() error {
 		invalidFields = append(invalidFields, errOctetCounting)
 	}
 
+	if len(cfg.Mapping.StructuredData) > 0 && cfg.Protocol != protocolRFC5424Str {
+		invalidFields = append(invalidFields, errStructuredData)
+	}
+
+	if err := cfg.Mapping.validate(); err != nil {
+		invalidFields = append(invalidFields, err)
+	}
+
 	if len(invalidFields) > 0 {
 		return errors.Join(invalidFields...)
 	}
@@ -83,6 +100,87 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+// MappingConfig defines OTTL value expressions, evaluated in the log context, building the fields
+// of the syslog messages. The fields without expression are read from the attributes of the log records.
+type MappingConfig struct {
+	// Priority is the PRI of the message.
+	Priority string `mapstructure:"priority"`
+	// Version is the VERSION of the message, only used by rfc5424.
+	Version string `mapstructure:"version"`
+	// Hostname is the HOSTNAME of the message.
+	Hostname string `mapstructure:"hostname"`
+	// Appname is the APP-NAME of the message, or the TAG for rfc3164.
+	Appname string `mapstructure:"appname"`
+	// ProcID is the PROCID of the message, only used by rfc5424.
+	ProcID string `mapstructure:"proc_id"`
+	// MsgID is the MSGID of the message, only used by rfc5424.
+	MsgID string `mapstructure:"msg_id"`
+	// Message is the MSG of the message.
+	Message string `mapstructure:"message"`
+	// StructuredData are the SD-ELEMENTs of the message, only used by rfc5424.
+	StructuredData []StructuredDataElementConfig `mapstructure:"structured_data"`
+	// CEF formats the MSG of the message as an ArcSight Common Event Format event.
+	CEF configoptional.Optional[CEFConfig] `mapstructure:"cef"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// StructuredDataElementConfig defines an SD-ELEMENT of the messages.
+type StructuredDataElementConfig struct {
+	// ID is the SD-ID of the element, such as `origin` or `exampleSDID@32473`.
+	ID string `mapstructure:"id"`
+	// Params are the OTTL value expressions of the SD-PARAMs of the element, by name.
+	// The params are written in the order of their names, and the ones evaluating to nil are omitted.
+	Params map[string]string `mapstructure:"params"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// CEFConfig defines the OTTL value expressions of the fields of Common Event Format events.
+type CEFConfig struct {
+	DeviceVendor  string `mapstructure:"device_vendor"`
+	DeviceProduct string `mapstructure:"device_product"`
+	DeviceVersion string `mapstructure:"device_version"`
+	// SignatureID is the Device Event Class ID of the events.
+	SignatureID string `mapstructure:"signature_id"`
+	Name        string `mapstructure:"name"`
+	// Severity is the importance of the events, from 0 to 10.
+	Severity string `mapstructure:"severity"`
+	// Extensions are the key-value pairs of the events, by key.
+	// The extensions are written in the order of their keys, and the ones evaluating to nil are omitted.
+	Extensions map[string]string `mapstructure:"extensions"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+func (cfg *MappingConfig) validate() error {
+	var errs []error
+	if cfg.Message != "" && cfg.CEF.HasValue() {
+		errs = append(errs, errMessageAndCEF)
+	}
+	for _, element := range cfg.StructuredData {
+		if !isSDName(element.ID) {
+			errs = append(errs, fmt.Errorf("invalid structured data ID %q", element.ID))
+		}
+		for name := range element.Params {
+			if !isSDName(name) {
+				errs = append(errs, fmt.Errorf("invalid structured data param name %q in %q", name, element.ID))
+			}
+		}
+	}
+	if cef := cfg.CEF.Get(); cef != nil {
+		for key := range cef.Extensions {
+			if !isCEFKey(key) {
+				errs = append(errs, fmt.Errorf("invalid cef extension key %q", key))
+			}
+		}
+	}
+	if _, err := newMapper(cfg, component.TelemetrySettings{Logger: zap.NewNop()}); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
 const (
 	// Syslog Network
 	DefaultNetwork = string(confignet.TransportTypeTCP)
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/configoptional"
 )
 
 func TestValidate(t *testing.T) {
@@ -65,6 +66,51 @@ func TestValidate(t *testing.T) {
 			},
 			err: "invalid endpoint: endpoint is required but it is not configured",
 		},
+		{
+			name: "structured data with rfc3164",
+			cfg: &Config{
+				Port:     514,
+				Endpoint: "host.domain.com",
+				Network:  "udp",
+				Protocol: "rfc3164",
+				Mapping: MappingConfig{
+					StructuredData: []StructuredDataElementConfig{{ID: "origin", Params: map[string]string{"software": `"otelcol"`}}},
+				},
+			},
+			err: "structured data is only supported for rfc5424 protocol",
+		},
+		{
+			name: "invalid structured data",
+			cfg: &Config{
+				Port:     514,
+				Endpoint: "host.domain.com",
+				Network:  "udp",
+				Protocol: "rfc5424",
+				Mapping: MappingConfig{
+					StructuredData: []StructuredDataElementConfig{{ID: "my origin", Params: map[string]string{"a=b": `"otelcol"`}}},
+				},
+			},
+			err: `invalid structured data ID "my origin"` + "\n" +
+				`invalid structured data param name "a=b" in "my origin"`,
+		},
+		{
+			name: "message and cef",
+			cfg: &Config{
+				Port:     514,
+				Endpoint: "host.domain.com",
+				Network:  "udp",
+				Protocol: "rfc5424",
+				Mapping: MappingConfig{
+					Message: "body",
+					CEF: configoptional.Some(CEFConfig{
+						Name:       `"Login failed"`,
+						Extensions: map[string]string{"source-ip": `attributes["ip"]`},
+					}),
+				},
+			},
+			err: "message and cef mappings are mutually exclusive" + "\n" +
+				`invalid cef extension key "source-ip"`,
+		},
 	}
 	for _, testInstance := range tests {
 		t.Run(testInstance.name, func(t *testing.T) {
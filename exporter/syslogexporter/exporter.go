@@ -23,6 +23,7 @@ type syslogexporter struct {
 	logger    *zap.Logger
 	tlsConfig *tls.Config
 	formatter formatter
+	mapper    *mapper
 }
 
 func initExporter(cfg *Config, createSettings exporter.Settings) (*syslogexporter, error) {
@@ -35,11 +36,17 @@ func initExporter(cfg *Config, createSettings exporter.Settings) (*syslogexporte
 		}
 	}
 
+	m, err := newMapper(&cfg.Mapping, createSettings.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+
 	s := &syslogexporter{
 		config:    cfg,
 		logger:    createSettings.Logger,
 		tlsConfig: loadedTLSConfig,
 		formatter: createFormatter(cfg.Protocol, cfg.EnableOctetCounting),
+		mapper:    m,
 	}
 
 	s.logger.Info("Syslog Exporter configured",
@@ -92,7 +99,11 @@ func (se *syslogexporter) exportBatch(ctx context.Context, logs plog.Logs) error
 			scopeLogs := resourceLogs.ScopeLogs().At(j)
 			for k := 0; k < scopeLogs.LogRecords().Len(); k++ {
 				logRecord := scopeLogs.LogRecords().At(k)
-				formatted := se.formatter.format(logRecord)
+				formatted, err := se.format(ctx, resourceLogs, scopeLogs, logRecord)
+				if err != nil {
+					se.logger.Warn("Failed to map the log record, dropping it", zap.Error(err))
+					continue
+				}
 				payload.WriteString(formatted)
 			}
 		}
@@ -129,7 +140,11 @@ func (se *syslogexporter) exportNonBatch(ctx context.Context, logs plog.Logs) er
 			droppedScopeLogs := droppedResourceLogs.ScopeLogs().AppendEmpty()
 			for k := 0; k < scopeLogs.LogRecords().Len(); k++ {
 				logRecord := scopeLogs.LogRecords().At(k)
-				formatted := se.formatter.format(logRecord)
+				formatted, mapErr := se.format(ctx, resourceLogs, scopeLogs, logRecord)
+				if mapErr != nil {
+					se.logger.Warn("Failed to map the log record, dropping it", zap.Error(mapErr))
+					continue
+				}
 				err = sender.Write(ctx, formatted)
 				if err != nil {
 					errs = append(errs, err)
@@ -147,3 +162,15 @@ func (se *syslogexporter) exportNonBatch(ctx context.Context, logs plog.Logs) er
 
 	return nil
 }
+
+// format formats the log record, built from the mapping if any.
+func (se *syslogexporter) format(ctx context.Context, resourceLogs plog.ResourceLogs, scopeLogs plog.ScopeLogs, logRecord plog.LogRecord) (string, error) {
+	if se.mapper == nil {
+		return se.formatter.format(logRecord), nil
+	}
+	mapped, err := se.mapper.apply(ctx, resourceLogs, scopeLogs, logRecord)
+	if err != nil {
+		return "", err
+	}
+	return se.formatter.format(mapped), nil
+}
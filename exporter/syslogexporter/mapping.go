@@ -0,0 +1,263 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package syslogexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/syslogexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+type valueExpression = *ottl.ValueExpression[*ottllog.TransformContext]
+
+// mapper evaluates the expressions of the mapping into the attributes read by the formatters.
+type mapper struct {
+	fields         []fieldMapping
+	structuredData []structuredDataMapping
+	cef            *cefMapping
+}
+
+type fieldMapping struct {
+	attribute  string
+	expression valueExpression
+}
+
+type paramMapping struct {
+	name       string
+	expression valueExpression
+}
+
+type structuredDataMapping struct {
+	id     string
+	params []paramMapping
+}
+
+type cefMapping struct {
+	// header are the expressions of the fields of the header, in order.
+	header     []valueExpression
+	extensions []paramMapping
+}
+
+// newMapper parses the expressions of the mapping. It returns a nil mapper when there is nothing to map.
+func newMapper(cfg *MappingConfig, settings component.TelemetrySettings) (*mapper, error) {
+	parser, err := ottllog.NewParser(ottlfuncs.StandardConverters[*ottllog.TransformContext](), settings)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	parse := func(expression string) valueExpression {
+		if expression == "" {
+			return nil
+		}
+		parsed, err := parser.ParseValueExpression(expression)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse expression %q: %w", expression, err))
+		}
+		return parsed
+	}
+	parseParams := func(params map[string]string) []paramMapping {
+		mappings := make([]paramMapping, 0, len(params))
+		for _, name := range slices.Sorted(maps.Keys(params)) {
+			mappings = append(mappings, paramMapping{name: name, expression: parse(params[name])})
+		}
+		return mappings
+	}
+
+	m := &mapper{}
+	for _, field := range []struct {
+		attribute  string
+		expression string
+	}{
+		{priority, cfg.Priority},
+		{version, cfg.Version},
+		{hostname, cfg.Hostname},
+		{app, cfg.Appname},
+		{pid, cfg.ProcID},
+		{msgID, cfg.MsgID},
+		{message, cfg.Message},
+	} {
+		if field.expression != "" {
+			m.fields = append(m.fields, fieldMapping{attribute: field.attribute, expression: parse(field.expression)})
+		}
+	}
+	for _, element := range cfg.StructuredData {
+		m.structuredData = append(m.structuredData, structuredDataMapping{id: element.ID, params: parseParams(element.Params)})
+	}
+	if cef := cfg.CEF.Get(); cef != nil {
+		m.cef = &cefMapping{
+			header: []valueExpression{
+				parse(cef.DeviceVendor),
+				parse(cef.DeviceProduct),
+				parse(cef.DeviceVersion),
+				parse(cef.SignatureID),
+				parse(cef.Name),
+				parse(cef.Severity),
+			},
+			extensions: parseParams(cef.Extensions),
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(m.fields) == 0 && len(m.structuredData) == 0 && m.cef == nil {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// apply returns a copy of the timestamp and attributes of the log record, along with
+// the attributes built from the mapping.
+func (m *mapper) apply(ctx context.Context, resourceLogs plog.ResourceLogs, scopeLogs plog.ScopeLogs, logRecord plog.LogRecord) (plog.LogRecord, error) {
+	mapped := plog.NewLogRecord()
+	mapped.SetTimestamp(logRecord.Timestamp())
+	logRecord.Attributes().CopyTo(mapped.Attributes())
+
+	tCtx := ottllog.NewTransformContextPtr(resourceLogs, scopeLogs, logRecord)
+	defer tCtx.Close()
+
+	for _, field := range m.fields {
+		value, ok, err := evalString(ctx, tCtx, field.expression)
+		if err != nil {
+			return plog.LogRecord{}, fmt.Errorf("failed to map %s: %w", field.attribute, err)
+		}
+		if ok {
+			mapped.Attributes().PutStr(field.attribute, value)
+		}
+	}
+
+	if len(m.structuredData) > 0 {
+		sd := mapped.Attributes().PutEmptyMap(structuredData)
+		for _, element := range m.structuredData {
+			params := sd.PutEmptyMap(element.id)
+			for _, param := range element.params {
+				value, ok, err := evalString(ctx, tCtx, param.expression)
+				if err != nil {
+					return plog.LogRecord{}, fmt.Errorf("failed to map structured data %s %s: %w", element.id, param.name, err)
+				}
+				if ok {
+					params.PutStr(param.name, value)
+				}
+			}
+		}
+	}
+
+	if m.cef != nil {
+		formatted, err := m.cef.format(ctx, tCtx)
+		if err != nil {
+			return plog.LogRecord{}, fmt.Errorf("failed to map cef: %w", err)
+		}
+		mapped.Attributes().PutStr(message, formatted)
+	}
+	return mapped, nil
+}
+
+// format formats a Common Event Format event, such as
+// `CEF:0|Vendor|Product|1.0|100|Name|5|src=10.0.0.1 msg=Hello`.
+func (m *cefMapping) format(ctx context.Context, tCtx *ottllog.TransformContext) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("CEF:0")
+	for _, expression := range m.header {
+		value, _, err := evalString(ctx, tCtx, expression)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte('|')
+		sb.WriteString(cefHeaderEscaper.Replace(value))
+	}
+	sb.WriteByte('|')
+	first := true
+	for _, extension := range m.extensions {
+		value, ok, err := evalString(ctx, tCtx, extension.expression)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		if !first {
+			sb.WriteByte(' ')
+		}
+		first = false
+		sb.WriteString(extension.name)
+		sb.WriteByte('=')
+		sb.WriteString(cefExtensionEscaper.Replace(value))
+	}
+	return sb.String(), nil
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+// evalString evaluates the expression as a string. It returns false when the expression
+// is unset or evaluates to nil.
+func evalString(ctx context.Context, tCtx *ottllog.TransformContext, expression valueExpression) (string, bool, error) {
+	if expression == nil {
+		return "", false, nil
+	}
+	value, err := expression.Eval(ctx, tCtx)
+	if err != nil || value == nil {
+		return "", false, err
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true, nil
+	case pcommon.Value:
+		return v.AsString(), true, nil
+	case pcommon.Map:
+		converted := pcommon.NewValueMap()
+		v.CopyTo(converted.Map())
+		return converted.AsString(), true, nil
+	case pcommon.Slice:
+		converted := pcommon.NewValueSlice()
+		v.CopyTo(converted.Slice())
+		return converted.AsString(), true, nil
+	}
+	converted := pcommon.NewValueEmpty()
+	if err := converted.FromRaw(value); err != nil {
+		return fmt.Sprint(value), true, nil
+	}
+	return converted.AsString(), true, nil
+}
+
+// isSDName reports whether name is a valid SD-NAME of RFC 5424: 1 to 32 printable
+// US-ASCII characters, except '=', ' ', ']' and '"'.
+func isSDName(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, c := range []byte(name) {
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			return false
+		}
+	}
+	return true
+}
+
+// isCEFKey reports whether key is a valid key of a CEF extension, made of alphanumeric characters.
+func isCEFKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
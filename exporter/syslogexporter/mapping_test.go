@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package syslogexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configoptional"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func newTestLogs() (plog.ResourceLogs, plog.ScopeLogs, plog.LogRecord) {
+	resourceLogs := plog.NewResourceLogs()
+	resourceLogs.Resource().Attributes().PutStr("host.name", "mymachine.example.com")
+	resourceLogs.Resource().Attributes().PutStr("service.name", "su")
+	scopeLogs := resourceLogs.ScopeLogs().AppendEmpty()
+	logRecord := scopeLogs.LogRecords().AppendEmpty()
+	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC)))
+	logRecord.SetSeverityNumber(plog.SeverityNumberWarn)
+	logRecord.Body().SetStr("'su root' failed for lonvick on /dev/pts/8")
+	logRecord.Attributes().PutStr("user", "lonvick")
+	logRecord.Attributes().PutInt("pid", 42)
+	logRecord.Attributes().PutStr("reason", "bad\npassword=1")
+	return resourceLogs, scopeLogs, logRecord
+}
+
+func formatWithMapping(t *testing.T, protocol string, cfg *MappingConfig) string {
+	m, err := newMapper(cfg, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	se := &syslogexporter{formatter: createFormatter(protocol, false), mapper: m}
+
+	resourceLogs, scopeLogs, logRecord := newTestLogs()
+	formatted, err := se.format(t.Context(), resourceLogs, scopeLogs, logRecord)
+	require.NoError(t, err)
+	return formatted
+}
+
+func TestMappingRFC5424(t *testing.T) {
+	formatted := formatWithMapping(t, protocolRFC5424Str, &MappingConfig{
+		Priority: `Int(severity_number) + 8`,
+		Hostname: `resource.attributes["host.name"]`,
+		Appname:  `resource.attributes["service.name"]`,
+		ProcID:   `attributes["pid"]`,
+		MsgID:    `"auth"`,
+		Message:  `body`,
+		StructuredData: []StructuredDataElementConfig{
+			{ID: "origin", Params: map[string]string{"software": `"otelcol"`, "ip": `resource.attributes["host.ip"]`}},
+			{ID: "auth@32473", Params: map[string]string{"user": `attributes["user"]`, "realm": `"a]b"`}},
+		},
+	})
+	assert.Equal(t, `<21>1 2003-10-11T22:14:15.003Z mymachine.example.com su 42 auth [origin software="otelcol"][auth@32473 realm="a\]b" user="lonvick"] 'su root' failed for lonvick on /dev/pts/8`+"\n", formatted)
+}
+
+func TestMappingRFC3164(t *testing.T) {
+	formatted := formatWithMapping(t, protocolRFC3164Str, &MappingConfig{
+		Hostname: `resource.attributes["host.name"]`,
+		Appname:  `Format("%s[%d]", [resource.attributes["service.name"], attributes["pid"]])`,
+		Message:  `Concat(["user=", attributes["user"], " ", body], "")`,
+	})
+	assert.Equal(t, "<165>Oct 11 22:14:15 mymachine.example.com su[42]: user=lonvick 'su root' failed for lonvick on /dev/pts/8\n", formatted)
+}
+
+func TestMappingCEF(t *testing.T) {
+	formatted := formatWithMapping(t, protocolRFC5424Str, &MappingConfig{
+		CEF: configoptional.Some(CEFConfig{
+			DeviceVendor:  `"Acme|Corp"`,
+			DeviceProduct: `resource.attributes["service.name"]`,
+			DeviceVersion: `"1.0"`,
+			SignatureID:   `"100"`,
+			Name:          `"Login failed"`,
+			Severity:      `severity_number`,
+			Extensions: map[string]string{
+				"suser":   `attributes["user"]`,
+				"msg":     `body`,
+				"reason":  `attributes["reason"]`,
+				"missing": `attributes["missing"]`,
+			},
+		}),
+	})
+	assert.Equal(t, `<165>1 2003-10-11T22:14:15.003Z - - - - - CEF:0|Acme\|Corp|su|1.0|100|Login failed|13|msg='su root' failed for lonvick on /dev/pts/8 reason=bad\npassword\=1 suser=lonvick`+"\n", formatted)
+}
+
+func TestMappingEmpty(t *testing.T) {
+	m, err := newMapper(&MappingConfig{}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestMappingInvalidExpression(t *testing.T) {
+	_, err := newMapper(&MappingConfig{Hostname: `resource.attributes[`}, componenttest.NewNopTelemetrySettings())
+	assert.ErrorContains(t, err, `failed to parse expression "resource.attributes["`)
+}
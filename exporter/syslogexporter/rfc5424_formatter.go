@@ -80,24 +80,25 @@ func (*rfc5424Formatter) formatStructuredData(logRecord plog.LogRecord) string {
 	}
 
 	var sdBuilder strings.Builder
-	for key, val := range structuredDataAttributeValue.Map().AsRaw() {
-		sdElements := []string{key}
-		vval, ok := val.(map[string]any)
-		if !ok {
+	for key, val := range structuredDataAttributeValue.Map().All() {
+		if val.Type() != pcommon.ValueTypeMap {
 			continue
 		}
-		for k, v := range vval {
-			vv, ok := v.(string)
-			if !ok {
+		sdElements := []string{key}
+		for k, v := range val.Map().All() {
+			if v.Type() != pcommon.ValueTypeStr {
 				continue
 			}
-			sdElements = append(sdElements, fmt.Sprintf("%s=%q", k, vv))
+			sdElements = append(sdElements, fmt.Sprintf("%s=\"%s\"", k, sdParamValueEscaper.Replace(v.Str())))
 		}
 		sdBuilder.WriteString(fmt.Sprint(sdElements))
 	}
 	return sdBuilder.String()
 }
 
+// sdParamValueEscaper escapes the characters which must be escaped in a PARAM-VALUE.
+var sdParamValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
 func (*rfc5424Formatter) formatMessage(logRecord plog.LogRecord) string {
 	formatted := getAttributeValueOrDefault(logRecord, message, emptyMessage)
 	if formatted != "" {
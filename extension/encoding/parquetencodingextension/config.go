@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquetencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/parquetencodingextension"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	CompressionNone   = "none"
+	CompressionSnappy = "snappy"
+	CompressionGzip   = "gzip"
+	CompressionZstd   = "zstd"
+)
+
+const (
+	// FromAny looks up the attribute in the attributes of the record, then of the resource, then of the scope.
+	FromAny      = ""
+	FromRecord   = "record"
+	FromResource = "resource"
+	FromScope    = "scope"
+)
+
+type Config struct {
+	// Compression is the codec used to compress the columns: none, snappy (default), gzip or zstd.
+	Compression string `mapstructure:"compression"`
+
+	// PromotedAttributes are attributes which are also written to their own columns,
+	// so that queries don't have to look them up in the attribute maps.
+	PromotedAttributes []PromotedAttributeConfig `mapstructure:"promoted_attributes"`
+
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// PromotedAttributeConfig describes an attribute written to its own column.
+type PromotedAttributeConfig struct {
+	// Key is the key of the attribute.
+	Key string `mapstructure:"key"`
+
+	// Column is the name of the column. It defaults to the key with dots replaced by underscores.
+	Column string `mapstructure:"column"`
+
+	// From is where the attribute is looked up: record, resource or scope.
+	// By default, the attributes of the record are used, then the ones of the resource and of the scope.
+	// For metrics, the record is the data point.
+	From string `mapstructure:"from"`
+}
+
+func (c PromotedAttributeConfig) column() string {
+	if c.Column != "" {
+		return c.Column
+	}
+	return strings.ReplaceAll(c.Key, ".", "_")
+}
+
+func (c *Config) Validate() error {
+	switch c.Compression {
+	case CompressionNone, CompressionSnappy, CompressionGzip, CompressionZstd:
+	default:
+		return fmt.Errorf("invalid compression %q", c.Compression)
+	}
+
+	columns := map[string]bool{}
+	for _, p := range c.PromotedAttributes {
+		if p.Key == "" {
+			return errors.New("promoted attribute without key")
+		}
+		switch p.From {
+		case FromAny, FromRecord, FromResource, FromScope:
+		default:
+			return fmt.Errorf("invalid from %q for promoted attribute %q", p.From, p.Key)
+		}
+		column := p.column()
+		if column == "" || strings.ContainsAny(column, ",\"") {
+			return fmt.Errorf("invalid column %q of promoted attribute %q", column, p.Key)
+		}
+		if reservedColumns[column] {
+			return fmt.Errorf("column %q of promoted attribute %q is reserved", column, p.Key)
+		}
+		if columns[column] {
+			return fmt.Errorf("duplicate column %q", column)
+		}
+		columns[column] = true
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquetencodingextension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *Config
+		expectedErr string
+	}{
+		{
+			name: "default",
+			cfg:  createDefaultConfig().(*Config),
+		},
+		{
+			name: "promoted attributes",
+			cfg: &Config{
+				Compression: CompressionZstd,
+				PromotedAttributes: []PromotedAttributeConfig{
+					{Key: "service.name", From: FromResource},
+					{Key: "http.route", Column: "route"},
+				},
+			},
+		},
+		{
+			name:        "invalid compression",
+			cfg:         &Config{Compression: "lzo"},
+			expectedErr: `invalid compression "lzo"`,
+		},
+		{
+			name: "missing key",
+			cfg: &Config{
+				Compression:        CompressionNone,
+				PromotedAttributes: []PromotedAttributeConfig{{Column: "route"}},
+			},
+			expectedErr: "promoted attribute without key",
+		},
+		{
+			name: "invalid from",
+			cfg: &Config{
+				Compression:        CompressionNone,
+				PromotedAttributes: []PromotedAttributeConfig{{Key: "service.name", From: "span"}},
+			},
+			expectedErr: `invalid from "span" for promoted attribute "service.name"`,
+		},
+		{
+			name: "reserved column",
+			cfg: &Config{
+				Compression:        CompressionNone,
+				PromotedAttributes: []PromotedAttributeConfig{{Key: "name"}},
+			},
+			expectedErr: `column "name" of promoted attribute "name" is reserved`,
+		},
+		{
+			name: "duplicate column",
+			cfg: &Config{
+				Compression: CompressionNone,
+				PromotedAttributes: []PromotedAttributeConfig{
+					{Key: "service.name"},
+					{Key: "service_name"},
+				},
+			},
+			expectedErr: `duplicate column "service_name"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
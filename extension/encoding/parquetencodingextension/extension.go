@@ -0,0 +1,289 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquetencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/parquetencodingextension"
+
+import (
+	"context"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding"
+)
+
+var (
+	_ encoding.LogsMarshalerExtension    = (*parquetExtension)(nil)
+	_ encoding.TracesMarshalerExtension  = (*parquetExtension)(nil)
+	_ encoding.MetricsMarshalerExtension = (*parquetExtension)(nil)
+)
+
+// parquetExtension marshals each batch of telemetry into a Parquet file with one row per log record, span or data point.
+type parquetExtension struct {
+	codec   compress.Codec
+	logs    *rowSchema
+	spans   *rowSchema
+	metrics *rowSchema
+}
+
+func newExtension(config *Config) *parquetExtension {
+	var codec compress.Codec
+	switch config.Compression {
+	case CompressionNone:
+		codec = &parquet.Uncompressed
+	case CompressionGzip:
+		codec = &parquet.Gzip
+	case CompressionZstd:
+		codec = &parquet.Zstd
+	default:
+		codec = &parquet.Snappy
+	}
+	return &parquetExtension{
+		codec:   codec,
+		logs:    newRowSchema("logs", logRow{}, config.PromotedAttributes),
+		spans:   newRowSchema("spans", spanRow{}, config.PromotedAttributes),
+		metrics: newRowSchema("data_points", dataPointRow{}, config.PromotedAttributes),
+	}
+}
+
+func (e *parquetExtension) MarshalLogs(ld plog.Logs) ([]byte, error) {
+	w := e.logs.newWriter(e.codec)
+	for _, rl := range ld.ResourceLogs().All() {
+		resource := rl.Resource()
+		for _, sl := range rl.ScopeLogs().All() {
+			scope := sl.Scope()
+			for _, lr := range sl.LogRecords().All() {
+				row := logRow{
+					TimeUnixNano:           int64(lr.Timestamp()),
+					ObservedTimeUnixNano:   int64(lr.ObservedTimestamp()),
+					SeverityNumber:         int32(lr.SeverityNumber()),
+					SeverityText:           lr.SeverityText(),
+					Body:                   lr.Body().AsString(),
+					EventName:              lr.EventName(),
+					TraceID:                lr.TraceID().String(),
+					SpanID:                 lr.SpanID().String(),
+					Flags:                  uint32(lr.Flags()),
+					Attributes:             attributesMap(lr.Attributes()),
+					DroppedAttributesCount: lr.DroppedAttributesCount(),
+					ResourceAttributes:     attributesMap(resource.Attributes()),
+					ResourceSchemaURL:      rl.SchemaUrl(),
+					ScopeName:              scope.Name(),
+					ScopeVersion:           scope.Version(),
+					ScopeAttributes:        attributesMap(scope.Attributes()),
+					ScopeSchemaURL:         sl.SchemaUrl(),
+				}
+				scopes := attributeScopes{record: lr.Attributes(), resource: resource.Attributes(), scope: scope.Attributes()}
+				if err := w.write(row, scopes); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return w.close()
+}
+
+func (e *parquetExtension) MarshalTraces(td ptrace.Traces) ([]byte, error) {
+	w := e.spans.newWriter(e.codec)
+	for _, rs := range td.ResourceSpans().All() {
+		resource := rs.Resource()
+		for _, ss := range rs.ScopeSpans().All() {
+			scope := ss.Scope()
+			for _, span := range ss.Spans().All() {
+				row := spanRow{
+					TraceID:                span.TraceID().String(),
+					SpanID:                 span.SpanID().String(),
+					ParentSpanID:           span.ParentSpanID().String(),
+					TraceState:             span.TraceState().AsRaw(),
+					Name:                   span.Name(),
+					Kind:                   span.Kind().String(),
+					StartTimeUnixNano:      int64(span.StartTimestamp()),
+					EndTimeUnixNano:        int64(span.EndTimestamp()),
+					DurationNano:           int64(span.EndTimestamp()) - int64(span.StartTimestamp()),
+					StatusCode:             span.Status().Code().String(),
+					StatusMessage:          span.Status().Message(),
+					Flags:                  span.Flags(),
+					Attributes:             attributesMap(span.Attributes()),
+					DroppedAttributesCount: span.DroppedAttributesCount(),
+					ResourceAttributes:     attributesMap(resource.Attributes()),
+					ResourceSchemaURL:      rs.SchemaUrl(),
+					ScopeName:              scope.Name(),
+					ScopeVersion:           scope.Version(),
+					ScopeAttributes:        attributesMap(scope.Attributes()),
+					ScopeSchemaURL:         ss.SchemaUrl(),
+				}
+				for _, event := range span.Events().All() {
+					row.Events = append(row.Events, spanEvent{
+						TimeUnixNano: int64(event.Timestamp()),
+						Name:         event.Name(),
+						Attributes:   attributesMap(event.Attributes()),
+					})
+				}
+				for _, link := range span.Links().All() {
+					row.Links = append(row.Links, spanLink{
+						TraceID:    link.TraceID().String(),
+						SpanID:     link.SpanID().String(),
+						TraceState: link.TraceState().AsRaw(),
+						Attributes: attributesMap(link.Attributes()),
+					})
+				}
+				scopes := attributeScopes{record: span.Attributes(), resource: resource.Attributes(), scope: scope.Attributes()}
+				if err := w.write(row, scopes); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return w.close()
+}
+
+func (e *parquetExtension) MarshalMetrics(md pmetric.Metrics) ([]byte, error) {
+	w := e.metrics.newWriter(e.codec)
+	for _, rm := range md.ResourceMetrics().All() {
+		resource := rm.Resource()
+		for _, sm := range rm.ScopeMetrics().All() {
+			scope := sm.Scope()
+			for _, metric := range sm.Metrics().All() {
+				base := dataPointRow{
+					MetricName:         metric.Name(),
+					MetricDescription:  metric.Description(),
+					MetricUnit:         metric.Unit(),
+					MetricType:         metric.Type().String(),
+					ResourceAttributes: attributesMap(resource.Attributes()),
+					ResourceSchemaURL:  rm.SchemaUrl(),
+					ScopeName:          scope.Name(),
+					ScopeVersion:       scope.Version(),
+					ScopeAttributes:    attributesMap(scope.Attributes()),
+					ScopeSchemaURL:     sm.SchemaUrl(),
+				}
+				write := func(row dataPointRow, attrs pcommon.Map) error {
+					row.Attributes = attributesMap(attrs)
+					return w.write(row, attributeScopes{record: attrs, resource: resource.Attributes(), scope: scope.Attributes()})
+				}
+				if err := marshalDataPoints(metric, base, write); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return w.close()
+}
+
+// marshalDataPoints writes a row for each data point of a metric.
+func marshalDataPoints(metric pmetric.Metric, base dataPointRow, write func(dataPointRow, pcommon.Map) error) error {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		for _, dp := range metric.Gauge().DataPoints().All() {
+			if err := write(numberDataPointRow(base, dp), dp.Attributes()); err != nil {
+				return err
+			}
+		}
+	case pmetric.MetricTypeSum:
+		sum := metric.Sum()
+		base.AggregationTemporality = ptr(sum.AggregationTemporality().String())
+		base.IsMonotonic = ptr(sum.IsMonotonic())
+		for _, dp := range sum.DataPoints().All() {
+			if err := write(numberDataPointRow(base, dp), dp.Attributes()); err != nil {
+				return err
+			}
+		}
+	case pmetric.MetricTypeHistogram:
+		histogram := metric.Histogram()
+		base.AggregationTemporality = ptr(histogram.AggregationTemporality().String())
+		for _, dp := range histogram.DataPoints().All() {
+			row := base
+			row.StartTimeUnixNano = int64(dp.StartTimestamp())
+			row.TimeUnixNano = int64(dp.Timestamp())
+			row.Flags = uint32(dp.Flags())
+			row.Count = ptr(dp.Count())
+			if dp.HasSum() {
+				row.Sum = ptr(dp.Sum())
+			}
+			if dp.HasMin() {
+				row.Min = ptr(dp.Min())
+			}
+			if dp.HasMax() {
+				row.Max = ptr(dp.Max())
+			}
+			row.BucketCounts = dp.BucketCounts().AsRaw()
+			row.ExplicitBounds = dp.ExplicitBounds().AsRaw()
+			if err := write(row, dp.Attributes()); err != nil {
+				return err
+			}
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		histogram := metric.ExponentialHistogram()
+		base.AggregationTemporality = ptr(histogram.AggregationTemporality().String())
+		for _, dp := range histogram.DataPoints().All() {
+			row := base
+			row.StartTimeUnixNano = int64(dp.StartTimestamp())
+			row.TimeUnixNano = int64(dp.Timestamp())
+			row.Flags = uint32(dp.Flags())
+			row.Count = ptr(dp.Count())
+			if dp.HasSum() {
+				row.Sum = ptr(dp.Sum())
+			}
+			if dp.HasMin() {
+				row.Min = ptr(dp.Min())
+			}
+			if dp.HasMax() {
+				row.Max = ptr(dp.Max())
+			}
+			row.Scale = ptr(dp.Scale())
+			row.ZeroCount = ptr(dp.ZeroCount())
+			row.PositiveOffset = ptr(dp.Positive().Offset())
+			row.PositiveBucketCounts = dp.Positive().BucketCounts().AsRaw()
+			row.NegativeOffset = ptr(dp.Negative().Offset())
+			row.NegativeBucketCounts = dp.Negative().BucketCounts().AsRaw()
+			if err := write(row, dp.Attributes()); err != nil {
+				return err
+			}
+		}
+	case pmetric.MetricTypeSummary:
+		for _, dp := range metric.Summary().DataPoints().All() {
+			row := base
+			row.StartTimeUnixNano = int64(dp.StartTimestamp())
+			row.TimeUnixNano = int64(dp.Timestamp())
+			row.Flags = uint32(dp.Flags())
+			row.Count = ptr(dp.Count())
+			row.Sum = ptr(dp.Sum())
+			for _, q := range dp.QuantileValues().All() {
+				row.QuantileValues = append(row.QuantileValues, quantileValue{Quantile: q.Quantile(), Value: q.Value()})
+			}
+			if err := write(row, dp.Attributes()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func numberDataPointRow(base dataPointRow, dp pmetric.NumberDataPoint) dataPointRow {
+	row := base
+	row.StartTimeUnixNano = int64(dp.StartTimestamp())
+	row.TimeUnixNano = int64(dp.Timestamp())
+	row.Flags = uint32(dp.Flags())
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeDouble:
+		row.ValueDouble = ptr(dp.DoubleValue())
+	case pmetric.NumberDataPointValueTypeInt:
+		row.ValueInt = ptr(dp.IntValue())
+	}
+	return row
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func (*parquetExtension) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (*parquetExtension) Shutdown(context.Context) error {
+	return nil
+}
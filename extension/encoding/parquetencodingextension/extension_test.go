@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquetencodingextension
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func readRows[T any](t *testing.T, data []byte) []T {
+	rows, err := parquet.Read[T](bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	return rows
+}
+
+func TestMarshalLogs(t *testing.T) {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("test")
+	lr := sl.LogRecords().AppendEmpty()
+	lr.SetTimestamp(pcommon.Timestamp(1_700_000_000_000_000_000))
+	lr.SetSeverityNumber(plog.SeverityNumberWarn)
+	lr.SetSeverityText("WARN")
+	lr.Body().SetStr("disk almost full")
+	lr.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	lr.Attributes().PutInt("disk.usage", 93)
+	lr.Attributes().PutEmptySlice("tags").AppendEmpty().SetStr("a")
+	sl.LogRecords().AppendEmpty().Body().SetEmptyMap().PutStr("msg", "hello")
+
+	ext := newExtension(createDefaultConfig().(*Config))
+	data, err := ext.MarshalLogs(ld)
+	require.NoError(t, err)
+
+	rows := readRows[logRow](t, data)
+	require.Len(t, rows, 2)
+	row := rows[0]
+	assert.Equal(t, int64(1_700_000_000_000_000_000), row.TimeUnixNano)
+	assert.Equal(t, int32(plog.SeverityNumberWarn), row.SeverityNumber)
+	assert.Equal(t, "WARN", row.SeverityText)
+	assert.Equal(t, "disk almost full", row.Body)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", row.TraceID)
+	assert.Empty(t, row.SpanID)
+	assert.Equal(t, map[string]string{"disk.usage": "93", "tags": `["a"]`}, row.Attributes)
+	assert.Equal(t, map[string]string{"service.name": "checkout"}, row.ResourceAttributes)
+	assert.Equal(t, "test", row.ScopeName)
+	assert.Empty(t, row.ScopeAttributes)
+	assert.JSONEq(t, `{"msg":"hello"}`, rows[1].Body)
+	assert.Empty(t, rows[1].TraceID)
+}
+
+func TestMarshalTraces(t *testing.T) {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	span.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetName("GET /cart")
+	span.SetKind(ptrace.SpanKindServer)
+	span.SetStartTimestamp(1000)
+	span.SetEndTimestamp(3500)
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Attributes().PutStr("http.route", "/cart")
+	event := span.Events().AppendEmpty()
+	event.SetName("exception")
+	event.SetTimestamp(2000)
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(pcommon.TraceID{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	link.SetSpanID(pcommon.SpanID{8, 7, 6, 5, 4, 3, 2, 1})
+
+	ext := newExtension(createDefaultConfig().(*Config))
+	data, err := ext.MarshalTraces(td)
+	require.NoError(t, err)
+
+	rows := readRows[spanRow](t, data)
+	require.Len(t, rows, 1)
+	row := rows[0]
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", row.TraceID)
+	assert.Equal(t, "0102030405060708", row.SpanID)
+	assert.Empty(t, row.ParentSpanID)
+	assert.Equal(t, "GET /cart", row.Name)
+	assert.Equal(t, "Server", row.Kind)
+	assert.Equal(t, int64(2500), row.DurationNano)
+	assert.Equal(t, "Error", row.StatusCode)
+	assert.Equal(t, map[string]string{"http.route": "/cart"}, row.Attributes)
+	require.Len(t, row.Events, 1)
+	assert.Equal(t, int64(2000), row.Events[0].TimeUnixNano)
+	assert.Equal(t, "exception", row.Events[0].Name)
+	require.Len(t, row.Links, 1)
+	assert.Equal(t, "100f0e0d0c0b0a090807060504030201", row.Links[0].TraceID)
+	assert.Equal(t, "0807060504030201", row.Links[0].SpanID)
+}
+
+func TestMarshalMetrics(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("temperature")
+	gauge.SetUnit("Cel")
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(21.5)
+
+	sum := metrics.AppendEmpty()
+	sum.SetName("requests")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.Sum().DataPoints().AppendEmpty()
+	dp.SetIntValue(42)
+	dp.Attributes().PutStr("http.route", "/cart")
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	histogram.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	hdp := histogram.Histogram().DataPoints().AppendEmpty()
+	hdp.SetCount(3)
+	hdp.SetSum(0.6)
+	hdp.BucketCounts().FromRaw([]uint64{1, 2})
+	hdp.ExplicitBounds().FromRaw([]float64{0.25})
+
+	summary := metrics.AppendEmpty()
+	summary.SetName("gc")
+	sdp := summary.SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetCount(10)
+	sdp.SetSum(5)
+	q := sdp.QuantileValues().AppendEmpty()
+	q.SetQuantile(0.99)
+	q.SetValue(1.5)
+
+	ext := newExtension(createDefaultConfig().(*Config))
+	data, err := ext.MarshalMetrics(md)
+	require.NoError(t, err)
+
+	rows := readRows[dataPointRow](t, data)
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, "Gauge", rows[0].MetricType)
+	assert.Equal(t, "Cel", rows[0].MetricUnit)
+	assert.Equal(t, ptr(21.5), rows[0].ValueDouble)
+	assert.Nil(t, rows[0].ValueInt)
+	assert.Nil(t, rows[0].AggregationTemporality)
+
+	assert.Equal(t, "Sum", rows[1].MetricType)
+	assert.Equal(t, ptr(int64(42)), rows[1].ValueInt)
+	assert.Equal(t, ptr("Cumulative"), rows[1].AggregationTemporality)
+	assert.Equal(t, ptr(true), rows[1].IsMonotonic)
+	assert.Equal(t, map[string]string{"http.route": "/cart"}, rows[1].Attributes)
+
+	assert.Equal(t, "Histogram", rows[2].MetricType)
+	assert.Equal(t, ptr(uint64(3)), rows[2].Count)
+	assert.Equal(t, ptr(0.6), rows[2].Sum)
+	assert.Nil(t, rows[2].Min)
+	assert.Equal(t, []uint64{1, 2}, rows[2].BucketCounts)
+	assert.Equal(t, []float64{0.25}, rows[2].ExplicitBounds)
+
+	assert.Equal(t, "Summary", rows[3].MetricType)
+	assert.Equal(t, []quantileValue{{Quantile: 0.99, Value: 1.5}}, rows[3].QuantileValues)
+}
+
+func TestPromotedAttributes(t *testing.T) {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	rl.Resource().Attributes().PutStr("http.route", "/resource")
+	lrs := rl.ScopeLogs().AppendEmpty().LogRecords()
+	lrs.AppendEmpty().Attributes().PutStr("http.route", "/cart")
+	lrs.AppendEmpty().Attributes().PutInt("service.name", 1)
+
+	cfg := &Config{
+		Compression: CompressionGzip,
+		PromotedAttributes: []PromotedAttributeConfig{
+			{Key: "service.name", From: FromResource},
+			{Key: "http.route", Column: "route"},
+			{Key: "user.id"},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	ext := newExtension(cfg)
+	data, err := ext.MarshalLogs(ld)
+	require.NoError(t, err)
+
+	file, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	for _, column := range []string{"service_name", "route", "user_id"} {
+		_, ok := file.Schema().Lookup(column)
+		assert.True(t, ok, column)
+	}
+
+	type promotedRow struct {
+		Body        string  `parquet:"body"`
+		ServiceName *string `parquet:"service_name"`
+		Route       *string `parquet:"route"`
+		UserID      *string `parquet:"user_id"`
+	}
+	rows := readRows[promotedRow](t, data)
+	require.Len(t, rows, 2)
+	assert.Equal(t, ptr("checkout"), rows[0].ServiceName)
+	assert.Equal(t, ptr("/cart"), rows[0].Route)
+	assert.Nil(t, rows[0].UserID)
+	assert.Equal(t, ptr("checkout"), rows[1].ServiceName)
+	assert.Equal(t, ptr("/resource"), rows[1].Route)
+}
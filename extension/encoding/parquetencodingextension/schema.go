@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parquetencodingextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/parquetencodingextension"
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// The rows below are the stable schema of the Parquet files. Columns may be added, but
+// existing columns must not be renamed or change type, since files written by different
+// versions end up in the same tables. Times are nanoseconds since the Unix epoch, and
+// attribute values are converted to strings, with maps and slices encoded as JSON.
+
+type logRow struct {
+	TimeUnixNano           int64             `parquet:"time_unix_nano"`
+	ObservedTimeUnixNano   int64             `parquet:"observed_time_unix_nano"`
+	SeverityNumber         int32             `parquet:"severity_number"`
+	SeverityText           string            `parquet:"severity_text"`
+	Body                   string            `parquet:"body"`
+	EventName              string            `parquet:"event_name"`
+	TraceID                string            `parquet:"trace_id,optional"`
+	SpanID                 string            `parquet:"span_id,optional"`
+	Flags                  uint32            `parquet:"flags"`
+	Attributes             map[string]string `parquet:"attributes"`
+	DroppedAttributesCount uint32            `parquet:"dropped_attributes_count"`
+	ResourceAttributes     map[string]string `parquet:"resource_attributes"`
+	ResourceSchemaURL      string            `parquet:"resource_schema_url"`
+	ScopeName              string            `parquet:"scope_name"`
+	ScopeVersion           string            `parquet:"scope_version"`
+	ScopeAttributes        map[string]string `parquet:"scope_attributes"`
+	ScopeSchemaURL         string            `parquet:"scope_schema_url"`
+}
+
+type spanRow struct {
+	TraceID                string            `parquet:"trace_id"`
+	SpanID                 string            `parquet:"span_id"`
+	ParentSpanID           string            `parquet:"parent_span_id,optional"`
+	TraceState             string            `parquet:"trace_state"`
+	Name                   string            `parquet:"name"`
+	Kind                   string            `parquet:"kind"`
+	StartTimeUnixNano      int64             `parquet:"start_time_unix_nano"`
+	EndTimeUnixNano        int64             `parquet:"end_time_unix_nano"`
+	DurationNano           int64             `parquet:"duration_nano"`
+	StatusCode             string            `parquet:"status_code"`
+	StatusMessage          string            `parquet:"status_message"`
+	Flags                  uint32            `parquet:"flags"`
+	Attributes             map[string]string `parquet:"attributes"`
+	DroppedAttributesCount uint32            `parquet:"dropped_attributes_count"`
+	Events                 []spanEvent       `parquet:"events,list"`
+	Links                  []spanLink        `parquet:"links,list"`
+	ResourceAttributes     map[string]string `parquet:"resource_attributes"`
+	ResourceSchemaURL      string            `parquet:"resource_schema_url"`
+	ScopeName              string            `parquet:"scope_name"`
+	ScopeVersion           string            `parquet:"scope_version"`
+	ScopeAttributes        map[string]string `parquet:"scope_attributes"`
+	ScopeSchemaURL         string            `parquet:"scope_schema_url"`
+}
+
+type spanEvent struct {
+	TimeUnixNano int64             `parquet:"time_unix_nano"`
+	Name         string            `parquet:"name"`
+	Attributes   map[string]string `parquet:"attributes"`
+}
+
+type spanLink struct {
+	TraceID    string            `parquet:"trace_id"`
+	SpanID     string            `parquet:"span_id"`
+	TraceState string            `parquet:"trace_state"`
+	Attributes map[string]string `parquet:"attributes"`
+}
+
+// dataPointRow is a data point of any type of metric. The columns which don't apply to the type of the metric are null.
+type dataPointRow struct {
+	MetricName             string            `parquet:"metric_name"`
+	MetricDescription      string            `parquet:"metric_description"`
+	MetricUnit             string            `parquet:"metric_unit"`
+	MetricType             string            `parquet:"metric_type"`
+	AggregationTemporality *string           `parquet:"aggregation_temporality"`
+	IsMonotonic            *bool             `parquet:"is_monotonic"`
+	StartTimeUnixNano      int64             `parquet:"start_time_unix_nano"`
+	TimeUnixNano           int64             `parquet:"time_unix_nano"`
+	Flags                  uint32            `parquet:"flags"`
+	Attributes             map[string]string `parquet:"attributes"`
+	ValueDouble            *float64          `parquet:"value_double"`
+	ValueInt               *int64            `parquet:"value_int"`
+	Count                  *uint64           `parquet:"count"`
+	Sum                    *float64          `parquet:"sum"`
+	Min                    *float64          `parquet:"min"`
+	Max                    *float64          `parquet:"max"`
+	BucketCounts           []uint64          `parquet:"bucket_counts,list"`
+	ExplicitBounds         []float64         `parquet:"explicit_bounds,list"`
+	Scale                  *int32            `parquet:"scale"`
+	ZeroCount              *uint64           `parquet:"zero_count"`
+	PositiveOffset         *int32            `parquet:"positive_offset"`
+	PositiveBucketCounts   []uint64          `parquet:"positive_bucket_counts,list"`
+	NegativeOffset         *int32            `parquet:"negative_offset"`
+	NegativeBucketCounts   []uint64          `parquet:"negative_bucket_counts,list"`
+	QuantileValues         []quantileValue   `parquet:"quantile_values,list"`
+	ResourceAttributes     map[string]string `parquet:"resource_attributes"`
+	ResourceSchemaURL      string            `parquet:"resource_schema_url"`
+	ScopeName              string            `parquet:"scope_name"`
+	ScopeVersion           string            `parquet:"scope_version"`
+	ScopeAttributes        map[string]string `parquet:"scope_attributes"`
+	ScopeSchemaURL         string            `parquet:"scope_schema_url"`
+}
+
+type quantileValue struct {
+	Quantile float64 `parquet:"quantile"`
+	Value    float64 `parquet:"value"`
+}
+
+func attributesMap(attrs pcommon.Map) map[string]string {
+	m := make(map[string]string, attrs.Len())
+	for k, v := range attrs.All() {
+		m[k] = v.AsString()
+	}
+	return m
+}
+
+// reservedColumns are the names of the top level columns of all the rows, which promoted attributes can't use.
+var reservedColumns = func() map[string]bool {
+	columns := map[string]bool{}
+	for _, row := range []any{logRow{}, spanRow{}, dataPointRow{}} {
+		for _, field := range reflect.VisibleFields(reflect.TypeOf(row)) {
+			if name, _, _ := strings.Cut(field.Tag.Get("parquet"), ","); name != "" {
+				columns[name] = true
+			}
+		}
+	}
+	return columns
+}()
+
+// rowSchema is the schema of the rows of a signal: the columns of the signal followed by the promoted attributes.
+type rowSchema struct {
+	typ      reflect.Type
+	fields   int
+	promoted []PromotedAttributeConfig
+	schema   *parquet.Schema
+}
+
+func newRowSchema(name string, row any, promoted []PromotedAttributeConfig) *rowSchema {
+	base := reflect.TypeOf(row)
+	fields := make([]reflect.StructField, 0, base.NumField()+len(promoted))
+	for i := 0; i < base.NumField(); i++ {
+		fields = append(fields, base.Field(i))
+	}
+	for i, p := range promoted {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Promoted%d", i),
+			Type: reflect.TypeFor[*string](),
+			Tag:  reflect.StructTag(fmt.Sprintf("parquet:%q", p.column())),
+		})
+	}
+	typ := reflect.StructOf(fields)
+	return &rowSchema{
+		typ:      typ,
+		fields:   base.NumField(),
+		promoted: promoted,
+		schema:   parquet.NewSchema(name, parquet.SchemaOf(reflect.New(typ).Interface())),
+	}
+}
+
+// attributeScopes are the attributes a row was built from, used to look up the promoted attributes.
+type attributeScopes struct {
+	record   pcommon.Map
+	resource pcommon.Map
+	scope    pcommon.Map
+}
+
+func (a attributeScopes) lookup(p PromotedAttributeConfig) (pcommon.Value, bool) {
+	var maps []pcommon.Map
+	switch p.From {
+	case FromRecord:
+		maps = []pcommon.Map{a.record}
+	case FromResource:
+		maps = []pcommon.Map{a.resource}
+	case FromScope:
+		maps = []pcommon.Map{a.scope}
+	default:
+		maps = []pcommon.Map{a.record, a.resource, a.scope}
+	}
+	for _, m := range maps {
+		if v, ok := m.Get(p.Key); ok {
+			return v, true
+		}
+	}
+	return pcommon.Value{}, false
+}
+
+// rowWriter writes the rows of a signal to a Parquet file.
+type rowWriter struct {
+	schema *rowSchema
+	buf    bytes.Buffer
+	writer *parquet.Writer
+}
+
+func (s *rowSchema) newWriter(codec compress.Codec) *rowWriter {
+	w := &rowWriter{schema: s}
+	w.writer = parquet.NewWriter(&w.buf, s.schema, parquet.Compression(codec))
+	return w
+}
+
+// write writes a row, which must be of the type the schema was created from.
+func (w *rowWriter) write(row any, scopes attributeScopes) error {
+	s := w.schema
+	v := reflect.New(s.typ).Elem()
+	r := reflect.ValueOf(row)
+	for i := 0; i < s.fields; i++ {
+		v.Field(i).Set(r.Field(i))
+	}
+	for i, p := range s.promoted {
+		if value, ok := scopes.lookup(p); ok {
+			str := value.AsString()
+			v.Field(s.fields + i).Set(reflect.ValueOf(&str))
+		}
+	}
+	return w.writer.Write(v.Addr().Interface())
+}
+
+func (w *rowWriter) close() ([]byte, error) {
+	if err := w.writer.Close(); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	cefPrefix = "CEF:"
+	// cefHeaderFields is the number of pipe delimited header fields that precede the extension,
+	// including the version.
+	cefHeaderFields = 7
+)
+
+// CEF header keys of the map returned by ParseCEF.
+const (
+	CEFVersionKey            = "version"
+	CEFDeviceVendorKey       = "device_vendor"
+	CEFDeviceProductKey      = "device_product"
+	CEFDeviceVersionKey      = "device_version"
+	CEFDeviceEventClassIDKey = "device_event_class_id"
+	CEFNameKey               = "name"
+	CEFSeverityKey           = "severity"
+	CEFExtensionsKey         = "extensions"
+)
+
+// ParseCEF parses an ArcSight Common Event Format (CEF) message.
+// Any text preceding the `CEF:` marker, such as a syslog header, is ignored.
+// Header fields are unescaped according to the CEF rules (`\|` and `\\`),
+// and the extension is parsed into a nested map of key value pairs where
+// `\=`, `\\`, `\n` and `\r` are unescaped in values.
+func ParseCEF(input string) (map[string]any, error) {
+	start := strings.Index(input, cefPrefix)
+	if start < 0 {
+		return nil, fmt.Errorf("missing %q prefix", cefPrefix)
+	}
+	input = input[start+len(cefPrefix):]
+
+	header, rest, err := splitCEFHeader(input, cefHeaderFields)
+	if err != nil {
+		return nil, err
+	}
+
+	extensions, err := parseCEFExtension(rest)
+	if err != nil {
+		return nil, fmt.Errorf("parse extension: %w", err)
+	}
+
+	return map[string]any{
+		CEFVersionKey:            header[0],
+		CEFDeviceVendorKey:       header[1],
+		CEFDeviceProductKey:      header[2],
+		CEFDeviceVersionKey:      header[3],
+		CEFDeviceEventClassIDKey: header[4],
+		CEFNameKey:               header[5],
+		CEFSeverityKey:           header[6],
+		CEFExtensionsKey:         extensions,
+	}, nil
+}
+
+// splitCEFHeader reads n pipe delimited fields from the input, unescaping `\|` and `\\`,
+// and returns them along with the remainder of the input following the n-th delimiter.
+func splitCEFHeader(input string, n int) ([]string, string, error) {
+	fields := make([]string, 0, n)
+	var current strings.Builder
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		if c == '\\' && i+1 < len(input) && (input[i+1] == '|' || input[i+1] == '\\') {
+			current.WriteByte(input[i+1])
+			i++
+			continue
+		}
+		if c != '|' {
+			current.WriteByte(c)
+			continue
+		}
+		fields = append(fields, current.String())
+		current.Reset()
+		if len(fields) == n {
+			return fields, input[i+1:], nil
+		}
+	}
+	found := len(fields)
+	if current.Len() > 0 {
+		found++
+	}
+	return nil, "", fmt.Errorf("expected %d header fields, found %d", n, found)
+}
+
+// parseCEFExtension parses the space separated key=value pairs of a CEF extension.
+// Values may contain unescaped spaces, so the boundary of a value is determined by
+// the position of the next key, i.e. the last space preceding the next unescaped `=`.
+func parseCEFExtension(ext string) (map[string]any, error) {
+	type pair struct {
+		keyStart int
+		eq       int
+	}
+
+	var pairs []pair
+	for i := 0; i < len(ext); i++ {
+		switch ext[i] {
+		case '\\':
+			i++
+		case '=':
+			keyStart := strings.LastIndexAny(ext[:i], " \t") + 1
+			// An `=` that isn't preceded by a valid key since the previous pair
+			// is treated as part of the previous value.
+			if len(pairs) > 0 && keyStart <= pairs[len(pairs)-1].eq {
+				continue
+			}
+			if !isCEFKey(ext[keyStart:i]) {
+				if len(pairs) == 0 {
+					return nil, fmt.Errorf("invalid key %q", ext[keyStart:i])
+				}
+				continue
+			}
+			pairs = append(pairs, pair{keyStart: keyStart, eq: i})
+		}
+	}
+
+	parsed := make(map[string]any, len(pairs))
+	if len(pairs) == 0 {
+		if strings.TrimSpace(ext) != "" {
+			return nil, errors.New("extension does not contain any key value pairs")
+		}
+		return parsed, nil
+	}
+
+	if leading := strings.TrimSpace(ext[:pairs[0].keyStart]); leading != "" {
+		return nil, fmt.Errorf("unexpected text %q before first key", leading)
+	}
+
+	for i, p := range pairs {
+		end := len(ext)
+		if i+1 < len(pairs) {
+			end = pairs[i+1].keyStart
+		}
+		key := ext[p.keyStart:p.eq]
+		parsed[key] = unescapeCEFValue(strings.TrimRight(ext[p.eq+1:end], " \t"))
+	}
+	return parsed, nil
+}
+
+func isCEFKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '_', c == '.', c == '-', c == '[', c == ']':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func unescapeCEFValue(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var b strings.Builder
+	b.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '\\' || i+1 == len(value) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '=', '\\', '|':
+			b.WriteByte(value[i])
+		default:
+			// Unknown escape sequences are kept as is.
+			b.WriteByte('\\')
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseCEF(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expected    map[string]any
+		expectedErr string
+	}{
+		{
+			name:  "typical message",
+			input: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232",
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Security",
+				"device_product":        "threatmanager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "worm successfully stopped",
+				"severity":              "10",
+				"extensions": map[string]any{
+					"src": "10.0.0.1",
+					"dst": "2.1.2.2",
+					"spt": "1232",
+				},
+			},
+		},
+		{
+			name:  "syslog prefix",
+			input: "Sep 19 08:26:10 host CEF:0|Vendor|Product|1.0|1|name|Low|act=blocked",
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Vendor",
+				"device_product":        "Product",
+				"device_version":        "1.0",
+				"device_event_class_id": "1",
+				"name":                  "name",
+				"severity":              "Low",
+				"extensions": map[string]any{
+					"act": "blocked",
+				},
+			},
+		},
+		{
+			name:  "escaped header",
+			input: `CEF:0|security|threat\|manager|1.0|100|detected a \\ in message|10|`,
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "security",
+				"device_product":        "threat|manager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  `detected a \ in message`,
+				"severity":              "10",
+				"extensions":            map[string]any{},
+			},
+		},
+		{
+			name:  "escaped extension values with spaces",
+			input: `CEF:0|V|P|1|1|n|1|msg=detected a \= sign and a \\ here\nnext line cs1Label=Rule Name cs1=allow all request=http://a/b?c\=d`,
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "V",
+				"device_product":        "P",
+				"device_version":        "1",
+				"device_event_class_id": "1",
+				"name":                  "n",
+				"severity":              "1",
+				"extensions": map[string]any{
+					"msg":      "detected a = sign and a \\ here\nnext line",
+					"cs1Label": "Rule Name",
+					"cs1":      "allow all",
+					"request":  "http://a/b?c=d",
+				},
+			},
+		},
+		{
+			name:  "pipe in extension is not a delimiter",
+			input: "CEF:0|V|P|1|1|n|1|msg=a|b",
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "V",
+				"device_product":        "P",
+				"device_version":        "1",
+				"device_event_class_id": "1",
+				"name":                  "n",
+				"severity":              "1",
+				"extensions": map[string]any{
+					"msg": "a|b",
+				},
+			},
+		},
+		{
+			name:        "missing prefix",
+			input:       "0|V|P|1|1|n|1|",
+			expectedErr: `missing "CEF:" prefix`,
+		},
+		{
+			name:        "incomplete header",
+			input:       "CEF:0|V|P|1|1|n",
+			expectedErr: "expected 7 header fields, found 6",
+		},
+		{
+			name:        "text before first key",
+			input:       "CEF:0|V|P|1|1|n|1|garbage src=1.2.3.4",
+			expectedErr: `unexpected text "garbage" before first key`,
+		},
+		{
+			name:        "extension without pairs",
+			input:       "CEF:0|V|P|1|1|n|1|garbage",
+			expectedErr: "extension does not contain any key value pairs",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseCEF(tc.input)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, m)
+		})
+	}
+}
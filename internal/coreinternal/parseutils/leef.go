@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	leefPrefix = "LEEF:"
+	// leefHeaderFields is the number of pipe delimited header fields common to
+	// LEEF 1.0 and 2.0, including the version.
+	leefHeaderFields      = 5
+	leefDefaultDelimiter  = "\t"
+	leefVersion2Qualifier = "2."
+)
+
+// LEEF header keys of the map returned by ParseLEEF.
+const (
+	LEEFVersionKey       = "version"
+	LEEFDeviceVendorKey  = "device_vendor"
+	LEEFDeviceProductKey = "device_product"
+	LEEFDeviceVersionKey = "device_version"
+	LEEFEventIDKey       = "event_id"
+	LEEFAttributesKey    = "attributes"
+)
+
+// ParseLEEF parses an IBM Log Event Extended Format (LEEF) 1.0 or 2.0 message.
+// Any text preceding the `LEEF:` marker, such as a syslog header, is ignored.
+// LEEF 1.0 attributes are tab delimited. LEEF 2.0 messages may declare a custom
+// attribute delimiter in the header, either as a single character or as a hex
+// code point such as `x5E` or `0x5E`; when omitted a tab is used.
+func ParseLEEF(input string) (map[string]any, error) {
+	start := strings.Index(input, leefPrefix)
+	if start < 0 {
+		return nil, fmt.Errorf("missing %q prefix", leefPrefix)
+	}
+	input = input[start+len(leefPrefix):]
+
+	header, rest, err := splitCEFHeader(input, leefHeaderFields)
+	if err != nil {
+		return nil, err
+	}
+
+	delimiter := leefDefaultDelimiter
+	if strings.HasPrefix(header[0], leefVersion2Qualifier) {
+		delimiter, rest, err = readLEEFDelimiter(rest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	attributes, err := parseLEEFAttributes(rest, delimiter)
+	if err != nil {
+		return nil, fmt.Errorf("parse attributes: %w", err)
+	}
+
+	return map[string]any{
+		LEEFVersionKey:       header[0],
+		LEEFDeviceVendorKey:  header[1],
+		LEEFDeviceProductKey: header[2],
+		LEEFDeviceVersionKey: header[3],
+		LEEFEventIDKey:       header[4],
+		LEEFAttributesKey:    attributes,
+	}, nil
+}
+
+// readLEEFDelimiter reads the optional LEEF 2.0 delimiter header field. The field is
+// only considered to be present when it is followed by a pipe and is either empty or
+// a valid delimiter specification, since attributes themselves may contain pipes.
+func readLEEFDelimiter(rest string) (string, string, error) {
+	end := strings.IndexByte(rest, '|')
+	if end < 0 {
+		return leefDefaultDelimiter, rest, nil
+	}
+
+	spec := rest[:end]
+	switch {
+	case spec == "":
+		return leefDefaultDelimiter, rest[end+1:], nil
+	case utf8.RuneCountInString(spec) == 1:
+		return spec, rest[end+1:], nil
+	}
+
+	hex, isHex := strings.CutPrefix(strings.TrimPrefix(spec, "0"), "x")
+	if !isHex || strings.ContainsRune(spec, '=') {
+		// Not a delimiter specification; the header has no delimiter field.
+		return leefDefaultDelimiter, rest, nil
+	}
+	code, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return "", "", fmt.Errorf("invalid delimiter %q", spec)
+	}
+	return string(rune(code)), rest[end+1:], nil
+}
+
+func parseLEEFAttributes(input, delimiter string) (map[string]any, error) {
+	attrs := strings.Split(input, delimiter)
+	parsed := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		if strings.TrimSpace(attr) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(attr, "=")
+		if !ok {
+			return nil, fmt.Errorf("cannot split %q into a key and value", attr)
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("empty key in %q", attr)
+		}
+		parsed[key] = value
+	}
+	return parsed, nil
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseLEEF(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expected    map[string]any
+		expectedErr string
+	}{
+		{
+			name:  "LEEF 1.0",
+			input: "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1\tsev=5\tcat=anomaly\tmsg=this is a message",
+			expected: map[string]any{
+				"version":        "1.0",
+				"device_vendor":  "Microsoft",
+				"device_product": "MSExchange",
+				"device_version": "4.0 SP1",
+				"event_id":       "15345",
+				"attributes": map[string]any{
+					"src": "192.0.2.0",
+					"dst": "172.50.123.1",
+					"sev": "5",
+					"cat": "anomaly",
+					"msg": "this is a message",
+				},
+			},
+		},
+		{
+			name:  "LEEF 2.0 with character delimiter",
+			input: "LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^sev=5",
+			expected: map[string]any{
+				"version":        "2.0",
+				"device_vendor":  "Lancope",
+				"device_product": "StealthWatch",
+				"device_version": "1.0",
+				"event_id":       "41",
+				"attributes": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+					"sev": "5",
+				},
+			},
+		},
+		{
+			name:  "LEEF 2.0 with hex delimiter",
+			input: "LEEF:2.0|Vendor|Product|1.0|1|0x7c|src=10.0.1.8|dst=10.0.0.5",
+			expected: map[string]any{
+				"version":        "2.0",
+				"device_vendor":  "Vendor",
+				"device_product": "Product",
+				"device_version": "1.0",
+				"event_id":       "1",
+				"attributes": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+				},
+			},
+		},
+		{
+			name:  "LEEF 2.0 without delimiter field",
+			input: "LEEF:2.0|Vendor|Product|1.0|1|src=10.0.1.8\tdst=10.0.0.5",
+			expected: map[string]any{
+				"version":        "2.0",
+				"device_vendor":  "Vendor",
+				"device_product": "Product",
+				"device_version": "1.0",
+				"event_id":       "1",
+				"attributes": map[string]any{
+					"src": "10.0.1.8",
+					"dst": "10.0.0.5",
+				},
+			},
+		},
+		{
+			name:  "syslog prefix and empty delimiter field",
+			input: "<13>Jan 18 11:07:53 host LEEF:2.0|Vendor|Product|1.0|1||usrName=joe",
+			expected: map[string]any{
+				"version":        "2.0",
+				"device_vendor":  "Vendor",
+				"device_product": "Product",
+				"device_version": "1.0",
+				"event_id":       "1",
+				"attributes": map[string]any{
+					"usrName": "joe",
+				},
+			},
+		},
+		{
+			name:        "missing prefix",
+			input:       "1.0|Vendor|Product|1.0|1|src=a",
+			expectedErr: `missing "LEEF:" prefix`,
+		},
+		{
+			name:        "incomplete header",
+			input:       "LEEF:1.0|Vendor|Product",
+			expectedErr: "expected 5 header fields, found 3",
+		},
+		{
+			name:        "invalid hex delimiter",
+			input:       "LEEF:2.0|Vendor|Product|1.0|1|xZZ|src=a",
+			expectedErr: `invalid delimiter "xZZ"`,
+		},
+		{
+			name:        "attribute without value",
+			input:       "LEEF:1.0|Vendor|Product|1.0|1|src=a\tbroken",
+			expectedErr: `cannot split "broken" into a key and value`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseLEEF(tc.input)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, m)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	w3cDirectivePrefix = "#"
+	w3cFieldsDirective = "#Fields:"
+	// w3cEmptyValue is used in place of a field value when no data is available.
+	w3cEmptyValue = "-"
+)
+
+// ParseW3CFields returns the field names declared by a W3C extended log format `#Fields` directive.
+// The `#Fields:` prefix is optional, so that the field list can also be configured directly.
+func ParseW3CFields(directive string) ([]string, error) {
+	directive = strings.TrimSpace(directive)
+	if strings.HasPrefix(directive, w3cDirectivePrefix) {
+		var ok bool
+		directive, ok = strings.CutPrefix(directive, w3cFieldsDirective)
+		if !ok {
+			return nil, fmt.Errorf("directive %q is not a %s directive", directive, w3cFieldsDirective)
+		}
+	}
+
+	fields := strings.Fields(directive)
+	if len(fields) == 0 {
+		return nil, errors.New("no fields declared")
+	}
+	return fields, nil
+}
+
+// ParseW3CEntry parses a W3C extended log format entry line and maps its values to the given field names.
+// Values are separated by whitespace. A value may be enclosed in double quotes in which case it may contain
+// whitespace, and a literal double quote is represented by two consecutive double quotes. Fields that have
+// the `-` placeholder value are omitted from the result.
+func ParseW3CEntry(fields []string, line string) (map[string]any, error) {
+	if strings.HasPrefix(line, w3cDirectivePrefix) {
+		return nil, fmt.Errorf("directive line cannot be parsed as an entry: %q", line)
+	}
+
+	values, err := splitW3CEntry(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(fields) {
+		return nil, fmt.Errorf("wrong number of fields: expected %d, found %d", len(fields), len(values))
+	}
+
+	parsed := make(map[string]any, len(fields))
+	for i, v := range values {
+		if v.raw == w3cEmptyValue {
+			continue
+		}
+		parsed[fields[i]] = v.value
+	}
+	return parsed, nil
+}
+
+type w3cValue struct {
+	// raw is the value as it appears in the entry, and is used to identify unquoted placeholders.
+	raw   string
+	value string
+}
+
+func splitW3CEntry(line string) ([]w3cValue, error) {
+	var values []w3cValue
+	for i := 0; i < len(line); {
+		if isW3CSeparator(line[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		if line[i] != '"' {
+			for i < len(line) && !isW3CSeparator(line[i]) {
+				i++
+			}
+			values = append(values, w3cValue{raw: line[start:i], value: line[start:i]})
+			continue
+		}
+
+		var b strings.Builder
+		closed := false
+		for i++; i < len(line); i++ {
+			if line[i] != '"' {
+				b.WriteByte(line[i])
+				continue
+			}
+			if i+1 < len(line) && line[i+1] == '"' {
+				b.WriteByte('"')
+				i++
+				continue
+			}
+			closed = true
+			i++
+			break
+		}
+		if !closed {
+			return nil, errors.New("never reached the end of a quoted value")
+		}
+		if i < len(line) && !isW3CSeparator(line[i]) {
+			return nil, fmt.Errorf("unexpected character %q after quoted value", line[i])
+		}
+		values = append(values, w3cValue{raw: line[start:i], value: b.String()})
+	}
+	return values, nil
+}
+
+func isW3CSeparator(c byte) bool {
+	return c == ' ' || c == '\t'
+}
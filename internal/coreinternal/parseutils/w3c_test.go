@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package parseutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseW3CFields(t *testing.T) {
+	testCases := []struct {
+		name        string
+		directive   string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:      "directive",
+			directive: "#Fields: date time cs-method cs-uri-stem sc-status",
+			expected:  []string{"date", "time", "cs-method", "cs-uri-stem", "sc-status"},
+		},
+		{
+			name:      "field list",
+			directive: "date time cs(User-Agent)",
+			expected:  []string{"date", "time", "cs(User-Agent)"},
+		},
+		{
+			name:        "other directive",
+			directive:   "#Version: 1.0",
+			expectedErr: "is not a #Fields: directive",
+		},
+		{
+			name:        "empty",
+			directive:   "#Fields:",
+			expectedErr: "no fields declared",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields, err := ParseW3CFields(tc.directive)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fields)
+		})
+	}
+}
+
+func Test_ParseW3CEntry(t *testing.T) {
+	testCases := []struct {
+		name        string
+		fields      []string
+		line        string
+		expected    map[string]any
+		expectedErr string
+	}{
+		{
+			name:   "IIS entry",
+			fields: []string{"date", "time", "s-ip", "cs-method", "cs-uri-stem", "cs-uri-query", "sc-status", "cs(User-Agent)"},
+			line:   "2024-01-02 03:04:05 10.0.0.1 GET /index.html - 200 Mozilla/5.0+(Windows+NT+10.0)",
+			expected: map[string]any{
+				"date":           "2024-01-02",
+				"time":           "03:04:05",
+				"s-ip":           "10.0.0.1",
+				"cs-method":      "GET",
+				"cs-uri-stem":    "/index.html",
+				"sc-status":      "200",
+				"cs(User-Agent)": "Mozilla/5.0+(Windows+NT+10.0)",
+			},
+		},
+		{
+			name:   "quoted values",
+			fields: []string{"c-ip", "cs(User-Agent)", "cs(Referer)", "x-note"},
+			line:   `10.0.0.2	"Mozilla/5.0 (X11; Linux)" "-" "say ""hi"""`,
+			expected: map[string]any{
+				"c-ip":           "10.0.0.2",
+				"cs(User-Agent)": "Mozilla/5.0 (X11; Linux)",
+				"cs(Referer)":    "-",
+				"x-note":         `say "hi"`,
+			},
+		},
+		{
+			name:        "directive line",
+			fields:      []string{"date"},
+			line:        "#Date: 2024-01-02 00:00:00",
+			expectedErr: "directive line cannot be parsed as an entry",
+		},
+		{
+			name:        "wrong number of fields",
+			fields:      []string{"date", "time"},
+			line:        "2024-01-02",
+			expectedErr: "wrong number of fields: expected 2, found 1",
+		},
+		{
+			name:        "unterminated quote",
+			fields:      []string{"a"},
+			line:        `"abc`,
+			expectedErr: "never reached the end of a quoted value",
+		},
+		{
+			name:        "text after quote",
+			fields:      []string{"a"},
+			line:        `"abc"d`,
+			expectedErr: `unexpected character 'd' after quoted value`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseW3CEntry(tc.fields, tc.line)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, m)
+		})
+	}
+}
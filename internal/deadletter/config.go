@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const defaultMaxItems = 10000
+
+// Config configures the dead-letter output of an exporter, where the data which is
+// permanently rejected by the destination is written along with the reason of the rejection.
+type Config struct {
+	// Storage is the ID of the storage extension the rejected data is written to.
+	// The dead-letter output is disabled when it is not set.
+	Storage *component.ID `mapstructure:"storage"`
+
+	// MaxItems is the maximum number of items kept in the storage.
+	// When it is reached, the oldest items are removed.
+	MaxItems int `mapstructure:"max_items"`
+
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// NewDefaultConfig returns the default configuration, which has the dead-letter output disabled.
+func NewDefaultConfig() Config {
+	return Config{
+		MaxItems: defaultMaxItems,
+	}
+}
+
+func (c *Config) Validate() error {
+	if c.Storage != nil && c.MaxItems <= 0 {
+		return errors.New("max_items must be positive")
+	}
+	return nil
+}
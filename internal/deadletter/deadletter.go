@@ -0,0 +1,279 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package deadletter writes the data which is permanently rejected by the destination
+// of an exporter to a storage extension, so that it can be inspected and replayed
+// instead of being dropped.
+package deadletter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/deadletter"
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// Content types of the bodies of the items.
+const (
+	// ContentTypeProtobuf is the content type of data encoded as OTLP protobuf.
+	ContentTypeProtobuf = "application/x-protobuf"
+	// ContentTypeJSON is the content type of documents encoded as JSON by the exporter.
+	ContentTypeJSON = "application/json"
+	// ContentTypeNDJSON is the content type of newline delimited JSON, such as the lines of a bulk request.
+	ContentTypeNDJSON = "application/x-ndjson"
+)
+
+const (
+	firstIndexKey = "first_index"
+	nextIndexKey  = "next_index"
+	itemKeyPrefix = "item_"
+)
+
+// Item is data which was permanently rejected by the destination of an exporter.
+type Item struct {
+	// Time is when the data was rejected. Write sets it to the current time when it is zero.
+	Time time.Time `json:"time"`
+	// Signal is the signal of the data, if known: logs, metrics or traces.
+	Signal string `json:"signal,omitempty"`
+	// Reason is why the data was rejected.
+	Reason string `json:"reason"`
+	// Attributes are details of the rejection, such as the destination or the status code.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// ContentType is the encoding of the body.
+	ContentType string `json:"content_type"`
+	// Body is the rejected data.
+	Body []byte `json:"body"`
+}
+
+// Writer writes the data rejected by an exporter to a storage extension. The items are
+// stored as JSON under consecutive keys item_<index>, and the keys first_index and
+// next_index hold the index of the oldest item and the one of the next item, as 8 bytes
+// little endian integers, so that the items can be read back in order.
+type Writer struct {
+	cfg    Config
+	id     component.ID
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	client storage.Client
+	first  uint64
+	next   uint64
+}
+
+// NewWriter creates a Writer for the exporter with the given ID.
+// The Writer does nothing when the dead-letter output is disabled.
+func NewWriter(cfg Config, id component.ID, logger *zap.Logger) *Writer {
+	return &Writer{cfg: cfg, id: id, logger: logger}
+}
+
+// Enabled reports whether the rejected data is written.
+func (w *Writer) Enabled() bool {
+	return w.cfg.Storage != nil
+}
+
+// Start gets the storage client from the storage extension and loads the indexes of the items.
+func (w *Writer) Start(ctx context.Context, host component.Host) error {
+	if !w.Enabled() {
+		return nil
+	}
+	ext, ok := host.GetExtensions()[*w.cfg.Storage]
+	if !ok {
+		return fmt.Errorf("storage extension '%s' not found", w.cfg.Storage)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("non-storage extension '%s' found", w.cfg.Storage)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindExporter, w.id, "dead_letter")
+	if err != nil {
+		return err
+	}
+	first, err := readIndex(ctx, client, firstIndexKey)
+	if err == nil {
+		w.next, err = readIndex(ctx, client, nextIndexKey)
+	}
+	if err != nil {
+		return errors.Join(err, client.Close(ctx))
+	}
+	w.first = first
+	w.client = client
+	return nil
+}
+
+// Shutdown closes the storage client.
+func (w *Writer) Shutdown(ctx context.Context) error {
+	if w.client == nil {
+		return nil
+	}
+	return w.client.Close(ctx)
+}
+
+// Write writes items to the storage, removing the oldest items when there are more than max_items.
+// It does nothing when w is nil.
+func (w *Writer) Write(ctx context.Context, items ...Item) error {
+	if w == nil || w.client == nil || len(items) == 0 {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	first, next := w.first, w.next
+	ops := make([]*storage.Operation, 0, len(items)+2)
+	for _, item := range items {
+		if item.Time.IsZero() {
+			item.Time = time.Now()
+		}
+		value, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, storage.SetOperation(itemKey(next), value))
+		next++
+	}
+	for next-first > uint64(w.cfg.MaxItems) {
+		ops = append(ops, storage.DeleteOperation(itemKey(first)))
+		first++
+	}
+	ops = append(ops,
+		storage.SetOperation(firstIndexKey, binary.LittleEndian.AppendUint64(nil, first)),
+		storage.SetOperation(nextIndexKey, binary.LittleEndian.AppendUint64(nil, next)),
+	)
+	if err := w.client.Batch(ctx, ops...); err != nil {
+		return err
+	}
+	w.first, w.next = first, next
+	return nil
+}
+
+// WriteLogs writes logs rejected because of reason, encoded as OTLP protobuf.
+func (w *Writer) WriteLogs(ctx context.Context, ld plog.Logs, reason error) error {
+	if w.client == nil {
+		return nil
+	}
+	body, err := (&plog.ProtoMarshaler{}).MarshalLogs(ld)
+	if err != nil {
+		return err
+	}
+	return w.Write(ctx, Item{Signal: "logs", Reason: reason.Error(), ContentType: ContentTypeProtobuf, Body: body})
+}
+
+// WriteMetrics writes metrics rejected because of reason, encoded as OTLP protobuf.
+func (w *Writer) WriteMetrics(ctx context.Context, md pmetric.Metrics, reason error) error {
+	if w.client == nil {
+		return nil
+	}
+	body, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(md)
+	if err != nil {
+		return err
+	}
+	return w.Write(ctx, Item{Signal: "metrics", Reason: reason.Error(), ContentType: ContentTypeProtobuf, Body: body})
+}
+
+// WriteTraces writes traces rejected because of reason, encoded as OTLP protobuf.
+func (w *Writer) WriteTraces(ctx context.Context, td ptrace.Traces, reason error) error {
+	if w.client == nil {
+		return nil
+	}
+	body, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	if err != nil {
+		return err
+	}
+	return w.Write(ctx, Item{Signal: "traces", Reason: reason.Error(), ContentType: ContentTypeProtobuf, Body: body})
+}
+
+// PushLogs wraps the push function of a logs exporter, so that the logs carried by the
+// permanent errors it returns, created with consumererror.NewLogs, are written to the
+// storage. Permanent errors which don't carry logs are ignored, since the function can't
+// tell which part of the request they apply to.
+func (w *Writer) PushLogs(push func(context.Context, plog.Logs) error) func(context.Context, plog.Logs) error {
+	return func(ctx context.Context, ld plog.Logs) error {
+		err := push(ctx, ld)
+		for _, rejection := range w.rejections(err) {
+			var logsErr consumererror.Logs
+			if errors.As(rejection, &logsErr) {
+				w.logWriteError(w.WriteLogs(ctx, logsErr.Data(), rejection))
+			}
+		}
+		return err
+	}
+}
+
+// PushMetrics is the equivalent of PushLogs for metrics.
+func (w *Writer) PushMetrics(push func(context.Context, pmetric.Metrics) error) func(context.Context, pmetric.Metrics) error {
+	return func(ctx context.Context, md pmetric.Metrics) error {
+		err := push(ctx, md)
+		for _, rejection := range w.rejections(err) {
+			var metricsErr consumererror.Metrics
+			if errors.As(rejection, &metricsErr) {
+				w.logWriteError(w.WriteMetrics(ctx, metricsErr.Data(), rejection))
+			}
+		}
+		return err
+	}
+}
+
+// PushTraces is the equivalent of PushLogs for traces.
+func (w *Writer) PushTraces(push func(context.Context, ptrace.Traces) error) func(context.Context, ptrace.Traces) error {
+	return func(ctx context.Context, td ptrace.Traces) error {
+		err := push(ctx, td)
+		for _, rejection := range w.rejections(err) {
+			var tracesErr consumererror.Traces
+			if errors.As(rejection, &tracesErr) {
+				w.logWriteError(w.WriteTraces(ctx, tracesErr.Data(), rejection))
+			}
+		}
+		return err
+	}
+}
+
+// rejections returns the permanent errors err is made of, splitting the errors
+// combined with errors.Join or multierr.
+func (w *Writer) rejections(err error) []error {
+	if err == nil || w.client == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var rejections []error
+		for _, e := range joined.Unwrap() {
+			rejections = append(rejections, w.rejections(e)...)
+		}
+		return rejections
+	}
+	if consumererror.IsPermanent(err) {
+		return []error{err}
+	}
+	return nil
+}
+
+func (w *Writer) logWriteError(err error) {
+	if err != nil {
+		w.logger.Error("failed to write rejected data to the dead-letter storage", zap.Error(err))
+	}
+}
+
+func itemKey(index uint64) string {
+	return itemKeyPrefix + strconv.FormatUint(index, 10)
+}
+
+func readIndex(ctx context.Context, client storage.Client, key string) (uint64, error) {
+	value, err := client.Get(ctx, key)
+	if err != nil || value == nil {
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, fmt.Errorf("invalid value of %s: %d bytes", key, len(value))
+	}
+	return binary.LittleEndian.Uint64(value), nil
+}
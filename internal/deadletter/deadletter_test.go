@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+var testExporterID = component.MustNewID("test")
+
+func newTestWriter(t *testing.T, maxItems int, ext *storagetest.TestStorage) *Writer {
+	cfg := NewDefaultConfig()
+	cfg.Storage = &ext.ID
+	cfg.MaxItems = maxItems
+	require.NoError(t, cfg.Validate())
+	w := NewWriter(cfg, testExporterID, zap.NewNop())
+	require.NoError(t, w.Start(t.Context(), storagetest.NewStorageHost().WithExtension(ext.ID, ext)))
+	return w
+}
+
+// readItems reads back the items written by newTestWriter, in order.
+func readItems(t *testing.T, ext *storagetest.TestStorage) []Item {
+	client, err := ext.GetClient(t.Context(), component.KindExporter, testExporterID, "dead_letter")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, client.Close(context.Background())) }()
+
+	first, err := readIndex(t.Context(), client, firstIndexKey)
+	require.NoError(t, err)
+	next, err := readIndex(t.Context(), client, nextIndexKey)
+	require.NoError(t, err)
+	var items []Item
+	for i := first; i < next; i++ {
+		value, err := client.Get(t.Context(), itemKey(i))
+		require.NoError(t, err)
+		var item Item
+		require.NoError(t, json.Unmarshal(value, &item))
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestWriterMaxItems(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("dlq", t.TempDir())
+
+	w := newTestWriter(t, 3, ext)
+	require.NoError(t, w.Write(t.Context(),
+		Item{Reason: "first", ContentType: ContentTypeJSON, Body: []byte(`{"n":1}`)},
+		Item{Reason: "second", ContentType: ContentTypeJSON, Body: []byte(`{"n":2}`)},
+	))
+	require.NoError(t, w.Shutdown(t.Context()))
+
+	// The indexes are loaded from the storage on restart.
+	w = newTestWriter(t, 3, ext)
+	require.NoError(t, w.Write(t.Context(),
+		Item{Reason: "third", Attributes: map[string]string{"index": "logs"}, ContentType: ContentTypeJSON, Body: []byte(`{"n":3}`)},
+		Item{Reason: "fourth", ContentType: ContentTypeJSON, Body: []byte(`{"n":4}`)},
+	))
+	require.NoError(t, w.Shutdown(t.Context()))
+
+	items := readItems(t, ext)
+	require.Len(t, items, 3)
+	var reasons []string
+	for _, item := range items {
+		assert.False(t, item.Time.IsZero())
+		reasons = append(reasons, item.Reason)
+	}
+	assert.Equal(t, []string{"second", "third", "fourth"}, reasons)
+	assert.Equal(t, map[string]string{"index": "logs"}, items[1].Attributes)
+	assert.JSONEq(t, `{"n":3}`, string(items[1].Body))
+}
+
+func TestPushLogs(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("dlq", t.TempDir())
+	w := newTestWriter(t, 10, ext)
+
+	rejected := plog.NewLogs()
+	rejected.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("rejected")
+	retryable := plog.NewLogs()
+	retryable.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("retryable")
+
+	pushErr := errors.Join(
+		consumererror.NewLogs(consumererror.NewPermanent(errors.New("bad request")), rejected),
+		consumererror.NewLogs(errors.New("too many requests"), retryable),
+		consumererror.NewPermanent(errors.New("encoding failed")),
+	)
+	push := w.PushLogs(func(context.Context, plog.Logs) error { return pushErr })
+	assert.Equal(t, pushErr, push(t.Context(), plog.NewLogs()))
+	require.NoError(t, w.Shutdown(t.Context()))
+
+	items := readItems(t, ext)
+	require.Len(t, items, 1)
+	assert.Equal(t, "logs", items[0].Signal)
+	assert.Equal(t, ContentTypeProtobuf, items[0].ContentType)
+	assert.Contains(t, items[0].Reason, "bad request")
+	ld, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(items[0].Body)
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
+
+func TestWriterDisabled(t *testing.T) {
+	w := NewWriter(NewDefaultConfig(), testExporterID, zap.NewNop())
+	assert.False(t, w.Enabled())
+	require.NoError(t, w.Start(t.Context(), componenttest.NewNopHost()))
+	require.NoError(t, w.WriteLogs(t.Context(), plog.NewLogs(), errors.New("rejected")))
+	require.NoError(t, w.Shutdown(t.Context()))
+}
+
+func TestWriterStartErrors(t *testing.T) {
+	missing := storagetest.NewStorageID("missing")
+	nonStorage := storagetest.NewNonStorageID("non_storage")
+	host := storagetest.NewStorageHost().WithNonStorageExtension("non_storage")
+
+	for _, id := range []component.ID{missing, nonStorage} {
+		cfg := NewDefaultConfig()
+		cfg.Storage = &id
+		w := NewWriter(cfg, testExporterID, zap.NewNop())
+		assert.Error(t, w.Start(t.Context(), host))
+	}
+}
@@ -86,6 +86,12 @@ func NewFranzSyncProducer(
 		opts = append(opts, kgo.AllowAutoTopicCreation())
 	}
 
+	// The transactional ID is set by the caller, which knows which component
+	// and collector instance the producer belongs to.
+	if cfg.Transaction.Enable {
+		opts = append(opts, kgo.TransactionTimeout(cfg.Transaction.Timeout))
+	}
+
 	return kgo.NewClient(opts...)
 }
 
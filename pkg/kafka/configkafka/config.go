@@ -252,6 +252,28 @@ type ProducerConfig struct {
 	// Linger controls the linger time for the producer.
 	// (default 10ms).
 	Linger time.Duration `mapstructure:"linger"`
+
+	// Transaction configures the transactional producer.
+	Transaction TransactionConfig `mapstructure:"transaction"`
+}
+
+type TransactionConfig struct {
+	// Whether or not to produce each batch in a transaction, so that it is
+	// committed atomically and read exactly once by consumers with the
+	// read_committed isolation level. Requires required_acks to be "all".
+	// (default disabled).
+	Enable bool `mapstructure:"enable"`
+
+	// ID identifies the collector instance in the transactional IDs, which
+	// are derived from it, the component and the signal. It is required, and
+	// must be stable across restarts and unique to each collector instance,
+	// so that a restarted instance fences out the transactions left open by
+	// its previous run.
+	ID string `mapstructure:"id"`
+
+	// Timeout is the time after which the broker aborts a transaction which
+	// wasn't committed. (default 1m).
+	Timeout time.Duration `mapstructure:"timeout"`
 }
 
 func NewDefaultProducerConfig() ProducerConfig {
@@ -262,6 +284,9 @@ func NewDefaultProducerConfig() ProducerConfig {
 		FlushMaxMessages:       10000,
 		AllowAutoTopicCreation: true,
 		Linger:                 10 * time.Millisecond,
+		Transaction: TransactionConfig{
+			Timeout: time.Minute,
+		},
 	}
 }
 
@@ -286,6 +311,17 @@ func (c ProducerConfig) Validate() error {
 	if c.FlushMaxMessages < 1 {
 		return fmt.Errorf("flush_max_messages (%d) must be at least 1", c.FlushMaxMessages)
 	}
+	if c.Transaction.Enable {
+		if c.RequiredAcks != WaitForAll {
+			return fmt.Errorf("transaction requires required_acks to be 'all' (-1); configured value is %v", c.RequiredAcks)
+		}
+		if c.Transaction.ID == "" {
+			return errors.New("transaction id must be specified")
+		}
+		if c.Transaction.Timeout <= 0 {
+			return fmt.Errorf("transaction timeout (%s) must be positive", c.Transaction.Timeout)
+		}
+	}
 	return nil
 }
 
@@ -265,6 +265,18 @@ func TestProducerConfig(t *testing.T) {
 				return cfg
 			}(),
 		},
+		"transaction": {
+			expected: func() ProducerConfig {
+				cfg := NewDefaultProducerConfig()
+				cfg.RequiredAcks = WaitForAll
+				cfg.Transaction = TransactionConfig{
+					Enable:  true,
+					ID:      "otelcol-0",
+					Timeout: 30 * time.Second,
+				}
+				return cfg
+			}(),
+		},
 		"custom_flush_max_messages": {
 			expected: func() ProducerConfig {
 				cfg := NewDefaultProducerConfig()
@@ -289,6 +301,15 @@ func TestProducerConfig(t *testing.T) {
 		"max_message_bytes_negative": {
 			expectedErr: "max_message_bytes (-1000) must be non-negative",
 		},
+		"transaction_required_acks": {
+			expectedErr: "transaction requires required_acks to be 'all' (-1); configured value is 1",
+		},
+		"transaction_id": {
+			expectedErr: "transaction id must be specified",
+		},
+		"transaction_timeout": {
+			expectedErr: "transaction timeout (0s) must be positive",
+		},
 	})
 }
 
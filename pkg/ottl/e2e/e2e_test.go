@@ -1014,6 +1014,71 @@ func Test_e2e_converters(t *testing.T) {
 				m.PutStr("k2", "v2__!__v2")
 			},
 		},
+		{
+			statement: `set(attributes["test"], ParseCEF("CEF:0|Vendor|Product|1.0|100|Blocked|5|src=10.0.0.1 msg=a b"))`,
+			want: func(tCtx *ottllog.TransformContext) {
+				m := tCtx.GetLogRecord().Attributes().PutEmptyMap("test")
+				m.PutStr("version", "0")
+				m.PutStr("device_vendor", "Vendor")
+				m.PutStr("device_product", "Product")
+				m.PutStr("device_version", "1.0")
+				m.PutStr("device_event_class_id", "100")
+				m.PutStr("name", "Blocked")
+				m.PutStr("severity", "5")
+				ext := m.PutEmptyMap("extensions")
+				ext.PutStr("src", "10.0.0.1")
+				ext.PutStr("msg", "a b")
+			},
+		},
+		{
+			statement: `set(attributes["test"], ParseLEEF("LEEF:2.0|Vendor|Product|1.0|41|^|src=10.0.0.1^usrName=joe"))`,
+			want: func(tCtx *ottllog.TransformContext) {
+				m := tCtx.GetLogRecord().Attributes().PutEmptyMap("test")
+				m.PutStr("version", "2.0")
+				m.PutStr("device_vendor", "Vendor")
+				m.PutStr("device_product", "Product")
+				m.PutStr("device_version", "1.0")
+				m.PutStr("event_id", "41")
+				attrs := m.PutEmptyMap("attributes")
+				attrs.PutStr("src", "10.0.0.1")
+				attrs.PutStr("usrName", "joe")
+			},
+		},
+		{
+			statement: `set(attributes["test"], ParseW3C("2024-01-02 GET - 200", "#Fields: date cs-method cs-uri-query sc-status"))`,
+			want: func(tCtx *ottllog.TransformContext) {
+				m := tCtx.GetLogRecord().Attributes().PutEmptyMap("test")
+				m.PutStr("date", "2024-01-02")
+				m.PutStr("cs-method", "GET")
+				m.PutStr("sc-status", "200")
+			},
+		},
+		{
+			statement: `set(attributes["test"], Query(ParseJSON("{\"items\":[{\"id\":\"a\"},{\"id\":\"b\"}]}"), "$.items[*].id"))`,
+			want: func(tCtx *ottllog.TransformContext) {
+				s := tCtx.GetLogRecord().Attributes().PutEmptySlice("test")
+				s.AppendEmpty().SetStr("a")
+				s.AppendEmpty().SetStr("b")
+			},
+		},
+		{
+			statement: `set(attributes["test"], Query(ParseJSON("{\"items\":[{\"id\":\"a\"}]}"), "$.items[0].id"))`,
+			want: func(tCtx *ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", "a")
+			},
+		},
+		{
+			statement: `set(attributes["test"], ToJSON(ParseKeyValue("k2=v2 k1=v1")))`,
+			want: func(tCtx *ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", `{"k1":"v1","k2":"v2"}`)
+			},
+		},
+		{
+			statement: `set(attributes["test"], Encode("hello world", "hex"))`,
+			want: func(tCtx *ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("test", "68656c6c6f20776f726c64")
+			},
+		},
 		{
 			statement: `set(attributes["test"], ToKeyValueString(ParseKeyValue("k1=v1 k2=v2"), "=", " ", true))`,
 			want: func(tCtx *ottllog.TransformContext) {
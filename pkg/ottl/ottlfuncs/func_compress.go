@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type CompressArguments[K any] struct {
+	Target ottl.ByteSliceLikeGetter[K]
+	Codec  string
+}
+
+func NewCompressFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("Compress", &CompressArguments[K]{}, createCompressFunction[K])
+}
+
+func createCompressFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*CompressArguments[K])
+
+	if !ok {
+		return nil, errors.New("CompressFactory args must be of type *CompressArguments[K]")
+	}
+
+	return compress(args.Target, args.Codec)
+}
+
+type compressWriterFunc func(io.Writer) (io.WriteCloser, error)
+
+var compressCodecs = map[string]compressWriterFunc{
+	"gzip": func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	},
+	"zlib": func(w io.Writer) (io.WriteCloser, error) {
+		return zlib.NewWriter(w), nil
+	},
+	"deflate": func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, flate.DefaultCompression)
+	},
+	"zstd": func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	},
+}
+
+func compress[K any](target ottl.ByteSliceLikeGetter[K], codec string) (ottl.ExprFunc[K], error) {
+	newWriter, ok := compressCodecs[codec]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression codec %q, must be one of gzip, zlib, deflate or zstd", codec)
+	}
+
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		w, err := newWriter(&buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s writer: %w", codec, err)
+		}
+		if _, err := w.Write(val); err != nil {
+			_ = w.Close()
+			return nil, fmt.Errorf("failed to compress with %s: %w", codec, err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress with %s: %w", codec, err)
+		}
+		return buf.Bytes(), nil
+	}, nil
+}
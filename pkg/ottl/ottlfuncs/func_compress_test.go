@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_Compress(t *testing.T) {
+	input := bytes.Repeat([]byte("a highly compressible payload "), 100)
+
+	tests := []struct {
+		codec      string
+		decompress func(t *testing.T, data []byte) []byte
+	}{
+		{
+			codec: "gzip",
+			decompress: func(t *testing.T, data []byte) []byte {
+				r, err := gzip.NewReader(bytes.NewReader(data))
+				require.NoError(t, err)
+				out, err := io.ReadAll(r)
+				require.NoError(t, err)
+				return out
+			},
+		},
+		{
+			codec: "zlib",
+			decompress: func(t *testing.T, data []byte) []byte {
+				r, err := zlib.NewReader(bytes.NewReader(data))
+				require.NoError(t, err)
+				out, err := io.ReadAll(r)
+				require.NoError(t, err)
+				return out
+			},
+		},
+		{
+			codec: "deflate",
+			decompress: func(t *testing.T, data []byte) []byte {
+				out, err := io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+				require.NoError(t, err)
+				return out
+			},
+		},
+		{
+			codec: "zstd",
+			decompress: func(t *testing.T, data []byte) []byte {
+				r, err := zstd.NewReader(bytes.NewReader(data))
+				require.NoError(t, err)
+				defer r.Close()
+				out, err := io.ReadAll(r)
+				require.NoError(t, err)
+				return out
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.codec, func(t *testing.T) {
+			exprFunc, err := compress[any](&ottl.StandardByteSliceLikeGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return string(input), nil
+				},
+			}, tt.codec)
+			require.NoError(t, err)
+
+			result, err := exprFunc(t.Context(), nil)
+			require.NoError(t, err)
+
+			compressed, ok := result.([]byte)
+			require.True(t, ok)
+			assert.Less(t, len(compressed), len(input))
+			assert.Equal(t, input, tt.decompress(t, compressed))
+		})
+	}
+}
+
+func Test_Compress_unsupported_codec(t *testing.T) {
+	_, err := compress[any](&ottl.StandardByteSliceLikeGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return "payload", nil
+		},
+	}, "lz4")
+	assert.ErrorContains(t, err, `unsupported compression codec "lz4"`)
+}
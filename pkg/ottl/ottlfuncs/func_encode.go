@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/textutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type EncodeArguments[K any] struct {
+	Target   ottl.ByteSliceLikeGetter[K]
+	Encoding ottl.StringGetter[K]
+}
+
+func NewEncodeFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("Encode", &EncodeArguments[K]{}, createEncodeFunction[K])
+}
+
+func createEncodeFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*EncodeArguments[K])
+	if !ok {
+		return nil, errors.New("EncodeFactory args must be of type *EncodeArguments[K]")
+	}
+
+	return Encode(args.Target, args.Encoding), nil
+}
+
+// Encode is the inverse of Decode: it encodes the target with base64, hex or an IANA character encoding.
+func Encode[K any](target ottl.ByteSliceLikeGetter[K], encoding ottl.StringGetter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		encodingVal, err := encoding.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		return encodeBytes(val, encodingVal)
+	}
+}
+
+// lookupBase64Encoding returns the base64 variant with the same name as accepted by Decode.
+func lookupBase64Encoding(name string) (*base64.Encoding, bool) {
+	switch name {
+	case "base64":
+		return base64.StdEncoding, true
+	case "base64-raw":
+		return base64.RawStdEncoding, true
+	case "base64-url":
+		return base64.URLEncoding, true
+	case "base64-raw-url":
+		return base64.RawURLEncoding, true
+	}
+	return nil, false
+}
+
+func encodeBytes(val []byte, encodingVal string) (string, error) {
+	if e, ok := lookupBase64Encoding(encodingVal); ok {
+		return e.EncodeToString(val), nil
+	}
+	if encodingVal == "hex" {
+		return hex.EncodeToString(val), nil
+	}
+
+	e, err := textutils.LookupEncoding(encodingVal)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := e.NewEncoder().String(string(val))
+	if err != nil {
+		return "", fmt.Errorf("could not encode: %w", err)
+	}
+	return encoded, nil
+}
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func TestEncode(t *testing.T) {
+	testByteSlice := pcommon.NewByteSlice()
+	testByteSlice.FromRaw([]byte("test string"))
+
+	tests := []struct {
+		name          string
+		value         any
+		encoding      string
+		want          any
+		expectedError string
+	}{
+		{
+			name:     "base64 string",
+			value:    "test string",
+			encoding: "base64",
+			want:     "dGVzdCBzdHJpbmc=",
+		},
+		{
+			name:     "base64 bytes",
+			value:    testByteSlice.AsRaw(),
+			encoding: "base64",
+			want:     "dGVzdCBzdHJpbmc=",
+		},
+		{
+			name:     "base64-raw",
+			value:    "test string",
+			encoding: "base64-raw",
+			want:     "dGVzdCBzdHJpbmc",
+		},
+		{
+			name:     "base64-url",
+			value:    "Go?/ô",
+			encoding: "base64-url",
+			want:     "R28_L8O0",
+		},
+		{
+			name:     "base64-raw-url",
+			value:    "Go?/ô",
+			encoding: "base64-raw-url",
+			want:     "R28_L8O0",
+		},
+		{
+			name:     "hex",
+			value:    "test",
+			encoding: "hex",
+			want:     "74657374",
+		},
+		{
+			name:     "utf-16le",
+			value:    "hi",
+			encoding: "utf-16le",
+			want:     "h\x00i\x00",
+		},
+		{
+			name:     "iso-8859-1",
+			value:    "café",
+			encoding: "iso-8859-1",
+			want:     "caf\xe9",
+		},
+		{
+			name:          "unsupported encoding",
+			value:         "test",
+			encoding:      "invalid encoding",
+			expectedError: "unsupported encoding 'invalid encoding'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expressionFunc := Encode[any](
+				&ottl.StandardByteSliceLikeGetter[any]{
+					Getter: func(context.Context, any) (any, error) {
+						return tt.value, nil
+					},
+				},
+				&ottl.StandardStringGetter[any]{
+					Getter: func(context.Context, any) (any, error) {
+						return tt.encoding, nil
+					},
+				})
+
+			result, err := expressionFunc(nil, nil)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	for _, encoding := range []string{"base64", "base64-raw", "base64-url", "base64-raw-url", "utf-16", "windows-1252"} {
+		t.Run(encoding, func(t *testing.T) {
+			encodingGetter := &ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return encoding, nil
+				},
+			}
+			encoded, err := Encode[any](
+				&ottl.StandardByteSliceLikeGetter[any]{
+					Getter: func(context.Context, any) (any, error) {
+						return "round trip €", nil
+					},
+				}, encodingGetter)(nil, nil)
+			require.NoError(t, err)
+
+			decoded, err := Decode[any](
+				&ottl.StandardGetSetter[any]{
+					Getter: func(context.Context, any) (any, error) {
+						return encoded, nil
+					},
+				}, encodingGetter)(nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "round trip €", decoded)
+		})
+	}
+}
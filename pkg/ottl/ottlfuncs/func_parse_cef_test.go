@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseCEF(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected map[string]any
+	}{
+		{
+			name:   "simple",
+			target: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232",
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Security",
+				"device_product":        "threatmanager",
+				"device_version":        "1.0",
+				"device_event_class_id": "100",
+				"name":                  "worm successfully stopped",
+				"severity":              "10",
+				"extensions": map[string]any{
+					"src": "10.0.0.1",
+					"dst": "2.1.2.2",
+					"spt": "1232",
+				},
+			},
+		},
+		{
+			name:   "escaped values",
+			target: `<134>Feb 14 19:04:54 fw CEF:0|Vendor|Fire\|wall|2.1|deny|Blocked connection|7|msg=rule a\=b matched cs1Label=Policy Name`,
+			expected: map[string]any{
+				"version":               "0",
+				"device_vendor":         "Vendor",
+				"device_product":        "Fire|wall",
+				"device_version":        "2.1",
+				"device_event_class_id": "deny",
+				"name":                  "Blocked connection",
+				"severity":              "7",
+				"extensions": map[string]any{
+					"msg":      "rule a=b matched",
+					"cs1Label": "Policy Name",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc := parseCEF[any](target)
+
+			result, err := exprFunc(t.Context(), nil)
+			require.NoError(t, err)
+
+			actual, ok := result.(pcommon.Map)
+			require.True(t, ok)
+
+			expected := pcommon.NewMap()
+			require.NoError(t, expected.FromRaw(tt.expected))
+			assert.Equal(t, expected.AsRaw(), actual.AsRaw())
+		})
+	}
+}
+
+func Test_parseCEF_bad_target(t *testing.T) {
+	target := ottl.StandardStringGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return 1, nil
+		},
+	}
+	exprFunc := parseCEF[any](target)
+	_, err := exprFunc(t.Context(), nil)
+	assert.Error(t, err)
+}
+
+func Test_parseCEF_empty_target(t *testing.T) {
+	target := ottl.StandardStringGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return "", nil
+		},
+	}
+	exprFunc := parseCEF[any](target)
+	_, err := exprFunc(t.Context(), nil)
+	assert.ErrorContains(t, err, "cannot parse from empty target")
+}
+
+func Test_parseCEF_invalid_message(t *testing.T) {
+	target := ottl.StandardStringGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return "CEF:0|Vendor|Product", nil
+		},
+	}
+	exprFunc := parseCEF[any](target)
+	_, err := exprFunc(t.Context(), nil)
+	assert.ErrorContains(t, err, "failed to parse CEF message: expected 7 header fields, found 3")
+}
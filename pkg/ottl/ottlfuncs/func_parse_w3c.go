@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type ParseW3CArguments[K any] struct {
+	Target ottl.StringGetter[K]
+	Fields ottl.StringGetter[K]
+}
+
+func NewParseW3CFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ParseW3C", &ParseW3CArguments[K]{}, createParseW3CFunction[K])
+}
+
+func createParseW3CFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ParseW3CArguments[K])
+
+	if !ok {
+		return nil, errors.New("ParseW3CFactory args must be of type *ParseW3CArguments[K]")
+	}
+
+	return parseW3C[K](args.Target, args.Fields), nil
+}
+
+func parseW3C[K any](target, fields ottl.StringGetter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		source, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting value for target in ParseW3C: %w", err)
+		}
+
+		if source == "" {
+			return nil, errors.New("cannot parse from empty target")
+		}
+
+		fieldsStr, err := fields.Get(ctx, tCtx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting value for fields in ParseW3C: %w", err)
+		}
+
+		fieldNames, err := parseutils.ParseW3CFields(fieldsStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fields: %w", err)
+		}
+
+		parsed, err := parseutils.ParseW3CEntry(fieldNames, source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse W3C entry: %w", err)
+		}
+
+		result := pcommon.NewMap()
+		err = result.FromRaw(parsed)
+		return result, err
+	}
+}
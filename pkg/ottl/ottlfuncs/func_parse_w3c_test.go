@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseW3C(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		fields   string
+		expected map[string]any
+	}{
+		{
+			name:   "fields directive",
+			target: "2024-01-02 03:04:05 GET /index.html - 200",
+			fields: "#Fields: date time cs-method cs-uri-stem cs-uri-query sc-status",
+			expected: map[string]any{
+				"date":        "2024-01-02",
+				"time":        "03:04:05",
+				"cs-method":   "GET",
+				"cs-uri-stem": "/index.html",
+				"sc-status":   "200",
+			},
+		},
+		{
+			name:   "field list with quoted values",
+			target: `10.0.0.1 "Mozilla/5.0 (X11; Linux x86_64)" "a ""quoted"" note"`,
+			fields: "c-ip cs(User-Agent) x-note",
+			expected: map[string]any{
+				"c-ip":           "10.0.0.1",
+				"cs(User-Agent)": "Mozilla/5.0 (X11; Linux x86_64)",
+				"x-note":         `a "quoted" note`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			fields := ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.fields, nil
+				},
+			}
+			exprFunc := parseW3C[any](target, fields)
+
+			result, err := exprFunc(t.Context(), nil)
+			require.NoError(t, err)
+
+			actual, ok := result.(pcommon.Map)
+			require.True(t, ok)
+
+			expected := pcommon.NewMap()
+			require.NoError(t, expected.FromRaw(tt.expected))
+			assert.Equal(t, expected.AsRaw(), actual.AsRaw())
+		})
+	}
+}
+
+func Test_parseW3C_errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		fields      string
+		expectedErr string
+	}{
+		{
+			name:        "empty target",
+			target:      "",
+			fields:      "date",
+			expectedErr: "cannot parse from empty target",
+		},
+		{
+			name:        "empty fields",
+			target:      "2024-01-02",
+			fields:      "",
+			expectedErr: "invalid fields: no fields declared",
+		},
+		{
+			name:        "directive line",
+			target:      "#Version: 1.0",
+			fields:      "date",
+			expectedErr: "failed to parse W3C entry: directive line cannot be parsed as an entry",
+		},
+		{
+			name:        "field count mismatch",
+			target:      "2024-01-02 03:04:05",
+			fields:      "date",
+			expectedErr: "failed to parse W3C entry: wrong number of fields: expected 1, found 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			fields := ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.fields, nil
+				},
+			}
+			exprFunc := parseW3C[any](target, fields)
+			_, err := exprFunc(t.Context(), nil)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type QueryArguments[K any] struct {
+	Target ottl.Getter[K]
+	Path   ottl.StringGetter[K]
+}
+
+func NewQueryFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("Query", &QueryArguments[K]{}, createQueryFunction[K])
+}
+
+func createQueryFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*QueryArguments[K])
+
+	if !ok {
+		return nil, errors.New("QueryFactory args must be of type *QueryArguments[K]")
+	}
+
+	return query(args.Target, args.Path)
+}
+
+func query[K any](target ottl.Getter[K], pathGetter ottl.StringGetter[K]) (ottl.ExprFunc[K], error) {
+	// Literal paths are compiled once, dynamic paths on every evaluation.
+	var compiled *jsonPath
+	if literal, isLiteral := ottl.GetLiteralValue(pathGetter); isLiteral {
+		var err error
+		compiled, err = parseJSONPath(literal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(ctx context.Context, tCtx K) (any, error) {
+		path := compiled
+		if path == nil {
+			expr, err := pathGetter.Get(ctx, tCtx)
+			if err != nil {
+				return nil, err
+			}
+			path, err = parseJSONPath(expr)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		root, err := queryRoot(val)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes := path.evaluate(root)
+		if path.singular {
+			if len(nodes) == 0 {
+				return nil, nil
+			}
+			return queryResult(nodes[0]), nil
+		}
+
+		result := pcommon.NewSlice()
+		result.EnsureCapacity(len(nodes))
+		for _, n := range nodes {
+			appendQueryNode(result, n)
+		}
+		return result, nil
+	}, nil
+}
+
+// queryRoot converts the target value into a JSONPath node. pcommon.Map, pcommon.Slice and
+// pcommon.Value targets are used as is, so the queried data is never copied or re-marshaled.
+func queryRoot(val any) (any, error) {
+	switch v := val.(type) {
+	case pcommon.Map, pcommon.Slice:
+		return v, nil
+	case pcommon.Value:
+		return toJSONPathNode(v), nil
+	case nil:
+		return pcommon.NewValueEmpty(), nil
+	default:
+		value := pcommon.NewValueEmpty()
+		if err := value.FromRaw(v); err != nil {
+			return nil, fmt.Errorf("unsupported target type %T: %w", val, err)
+		}
+		return toJSONPathNode(value), nil
+	}
+}
+
+func queryResult(node any) any {
+	switch n := node.(type) {
+	case pcommon.Map, pcommon.Slice:
+		return n
+	case pcommon.Value:
+		switch n.Type() {
+		case pcommon.ValueTypeStr:
+			return n.Str()
+		case pcommon.ValueTypeBool:
+			return n.Bool()
+		case pcommon.ValueTypeInt:
+			return n.Int()
+		case pcommon.ValueTypeDouble:
+			return n.Double()
+		case pcommon.ValueTypeBytes:
+			return n.Bytes().AsRaw()
+		}
+	}
+	return nil
+}
+
+func appendQueryNode(dst pcommon.Slice, node any) {
+	switch n := node.(type) {
+	case pcommon.Map:
+		n.CopyTo(dst.AppendEmpty().SetEmptyMap())
+	case pcommon.Slice:
+		n.CopyTo(dst.AppendEmpty().SetEmptySlice())
+	case pcommon.Value:
+		n.CopyTo(dst.AppendEmpty())
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func queryTestMap(t *testing.T) pcommon.Map {
+	m := pcommon.NewMap()
+	require.NoError(t, m.FromRaw(map[string]any{
+		"store": map[string]any{
+			"name": "corner shop",
+			"items": []any{
+				map[string]any{"id": "a1", "price": 8.95, "tags": []any{"food"}},
+				map[string]any{"id": "b2", "price": int64(12), "status": "sold"},
+				map[string]any{"id": "c3", "price": 22.99, "status": "available"},
+			},
+		},
+		"key.with.dots": true,
+		"empty":         map[string]any{},
+	}))
+	return m
+}
+
+func Test_query(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected any
+	}{
+		{
+			name:     "root",
+			path:     "$",
+			expected: queryTestMap(t),
+		},
+		{
+			name:     "dot notation scalar",
+			path:     "$.store.name",
+			expected: "corner shop",
+		},
+		{
+			name:     "bracket notation",
+			path:     `$['store']["items"][1].id`,
+			expected: "b2",
+		},
+		{
+			name:     "negative index",
+			path:     "$.store.items[-1].price",
+			expected: 22.99,
+		},
+		{
+			name:     "int value",
+			path:     "$.store.items[1].price",
+			expected: int64(12),
+		},
+		{
+			name:     "quoted key with dots",
+			path:     "$['key.with.dots']",
+			expected: true,
+		},
+		{
+			name: "singular map result",
+			path: "$.store.items[0].tags",
+			expected: func() any {
+				s := pcommon.NewSlice()
+				s.AppendEmpty().SetStr("food")
+				return s
+			}(),
+		},
+		{
+			name:     "missing key",
+			path:     "$.store.owner.name",
+			expected: nil,
+		},
+		{
+			name:     "index out of range",
+			path:     "$.store.items[5]",
+			expected: nil,
+		},
+		{
+			name:     "index on map",
+			path:     "$.store[0]",
+			expected: nil,
+		},
+		{
+			name:     "wildcard",
+			path:     "$.store.items[*].id",
+			expected: []any{"a1", "b2", "c3"},
+		},
+		{
+			name:     "dot wildcard",
+			path:     "$.store.items.*.status",
+			expected: []any{"sold", "available"},
+		},
+		{
+			name:     "union",
+			path:     "$.store.items[0,2].id",
+			expected: []any{"a1", "c3"},
+		},
+		{
+			name:     "slice",
+			path:     "$.store.items[1:].id",
+			expected: []any{"b2", "c3"},
+		},
+		{
+			name:     "reverse slice",
+			path:     "$.store.items[::-1].id",
+			expected: []any{"c3", "b2", "a1"},
+		},
+		{
+			name:     "recursive descent",
+			path:     "$..price",
+			expected: []any{8.95, int64(12), 22.99},
+		},
+		{
+			name:     "filter comparison",
+			path:     "$.store.items[?(@.price > 10)].id",
+			expected: []any{"b2", "c3"},
+		},
+		{
+			name:     "filter equality",
+			path:     "$.store.items[?(@.status == 'available')].id",
+			expected: []any{"c3"},
+		},
+		{
+			name:     "filter existence",
+			path:     "$.store.items[?(@.tags)].id",
+			expected: []any{"a1"},
+		},
+		{
+			name:     "filter on nested index",
+			path:     "$.store.items[?(@.tags[0] == 'food')].id",
+			expected: []any{"a1"},
+		},
+		{
+			name:     "wildcard without matches",
+			path:     "$.empty[*]",
+			expected: []any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardGetSetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return queryTestMap(t), nil
+				},
+			}
+			path := ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.path, nil
+				},
+			}
+			exprFunc, err := query[any](target, path)
+			require.NoError(t, err)
+
+			result, err := exprFunc(t.Context(), nil)
+			require.NoError(t, err)
+
+			switch expected := tt.expected.(type) {
+			case []any:
+				actual, ok := result.(pcommon.Slice)
+				require.True(t, ok)
+				assert.Equal(t, expected, actual.AsRaw())
+			case pcommon.Map:
+				actual, ok := result.(pcommon.Map)
+				require.True(t, ok)
+				assert.Equal(t, expected.AsRaw(), actual.AsRaw())
+			case pcommon.Slice:
+				actual, ok := result.(pcommon.Slice)
+				require.True(t, ok)
+				assert.Equal(t, expected.AsRaw(), actual.AsRaw())
+			default:
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_query_targets(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   any
+		path     string
+		expected any
+	}{
+		{
+			name: "pcommon.Slice",
+			target: func() pcommon.Slice {
+				s := pcommon.NewSlice()
+				s.AppendEmpty().SetStr("first")
+				s.AppendEmpty().SetStr("second")
+				return s
+			}(),
+			path:     "$[1]",
+			expected: "second",
+		},
+		{
+			name: "pcommon.Value",
+			target: func() pcommon.Value {
+				v := pcommon.NewValueMap()
+				v.Map().PutInt("count", 3)
+				return v
+			}(),
+			path:     "$.count",
+			expected: int64(3),
+		},
+		{
+			name:     "map[string]any",
+			target:   map[string]any{"a": map[string]any{"b": "c"}},
+			path:     "$.a.b",
+			expected: "c",
+		},
+		{
+			name:     "scalar",
+			target:   "value",
+			path:     "$.a",
+			expected: nil,
+		},
+		{
+			name:     "nil",
+			target:   nil,
+			path:     "$.a",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardGetSetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return tt.target, nil
+				},
+			}
+			path := ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.path, nil
+				},
+			}
+			exprFunc, err := query[any](target, path)
+			require.NoError(t, err)
+
+			result, err := exprFunc(t.Context(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_query_invalid_path(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		expectedErr string
+	}{
+		{
+			name:        "missing root",
+			path:        "store.name",
+			expectedErr: "must start with '$'",
+		},
+		{
+			name:        "unterminated bracket",
+			path:        "$.store[0",
+			expectedErr: "expected ',' or ']'",
+		},
+		{
+			name:        "unterminated string",
+			path:        "$['store",
+			expectedErr: "unterminated string",
+		},
+		{
+			name:        "zero step",
+			path:        "$.items[::0]",
+			expectedErr: "slice step cannot be zero",
+		},
+		{
+			name:        "empty member",
+			path:        "$.",
+			expectedErr: "missing member name",
+		},
+		{
+			name:        "invalid filter",
+			path:        "$.items[?(.price > 1)]",
+			expectedErr: "filter must start with '@'",
+		},
+		{
+			name:        "invalid literal",
+			path:        "$.items[?(@.price > abc)]",
+			expectedErr: "invalid literal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := ottl.StandardGetSetter[any]{
+				Getter: func(_ context.Context, _ any) (any, error) {
+					return pcommon.NewMap(), nil
+				},
+			}
+			path := ottl.StandardStringGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.path, nil
+				},
+			}
+			exprFunc, err := query[any](target, path)
+			require.NoError(t, err)
+
+			_, err = exprFunc(t.Context(), nil)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
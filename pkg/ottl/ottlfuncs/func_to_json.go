@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type ToJSONArguments[K any] struct {
+	Target ottl.Getter[K]
+}
+
+func NewToJSONFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ToJSON", &ToJSONArguments[K]{}, createToJSONFunction[K])
+}
+
+func createToJSONFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ToJSONArguments[K])
+
+	if !ok {
+		return nil, errors.New("ToJSONFactory args must be of type *ToJSONArguments[K]")
+	}
+
+	return toJSON(args.Target), nil
+}
+
+func toJSON[K any](target ottl.Getter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		// encoding/json sorts map keys, so the output is stable for equal inputs.
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(toRawValue(val)); err != nil {
+			return nil, fmt.Errorf("failed to encode %T as JSON: %w", val, err)
+		}
+		return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+	}
+}
+
+// toRawValue converts pdata values into their raw Go representation so they can be serialized.
+func toRawValue(val any) any {
+	switch v := val.(type) {
+	case pcommon.Map:
+		return v.AsRaw()
+	case pcommon.Slice:
+		return v.AsRaw()
+	case pcommon.Value:
+		return v.AsRaw()
+	case pcommon.ByteSlice:
+		return v.AsRaw()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_ToJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    func() any
+		expected string
+	}{
+		{
+			name: "map with stable key order",
+			value: func() any {
+				m := pcommon.NewMap()
+				m.PutStr("zeta", "last")
+				m.PutInt("alpha", 1)
+				nested := m.PutEmptyMap("middle")
+				nested.PutBool("b", true)
+				nested.PutDouble("a", 1.5)
+				return m
+			}(),
+			expected: `{"alpha":1,"middle":{"a":1.5,"b":true},"zeta":"last"}`,
+		},
+		{
+			name: "slice",
+			value: func() any {
+				s := pcommon.NewSlice()
+				s.AppendEmpty().SetStr("a")
+				s.AppendEmpty().SetInt(2)
+				s.AppendEmpty().SetEmptyMap().PutStr("k", "v")
+				return s
+			}(),
+			expected: `["a",2,{"k":"v"}]`,
+		},
+		{
+			name: "pcommon.Value",
+			value: func() any {
+				return pcommon.NewValueStr("<html> & \"quotes\"")
+			}(),
+			expected: `"<html> & \"quotes\""`,
+		},
+		{
+			name: "bytes",
+			value: func() any {
+				return []byte("hello")
+			}(),
+			expected: `"aGVsbG8="`,
+		},
+		{
+			name: "raw map",
+			value: func() any {
+				return map[string]any{"b": int64(2), "a": []any{"x"}}
+			}(),
+			expected: `{"a":["x"],"b":2}`,
+		},
+		{
+			name: "nil",
+			value: func() any {
+				return nil
+			}(),
+			expected: `null`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc := toJSON[any](&ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.value, nil
+				},
+			})
+			result, err := exprFunc(t.Context(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_ToJSON_unsupported_value(t *testing.T) {
+	exprFunc := toJSON[any](&ottl.StandardGetSetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return math.NaN(), nil
+		},
+	})
+	_, err := exprFunc(t.Context(), nil)
+	assert.ErrorContains(t, err, "failed to encode float64 as JSON")
+}
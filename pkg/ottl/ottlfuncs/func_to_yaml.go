@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type ToYAMLArguments[K any] struct {
+	Target ottl.Getter[K]
+}
+
+func NewToYAMLFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ToYAML", &ToYAMLArguments[K]{}, createToYAMLFunction[K])
+}
+
+func createToYAMLFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ToYAMLArguments[K])
+
+	if !ok {
+		return nil, errors.New("ToYAMLFactory args must be of type *ToYAMLArguments[K]")
+	}
+
+	return toYAML(args.Target), nil
+}
+
+func toYAML[K any](target ottl.Getter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		// yaml.v3 sorts map keys, so the output is stable for equal inputs.
+		out, err := yaml.Marshal(toRawValue(val))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %T as YAML: %w", val, err)
+		}
+		return string(out), nil
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_ToYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name: "map with stable key order",
+			value: func() any {
+				m := pcommon.NewMap()
+				m.PutStr("zeta", "last")
+				m.PutInt("alpha", 1)
+				s := m.PutEmptySlice("middle")
+				s.AppendEmpty().SetStr("a")
+				s.AppendEmpty().SetBool(true)
+				return m
+			}(),
+			expected: "alpha: 1\nmiddle:\n    - a\n    - true\nzeta: last\n",
+		},
+		{
+			name:     "string",
+			value:    "hello",
+			expected: "hello\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc := toYAML[any](&ottl.StandardGetSetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.value, nil
+				},
+			})
+			result, err := exprFunc(t.Context(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -42,6 +42,7 @@ func converters[K any]() []ottl.Factory[K] {
 		NewBoolFactory[K](),
 		NewDecodeFactory[K](),
 		NewCommunityIDFactory[K](),
+		NewCompressFactory[K](),
 		NewConcatFactory[K](),
 		NewContainsValueFactory[K](),
 		NewConvertCaseFactory[K](),
@@ -50,6 +51,7 @@ func converters[K any]() []ottl.Factory[K] {
 		NewDayFactory[K](),
 		NewDoubleFactory[K](),
 		NewDurationFactory[K](),
+		NewEncodeFactory[K](),
 		NewExtractPatternsFactory[K](),
 		NewExtractGrokPatternsFactory[K](),
 		NewFnvFactory[K](),
@@ -82,11 +84,15 @@ func converters[K any]() []ottl.Factory[K] {
 		NewNanosecondFactory[K](),
 		NewNanosecondsFactory[K](),
 		NewNowFactory[K](),
+		NewParseCEFFactory[K](),
 		NewParseCSVFactory[K](),
 		NewParseJSONFactory[K](),
 		NewParseKeyValueFactory[K](),
+		NewParseLEEFFactory[K](),
 		NewParseSimplifiedXMLFactory[K](),
+		NewParseW3CFactory[K](),
 		NewParseXMLFactory[K](),
+		NewQueryFactory[K](),
 		NewRemoveXMLFactory[K](),
 		NewSecondFactory[K](),
 		NewSecondsFactory[K](),
@@ -104,11 +110,13 @@ func converters[K any]() []ottl.Factory[K] {
 		NewTrimFactory[K](),
 		NewTrimPrefixFactory[K](),
 		NewTrimSuffixFactory[K](),
+		NewToJSONFactory[K](),
 		NewToKeyValueStringFactory[K](),
 		NewToCamelCaseFactory[K](),
 		NewToLowerCaseFactory[K](),
 		NewToSnakeCaseFactory[K](),
 		NewToUpperCaseFactory[K](),
+		NewToYAMLFactory[K](),
 		NewTruncateTimeFactory[K](),
 		NewTraceIDFactory[K](),
 		NewUnixFactory[K](),
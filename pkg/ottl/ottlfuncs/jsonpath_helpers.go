@@ -0,0 +1,593 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// jsonPath is a compiled JSONPath expression. It supports the following subset of RFC 9535:
+//   - the root identifier `$`
+//   - member names in dot (`.name`) and bracket (`['name']`, `["name"]`) notation
+//   - wildcards (`.*`, `[*]`)
+//   - array indexes (`[0]`, `[-1]`), slices (`[start:end:step]`) and unions (`[0,2]`, `['a','b']`)
+//   - recursive descent (`..name`, `..*`, `..[0]`)
+//   - filters comparing a relative singular path to a literal (`[?(@.status == 'ok')]`, `[?(@.count > 1)]`)
+//     or testing its existence (`[?(@.id)]`)
+//
+// Nodes are pcommon.Map, pcommon.Slice or (for scalars) pcommon.Value, so evaluating a path
+// never needs to convert the queried data to another representation.
+type jsonPath struct {
+	segments []jsonPathSegment
+	// singular is true when the path can select at most one node.
+	singular bool
+}
+
+type jsonPathSegment struct {
+	recursive bool
+	selectors []jsonPathSelector
+}
+
+type jsonPathSelector interface {
+	// appendSelected appends the children of node matched by the selector to dst.
+	appendSelected(dst []any, node any) []any
+}
+
+// evaluate returns the nodes selected by the path from root, in document order.
+func (p *jsonPath) evaluate(root any) []any {
+	nodes := []any{root}
+	for _, segment := range p.segments {
+		if segment.recursive {
+			var descendants []any
+			for _, n := range nodes {
+				descendants = appendDescendants(descendants, n)
+			}
+			nodes = descendants
+		}
+		var selected []any
+		for _, n := range nodes {
+			for _, s := range segment.selectors {
+				selected = s.appendSelected(selected, n)
+			}
+		}
+		nodes = selected
+		if len(nodes) == 0 {
+			break
+		}
+	}
+	return nodes
+}
+
+// toJSONPathNode unwraps pcommon.Value containers so that nodes are uniformly represented.
+func toJSONPathNode(v pcommon.Value) any {
+	switch v.Type() {
+	case pcommon.ValueTypeMap:
+		return v.Map()
+	case pcommon.ValueTypeSlice:
+		return v.Slice()
+	default:
+		return v
+	}
+}
+
+// appendDescendants appends node and all of its descendants to dst, in document order.
+func appendDescendants(dst []any, node any) []any {
+	dst = append(dst, node)
+	switch n := node.(type) {
+	case pcommon.Map:
+		for _, v := range n.All() {
+			dst = appendDescendants(dst, toJSONPathNode(v))
+		}
+	case pcommon.Slice:
+		for _, v := range n.All() {
+			dst = appendDescendants(dst, toJSONPathNode(v))
+		}
+	}
+	return dst
+}
+
+type jsonPathNameSelector struct {
+	name string
+}
+
+func (s jsonPathNameSelector) appendSelected(dst []any, node any) []any {
+	m, ok := node.(pcommon.Map)
+	if !ok {
+		return dst
+	}
+	if v, ok := m.Get(s.name); ok {
+		dst = append(dst, toJSONPathNode(v))
+	}
+	return dst
+}
+
+type jsonPathWildcardSelector struct{}
+
+func (jsonPathWildcardSelector) appendSelected(dst []any, node any) []any {
+	switch n := node.(type) {
+	case pcommon.Map:
+		for _, v := range n.All() {
+			dst = append(dst, toJSONPathNode(v))
+		}
+	case pcommon.Slice:
+		for _, v := range n.All() {
+			dst = append(dst, toJSONPathNode(v))
+		}
+	}
+	return dst
+}
+
+type jsonPathIndexSelector struct {
+	index int
+}
+
+func (s jsonPathIndexSelector) appendSelected(dst []any, node any) []any {
+	sl, ok := node.(pcommon.Slice)
+	if !ok {
+		return dst
+	}
+	i := s.index
+	if i < 0 {
+		i += sl.Len()
+	}
+	if i < 0 || i >= sl.Len() {
+		return dst
+	}
+	return append(dst, toJSONPathNode(sl.At(i)))
+}
+
+type jsonPathSliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s jsonPathSliceSelector) appendSelected(dst []any, node any) []any {
+	sl, ok := node.(pcommon.Slice)
+	if !ok {
+		return dst
+	}
+	n := sl.Len()
+	normalize := func(i int) int {
+		if i < 0 {
+			return i + n
+		}
+		return i
+	}
+
+	if s.step > 0 {
+		lower, upper := 0, n
+		if s.start != nil {
+			lower = min(max(normalize(*s.start), 0), n)
+		}
+		if s.end != nil {
+			upper = min(max(normalize(*s.end), 0), n)
+		}
+		for i := lower; i < upper; i += s.step {
+			dst = append(dst, toJSONPathNode(sl.At(i)))
+		}
+		return dst
+	}
+
+	upper, lower := n-1, -1
+	if s.start != nil {
+		upper = min(max(normalize(*s.start), -1), n-1)
+	}
+	if s.end != nil {
+		lower = min(max(normalize(*s.end), -1), n-1)
+	}
+	for i := upper; i > lower; i += s.step {
+		dst = append(dst, toJSONPathNode(sl.At(i)))
+	}
+	return dst
+}
+
+type jsonPathFilterSelector struct {
+	// path is a singular path relative to the current node (`@`).
+	path *jsonPath
+	// op is empty for existence tests.
+	op      string
+	literal any
+}
+
+func (s jsonPathFilterSelector) appendSelected(dst []any, node any) []any {
+	var candidates []any
+	candidates = jsonPathWildcardSelector{}.appendSelected(candidates, node)
+	for _, c := range candidates {
+		if s.matches(c) {
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+func (s jsonPathFilterSelector) matches(node any) bool {
+	selected := s.path.evaluate(node)
+	if s.op == "" {
+		return len(selected) == 1
+	}
+	if len(selected) != 1 {
+		// A missing value only equals nothing, so it is only matched by `!=`.
+		return s.op == "!="
+	}
+
+	v, ok := selected[0].(pcommon.Value)
+	if !ok {
+		// Maps and slices can only be compared for inequality against literals.
+		return s.op == "!="
+	}
+
+	switch s.op {
+	case "==":
+		return jsonPathEqual(v, s.literal)
+	case "!=":
+		return !jsonPathEqual(v, s.literal)
+	}
+
+	cmp, ok := jsonPathCompare(v, s.literal)
+	if !ok {
+		return false
+	}
+	switch s.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func jsonPathEqual(v pcommon.Value, literal any) bool {
+	switch l := literal.(type) {
+	case nil:
+		return v.Type() == pcommon.ValueTypeEmpty
+	case string:
+		return v.Type() == pcommon.ValueTypeStr && v.Str() == l
+	case bool:
+		return v.Type() == pcommon.ValueTypeBool && v.Bool() == l
+	case float64:
+		f, ok := jsonPathNumber(v)
+		return ok && f == l
+	}
+	return false
+}
+
+func jsonPathCompare(v pcommon.Value, literal any) (int, bool) {
+	switch l := literal.(type) {
+	case string:
+		if v.Type() != pcommon.ValueTypeStr {
+			return 0, false
+		}
+		return strings.Compare(v.Str(), l), true
+	case float64:
+		f, ok := jsonPathNumber(v)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case f < l:
+			return -1, true
+		case f > l:
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func jsonPathNumber(v pcommon.Value) (float64, bool) {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return float64(v.Int()), true
+	case pcommon.ValueTypeDouble:
+		return v.Double(), true
+	}
+	return 0, false
+}
+
+// parseJSONPath compiles a JSONPath expression, which must start with the root identifier `$`.
+func parseJSONPath(expr string) (*jsonPath, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with '$'", expr)
+	}
+	p := &jsonPathParser{input: expr, pos: 1}
+	path, err := p.parseSegments(false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+	}
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q at position %d", expr, p.input[p.pos], p.pos)
+	}
+	return path, nil
+}
+
+type jsonPathParser struct {
+	input string
+	pos   int
+}
+
+// parseSegments parses segments until the end of the input or, for relative filter
+// paths, until a character that can't start a segment.
+func (p *jsonPathParser) parseSegments(relative bool) (*jsonPath, error) {
+	path := &jsonPath{singular: true}
+	for p.pos < len(p.input) {
+		var segment jsonPathSegment
+		var err error
+		switch {
+		case strings.HasPrefix(p.input[p.pos:], ".."):
+			if relative {
+				return nil, errors.New("recursive descent is not supported in filter paths")
+			}
+			p.pos += 2
+			segment.recursive = true
+			if p.peek() == '[' {
+				segment.selectors, err = p.parseBracket(relative)
+			} else {
+				segment.selectors, err = p.parseMember(relative)
+			}
+		case p.peek() == '.':
+			p.pos++
+			segment.selectors, err = p.parseMember(relative)
+		case p.peek() == '[':
+			segment.selectors, err = p.parseBracket(relative)
+		default:
+			if relative {
+				return path, nil
+			}
+			return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+		}
+		if err != nil {
+			return nil, err
+		}
+		path.segments = append(path.segments, segment)
+		path.singular = path.singular && isSingularSegment(segment)
+	}
+	return path, nil
+}
+
+func isSingularSegment(segment jsonPathSegment) bool {
+	if segment.recursive || len(segment.selectors) != 1 {
+		return false
+	}
+	switch segment.selectors[0].(type) {
+	case jsonPathNameSelector, jsonPathIndexSelector:
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *jsonPathParser) skipSpaces() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// parseMember parses the member name or wildcard following a dot.
+func (p *jsonPathParser) parseMember(relative bool) ([]jsonPathSelector, error) {
+	if p.peek() == '*' {
+		if relative {
+			return nil, errors.New("wildcards are not supported in filter paths")
+		}
+		p.pos++
+		return []jsonPathSelector{jsonPathWildcardSelector{}}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune(".[]() \t=!<>,", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, fmt.Errorf("missing member name at position %d", start)
+	}
+	return []jsonPathSelector{jsonPathNameSelector{name: p.input[start:p.pos]}}, nil
+}
+
+// parseBracket parses a comma separated list of selectors enclosed in brackets.
+func (p *jsonPathParser) parseBracket(relative bool) ([]jsonPathSelector, error) {
+	p.pos++ // '['
+	var selectors []jsonPathSelector
+	for {
+		p.skipSpaces()
+		selector, err := p.parseSelector(relative)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+		p.skipSpaces()
+		switch p.peek() {
+		case ',':
+			if relative {
+				return nil, errors.New("unions are not supported in filter paths")
+			}
+			p.pos++
+		case ']':
+			p.pos++
+			return selectors, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or ']' at position %d", p.pos)
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSelector(relative bool) (jsonPathSelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathNameSelector{name: name}, nil
+	case c == '*' && !relative:
+		p.pos++
+		return jsonPathWildcardSelector{}, nil
+	case c == '?' && !relative:
+		p.pos++
+		return p.parseFilter()
+	case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+		return p.parseIndexOrSlice(relative)
+	}
+	return nil, fmt.Errorf("invalid selector at position %d", p.pos)
+}
+
+func (p *jsonPathParser) parseString() (string, error) {
+	quote := p.input[p.pos]
+	var b strings.Builder
+	for p.pos++; p.pos < len(p.input); p.pos++ {
+		c := p.input[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.input):
+			p.pos++
+			b.WriteByte(p.input[p.pos])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated string")
+}
+
+func (p *jsonPathParser) parseInt() (*int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer %q", p.input[start:p.pos])
+	}
+	return &i, nil
+}
+
+func (p *jsonPathParser) parseIndexOrSlice(relative bool) (jsonPathSelector, error) {
+	start, err := p.parseInt()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.peek() != ':' {
+		if start == nil {
+			return nil, fmt.Errorf("invalid index at position %d", p.pos)
+		}
+		return jsonPathIndexSelector{index: *start}, nil
+	}
+	if relative {
+		return nil, errors.New("slices are not supported in filter paths")
+	}
+
+	p.pos++ // ':'
+	p.skipSpaces()
+	end, err := p.parseInt()
+	if err != nil {
+		return nil, err
+	}
+	step := 1
+	p.skipSpaces()
+	if p.peek() == ':' {
+		p.pos++
+		p.skipSpaces()
+		s, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			step = *s
+		}
+	}
+	if step == 0 {
+		return nil, errors.New("slice step cannot be zero")
+	}
+	return jsonPathSliceSelector{start: start, end: end, step: step}, nil
+}
+
+// parseFilter parses a filter expression of the form `(@<path> <op> <literal>)` or `(@<path>)`.
+// The parentheses are optional.
+func (p *jsonPathParser) parseFilter() (jsonPathSelector, error) {
+	p.skipSpaces()
+	parenthesized := p.peek() == '('
+	if parenthesized {
+		p.pos++
+		p.skipSpaces()
+	}
+	if p.peek() != '@' {
+		return nil, fmt.Errorf("filter must start with '@' at position %d", p.pos)
+	}
+	p.pos++
+	path, err := p.parseSegments(true)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := jsonPathFilterSelector{path: path}
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			filter.op = op
+			p.pos += len(op)
+			p.skipSpaces()
+			filter.literal, err = p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpaces()
+			break
+		}
+	}
+
+	if parenthesized {
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("expected ')' at position %d", p.pos)
+		}
+		p.pos++
+	}
+	return filter, nil
+}
+
+func (p *jsonPathParser) parseLiteral() (any, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		return p.parseString()
+	case strings.HasPrefix(p.input[p.pos:], "true"):
+		p.pos += len("true")
+		return true, nil
+	case strings.HasPrefix(p.input[p.pos:], "false"):
+		p.pos += len("false")
+		return false, nil
+	case strings.HasPrefix(p.input[p.pos:], "null"):
+		p.pos += len("null")
+		return nil, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && strings.ContainsRune("+-.eE0123456789", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid literal at position %d", start)
+	}
+	return f, nil
+}
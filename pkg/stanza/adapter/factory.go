@@ -71,9 +71,13 @@ func createLogsReceiver(logReceiverType LogReceiverType) rcvr.CreateLogsFunc {
 		}
 
 		var emitter helper.LogEmitter
-		if metadata.StanzaSynchronousLogEmitterFeatureGate.IsEnabled() {
+		switch {
+		case requiresAcknowledgement(inputCfg):
+			// The input only moves past its entries once they're accepted by the consumer.
+			emitter = helper.NewAcknowledgingLogEmitter(params.TelemetrySettings, rcv.deliverEntries)
+		case metadata.StanzaSynchronousLogEmitterFeatureGate.IsEnabled():
 			emitter = helper.NewSynchronousLogEmitter(params.TelemetrySettings, rcv.consumeEntries)
-		} else {
+		default:
 			emitter = helper.NewBatchingLogEmitter(params.TelemetrySettings, rcv.consumeEntries, emitterOpts...)
 		}
 
@@ -91,3 +95,8 @@ func createLogsReceiver(logReceiverType LogReceiverType) rcvr.CreateLogsFunc {
 		return rcv, nil
 	}
 }
+
+func requiresAcknowledgement(cfg operator.Config) bool {
+	builder, ok := cfg.Builder.(operator.AcknowledgingBuilder)
+	return ok && builder.RequiresAcknowledgement()
+}
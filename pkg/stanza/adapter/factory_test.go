@@ -4,6 +4,7 @@
 package adapter
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -11,11 +12,23 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/receiver/receivertest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/jsonparser"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/regex"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/noop"
 )
 
+// acknowledgingConfig is the config of an input which requires its entries to be acknowledged.
+type acknowledgingConfig struct {
+	*noop.Config
+}
+
+func (acknowledgingConfig) RequiresAcknowledgement() bool {
+	return true
+}
+
 func TestCreateReceiver(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		factory := NewFactory(TestReceiverType{}, component.StabilityLevelDevelopment)
@@ -30,6 +43,19 @@ func TestCreateReceiver(t *testing.T) {
 		require.NotNil(t, receiver, "receiver creation failed")
 	})
 
+	t.Run("AcknowledgingInput", func(t *testing.T) {
+		factory := NewFactory(TestReceiverType{}, component.StabilityLevelDevelopment)
+		cfg := factory.CreateDefaultConfig().(*TestConfig)
+		cfg.Input = operator.NewConfig(acknowledgingConfig{Config: noop.NewConfig()})
+		consumeErr := errors.New("rejected")
+		rcv, err := factory.CreateLogs(t.Context(), receivertest.NewNopSettings(factory.Type()), cfg, consumertest.NewErr(consumeErr))
+		require.NoError(t, err, "receiver creation failed")
+
+		emitter := rcv.(*receiver).emitter
+		require.IsType(t, &helper.SynchronousLogEmitter{}, emitter)
+		require.ErrorIs(t, emitter.ProcessBatch(t.Context(), []*entry.Entry{entry.New()}), consumeErr)
+	})
+
 	t.Run("DecodeOperatorConfigsFailureMissingFields", func(t *testing.T) {
 		factory := NewFactory(TestReceiverType{}, component.StabilityLevelDevelopment)
 		badCfg := factory.CreateDefaultConfig().(*TestConfig)
@@ -9,6 +9,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/extension/xextension/storage"
 	rcvr "go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
@@ -52,6 +53,13 @@ func (r *receiver) Start(ctx context.Context, host component.Host) error {
 }
 
 func (r *receiver) consumeEntries(ctx context.Context, entries []*entry.Entry) {
+	_ = r.deliverEntries(ctx, entries)
+}
+
+// deliverEntries consumes the entries, and returns the error of the consumer so that inputs
+// which wait for their entries to be accepted can emit them again. The entries rejected with
+// a permanent error are dropped, since they would be rejected again.
+func (r *receiver) deliverEntries(ctx context.Context, entries []*entry.Entry) error {
 	obsrecvCtx := r.obsrecv.StartLogsOp(ctx)
 	pLogs := ConvertEntries(entries)
 	logRecordCount := pLogs.LogRecordCount()
@@ -61,6 +69,10 @@ func (r *receiver) consumeEntries(ctx context.Context, entries []*entry.Entry) {
 		r.set.Logger.Error("ConsumeLogs() failed", zap.Error(cErr))
 	}
 	r.obsrecv.EndLogsOp(obsrecvCtx, "stanza", logRecordCount, cErr)
+	if consumererror.IsPermanent(cErr) {
+		return nil
+	}
+	return cErr
 }
 
 // Shutdown is invoked during service shutdown
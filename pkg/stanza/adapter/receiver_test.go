@@ -5,8 +5,11 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -16,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
@@ -26,6 +30,8 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/file"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/regex"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/pipeline"
 )
 
@@ -160,6 +166,99 @@ func TestShutdownFlush(t *testing.T) {
 	)
 }
 
+// startAtLeastOnceReceiver starts a receiver reading the lines of a file at least once, and
+// parsing them with a regex parser which fails on the lines that aren't numbers.
+func startAtLeastOnceReceiver(t *testing.T, next consumer.Logs) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "test.log"), []byte("1\nnot a number\n3\n"), 0o600))
+
+	fileCfg := file.NewConfig()
+	fileCfg.Include = []string{filepath.Join(tempDir, "*.log")}
+	fileCfg.StartAt = "beginning"
+	fileCfg.PollInterval = 10 * time.Millisecond
+	fileCfg.AtLeastOnce = true
+
+	regexCfg := regex.NewConfig()
+	regexCfg.Regex = `^(?P<number>\d+)$`
+
+	factory := NewFactory(TestReceiverType{}, component.StabilityLevelDevelopment)
+	cfg := factory.CreateDefaultConfig().(*TestConfig)
+	cfg.Input = operator.NewConfig(fileCfg)
+	cfg.Operators = []operator.Config{operator.NewConfig(regexCfg)}
+
+	rcv, err := factory.CreateLogs(t.Context(), receivertest.NewNopSettings(factory.Type()), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(t.Context(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	})
+}
+
+func TestAtLeastOnceOperatorError(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	startAtLeastOnceReceiver(t, sink)
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The line which failed to be parsed was sent on, so the lines aren't emitted again.
+	time.Sleep(100 * time.Millisecond)
+	var bodies []string
+	for _, logs := range sink.AllLogs() {
+		records := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			bodies = append(bodies, records.At(i).Body().AsString())
+		}
+	}
+	assert.Equal(t, []string{"1", "not a number", "3"}, bodies)
+}
+
+func TestAtLeastOncePermanentError(t *testing.T) {
+	var calls atomic.Int64
+	startAtLeastOnceReceiver(t, consumerFunc(func(context.Context, plog.Logs) error {
+		calls.Add(1)
+		return consumererror.NewPermanent(errors.New("rejected"))
+	}))
+
+	require.Eventually(t, func() bool {
+		return calls.Load() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The lines rejected permanently are dropped rather than emitted again.
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int64(1), calls.Load())
+}
+
+func TestAtLeastOnceRetryableError(t *testing.T) {
+	var calls atomic.Int64
+	sink := &consumertest.LogsSink{}
+	startAtLeastOnceReceiver(t, consumerFunc(func(ctx context.Context, ld plog.Logs) error {
+		if calls.Add(1) == 1 {
+			return errors.New("rejected")
+		}
+		return sink.ConsumeLogs(ctx, ld)
+	}))
+
+	// The lines rejected with a retryable error are emitted again.
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 3, sink.LogRecordCount())
+}
+
+// consumerFunc is a consumer.Logs calling the function for each plog.Logs.
+type consumerFunc func(context.Context, plog.Logs) error
+
+func (consumerFunc) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{}
+}
+
+func (f consumerFunc) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	return f(ctx, ld)
+}
+
 func BenchmarkReceiverWithBatchingLogEmitter(b *testing.B) {
 	for n := range 6 {
 		logEntries := int(math.Pow(10, float64(n)))
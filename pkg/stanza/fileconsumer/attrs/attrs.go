@@ -19,8 +19,12 @@ const (
 	LogFileOwnerGroupName = "log.file.owner.group.name"
 	LogFileRecordNumber   = "log.file.record_number"
 	LogFileRecordOffset   = "log.file.record_offset"
+	LogFileEncoding       = "log.file.encoding"
 )
 
+// MemberSeparator separates the name of an archive from the name of a file read from it.
+const MemberSeparator = "!"
+
 type Resolver struct {
 	IncludeFileName           bool `mapstructure:"include_file_name,omitempty"`
 	IncludeFilePath           bool `mapstructure:"include_file_path,omitempty"`
@@ -72,3 +76,18 @@ func (r *Resolver) Resolve(file *os.File) (attributes map[string]any, err error)
 	}
 	return attributes, nil
 }
+
+// ResolveMember resolves the attributes of a file read from an archive. The names and paths
+// are those of the archive, followed by the name of the file in the archive.
+func (r *Resolver) ResolveMember(archive *os.File, member string) (map[string]any, error) {
+	attributes, err := r.Resolve(archive)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{LogFileName, LogFilePath, LogFileNameResolved, LogFilePathResolved} {
+		if value, ok := attributes[key]; ok {
+			attributes[key] = value.(string) + MemberSeparator + member
+		}
+	}
+	return attributes, nil
+}
@@ -89,3 +89,20 @@ func TestResolver(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveMember(t *testing.T) {
+	t.Parallel()
+
+	r := Resolver{
+		IncludeFileName: true,
+		IncludeFilePath: true,
+	}
+	temp := filetest.OpenTemp(t, t.TempDir())
+
+	attributes, err := r.ResolveMember(temp, "logs/app.log")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		LogFileName: filepath.Base(temp.Name()) + "!logs/app.log",
+		LogFilePath: temp.Name() + "!logs/app.log",
+	}, attributes)
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+)
+
+// backlog keeps track of how far reading the files is behind their end during a poll.
+type backlog struct {
+	mu    sync.Mutex
+	files map[string]reader.Lag
+	// lags are the lags of the files read during the last poll, which are the files
+	// still open. They are reported by the callback of the file lag gauge, so that
+	// the files which are no longer tracked aren't reported anymore.
+	lags map[string]int64
+}
+
+func newBacklog() *backlog {
+	return &backlog{files: make(map[string]reader.Lag)}
+}
+
+// add sets the lag of a file after it was read. Files may be read more than once
+// during a poll, in which case only the last lag is kept.
+func (b *backlog) add(path string, lag reader.Lag) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files[path] = lag
+}
+
+// record records the backlog of the files that were read since the last call, and starts
+// over for the next poll.
+func (b *backlog) record(ctx context.Context, tb *metadata.TelemetryBuilder) {
+	b.mu.Lock()
+	files := b.files
+	b.files = make(map[string]reader.Lag)
+	b.lags = make(map[string]int64, len(files))
+	for path, lag := range files {
+		b.lags[path] = lag.Bytes
+	}
+	b.mu.Unlock()
+
+	var bytes, pending, oldest int64
+	for _, lag := range files {
+		if lag.Bytes == 0 {
+			continue
+		}
+		bytes += lag.Bytes
+		pending++
+		if modTime := lag.ModTime.Unix(); oldest == 0 || modTime < oldest {
+			oldest = modTime
+		}
+	}
+	tb.FileconsumerBytesRemaining.Record(ctx, bytes)
+	tb.FileconsumerFilesPending.Record(ctx, pending)
+	tb.FileconsumerOldestUnreadModificationTime.Record(ctx, oldest)
+}
+
+// observe reports the lag of the files read during the last poll.
+func (b *backlog) observe(_ context.Context, o metric.Int64Observer) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for path, bytes := range b.lags {
+		o.Observe(bytes, metric.WithAttributes(attribute.String(attrs.LogFilePath, path)))
+	}
+	return nil
+}
+
+// reset forgets the lags of the files, once they are no longer tracked.
+func (b *backlog) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files = make(map[string]reader.Lag)
+	b.lags = nil
+}
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileconsumer
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/metadatatest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/internal/filetest"
+)
+
+func TestBacklogTelemetry(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.RateLimit.MaxBytesPerRead = 5
+	operator, sink, tel := testManagerWithTelemetry(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "log1\nlog2\n")
+	info, err := os.Stat(temp.Name())
+	require.NoError(t, err)
+
+	// The file yields after its first log
+	operator.poll(t.Context())
+	sink.ExpectToken(t, []byte("log1"))
+	metadatatest.AssertEqualFileconsumerBytesRemaining(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 5}},
+		metricdatatest.IgnoreTimestamp())
+	metadatatest.AssertEqualFileconsumerFilesPending(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 1}},
+		metricdatatest.IgnoreTimestamp())
+	metadatatest.AssertEqualFileconsumerOldestUnreadModificationTime(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: info.ModTime().Unix()}},
+		metricdatatest.IgnoreTimestamp())
+	metadatatest.AssertEqualFileconsumerFileLag(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 5, Attributes: attribute.NewSet(attribute.String("log.file.path", temp.Name()))}},
+		metricdatatest.IgnoreTimestamp())
+
+	operator.catchUp(t.Context())
+	sink.ExpectToken(t, []byte("log2"))
+	metadatatest.AssertEqualFileconsumerBytesRemaining(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 0}},
+		metricdatatest.IgnoreTimestamp())
+	metadatatest.AssertEqualFileconsumerFilesPending(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 0}},
+		metricdatatest.IgnoreTimestamp())
+	metadatatest.AssertEqualFileconsumerOldestUnreadModificationTime(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 0}},
+		metricdatatest.IgnoreTimestamp())
+}
+
+func TestFileLagTelemetry(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("Removing files while open is unsupported on Windows")
+	}
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	operator, sink, tel := testManagerWithTelemetry(t, cfg)
+
+	temp1 := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp1, "log1\n")
+	temp2 := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp2, "log2\n")
+
+	operator.poll(t.Context())
+	sink.ExpectTokens(t, []byte("log1"), []byte("log2"))
+	metadatatest.AssertEqualFileconsumerFileLag(t, tel,
+		[]metricdata.DataPoint[int64]{
+			{Value: 0, Attributes: attribute.NewSet(attribute.String("log.file.path", temp1.Name()))},
+			{Value: 0, Attributes: attribute.NewSet(attribute.String("log.file.path", temp2.Name()))},
+		},
+		metricdatatest.IgnoreTimestamp())
+
+	// The lag of a file is no longer reported once it is removed.
+	require.NoError(t, temp2.Close())
+	require.NoError(t, os.Remove(temp2.Name()))
+	operator.poll(t.Context())
+	metadatatest.AssertEqualFileconsumerFileLag(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 0, Attributes: attribute.NewSet(attribute.String("log.file.path", temp1.Name()))}},
+		metricdatatest.IgnoreTimestamp())
+}
+
+func TestRotationTelemetry(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("Moving files while open is unsupported on Windows")
+	}
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	operator, sink, tel := testManagerWithTelemetry(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "testlog1\n")
+	operator.poll(t.Context())
+	sink.ExpectToken(t, []byte("testlog1"))
+
+	// Move the file within the pattern, and create a new one in its place
+	require.NoError(t, temp.Close())
+	require.NoError(t, os.Rename(temp.Name(), temp.Name()+".1"))
+	filetest.WriteString(t, filetest.OpenFile(t, temp.Name()), "testlog2\n")
+
+	operator.poll(t.Context())
+	sink.ExpectToken(t, []byte("testlog2"))
+	metadatatest.AssertEqualFileconsumerRotations(t, tel,
+		[]metricdata.DataPoint[int64]{{Value: 1, Attributes: attribute.NewSet(attribute.String("rotation.type", "moved"))}},
+		metricdatatest.IgnoreTimestamp())
+}
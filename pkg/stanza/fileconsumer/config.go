@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -19,9 +20,13 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/textutils"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/bundle"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/charset"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/compression"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/header"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/ratelimit"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/scanner"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher"
@@ -35,6 +40,12 @@ const (
 	defaultMaxConcurrentFiles = 1024
 	defaultEncoding           = "utf-8"
 	defaultPollInterval       = 200 * time.Millisecond
+	defaultFallbackInterval   = 10 * time.Second
+)
+
+const (
+	watchModePoll   = "poll"
+	watchModeNotify = "notify"
 )
 
 // NewConfig creates a new input config with default values
@@ -51,6 +62,10 @@ func NewConfig() *Config {
 		Resolver: attrs.Resolver{
 			IncludeFileName: true,
 		},
+		Watch: WatchConfig{
+			Mode:             watchModePoll,
+			FallbackInterval: defaultFallbackInterval,
+		},
 	}
 }
 
@@ -74,8 +89,78 @@ type Config struct {
 	IncludeFileRecordNumber bool            `mapstructure:"include_file_record_number,omitempty"`
 	IncludeFileRecordOffset bool            `mapstructure:"include_file_record_offset,omitempty"`
 	Compression             string          `mapstructure:"compression,omitempty"`
+	Archive                 string          `mapstructure:"archive,omitempty"`
 	PollsToArchive          int             `mapstructure:"polls_to_archive,omitempty"`
 	AcquireFSLock           bool            `mapstructure:"acquire_fs_lock,omitempty"`
+	Watch                   WatchConfig     `mapstructure:"watch,omitempty"`
+	// AtLeastOnce only moves the offsets of files past the logs that were accepted by the emit
+	// function, so that rejected logs are read again and only accepted logs are checkpointed.
+	AtLeastOnce bool            `mapstructure:"at_least_once,omitempty"`
+	RateLimit   RateLimitConfig `mapstructure:"rate_limit,omitempty"`
+}
+
+// RateLimitConfig limits how fast files are read, so that a few busy files can't starve the others.
+type RateLimitConfig struct {
+	// MaxBytesPerRead is the number of bytes read from a file before moving on to the next file.
+	// The remaining contents of the file are read right after the other files were read.
+	MaxBytesPerRead helper.ByteSize `mapstructure:"max_bytes_per_read,omitempty"`
+	// BytesPerSecond limits the number of bytes read per second from each file.
+	BytesPerSecond helper.ByteSize `mapstructure:"bytes_per_second,omitempty"`
+	// LinesPerSecond limits the number of logs read per second from each file.
+	LinesPerSecond int `mapstructure:"lines_per_second,omitempty"`
+	// Groups limit the bytes and logs read per second from all the files matching their patterns.
+	Groups []RateLimitGroupConfig `mapstructure:"groups,omitempty"`
+}
+
+// RateLimitGroupConfig is a rate limit shared by the files matching one of the patterns.
+type RateLimitGroupConfig struct {
+	Include        []string        `mapstructure:"include,omitempty"`
+	BytesPerSecond helper.ByteSize `mapstructure:"bytes_per_second,omitempty"`
+	LinesPerSecond int             `mapstructure:"lines_per_second,omitempty"`
+}
+
+func (c RateLimitConfig) validate() error {
+	if c.MaxBytesPerRead < 0 {
+		return errors.New("'max_bytes_per_read' must not be negative")
+	}
+	if c.BytesPerSecond < 0 {
+		return errors.New("'bytes_per_second' must not be negative")
+	}
+	if c.LinesPerSecond < 0 {
+		return errors.New("'lines_per_second' must not be negative")
+	}
+	_, err := c.limiter()
+	return err
+}
+
+// limiter builds the rate limiter, or returns nil if no rate is limited.
+func (c RateLimitConfig) limiter() (*ratelimit.Limiter, error) {
+	if c.BytesPerSecond == 0 && c.LinesPerSecond == 0 && len(c.Groups) == 0 {
+		return nil, nil
+	}
+	groups := make([]*ratelimit.Group, 0, len(c.Groups))
+	for i, g := range c.Groups {
+		if g.BytesPerSecond < 0 || g.LinesPerSecond < 0 {
+			return nil, fmt.Errorf("invalid group %d: rates must not be negative", i)
+		}
+		group, err := ratelimit.NewGroup(g.Include, int64(g.BytesPerSecond), int64(g.LinesPerSecond))
+		if err != nil {
+			return nil, fmt.Errorf("invalid group %d: %w", i, err)
+		}
+		groups = append(groups, group)
+	}
+	return ratelimit.New(int64(c.BytesPerSecond), int64(c.LinesPerSecond), groups...), nil
+}
+
+// WatchConfig configures how changes to the files are discovered.
+type WatchConfig struct {
+	// Mode is either "poll", to match and read files every 'poll_interval', or "notify",
+	// to read files as soon as file system notifications report them as changed.
+	// Notifications are only supported on Linux, other platforms fall back to polling.
+	Mode string `mapstructure:"mode,omitempty"`
+	// FallbackInterval is the duration between polls in "notify" mode, which catch up
+	// with missed notifications and directories that can't be watched.
+	FallbackInterval time.Duration `mapstructure:"fallback_interval,omitempty"`
 }
 
 type HeaderConfig struct {
@@ -96,7 +181,7 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		opt(o)
 	}
 
-	enc, err := textutils.LookupEncoding(c.Encoding)
+	enc, err := c.lookupEncoding()
 	if err != nil {
 		return nil, fmt.Errorf("failed to find encoding: %w", err)
 	}
@@ -109,6 +194,19 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		}
 	}
 
+	var encodingSplitFuncs map[string]bufio.SplitFunc
+	if c.detectEncoding() {
+		encodingSplitFuncs = make(map[string]bufio.SplitFunc)
+		for name, detectable := range charset.Encodings() {
+			encodingSplitFuncs[name] = o.splitFunc
+			if o.splitFunc == nil {
+				if encodingSplitFuncs[name], err = c.SplitConfig.Func(detectable, false, int(c.MaxLogSize)); err != nil {
+					return nil, fmt.Errorf("encoding %s: %w", name, err)
+				}
+			}
+		}
+	}
+
 	trimFunc := trim.Nop
 	if enc != encoding.Nop {
 		trimFunc = c.TrimConfig.Func()
@@ -137,6 +235,11 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		return nil, err
 	}
 
+	rateLimiter, err := c.RateLimit.limiter()
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'rate_limit': %w", err)
+	}
+
 	set.Logger = set.Logger.With(zap.String("component", "fileconsumer"))
 	readerFactory := &reader.Factory{
 		TelemetrySettings:       set,
@@ -146,6 +249,7 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		MaxLogSize:              int(c.MaxLogSize),
 		Encoding:                enc,
 		SplitFunc:               splitFunc,
+		EncodingSplitFuncs:      encodingSplitFuncs,
 		TrimFunc:                trimFunc,
 		FlushTimeout:            c.FlushPeriod,
 		EmitFunc:                emit,
@@ -155,6 +259,9 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		IncludeFileRecordNumber: c.IncludeFileRecordNumber,
 		Compression:             c.Compression,
 		AcquireFSLock:           c.AcquireFSLock,
+		AtLeastOnce:             c.AtLeastOnce,
+		MaxBytesPerRead:         int64(c.RateLimit.MaxBytesPerRead),
+		RateLimiter:             rateLimiter,
 	}
 
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(set)
@@ -162,6 +269,11 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		return nil, err
 	}
 
+	backlog := newBacklog()
+	if err = telemetryBuilder.RegisterFileconsumerFileLagCallback(backlog.observe); err != nil {
+		return nil, err
+	}
+
 	maxBatchFiles := c.MaxConcurrentFiles / 2
 	if maxBatchFiles == 0 {
 		maxBatchFiles = 1
@@ -177,6 +289,12 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		telemetryBuilder: telemetryBuilder,
 		noTracking:       o.noTracking,
 		pollsToArchive:   c.PollsToArchive,
+		watchMode:        c.Watch.Mode,
+		fallbackInterval: c.Watch.FallbackInterval,
+		include:          c.Include,
+		archiveFormat:    c.Archive,
+		members:          newMemberTracker(),
+		backlog:          backlog,
 	}, nil
 }
 
@@ -201,7 +319,29 @@ func (c Config) validate() error {
 		return errors.New("'max_batches' must not be negative")
 	}
 
-	enc, err := textutils.LookupEncoding(c.Encoding)
+	switch c.Watch.Mode {
+	case "", watchModePoll:
+	case watchModeNotify:
+		if c.Watch.FallbackInterval <= 0 {
+			return errors.New("'watch.fallback_interval' must be positive")
+		}
+	default:
+		return fmt.Errorf("invalid 'watch.mode' %q, must be one of %q or %q", c.Watch.Mode, watchModePoll, watchModeNotify)
+	}
+
+	if err := c.RateLimit.validate(); err != nil {
+		return fmt.Errorf("invalid 'rate_limit': %w", err)
+	}
+
+	if err := compression.Validate(c.Compression); err != nil {
+		return fmt.Errorf("invalid 'compression': %w", err)
+	}
+
+	if err := bundle.Validate(c.Archive); err != nil {
+		return fmt.Errorf("invalid 'archive': %w", err)
+	}
+
+	enc, err := c.lookupEncoding()
 	if err != nil {
 		return err
 	}
@@ -215,7 +355,17 @@ func (c Config) validate() error {
 		}
 	}
 
+	if c.Archive != "" && c.DeleteAfterRead {
+		return errors.New("'delete_after_read' cannot be used with 'archive'")
+	}
+
 	if c.Header != nil {
+		if c.Archive != "" {
+			return errors.New("'header' cannot be used with 'archive'")
+		}
+		if c.detectEncoding() {
+			return fmt.Errorf("'header' cannot be used with 'encoding: %s'", charset.Auto)
+		}
 		if !metadata.FilelogAllowHeaderMetadataParsingFeatureGate.IsEnabled() {
 			return fmt.Errorf("'header' requires feature gate '%s'", metadata.FilelogAllowHeaderMetadataParsingFeatureGate.ID())
 		}
@@ -235,6 +385,20 @@ func (c Config) validate() error {
 	return nil
 }
 
+// detectEncoding reports whether the encoding of each file is detected from its first bytes.
+func (c Config) detectEncoding() bool {
+	return strings.EqualFold(c.Encoding, charset.Auto)
+}
+
+// lookupEncoding returns the encoding of the files. When the encoding of each file is detected,
+// it's the encoding of the files whose encoding can't be detected, such as files in archives.
+func (c Config) lookupEncoding() (encoding.Encoding, error) {
+	if c.detectEncoding() {
+		return charset.Lookup(charset.UTF8), nil
+	}
+	return textutils.LookupEncoding(c.Encoding)
+}
+
 type options struct {
 	splitFunc  bufio.SplitFunc
 	noTracking bool
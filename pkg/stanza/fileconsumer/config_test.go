@@ -41,6 +41,9 @@ func TestNewConfig(t *testing.T) {
 	assert.False(t, cfg.IncludeFileOwnerGroupName)
 	assert.False(t, cfg.IncludeFileRecordNumber)
 	assert.False(t, cfg.AcquireFSLock)
+	assert.False(t, cfg.AtLeastOnce)
+	assert.Equal(t, "poll", cfg.Watch.Mode)
+	assert.Equal(t, 10*time.Second, cfg.Watch.FallbackInterval)
 }
 
 func TestUnmarshal(t *testing.T) {
@@ -436,6 +439,41 @@ func TestUnmarshal(t *testing.T) {
 					return newMockOperatorConfig(cfg)
 				}(),
 			},
+			{
+				Name: "watch_notify",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.Watch.Mode = "notify"
+					cfg.Watch.FallbackInterval = time.Minute
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
+			{
+				Name: "at_least_once",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.AtLeastOnce = true
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
+			{
+				Name: "rate_limit",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.RateLimit = RateLimitConfig{
+						MaxBytesPerRead: 1024 * 1024,
+						BytesPerSecond:  64 * 1024,
+						LinesPerSecond:  100,
+						Groups: []RateLimitGroupConfig{
+							{
+								Include:        []string{"/var/log/app/*.log"},
+								LinesPerSecond: 1000,
+							},
+						},
+					}
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
 			{
 				Name: "ordering_criteria_top_n",
 				Expect: func() *mockOperatorConfig {
@@ -524,6 +562,35 @@ func TestBuild(t *testing.T) {
 			require.Error,
 			nil,
 		},
+		{
+			"AutoEncoding",
+			func(cfg *Config) {
+				cfg.Encoding = "auto"
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Len(t, m.readerFactory.EncodingSplitFuncs, 4)
+			},
+		},
+		{
+			"AutoEncodingWithPreset",
+			func(cfg *Config) {
+				cfg.Encoding = "auto"
+				cfg.SplitConfig.Preset = "java"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"AutoEncodingWithHeader",
+			func(cfg *Config) {
+				cfg.Encoding = "auto"
+				cfg.StartAt = "beginning"
+				cfg.Header = &HeaderConfig{Pattern: "^#"}
+			},
+			require.Error,
+			nil,
+		},
 		{
 			"LineStartAndEnd",
 			func(cfg *Config) {
@@ -582,6 +649,142 @@ func TestBuild(t *testing.T) {
 				require.Equal(t, 6, m.maxBatches)
 			},
 		},
+		{
+			"WatchNotify",
+			func(cfg *Config) {
+				cfg.Watch.Mode = "notify"
+				cfg.Watch.FallbackInterval = time.Minute
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, "notify", m.watchMode)
+				require.Equal(t, time.Minute, m.fallbackInterval)
+			},
+		},
+		{
+			"InvalidWatchMode",
+			func(cfg *Config) {
+				cfg.Watch.Mode = "inotify"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"InvalidWatchFallbackInterval",
+			func(cfg *Config) {
+				cfg.Watch.Mode = "notify"
+				cfg.Watch.FallbackInterval = 0
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"RateLimit",
+			func(cfg *Config) {
+				cfg.RateLimit.MaxBytesPerRead = 1024
+				cfg.RateLimit.LinesPerSecond = 10
+				cfg.RateLimit.Groups = []RateLimitGroupConfig{
+					{Include: []string{"*.log"}, BytesPerSecond: 1024},
+				}
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, int64(1024), m.readerFactory.MaxBytesPerRead)
+				require.NotNil(t, m.readerFactory.RateLimiter)
+			},
+		},
+		{
+			"NoRateLimit",
+			func(_ *Config) {},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Nil(t, m.readerFactory.RateLimiter)
+			},
+		},
+		{
+			"NegativeMaxBytesPerRead",
+			func(cfg *Config) {
+				cfg.RateLimit.MaxBytesPerRead = -1
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"NegativeLinesPerSecond",
+			func(cfg *Config) {
+				cfg.RateLimit.LinesPerSecond = -1
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"RateLimitGroupWithoutInclude",
+			func(cfg *Config) {
+				cfg.RateLimit.Groups = []RateLimitGroupConfig{{BytesPerSecond: 1024}}
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"RateLimitGroupWithoutRate",
+			func(cfg *Config) {
+				cfg.RateLimit.Groups = []RateLimitGroupConfig{{Include: []string{"*.log"}}}
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"AutoCompression",
+			func(cfg *Config) {
+				cfg.Compression = "auto"
+			},
+			require.NoError,
+			func(_ *testing.T, _ *Manager) {},
+		},
+		{
+			"InvalidCompression",
+			func(cfg *Config) {
+				cfg.Compression = "snappy"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"Archive",
+			func(cfg *Config) {
+				cfg.Archive = "auto"
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, "auto", m.archiveFormat)
+			},
+		},
+		{
+			"InvalidArchive",
+			func(cfg *Config) {
+				cfg.Archive = "rar"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"ArchiveWithDeleteAfterRead",
+			func(cfg *Config) {
+				cfg.Archive = "tar"
+				cfg.DeleteAfterRead = true
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"ArchiveWithHeader",
+			func(cfg *Config) {
+				cfg.Archive = "zip"
+				cfg.Header = &HeaderConfig{}
+			},
+			require.Error,
+			nil,
+		},
 		{
 			"HeaderConfigNoFlag",
 			func(cfg *Config) {
@@ -768,6 +971,11 @@ func (c *Config) withGzip() *Config {
 	return c
 }
 
+func (c *Config) withCompression(compression string) *Config {
+	c.Compression = compression
+	return c
+}
+
 const mockOperatorType = "mock"
 
 func init() {
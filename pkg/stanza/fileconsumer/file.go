@@ -9,10 +9,14 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/checkpoint"
@@ -20,6 +24,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/tracker"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/watch"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 )
@@ -30,6 +35,12 @@ const (
 	maxUnreadableEntries = 10000
 )
 
+const (
+	rotationTypeAttribute = "rotation.type"
+	rotationTypeMoved     = "moved"
+	rotationTypeTruncated = "truncated"
+)
+
 type Manager struct {
 	set    component.TelemetrySettings
 	wg     sync.WaitGroup
@@ -46,6 +57,19 @@ type Manager struct {
 	maxBatchFiles  int
 	pollsToArchive int
 
+	watchMode        string
+	fallbackInterval time.Duration
+	include          []string
+	watcher          *watch.Watcher
+
+	archiveFormat string
+	members       *memberTracker
+
+	// yielded is set when a file stopped reading at the maximum number of bytes per read,
+	// so that its remaining contents are read without waiting for the next poll.
+	yielded atomic.Bool
+	backlog *backlog
+
 	telemetryBuilder *metadata.TelemetryBuilder
 
 	unreadable map[string]struct{}
@@ -71,10 +95,15 @@ func (m *Manager) Start(persister operator.Persister) error {
 		if err != nil {
 			return fmt.Errorf("read known files from database: %w", err)
 		}
-		if len(offsets) > 0 {
+		members, err := checkpoint.LoadKey(ctx, m.persister, knownMembersKey)
+		if err != nil {
+			return fmt.Errorf("read known archive members from database: %w", err)
+		}
+		if len(offsets) > 0 || len(members) > 0 {
 			m.set.Logger.Info("Resuming from previously known offset(s). 'start_at' setting is not applicable.")
 			m.readerFactory.FromBeginning = true
 			m.tracker.LoadMetadata(offsets)
+			m.members.loadMetadata(members)
 		}
 	} else if m.pollsToArchive > 0 {
 		m.set.Logger.Error("archiving is not supported in memory, please use a storage extension")
@@ -93,6 +122,8 @@ func (m *Manager) Stop() error {
 		m.cancel = nil
 	}
 	m.wg.Wait()
+	// The files are no longer tracked, so their lag isn't reported anymore.
+	m.backlog.reset()
 	if m.tracker != nil {
 		m.telemetryBuilder.FileconsumerOpenFiles.Add(context.TODO(), int64(0-m.tracker.ClosePreviousFiles()))
 	}
@@ -100,6 +131,7 @@ func (m *Manager) Stop() error {
 		if err := checkpoint.Save(context.Background(), m.persister, m.tracker.GetMetadata()); err != nil {
 			m.set.Logger.Error("save offsets", zap.Error(err))
 		}
+		m.saveMembers()
 	}
 	return nil
 }
@@ -107,6 +139,15 @@ func (m *Manager) Stop() error {
 // startPoller kicks off a goroutine that will poll the filesystem periodically,
 // checking if there are new files or new logs in the watched files
 func (m *Manager) startPoller(ctx context.Context) {
+	if m.watchMode == watchModeNotify {
+		w, err := watch.New(m.set.Logger, m.include)
+		if err == nil {
+			m.startWatcher(ctx, w)
+			return
+		}
+		m.set.Logger.Warn("File system notifications are unavailable, falling back to polling", zap.Error(err))
+	}
+
 	m.wg.Go(func() {
 		globTicker := time.NewTicker(m.pollInterval)
 		defer globTicker.Stop()
@@ -119,12 +160,91 @@ func (m *Manager) startPoller(ctx context.Context) {
 			}
 
 			m.poll(ctx)
+			m.catchUp(ctx)
 		}
 	})
 }
 
+// startWatcher kicks off a goroutine that reads the files reported as changed by
+// file system notifications, and polls the filesystem every fallback interval to
+// catch up with missed notifications.
+func (m *Manager) startWatcher(ctx context.Context, w *watch.Watcher) {
+	m.watcher = w
+	m.wg.Go(func() {
+		defer func() {
+			m.watcher = nil
+			if err := w.Close(); err != nil {
+				m.set.Logger.Debug("problem closing watcher", zap.Error(err))
+			}
+		}()
+
+		m.poll(ctx)
+		fallbackTicker := time.NewTicker(m.fallbackInterval)
+		defer fallbackTicker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-fallbackTicker.C:
+				m.poll(ctx)
+			case <-w.Notify():
+				changes := w.Drain()
+				// Whether a file is matched by ordering criteria depends on the other files,
+				// so these can only be applied by matching all files again.
+				if changes.Rescan || m.fileMatcher.Filtered() {
+					m.poll(ctx)
+					fallbackTicker.Reset(m.fallbackInterval)
+					continue
+				}
+				m.consumeChanges(ctx, changes.Paths)
+			}
+			m.catchUp(ctx)
+		}
+	})
+}
+
+// catchUp polls again for as long as files yield to the other files before reaching their end.
+func (m *Manager) catchUp(ctx context.Context) {
+	for m.yielded.Swap(false) && ctx.Err() == nil {
+		m.poll(ctx)
+	}
+}
+
+// consumeChanges reads the changed files that are matched, along with the files that are
+// still open since the last poll, which would otherwise be considered lost.
+func (m *Manager) consumeChanges(ctx context.Context, changed []string) {
+	paths := make([]string, 0, len(changed))
+	for _, path := range changed {
+		if m.fileMatcher.MatchPath(path) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+	if paths = m.consumeArchives(ctx, paths); len(paths) == 0 {
+		m.saveMembers()
+		return
+	}
+	for _, r := range m.tracker.PreviousPollFiles() {
+		paths = append(paths, r.GetFileName())
+	}
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+
+	for len(paths) > m.maxBatchFiles {
+		m.consume(ctx, paths[:m.maxBatchFiles])
+		paths = paths[m.maxBatchFiles:]
+	}
+	m.consume(ctx, paths)
+	m.saveCheckpoint()
+}
+
 // poll checks all the watched paths for new entries
 func (m *Manager) poll(ctx context.Context) {
+	defer m.backlog.record(ctx, m.telemetryBuilder)
+
 	// Used to keep track of the number of batches processed in this poll cycle
 	batchesProcessed := 0
 
@@ -134,6 +254,13 @@ func (m *Manager) poll(ctx context.Context) {
 		m.set.Logger.Debug("finding files", zap.Error(err))
 	}
 	m.set.Logger.Debug("matched files", zap.Strings("paths", matches))
+	if m.watcher != nil {
+		m.watcher.Sync(matches)
+	}
+	if m.archiveFormat != "" {
+		matches = m.consumeArchives(ctx, matches)
+		m.members.endPoll()
+	}
 
 	for len(matches) > m.maxBatchFiles {
 		m.consume(ctx, matches[:m.maxBatchFiles])
@@ -152,18 +279,34 @@ func (m *Manager) poll(ctx context.Context) {
 
 	// Any new files that appear should be consumed entirely
 	m.readerFactory.FromBeginning = true
-	if m.persister != nil {
-		metadata := m.tracker.GetMetadata()
-		if metadata != nil {
-			if err := checkpoint.Save(context.Background(), m.persister, metadata); err != nil {
-				m.set.Logger.Error("save offsets", zap.Error(err))
-			}
-		}
-	}
+	m.saveCheckpoint()
 	// rotate at end of every poll()
 	m.tracker.EndPoll(ctx)
 }
 
+func (m *Manager) saveCheckpoint() {
+	if m.persister == nil {
+		return
+	}
+	metadata := m.tracker.GetMetadata()
+	if metadata != nil {
+		if err := checkpoint.Save(context.Background(), m.persister, metadata); err != nil {
+			m.set.Logger.Error("save offsets", zap.Error(err))
+		}
+	}
+	m.saveMembers()
+}
+
+// saveMembers saves the offsets of the files read from archives.
+func (m *Manager) saveMembers() {
+	if m.persister == nil || m.archiveFormat == "" {
+		return
+	}
+	if err := checkpoint.SaveKey(context.Background(), m.persister, m.members.getMetadata(), knownMembersKey); err != nil {
+		m.set.Logger.Error("save archive member offsets", zap.Error(err))
+	}
+}
+
 func (m *Manager) consume(ctx context.Context, paths []string) {
 	m.set.Logger.Debug("Consuming files", zap.Strings("paths", paths))
 	m.makeReaders(ctx, paths)
@@ -176,9 +319,7 @@ func (m *Manager) consume(ctx context.Context, paths []string) {
 		wg.Add(1)
 		go func(r *reader.Reader) {
 			defer wg.Done()
-			m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, 1)
-			r.ReadToEnd(ctx)
-			m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, -1)
+			m.readToEnd(ctx, r)
 		}(r)
 	}
 	wg.Wait()
@@ -186,6 +327,27 @@ func (m *Manager) consume(ctx context.Context, paths []string) {
 	m.telemetryBuilder.FileconsumerOpenFiles.Add(ctx, int64(0-m.tracker.EndConsume()))
 }
 
+// readToEnd reads a file until its end, or until it yields to the other files or is rate limited,
+// and records how many bytes remain to be read.
+func (m *Manager) readToEnd(ctx context.Context, r *reader.Reader) {
+	m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, 1)
+	r.ReadToEnd(ctx)
+	m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, -1)
+
+	if r.Yielded() {
+		m.yielded.Store(true)
+	}
+	if lag, ok := r.Lag(); ok {
+		m.backlog.add(r.GetFileName(), lag)
+	}
+}
+
+// rotated records that a file was rotated, by moving it or by copying and truncating it.
+func (m *Manager) rotated(ctx context.Context, rotationType string) {
+	m.telemetryBuilder.FileconsumerRotations.Add(ctx, 1,
+		metric.WithAttributes(attribute.String(rotationTypeAttribute, rotationType)))
+}
+
 // makeFingerprint opens `path` and computes a fingerprint for the file
 // and contains logic to only log file permission errors once per file per startup
 func (m *Manager) makeFingerprint(path string) (*fingerprint.Fingerprint, *os.File) {
@@ -319,11 +481,13 @@ func (m *Manager) newReader(ctx context.Context, file *os.File, fp *fingerprint.
 					"File has been rotated(truncated)",
 					zap.String("original_path", oldReader.GetFileName()),
 					zap.String("rotated_path", file.Name()))
+				m.rotated(ctx, rotationTypeTruncated)
 			} else {
 				m.set.Logger.Debug(
 					"File has been rotated(moved)",
 					zap.String("original_path", oldReader.GetFileName()),
 					zap.String("rotated_path", file.Name()))
+				m.rotated(ctx, rotationTypeMoved)
 			}
 		}
 		return m.readerFactory.NewReaderFromMetadata(file, oldReader.Close())
@@ -45,10 +45,12 @@ OUTER:
 			// the Validate method to ensure that the file has not been truncated.
 			if !oldReader.Validate() {
 				m.set.Logger.Debug("File has been rotated(truncated)", zap.String("path", oldReader.GetFileName()))
+				m.rotated(ctx, rotationTypeTruncated)
 				continue OUTER
 			}
 			// oldreader points to the rotated file after the move/rename. We can still read from it.
 			m.set.Logger.Debug("File has been rotated(moved)", zap.String("path", oldReader.GetFileName()))
+			m.rotated(ctx, rotationTypeMoved)
 		}
 		lostReaders = append(lostReaders, oldReader)
 	}
@@ -59,9 +61,7 @@ OUTER:
 		m.set.Logger.Debug("Reading lost file", zap.String("path", lostReader.GetFileName()))
 		go func(r *reader.Reader) {
 			defer lostWG.Done()
-			m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, 1)
-			r.ReadToEnd(ctx)
-			m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, -1)
+			m.readToEnd(ctx, r)
 		}(lostReader)
 	}
 	lostWG.Wait()
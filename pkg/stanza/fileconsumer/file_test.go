@@ -6,6 +6,7 @@ package fileconsumer
 import (
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,8 +16,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz"
 	"go.opentelemetry.io/collector/featuregate"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
@@ -298,6 +301,44 @@ func TestReadExistingAndNewLogs(t *testing.T) {
 	sink.ExpectToken(t, []byte("testlog2"))
 }
 
+// TestWatchNotify tests that, in notify mode, changes are read as soon as they're
+// reported by file system notifications rather than on the next poll.
+func TestWatchNotify(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("File system notifications are only supported on Linux")
+	}
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Watch.Mode = "notify"
+	cfg.Watch.FallbackInterval = time.Hour
+	operator, sink := testManager(t, cfg)
+
+	temp1 := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp1, "testlog1\n")
+
+	require.NoError(t, operator.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	// Existing files are read by the initial poll
+	sink.ExpectToken(t, []byte("testlog1"))
+
+	// Changes to existing files and new files are read without waiting for the next poll
+	filetest.WriteString(t, temp1, "testlog2\n")
+	sink.ExpectToken(t, []byte("testlog2"))
+
+	temp2 := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp2, "testlog3\n")
+	sink.ExpectToken(t, []byte("testlog3"))
+
+	filetest.WriteString(t, temp1, "testlog4\n")
+	sink.ExpectToken(t, []byte("testlog4"))
+}
+
 // StartAtEnd tests that when `start_at` is configured to `end`,
 // we don't read any entries that were in the file before startup
 func TestStartAtEnd(t *testing.T) {
@@ -568,6 +609,62 @@ func TestDecodeBufferIsResized(t *testing.T) {
 	sink.ExpectToken(t, expected)
 }
 
+// TestMaxBytesPerRead tests that files yield to each other after reading the maximum number of
+// bytes per read, and are read again until their end without waiting for the next poll.
+func TestMaxBytesPerRead(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.RateLimit.MaxBytesPerRead = 5
+	operator, sink := testManager(t, cfg)
+
+	temp1 := filetest.OpenTemp(t, tempDir)
+	temp2 := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp1, "log1\nlog2\nlog3\n")
+	filetest.WriteString(t, temp2, "log4\nlog5\nlog6\n")
+
+	operator.poll(t.Context())
+	sink.ExpectTokens(t, []byte("log1"), []byte("log4"))
+	sink.ExpectNoCalls(t)
+	require.True(t, operator.yielded.Load())
+
+	operator.catchUp(t.Context())
+	sink.ExpectTokens(t, []byte("log2"), []byte("log5"), []byte("log3"), []byte("log6"))
+	sink.ExpectNoCalls(t)
+	require.False(t, operator.yielded.Load())
+}
+
+// TestMaxBytesPerReadGzip tests that gzip files are read to their end, whatever the maximum
+// number of bytes per read, since they can't resume from the middle.
+func TestMaxBytesPerReadGzip(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir).withGzip()
+	cfg.StartAt = "beginning"
+	cfg.RateLimit.MaxBytesPerRead = 5
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTempWithPattern(t, tempDir, "*.gz")
+	writer := gzip.NewWriter(temp)
+	_, err := writer.Write([]byte("log1\nlog2\nlog3\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	operator.poll(t.Context())
+	sink.ExpectTokens(t, []byte("log1"), []byte("log2"), []byte("log3"))
+	sink.ExpectNoCalls(t)
+	require.False(t, operator.yielded.Load())
+
+	info, err := temp.Stat()
+	require.NoError(t, err)
+	known := operator.tracker.GetMetadata()
+	require.Len(t, known, 1)
+	require.Equal(t, info.Size(), known[0].Offset)
+}
+
 func TestMultiFileSimple(t *testing.T) {
 	t.Parallel()
 
@@ -1612,6 +1709,104 @@ func TestReadGzipCompressedLogsFromEnd(t *testing.T) {
 	sink.ExpectToken(t, []byte("testlog4"))
 }
 
+// TestReadZstdCompressedLogsFromEnd tests that, when starting at the end of a zstd compressed file, we
+// read all the lines that are added afterward
+func TestReadZstdCompressedLogsFromEnd(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir).withCompression("zstd")
+	cfg.StartAt = "end"
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTempWithPattern(t, tempDir, "*.zst")
+
+	appendToLog := func(t *testing.T, content string) {
+		writer, err := zstd.NewWriter(temp)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	}
+
+	appendToLog(t, "testlog1\ntestlog2\n")
+
+	// poll for the first time - this should not lead to emitted
+	// logs as those were already in the existing file
+	operator.poll(t.Context())
+	sink.ExpectNoCalls(t)
+
+	// append new frames to the log and poll again - only the new lines should be picked up
+	appendToLog(t, "testlog3\n")
+	operator.poll(t.Context())
+	sink.ExpectToken(t, []byte("testlog3"))
+
+	appendToLog(t, "testlog4\n")
+	operator.poll(t.Context())
+	sink.ExpectToken(t, []byte("testlog4"))
+	sink.ExpectNoCalls(t)
+}
+
+// TestReadAutoDetectedCompressedLogs tests that the compression of each file is detected when
+// compression is set to auto, and that uncompressed files are still read as they are
+func TestReadAutoDetectedCompressedLogs(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir).withCompression("auto")
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	zstdFile := filetest.OpenTempWithPattern(t, tempDir, "*.zst")
+	zstdWriter, err := zstd.NewWriter(zstdFile)
+	require.NoError(t, err)
+	_, err = zstdWriter.Write([]byte("zstd log\n"))
+	require.NoError(t, err)
+	require.NoError(t, zstdWriter.Close())
+
+	xzFile := filetest.OpenTempWithPattern(t, tempDir, "*.xz")
+	xzWriter, err := xz.NewWriter(xzFile)
+	require.NoError(t, err)
+	_, err = xzWriter.Write([]byte("xz log\n"))
+	require.NoError(t, err)
+	require.NoError(t, xzWriter.Close())
+
+	plainFile := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, plainFile, "plain log\n")
+
+	operator.poll(t.Context())
+	sink.ExpectTokens(t, []byte("zstd log"), []byte("xz log"), []byte("plain log"))
+}
+
+// TestReadGzipCompressedAndPlainLogs tests that, when compression is set to gzip, the files which
+// aren't gzip compressed, such as the active file next to its rotated files, are read as they are
+func TestReadGzipCompressedAndPlainLogs(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir).withGzip()
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	rotated := filetest.OpenTempWithPattern(t, tempDir, "app.log.*.gz")
+	writer := gzip.NewWriter(rotated)
+	_, err := writer.Write([]byte("rotated log\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	active := filetest.OpenTempWithPattern(t, tempDir, "app.log")
+	filetest.WriteString(t, active, "active log1\n")
+
+	operator.poll(t.Context())
+	sink.ExpectTokens(t, []byte("rotated log"), []byte("active log1"))
+
+	// The plain file keeps being read from its offset.
+	filetest.WriteString(t, active, "active log2\n")
+	operator.poll(t.Context())
+	sink.ExpectToken(t, []byte("active log2"))
+	sink.ExpectNoCalls(t)
+}
+
 func TestArchive(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("Time sensitive tests disabled for now on Windows. See https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/32715#issuecomment-2107737828")
@@ -1669,3 +1864,123 @@ func TestArchive(t *testing.T) {
 
 	sink.ExpectCalls(t, log3, log4)
 }
+
+// TestAtLeastOnceGzip tests that a gzip file is read again from the offset it was read from
+// when a batch is rejected, since the offsets of its logs are in the decompressed data.
+func TestAtLeastOnceGzip(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir).withGzip()
+	cfg.StartAt = "beginning"
+	cfg.AtLeastOnce = true
+
+	var rejected sync.Mutex
+	reject := true
+	sink := emittest.NewSink(emittest.WithCallBuffer(300))
+	accept := sink.Callback
+	sink.Callback = func(ctx context.Context, tokens [][]byte, attributes map[string]any, lastRecordNumber int64, offsets []int64) error {
+		rejected.Lock()
+		defer rejected.Unlock()
+		// The second batch of the file is rejected.
+		if reject && string(tokens[0]) != "log1" {
+			return errors.New("rejected")
+		}
+		return accept(ctx, tokens, attributes, lastRecordNumber, offsets)
+	}
+	operator := testManagerWithSink(t, cfg, sink)
+
+	temp := filetest.OpenTempWithPattern(t, tempDir, "*.gz")
+	var logs [][]byte
+	writer := gzip.NewWriter(temp)
+	for i := 1; i <= 150; i++ {
+		logs = append(logs, []byte(fmt.Sprintf("log%d", i)))
+		_, err := fmt.Fprintf(writer, "log%d\n", i)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	operator.poll(t.Context())
+	sink.ExpectTokens(t, logs[:100]...)
+	sink.ExpectNoCalls(t)
+	known := operator.tracker.GetMetadata()
+	require.Len(t, known, 1)
+	require.Equal(t, int64(0), known[0].Offset)
+
+	rejected.Lock()
+	reject = false
+	rejected.Unlock()
+
+	operator.poll(t.Context())
+	sink.ExpectTokens(t, logs...)
+	sink.ExpectNoCalls(t)
+	info, err := temp.Stat()
+	require.NoError(t, err)
+	known = operator.tracker.GetMetadata()
+	require.Len(t, known, 1)
+	require.Equal(t, info.Size(), known[0].Offset)
+}
+
+// TestAtLeastOnce tests that logs rejected by the emit function are emitted again when
+// reading at least once, and that the offset of the file only moves past accepted logs.
+func TestAtLeastOnce(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		atLeastOnce bool
+		expected    [][]byte
+	}{
+		{name: "AtLeastOnce", atLeastOnce: true, expected: [][]byte{[]byte("log1"), []byte("log2"), []byte("log3")}},
+		{name: "AtMostOnce", atLeastOnce: false, expected: [][]byte{[]byte("log3")}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			cfg := NewConfig().includeDir(tempDir)
+			cfg.StartAt = "beginning"
+			cfg.AtLeastOnce = tc.atLeastOnce
+
+			var rejected sync.Mutex
+			reject := true
+			sink := emittest.NewSink()
+			accept := sink.Callback
+			sink.Callback = func(ctx context.Context, tokens [][]byte, attributes map[string]any, lastRecordNumber int64, offsets []int64) error {
+				rejected.Lock()
+				defer rejected.Unlock()
+				if reject {
+					return errors.New("rejected")
+				}
+				return accept(ctx, tokens, attributes, lastRecordNumber, offsets)
+			}
+			operator := testManagerWithSink(t, cfg, sink)
+
+			temp := filetest.OpenTemp(t, tempDir)
+			filetest.WriteString(t, temp, "log1\nlog2\n")
+
+			operator.poll(t.Context())
+			sink.ExpectNoCalls(t)
+			known := operator.tracker.GetMetadata()
+			require.Len(t, known, 1)
+			if tc.atLeastOnce {
+				require.Equal(t, int64(0), known[0].Offset)
+			} else {
+				require.Equal(t, int64(len("log1\nlog2\n")), known[0].Offset)
+			}
+
+			rejected.Lock()
+			reject = false
+			rejected.Unlock()
+			filetest.WriteString(t, temp, "log3\n")
+
+			operator.poll(t.Context())
+			sink.ExpectTokens(t, tc.expected...)
+			sink.ExpectNoCalls(t)
+			known = operator.tracker.GetMetadata()
+			require.Len(t, known, 1)
+			require.Equal(t, int64(len("log1\nlog2\nlog3\n")), known[0].Offset)
+		})
+	}
+}
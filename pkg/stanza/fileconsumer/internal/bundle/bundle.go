@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/bundle"
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/compression"
+)
+
+// Supported archive formats.
+const (
+	Tar = "tar"
+	Zip = "zip"
+)
+
+// Auto detects the archive format of each file, and reads files that aren't archives as they are.
+const Auto = "auto"
+
+const (
+	zipHeader   = "PK\x03\x04" // local file header signature
+	tarMagic    = "ustar"      // POSIX and GNU tar magic
+	tarMagicPos = 257
+)
+
+// Member is a regular file stored in an archive.
+type Member struct {
+	// Name is the path of the file in the archive.
+	Name string
+	// Size is the size of the contents of the file.
+	Size int64
+}
+
+// Validate returns an error if the archive setting is not supported.
+func Validate(format string) error {
+	switch format {
+	case "", Auto, Tar, Zip:
+		return nil
+	default:
+		return fmt.Errorf("unsupported archive format %q", format)
+	}
+}
+
+// Detect returns the archive format of a file, or an empty string if the file is not an archive.
+// Tar archives compressed with any of the supported compression formats are detected as well.
+func Detect(f *os.File, logger *zap.Logger) string {
+	header := make([]byte, tarMagicPos+len(tarMagic))
+	n, err := f.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		logger.Error(fmt.Sprintf("error reading file: %s: %s", f.Name(), err))
+		return ""
+	}
+	if bytes.HasPrefix(header[:n], []byte(zipHeader)) {
+		return Zip
+	}
+
+	if format := compression.Detect(f, logger); format != "" {
+		decompressor, err := compression.NewReader(format, io.NewSectionReader(f, 0, math.MaxInt64))
+		if err != nil {
+			return ""
+		}
+		defer decompressor.Close()
+		if n, err = io.ReadFull(decompressor, header); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return ""
+		}
+	}
+	if n == len(header) && string(header[tarMagicPos:]) == tarMagic {
+		return Tar
+	}
+	return ""
+}
+
+// Walk calls fn with each regular file of the archive, in the order they're stored, along with
+// a reader of its contents which is only valid until fn returns. Tar archives are decompressed
+// if they're compressed with any of the supported compression formats.
+//
+// A tar archive that is cut short, which is usually the case while it's still being written,
+// is read up to where it ends. Zip archives can't be read until they're complete.
+func Walk(f *os.File, format string, fn func(Member, io.Reader) error) error {
+	switch format {
+	case Tar:
+		return walkTar(f, fn)
+	case Zip:
+		return walkZip(f, fn)
+	default:
+		return fmt.Errorf("unsupported archive format %q", format)
+	}
+}
+
+func walkTar(f *os.File, fn func(Member, io.Reader) error) error {
+	var src io.Reader = io.NewSectionReader(f, 0, math.MaxInt64)
+	if format := compression.Detect(f, zap.NewNop()); format != "" {
+		decompressor, err := compression.NewReader(format, src)
+		if err != nil {
+			return fmt.Errorf("decompress: %w", err)
+		}
+		defer decompressor.Close()
+		src = decompressor
+	}
+
+	tr := tar.NewReader(src)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(Member{Name: hdr.Name, Size: hdr.Size}, &truncatedReader{Reader: tr}); err != nil {
+			return err
+		}
+	}
+}
+
+func walkZip(f *os.File, fn func(Member, io.Reader) error) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return err
+	}
+	for _, zf := range zr.File {
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+		if err := walkZipFile(zf, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkZipFile(zf *zip.File, fn func(Member, io.Reader) error) error {
+	rc, err := zf.Open()
+	if err != nil {
+		return fmt.Errorf("open %s: %w", zf.Name, err)
+	}
+	defer rc.Close()
+	size := int64(zf.UncompressedSize64)
+	if zf.UncompressedSize64 > math.MaxInt64 {
+		size = math.MaxInt64
+	}
+	return fn(Member{Name: zf.Name, Size: size}, rc)
+}
+
+// truncatedReader reports the end of a member that is cut short as io.EOF, so that the data
+// that could be read is consumed and the rest is read once the archive is complete.
+type truncatedReader struct {
+	io.Reader
+}
+
+func (r *truncatedReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
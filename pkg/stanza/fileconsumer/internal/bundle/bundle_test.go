@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type entry struct {
+	name    string
+	content string
+	dir     bool
+}
+
+var entries = []entry{
+	{name: "logs/", dir: true},
+	{name: "logs/app.log", content: "app line 1\napp line 2\n"},
+	{name: "logs/db.log", content: "db line 1\n"},
+	{name: "logs/empty.log"},
+}
+
+func tarData(t *testing.T, entries []entry) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o600, Size: int64(len(e.content)), Typeflag: tar.TypeReg}
+		if e.dir {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Mode = 0o700
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func gzipData(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func zipData(t *testing.T, entries []entry) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func writeTemp(t *testing.T, data []byte) *os.File {
+	f, err := os.Create(filepath.Join(t.TempDir(), "bundle"))
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	_, err = f.Write(data)
+	require.NoError(t, err)
+	return f
+}
+
+func walk(t *testing.T, f *os.File, format string) map[Member]string {
+	members := map[Member]string{}
+	require.NoError(t, Walk(f, format, func(m Member, r io.Reader) error {
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		members[m] = string(content)
+		return nil
+	}))
+	return members
+}
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "tar", data: tarData(t, entries), expected: Tar},
+		{name: "tar.gz", data: gzipData(t, tarData(t, entries)), expected: Tar},
+		{name: "zip", data: zipData(t, entries[1:]), expected: Zip},
+		{name: "gzip", data: gzipData(t, []byte("this is not an archive")), expected: ""},
+		{name: "plain", data: []byte("this is not an archive"), expected: ""},
+		{name: "empty", data: nil, expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Detect(writeTemp(t, tc.data), zap.NewNop()))
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	expected := map[Member]string{
+		{Name: "logs/app.log", Size: 22}:  "app line 1\napp line 2\n",
+		{Name: "logs/db.log", Size: 10}:   "db line 1\n",
+		{Name: "logs/empty.log", Size: 0}: "",
+	}
+
+	t.Run("tar", func(t *testing.T) {
+		assert.Equal(t, expected, walk(t, writeTemp(t, tarData(t, entries)), Tar))
+	})
+	t.Run("tar.gz", func(t *testing.T) {
+		assert.Equal(t, expected, walk(t, writeTemp(t, gzipData(t, tarData(t, entries))), Tar))
+	})
+	t.Run("zip", func(t *testing.T) {
+		assert.Equal(t, expected, walk(t, writeTemp(t, zipData(t, entries)), Zip))
+	})
+}
+
+func TestWalkOrder(t *testing.T) {
+	var names []string
+	require.NoError(t, Walk(writeTemp(t, tarData(t, entries)), Tar, func(m Member, _ io.Reader) error {
+		names = append(names, m.Name)
+		return nil
+	}))
+	assert.Equal(t, []string{"logs/app.log", "logs/db.log", "logs/empty.log"}, names)
+}
+
+func TestWalkTruncatedTar(t *testing.T) {
+	data := tarData(t, entries)
+	// Cut the archive in the middle of the contents of the first file, which follows the
+	// headers of the directory and of the file itself.
+	truncated := data[:512*2+5]
+
+	members := walk(t, writeTemp(t, truncated), Tar)
+	assert.Equal(t, map[Member]string{{Name: "logs/app.log", Size: 22}: "app l"}, members)
+}
+
+func TestWalkIncompleteZip(t *testing.T) {
+	data := zipData(t, entries)
+	err := Walk(writeTemp(t, data[:len(data)-10]), Zip, func(Member, io.Reader) error {
+		return nil
+	})
+	assert.Error(t, err)
+}
+
+func TestWalkError(t *testing.T) {
+	err := Walk(writeTemp(t, tarData(t, entries)), Tar, func(Member, io.Reader) error {
+		return io.ErrClosedPipe
+	})
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+func TestWalkUnsupported(t *testing.T) {
+	err := Walk(writeTemp(t, nil), "rar", nil)
+	assert.EqualError(t, err, `unsupported archive format "rar"`)
+}
+
+func TestValidate(t *testing.T) {
+	for _, format := range []string{"", Auto, Tar, Zip} {
+		assert.NoError(t, Validate(format))
+	}
+	assert.EqualError(t, Validate("rar"), `unsupported archive format "rar"`)
+}
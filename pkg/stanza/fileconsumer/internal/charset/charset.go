@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package charset detects the encoding of files from their first bytes.
+package charset // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/charset"
+
+import (
+	"bytes"
+	"maps"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Auto is the encoding setting which detects the encoding of each file.
+const Auto = "auto"
+
+// The encodings which can be detected.
+const (
+	UTF8    = "utf-8"
+	UTF16LE = "utf-16le"
+	UTF16BE = "utf-16be"
+	Latin1  = "iso-8859-1"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// Byte order marks are skipped by the reader rather than the decoder, since each token is decoded on its own.
+var encodings = map[string]encoding.Encoding{
+	UTF8:    unicode.UTF8,
+	UTF16LE: unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+	UTF16BE: unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+	Latin1:  charmap.ISO8859_1,
+}
+
+// Encodings returns the encodings which can be detected, by name.
+func Encodings() map[string]encoding.Encoding {
+	return maps.Clone(encodings)
+}
+
+// Lookup returns the encoding with the given name, or UTF-8 if it can't be detected.
+func Lookup(name string) encoding.Encoding {
+	if enc, ok := encodings[name]; ok {
+		return enc
+	}
+	return unicode.UTF8
+}
+
+// Result is the encoding detected from the first bytes of a file.
+type Result struct {
+	// Name is the name of the encoding.
+	Name string
+	// BOMLen is the length of the byte order mark at the start of the file, if any.
+	BOMLen int
+	// Final is set when more bytes from the file could not change the result.
+	Final bool
+}
+
+// Detect detects the encoding of a file from its first bytes. Complete is set when no more bytes
+// will be available from the start of the file, e.g. because the prefix is as long as the fingerprint.
+//
+// Byte order marks are detected first. Without one, text made mostly of ASCII characters encoded
+// in UTF-16 is detected by its zero bytes, after which valid UTF-8 is detected as such. Anything else
+// is assumed to be ISO-8859-1. Text made only of ASCII characters is detected as UTF-8, but the result
+// is not final unless the prefix is complete, since a later byte may reveal a different encoding.
+func Detect(prefix []byte, complete bool) Result {
+	switch {
+	case bytes.HasPrefix(prefix, bomUTF8):
+		return Result{Name: UTF8, BOMLen: len(bomUTF8), Final: true}
+	case bytes.HasPrefix(prefix, bomUTF16LE):
+		return Result{Name: UTF16LE, BOMLen: len(bomUTF16LE), Final: true}
+	case bytes.HasPrefix(prefix, bomUTF16BE):
+		return Result{Name: UTF16BE, BOMLen: len(bomUTF16BE), Final: true}
+	case !complete && len(prefix) < len(bomUTF8) && bytes.HasPrefix(bomUTF8, prefix):
+		// The start of a UTF-8 byte order mark
+		return Result{Name: UTF8}
+	}
+
+	if name, ok := detectUTF16(prefix); ok {
+		return Result{Name: name, Final: true}
+	}
+
+	prefix = trimIncompleteRune(prefix)
+	if !utf8.Valid(prefix) {
+		return Result{Name: Latin1, Final: true}
+	}
+	return Result{Name: UTF8, Final: complete || !isASCII(prefix)}
+}
+
+// detectUTF16 detects UTF-16 from the zero bytes of ASCII characters. At least half of the characters
+// must be ASCII, and few of them may have a zero byte on the other side.
+func detectUTF16(prefix []byte) (string, bool) {
+	chars := len(prefix) / 2
+	if chars == 0 {
+		return "", false
+	}
+	var even, odd int
+	for i := 0; i+1 < len(prefix); i += 2 {
+		if prefix[i] == 0 {
+			even++
+		}
+		if prefix[i+1] == 0 {
+			odd++
+		}
+	}
+	switch {
+	case 2*odd >= chars && 4*even < chars:
+		return UTF16LE, true
+	case 2*even >= chars && 4*odd < chars:
+		return UTF16BE, true
+	}
+	return "", false
+}
+
+// trimIncompleteRune trims the start of a multi-byte UTF-8 character from the end of the prefix.
+func trimIncompleteRune(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0 && i >= len(prefix)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(prefix[i]) {
+			if !utf8.FullRune(prefix[i:]) {
+				return prefix[:i]
+			}
+			break
+		}
+	}
+	return prefix
+}
+
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
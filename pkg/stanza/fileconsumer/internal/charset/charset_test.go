@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package charset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestDetect(t *testing.T) {
+	utf16le, err := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().String("log line\n")
+	require.NoError(t, err)
+	utf16be, err := unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewEncoder().String("log line\n")
+	require.NoError(t, err)
+	latin1, err := charmap.ISO8859_1.NewEncoder().String("café\n")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		prefix   string
+		complete bool
+		expected Result
+	}{
+		{
+			name:     "UTF8BOM",
+			prefix:   "\xEF\xBB\xBFlog",
+			expected: Result{Name: UTF8, BOMLen: 3, Final: true},
+		},
+		{
+			name:     "UTF16LEBOM",
+			prefix:   "\xFF\xFE" + utf16le,
+			expected: Result{Name: UTF16LE, BOMLen: 2, Final: true},
+		},
+		{
+			name:     "UTF16BEBOM",
+			prefix:   "\xFE\xFF" + utf16be,
+			expected: Result{Name: UTF16BE, BOMLen: 2, Final: true},
+		},
+		{
+			name:     "PartialUTF8BOM",
+			prefix:   "\xEF\xBB",
+			expected: Result{Name: UTF8},
+		},
+		{
+			name:     "UTF16LE",
+			prefix:   utf16le,
+			expected: Result{Name: UTF16LE, Final: true},
+		},
+		{
+			name:     "UTF16BE",
+			prefix:   utf16be,
+			expected: Result{Name: UTF16BE, Final: true},
+		},
+		{
+			name:     "UTF8",
+			prefix:   "café\n",
+			expected: Result{Name: UTF8, Final: true},
+		},
+		{
+			name:     "UTF8IncompleteRune",
+			prefix:   "café\n\xC3",
+			expected: Result{Name: UTF8, Final: true},
+		},
+		{
+			name:     "ASCII",
+			prefix:   "log line\n",
+			expected: Result{Name: UTF8},
+		},
+		{
+			name:     "ASCIIComplete",
+			prefix:   "log line\n",
+			complete: true,
+			expected: Result{Name: UTF8, Final: true},
+		},
+		{
+			name:     "Latin1",
+			prefix:   latin1,
+			expected: Result{Name: Latin1, Final: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Detect([]byte(tc.prefix), tc.complete))
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for name, enc := range Encodings() {
+		assert.Equal(t, enc, Lookup(name))
+	}
+	assert.Equal(t, unicode.UTF8, Lookup("unknown"))
+}
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package compression // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/compression"
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/ulikunitz/xz"
+	"go.uber.org/zap"
+)
+
+// Supported compression formats.
+const (
+	Gzip  = "gzip"
+	Zstd  = "zstd"
+	Bzip2 = "bzip2"
+	Xz    = "xz"
+	Lz4   = "lz4"
+)
+
+// Auto detects the compression format of each file from its magic bytes.
+const Auto = "auto"
+
+const (
+	gzipHeader  = "\x1f\x8b"         // RFC 1952 magic bytes
+	zstdHeader  = "\x28\xb5\x2f\xfd" // RFC 8878 magic number
+	bzip2Header = "BZh"              // bzip2 stream header
+	xzHeader    = "\xfd7zXZ\x00"     // xz file format stream header
+	lz4Header   = "\x04\x22\x4d\x18" // lz4 frame format magic number
+	maxMagicLen = len(xzHeader)
+)
+
+var magicBytes = []struct {
+	format string
+	magic  string
+}{
+	{format: Gzip, magic: gzipHeader},
+	{format: Zstd, magic: zstdHeader},
+	{format: Bzip2, magic: bzip2Header},
+	{format: Xz, magic: xzHeader},
+	{format: Lz4, magic: lz4Header},
+}
+
+// Validate returns an error if the compression setting is not supported.
+func Validate(compression string) error {
+	switch compression {
+	case "", Auto, Gzip, Zstd, Bzip2, Xz, Lz4:
+		return nil
+	default:
+		return fmt.Errorf("unsupported compression %q", compression)
+	}
+}
+
+// Detect returns the compression format of a file based on its magic bytes, or an
+// empty string if the file is not compressed with any of the supported formats.
+func Detect(f *os.File, logger *zap.Logger) string {
+	header := make([]byte, maxMagicLen)
+	n, err := f.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		logger.Error(fmt.Sprintf("error reading file: %s: %s", f.Name(), err))
+		return ""
+	}
+	for _, m := range magicBytes {
+		if bytes.HasPrefix(header[:n], []byte(m.magic)) {
+			return m.format
+		}
+	}
+	return ""
+}
+
+// NewReader returns a reader that decompresses the data of the given format read from r.
+//
+// Concatenated streams are read one after the other. A stream that is cut short, which is
+// usually the case while a compressed file is still being written, is reported as io.EOF
+// so that the data that could be decompressed is consumed and the rest is read later.
+func NewReader(format string, r io.Reader) (io.ReadCloser, error) {
+	switch format {
+	case Gzip:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return &readCloser{Reader: zr, close: zr.Close}, nil
+	case Zstd:
+		zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return &readCloser{Reader: zr, close: func() error {
+			zr.Close()
+			return nil
+		}}, nil
+	case Bzip2:
+		return &readCloser{Reader: bzip2.NewReader(r)}, nil
+	case Xz:
+		zr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return &readCloser{Reader: zr}, nil
+	case Lz4:
+		return &readCloser{Reader: newLz4Reader(r)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", format)
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r *readCloser) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (r *readCloser) Close() error {
+	if r.close == nil {
+		return nil
+	}
+	return r.close()
+}
+
+// lz4Reader reads concatenated lz4 frames.
+type lz4Reader struct {
+	src *bufio.Reader
+	zr  *lz4.Reader
+}
+
+func newLz4Reader(r io.Reader) *lz4Reader {
+	src := bufio.NewReader(r)
+	return &lz4Reader{src: src, zr: lz4.NewReader(src)}
+}
+
+func (r *lz4Reader) Read(p []byte) (int, error) {
+	n, err := r.zr.Read(p)
+	if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+	if next, peekErr := r.src.Peek(len(lz4Header)); peekErr == nil && string(next) == lz4Header {
+		r.zr.Reset(r.src)
+		return n, nil
+	}
+	return n, err
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz"
+	"go.uber.org/zap"
+)
+
+// bzip2Fixtures are the files compressed with bzip2, since the standard library can only decompress it.
+var bzip2Fixtures = map[string]string{
+	"this is test data": "data.bz2",
+	"first line\n":      "first.bz2",
+	"second line\n":     "second.bz2",
+	strings.Repeat("this is a test line\n", 1000): "lines.bz2",
+}
+
+// compress returns data compressed in the given format.
+func compress(t *testing.T, format string, data []byte) []byte {
+	if format == Bzip2 {
+		compressed, err := os.ReadFile(filepath.Join("testdata", bzip2Fixtures[string(data)]))
+		require.NoError(t, err)
+		return compressed
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	switch format {
+	case Gzip:
+		w = gzip.NewWriter(&buf)
+	case Zstd:
+		w, err = zstd.NewWriter(&buf)
+	case Xz:
+		w, err = xz.NewWriter(&buf)
+	case Lz4:
+		w = lz4.NewWriter(&buf)
+	}
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+var formats = []string{Gzip, Zstd, Bzip2, Xz, Lz4}
+
+func TestDetect(t *testing.T) {
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			f := writeTemp(t, compress(t, format, []byte("this is test data")))
+			assert.Equal(t, format, Detect(f, zap.NewNop()))
+		})
+	}
+
+	t.Run("uncompressed", func(t *testing.T) {
+		f := writeTemp(t, []byte("this is test data"))
+		assert.Empty(t, Detect(f, zap.NewNop()))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		f := writeTemp(t, nil)
+		assert.Empty(t, Detect(f, zap.NewNop()))
+	})
+}
+
+func TestNewReader(t *testing.T) {
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			data := bytes.Repeat([]byte("this is a test line\n"), 1000)
+			r, err := NewReader(format, bytes.NewReader(compress(t, format, data)))
+			require.NoError(t, err)
+			decompressed, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, data, decompressed)
+		})
+	}
+}
+
+func TestNewReaderConcatenated(t *testing.T) {
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			first, second := []byte("first line\n"), []byte("second line\n")
+			compressed := append(compress(t, format, first), compress(t, format, second)...)
+			r, err := NewReader(format, bytes.NewReader(compressed))
+			require.NoError(t, err)
+			decompressed, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, append(first, second...), decompressed)
+		})
+	}
+}
+
+func TestNewReaderTruncated(t *testing.T) {
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			data := bytes.Repeat([]byte("this is a test line\n"), 1000)
+			compressed := compress(t, format, data)
+			r, err := NewReader(format, bytes.NewReader(compressed[:len(compressed)-8]))
+			require.NoError(t, err)
+			// The data that can be decompressed is returned without an error.
+			decompressed, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.True(t, bytes.HasPrefix(data, decompressed))
+		})
+	}
+}
+
+func TestNewReaderUnsupported(t *testing.T) {
+	_, err := NewReader("snappy", bytes.NewReader(nil))
+	assert.EqualError(t, err, `unsupported compression "snappy"`)
+}
+
+func TestValidate(t *testing.T) {
+	for _, compression := range append([]string{"", Auto}, formats...) {
+		assert.NoError(t, Validate(compression))
+	}
+	assert.EqualError(t, Validate("snappy"), `unsupported compression "snappy"`)
+}
+
+func writeTemp(t *testing.T, data []byte) *os.File {
+	f, err := os.Create(filepath.Join(t.TempDir(), "test.log"))
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	_, err = f.Write(data)
+	require.NoError(t, err)
+	return f
+}
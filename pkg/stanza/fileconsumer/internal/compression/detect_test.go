@@ -14,7 +14,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func TestIsGzipFile(t *testing.T) {
+func TestDetectGzip(t *testing.T) {
 	t.Run("file is gzip compressed", func(t *testing.T) {
 		temp, err := os.Create(filepath.Join(t.TempDir(), "test.log"))
 		require.NoError(t, err)
@@ -29,7 +29,7 @@ func TestIsGzipFile(t *testing.T) {
 		_, err = temp.Seek(0, io.SeekStart)
 		require.NoError(t, err)
 
-		require.True(t, IsGzipFile(temp, zap.NewNop()), "expected file to be detected as gzip compressed")
+		require.Equal(t, Gzip, Detect(temp, zap.NewNop()), "expected file to be detected as gzip compressed")
 	})
 
 	t.Run("file is NOT gzip compressed", func(t *testing.T) {
@@ -44,6 +44,6 @@ func TestIsGzipFile(t *testing.T) {
 		_, err = tempFile.Seek(0, io.SeekStart)
 		require.NoError(t, err)
 
-		require.False(t, IsGzipFile(tempFile, zap.NewNop()), "expected file to not be detected as gzip compressed")
+		require.Empty(t, Detect(tempFile, zap.NewNop()), "expected file to not be detected as gzip compressed")
 	})
 }
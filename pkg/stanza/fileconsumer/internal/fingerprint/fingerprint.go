@@ -5,11 +5,11 @@ package fingerprint // import "github.com/open-telemetry/opentelemetry-collector
 
 import (
 	"bytes"
-	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 
 	"go.uber.org/zap"
@@ -38,16 +38,16 @@ func NewFromFile(file *os.File, size int, decompressData bool, logger *zap.Logge
 	buf := make([]byte, size)
 	if metadata.FilelogDecompressFingerprintFeatureGate.IsEnabled() {
 		if decompressData {
-			if compression.IsGzipFile(file, logger) {
+			if format := compression.Detect(file, logger); format != "" {
 				// If the file is of compressed type, uncompress the data before creating its fingerprint
-				uncompressedData, err := gzip.NewReader(file)
+				uncompressedData, err := compression.NewReader(format, io.NewSectionReader(file, 0, math.MaxInt64))
 				if err != nil {
-					return nil, fmt.Errorf("error uncompressing gzip file: %w", err)
+					return nil, fmt.Errorf("error uncompressing %s file: %w", format, err)
 				}
 				defer uncompressedData.Close()
 
-				n, err := uncompressedData.Read(buf)
-				if err != nil && !errors.Is(err, io.EOF) {
+				n, err := io.ReadFull(uncompressedData, buf)
+				if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
 					return nil, fmt.Errorf("error reading fingerprint bytes: %w", err)
 				}
 				return New(buf[:n]), nil
@@ -69,6 +69,11 @@ func (f Fingerprint) Copy() *Fingerprint {
 	return New(buf[:n])
 }
 
+// FirstBytes returns the first bytes of the file. They must not be modified.
+func (f *Fingerprint) FirstBytes() []byte {
+	return f.firstBytes
+}
+
 func (f *Fingerprint) Len() int {
 	return len(f.firstBytes)
 }
@@ -3,11 +3,13 @@
 package metadata
 
 import (
+	"context"
 	"errors"
 	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -22,11 +24,16 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 // TelemetryBuilder provides an interface for components to report telemetry
 // as defined in metadata and user config.
 type TelemetryBuilder struct {
-	meter                    metric.Meter
-	mu                       sync.Mutex
-	registrations            []metric.Registration
-	FileconsumerOpenFiles    metric.Int64UpDownCounter
-	FileconsumerReadingFiles metric.Int64UpDownCounter
+	meter                                    metric.Meter
+	mu                                       sync.Mutex
+	registrations                            []metric.Registration
+	FileconsumerBytesRemaining               metric.Int64Gauge
+	FileconsumerFileLag                      metric.Int64ObservableGauge
+	FileconsumerFilesPending                 metric.Int64Gauge
+	FileconsumerOldestUnreadModificationTime metric.Int64Gauge
+	FileconsumerOpenFiles                    metric.Int64UpDownCounter
+	FileconsumerReadingFiles                 metric.Int64UpDownCounter
+	FileconsumerRotations                    metric.Int64Counter
 }
 
 // TelemetryBuilderOption applies changes to default builder.
@@ -40,6 +47,31 @@ func (tbof telemetryBuilderOptionFunc) apply(mb *TelemetryBuilder) {
 	tbof(mb)
 }
 
+// RegisterFileconsumerFileLagCallback sets callback for observable FileconsumerFileLag metric.
+func (builder *TelemetryBuilder) RegisterFileconsumerFileLagCallback(cb metric.Int64Callback) error {
+	reg, err := builder.meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		cb(ctx, &observerInt64{inst: builder.FileconsumerFileLag, obs: o})
+		return nil
+	}, builder.FileconsumerFileLag)
+	if err != nil {
+		return err
+	}
+	builder.mu.Lock()
+	defer builder.mu.Unlock()
+	builder.registrations = append(builder.registrations, reg)
+	return nil
+}
+
+type observerInt64 struct {
+	embedded.Int64Observer
+	inst metric.Int64Observable
+	obs  metric.Observer
+}
+
+func (oi *observerInt64) Observe(value int64, opts ...metric.ObserveOption) {
+	oi.obs.ObserveInt64(oi.inst, value, opts...)
+}
+
 // Shutdown unregister all registered callbacks for async instruments.
 func (builder *TelemetryBuilder) Shutdown() {
 	builder.mu.Lock()
@@ -58,6 +90,30 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 	}
 	builder.meter = Meter(settings)
 	var err, errs error
+	builder.FileconsumerBytesRemaining, err = builder.meter.Int64Gauge(
+		"otelcol_fileconsumer_bytes_remaining",
+		metric.WithDescription("Number of bytes that remained to be read from the files after the last poll [Development]"),
+		metric.WithUnit("By"),
+	)
+	errs = errors.Join(errs, err)
+	builder.FileconsumerFileLag, err = builder.meter.Int64ObservableGauge(
+		"otelcol_fileconsumer_file_lag",
+		metric.WithDescription("Number of bytes between the offset of a file and its end, after it was last read [Development]"),
+		metric.WithUnit("By"),
+	)
+	errs = errors.Join(errs, err)
+	builder.FileconsumerFilesPending, err = builder.meter.Int64Gauge(
+		"otelcol_fileconsumer_files_pending",
+		metric.WithDescription("Number of files that were not read to their end after the last poll [Development]"),
+		metric.WithUnit("{files}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.FileconsumerOldestUnreadModificationTime, err = builder.meter.Int64Gauge(
+		"otelcol_fileconsumer_oldest_unread_modification_time",
+		metric.WithDescription("Modification time, in seconds since the Unix epoch, of the least recently modified file that was not read to its end after the last poll, or 0 if all files were read to their end [Development]"),
+		metric.WithUnit("s"),
+	)
+	errs = errors.Join(errs, err)
 	builder.FileconsumerOpenFiles, err = builder.meter.Int64UpDownCounter(
 		"otelcol_fileconsumer_open_files",
 		metric.WithDescription("Number of open files [Development]"),
@@ -70,5 +126,11 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.FileconsumerRotations, err = builder.meter.Int64Counter(
+		"otelcol_fileconsumer_rotations",
+		metric.WithDescription("Number of file rotations that were detected [Development]"),
+		metric.WithUnit("{rotations}"),
+	)
+	errs = errors.Join(errs, err)
 	return &builder, errs
 }
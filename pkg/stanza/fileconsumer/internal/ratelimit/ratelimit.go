@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ratelimit limits the rate at which files are read, with token buckets that
+// are shared by all the files they apply to.
+package ratelimit // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/ratelimit"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// Bucket is a token bucket which is refilled at a fixed rate, and holds up to a second
+// worth of tokens. Tokens can be taken as long as the bucket is not empty, so that large
+// tokens can be read, after which the bucket must be refilled until more can be taken.
+type Bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+	// lines is set when the bucket counts lines rather than bytes.
+	lines bool
+}
+
+func newBucket(perSecond int64, lines bool) *Bucket {
+	return &Bucket{
+		rate:   float64(perSecond),
+		tokens: float64(perSecond),
+		last:   time.Now(),
+		now:    time.Now,
+		lines:  lines,
+	}
+}
+
+// available refills the bucket, and reports whether tokens can be taken from it.
+func (b *Bucket) available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.tokens = min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	return b.tokens > 0
+}
+
+func (b *Bucket) take(bytes int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.lines {
+		b.tokens--
+	} else {
+		b.tokens -= float64(bytes)
+	}
+}
+
+// Limits are the buckets which apply to a file.
+type Limits []*Bucket
+
+// Allow reports whether the file can be read further.
+func (l Limits) Allow() bool {
+	for _, b := range l {
+		if !b.available() {
+			return false
+		}
+	}
+	return true
+}
+
+// Take takes a line of the given number of bytes from the buckets.
+func (l Limits) Take(bytes int64) {
+	for _, b := range l {
+		b.take(bytes)
+	}
+}
+
+// Group limits the rate at which the files matched by any of its patterns are read together.
+type Group struct {
+	include []string
+	buckets Limits
+}
+
+// NewGroup creates a group of files. Rates of zero are not limited.
+func NewGroup(include []string, bytesPerSecond, linesPerSecond int64) (*Group, error) {
+	if len(include) == 0 {
+		return nil, errors.New("'include' must be specified")
+	}
+	for _, pattern := range include {
+		if !doublestar.ValidatePathPattern(pattern) {
+			return nil, fmt.Errorf("invalid pattern %q", pattern)
+		}
+	}
+	if bytesPerSecond == 0 && linesPerSecond == 0 {
+		return nil, errors.New("one of 'bytes_per_second' or 'lines_per_second' must be specified")
+	}
+	return &Group{include: include, buckets: newBuckets(bytesPerSecond, linesPerSecond)}, nil
+}
+
+func (g *Group) matches(path string) bool {
+	for _, pattern := range g.include {
+		if ok, _ := doublestar.PathMatch(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Limiter creates the limits of files.
+type Limiter struct {
+	bytesPerSecond int64
+	linesPerSecond int64
+	groups         []*Group
+}
+
+// New creates a limiter which limits each file to the given rates, along with the groups
+// the file belongs to. Rates of zero are not limited.
+func New(bytesPerSecond, linesPerSecond int64, groups ...*Group) *Limiter {
+	return &Limiter{
+		bytesPerSecond: bytesPerSecond,
+		linesPerSecond: linesPerSecond,
+		groups:         groups,
+	}
+}
+
+// NewFileLimits creates the buckets of a single file, which are kept along with
+// the other metadata of the file.
+func (l *Limiter) NewFileLimits() Limits {
+	if l == nil {
+		return nil
+	}
+	return newBuckets(l.bytesPerSecond, l.linesPerSecond)
+}
+
+// Limits returns the limits of the file at path, that is its own limits and the limits
+// of the groups whose patterns match the path.
+func (l *Limiter) Limits(path string, file Limits) Limits {
+	if l == nil {
+		return nil
+	}
+	limits := file
+	for _, g := range l.groups {
+		if g.matches(path) {
+			limits = append(limits[:len(limits):len(limits)], g.buckets...)
+		}
+	}
+	return limits
+}
+
+func newBuckets(bytesPerSecond, linesPerSecond int64) Limits {
+	var buckets Limits
+	if bytesPerSecond > 0 {
+		buckets = append(buckets, newBucket(bytesPerSecond, false))
+	}
+	if linesPerSecond > 0 {
+		buckets = append(buckets, newBucket(linesPerSecond, true))
+	}
+	return buckets
+}
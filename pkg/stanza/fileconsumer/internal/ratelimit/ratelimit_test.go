@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) set(limits Limits) {
+	for _, b := range limits {
+		b.now = c.Now
+		b.last = c.now
+	}
+}
+
+func TestBytes(t *testing.T) {
+	c := &clock{now: time.Now()}
+	limits := New(100, 0).NewFileLimits()
+	require.Len(t, limits, 1)
+	c.set(limits)
+
+	// A full bucket allows a line larger than the rate, after which it must be refilled.
+	assert.True(t, limits.Allow())
+	limits.Take(150)
+	assert.False(t, limits.Allow())
+
+	c.now = c.now.Add(400 * time.Millisecond)
+	assert.False(t, limits.Allow())
+	c.now = c.now.Add(200 * time.Millisecond)
+	assert.True(t, limits.Allow())
+
+	// The bucket holds up to a second worth of tokens.
+	c.now = c.now.Add(time.Hour)
+	assert.True(t, limits.Allow())
+	limits.Take(100)
+	assert.False(t, limits.Allow())
+}
+
+func TestLines(t *testing.T) {
+	c := &clock{now: time.Now()}
+	limits := New(0, 2).NewFileLimits()
+	require.Len(t, limits, 1)
+	c.set(limits)
+
+	assert.True(t, limits.Allow())
+	limits.Take(1000)
+	assert.True(t, limits.Allow())
+	limits.Take(1000)
+	assert.False(t, limits.Allow())
+
+	c.now = c.now.Add(500 * time.Millisecond)
+	assert.True(t, limits.Allow())
+}
+
+func TestNoLimits(t *testing.T) {
+	var l *Limiter
+	assert.Empty(t, l.NewFileLimits())
+	assert.Empty(t, l.Limits("/var/log/app.log", nil))
+
+	limits := New(0, 0).Limits("/var/log/app.log", New(0, 0).NewFileLimits())
+	assert.Empty(t, limits)
+	assert.True(t, limits.Allow())
+	limits.Take(100)
+}
+
+func TestGroups(t *testing.T) {
+	app, err := NewGroup([]string{"/var/log/app/*.log"}, 100, 0)
+	require.NoError(t, err)
+	all, err := NewGroup([]string{"/var/log/**/*.log"}, 0, 10)
+	require.NoError(t, err)
+	l := New(1000, 0, app, all)
+
+	c := &clock{now: time.Now()}
+	c.set(app.buckets)
+	c.set(all.buckets)
+
+	first := l.NewFileLimits()
+	second := l.NewFileLimits()
+	c.set(first)
+	c.set(second)
+
+	firstLimits := l.Limits("/var/log/app/first.log", first)
+	assert.Len(t, firstLimits, 3)
+	secondLimits := l.Limits("/var/log/app/second.log", second)
+	assert.Len(t, secondLimits, 3)
+	otherLimits := l.Limits("/var/log/other.log", l.NewFileLimits())
+	assert.Len(t, otherLimits, 2)
+	assert.Len(t, l.Limits("/tmp/other.log", nil), 0)
+
+	// The files of a group share its buckets.
+	firstLimits.Take(100)
+	assert.False(t, firstLimits.Allow())
+	assert.False(t, secondLimits.Allow())
+	assert.True(t, otherLimits.Allow())
+
+	// The limits of a file don't alias the limits of other files.
+	assert.Len(t, first, 1)
+	assert.Len(t, second, 1)
+}
+
+func TestNewGroup(t *testing.T) {
+	_, err := NewGroup(nil, 100, 0)
+	assert.EqualError(t, err, "'include' must be specified")
+
+	_, err = NewGroup([]string{"/var/log/[.log"}, 100, 0)
+	assert.EqualError(t, err, `invalid pattern "/var/log/[.log"`)
+
+	_, err = NewGroup([]string{"/var/log/*.log"}, 0, 0)
+	assert.EqualError(t, err, "one of 'bytes_per_second' or 'lines_per_second' must be specified")
+}
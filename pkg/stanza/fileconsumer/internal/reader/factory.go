@@ -7,7 +7,9 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
+	"math"
 	"os"
 	"sync"
 	"time"
@@ -18,9 +20,11 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/charset"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/compression"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/header"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/ratelimit"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/flush"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/tokenlen"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/trim"
@@ -34,14 +38,17 @@ const (
 
 type Factory struct {
 	component.TelemetrySettings
-	HeaderConfig            *header.Config
-	FromBeginning           bool
-	FingerprintSize         int
-	BufPool                 sync.Pool
-	InitialBufferSize       int
-	MaxLogSize              int
-	Encoding                encoding.Encoding
-	SplitFunc               bufio.SplitFunc
+	HeaderConfig      *header.Config
+	FromBeginning     bool
+	FingerprintSize   int
+	BufPool           sync.Pool
+	InitialBufferSize int
+	MaxLogSize        int
+	Encoding          encoding.Encoding
+	SplitFunc         bufio.SplitFunc
+	// EncodingSplitFuncs are the split funcs of the encodings which can be detected. If set, the
+	// encoding of each file is detected from its first bytes, instead of using Encoding.
+	EncodingSplitFuncs      map[string]bufio.SplitFunc
 	TrimFunc                trim.Func
 	FlushTimeout            time.Duration
 	EmitFunc                emit.Callback
@@ -51,6 +58,10 @@ type Factory struct {
 	IncludeFileRecordOffset bool
 	Compression             string
 	AcquireFSLock           bool
+	AtLeastOnce             bool
+	// MaxBytesPerRead is the number of bytes read from a file before it yields to the other files.
+	MaxBytesPerRead int64
+	RateLimiter     *ratelimit.Limiter
 }
 
 func (f *Factory) NewFingerprint(file *os.File) (*fingerprint.Fingerprint, error) {
@@ -63,9 +74,16 @@ func (f *Factory) NewReader(file *os.File, fp *fingerprint.Fingerprint) (*Reader
 		return nil, err
 	}
 	var filetype string
-
-	if f.Compression != "" && compression.IsGzipFile(file, f.Logger) {
-		filetype = gzipExtension
+	if f.Compression != "" {
+		// The format is detected for each file, so that the files which aren't compressed with
+		// the configured format, e.g. the active file next to its rotated files, are read as is.
+		filetype = compression.Detect(file, f.Logger)
+		if f.Compression != compression.Auto && filetype != f.Compression {
+			filetype = ""
+		}
+		if filetype == compression.Gzip {
+			filetype = gzipExtension
+		}
 	}
 
 	m := &Metadata{
@@ -96,6 +114,7 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		acquireFSLock:     f.AcquireFSLock,
 		maxBatchSize:      DefaultMaxBatchSize,
 		emitFunc:          f.EmitFunc,
+		atLeastOnce:       f.AtLeastOnce,
 	}
 	r.set.Logger = r.set.Logger.With(zap.String("path", r.fileName))
 
@@ -116,10 +135,47 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		if info, err = r.file.Stat(); err != nil {
 			return nil, fmt.Errorf("stat: %w", err)
 		}
-		r.Offset = info.Size()
+		switch r.FileType {
+		case "", gzipExtension:
+			r.Offset = info.Size()
+		default:
+			// The offset of compressed files is tracked in the decompressed data.
+			if r.Offset, err = decompressedSize(r.file, r.FileType); err != nil {
+				return nil, fmt.Errorf("decompress: %w", err)
+			}
+			r.CompressedSize = info.Size()
+		}
 	}
 
-	tokenLenFunc := m.TokenLenState.Func(f.SplitFunc)
+	// The offset of gzip files only moves once they're read to the end.
+	if r.FileType != gzipExtension {
+		r.maxBytesPerRead = f.MaxBytesPerRead
+		if f.RateLimiter != nil {
+			if m.RateLimits == nil {
+				m.RateLimits = f.RateLimiter.NewFileLimits()
+			}
+			r.limits = f.RateLimiter.Limits(r.fileName, m.RateLimits)
+		}
+	}
+
+	splitFunc := f.SplitFunc
+	encodingName := ""
+	if f.EncodingSplitFuncs != nil {
+		encodingName = m.Encoding
+		if encodingName == "" {
+			detected := charset.Detect(m.Fingerprint.FirstBytes(), m.Fingerprint.Len() >= f.FingerprintSize)
+			encodingName = detected.Name
+			if detected.Final {
+				m.Encoding = encodingName
+			}
+			// The byte order mark is not part of the first log.
+			r.Offset = max(r.Offset, int64(detected.BOMLen))
+		}
+		r.decoder = charset.Lookup(encodingName).NewDecoder()
+		splitFunc = f.EncodingSplitFuncs[encodingName]
+	}
+
+	tokenLenFunc := m.TokenLenState.Func(splitFunc)
 	flushFunc := m.FlushState.Func(tokenLenFunc, f.FlushTimeout)
 	r.contentSplitFunc = trim.WithFunc(trim.ToLength(flushFunc, f.MaxLogSize), f.TrimFunc)
 
@@ -140,7 +196,58 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 	mergedAttributes := make(map[string]any, len(r.FileAttributes)+len(attributes))
 	maps.Copy(mergedAttributes, r.FileAttributes)
 	maps.Copy(mergedAttributes, attributes)
+	if encodingName != "" {
+		mergedAttributes[attrs.LogFileEncoding] = encodingName
+	}
+	r.FileAttributes = mergedAttributes
+
+	return r, nil
+}
+
+// NewMemberReader creates a reader for a file in an archive, whose contents are read from src.
+// The reader reads src to the end once, starting from the offset of the metadata.
+func (f *Factory) NewMemberReader(archive *os.File, src io.Reader, m *Metadata) (*Reader, error) {
+	r := &Reader{
+		Metadata:          m,
+		set:               f.TelemetrySettings,
+		src:               src,
+		fileName:          archive.Name() + attrs.MemberSeparator + m.Member,
+		fingerprintSize:   f.FingerprintSize,
+		bufPool:           &f.BufPool,
+		initialBufferSize: f.InitialBufferSize,
+		maxLogSize:        f.MaxLogSize,
+		decoder:           f.Encoding.NewDecoder(),
+		maxBatchSize:      DefaultMaxBatchSize,
+		emitFunc:          f.EmitFunc,
+		atLeastOnce:       f.AtLeastOnce,
+	}
+	r.set.Logger = r.set.Logger.With(zap.String("path", r.fileName))
+
+	tokenLenFunc := m.TokenLenState.Func(f.SplitFunc)
+	flushFunc := m.FlushState.Func(tokenLenFunc, f.FlushTimeout)
+	r.contentSplitFunc = trim.WithFunc(trim.ToLength(flushFunc, f.MaxLogSize), f.TrimFunc)
+
+	attributes, err := f.Attributes.ResolveMember(archive, m.Member)
+	if err != nil {
+		return nil, err
+	}
+	mergedAttributes := make(map[string]any, len(r.FileAttributes)+len(attributes))
+	maps.Copy(mergedAttributes, r.FileAttributes)
+	maps.Copy(mergedAttributes, attributes)
 	r.FileAttributes = mergedAttributes
 
 	return r, nil
 }
+
+// decompressedSize returns the size of the decompressed data of a compressed file.
+func decompressedSize(file *os.File, format string) (int64, error) {
+	decompressor, err := compression.NewReader(format, io.NewSectionReader(file, 0, math.MaxInt64))
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer decompressor.Close()
+	return io.Copy(io.Discard, decompressor)
+}
@@ -4,6 +4,7 @@
 package reader
 
 import (
+	"bufio"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"golang.org/x/text/encoding/unicode"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/charset"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/emittest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/scanner"
@@ -48,19 +50,29 @@ func testFactory(t *testing.T, opts ...testFactoryOpt) (*Factory, *emittest.Sink
 	splitFunc, err := cfg.splitCfg.Func(cfg.encoding, false, cfg.maxLogSize)
 	require.NoError(t, err)
 
+	var encodingSplitFuncs map[string]bufio.SplitFunc
+	if cfg.detectEncoding {
+		encodingSplitFuncs = make(map[string]bufio.SplitFunc)
+		for name, enc := range charset.Encodings() {
+			encodingSplitFuncs[name], err = cfg.splitCfg.Func(enc, false, cfg.maxLogSize)
+			require.NoError(t, err)
+		}
+	}
+
 	sink := emittest.NewSink(emittest.WithCallBuffer(cfg.sinkChanSize))
 	return &Factory{
-		TelemetrySettings: componenttest.NewNopTelemetrySettings(),
-		FromBeginning:     cfg.fromBeginning,
-		FingerprintSize:   cfg.fingerprintSize,
-		InitialBufferSize: cfg.initialBufferSize,
-		MaxLogSize:        cfg.maxLogSize,
-		Encoding:          cfg.encoding,
-		SplitFunc:         splitFunc,
-		TrimFunc:          cfg.trimFunc,
-		FlushTimeout:      cfg.flushPeriod,
-		EmitFunc:          sink.Callback,
-		Attributes:        cfg.attributes,
+		TelemetrySettings:  componenttest.NewNopTelemetrySettings(),
+		FromBeginning:      cfg.fromBeginning,
+		FingerprintSize:    cfg.fingerprintSize,
+		InitialBufferSize:  cfg.initialBufferSize,
+		MaxLogSize:         cfg.maxLogSize,
+		Encoding:           cfg.encoding,
+		SplitFunc:          splitFunc,
+		EncodingSplitFuncs: encodingSplitFuncs,
+		TrimFunc:           cfg.trimFunc,
+		FlushTimeout:       cfg.flushPeriod,
+		EmitFunc:           sink.Callback,
+		Attributes:         cfg.attributes,
 	}, sink
 }
 
@@ -77,6 +89,7 @@ type testFactoryCfg struct {
 	flushPeriod       time.Duration
 	sinkChanSize      int
 	attributes        attrs.Resolver
+	detectEncoding    bool
 }
 
 func withFingerprintSize(size int) testFactoryOpt {
@@ -115,6 +128,12 @@ func withSinkChanSize(n int) testFactoryOpt {
 	}
 }
 
+func withDetectEncoding() testFactoryOpt {
+	return func(c *testFactoryCfg) {
+		c.detectEncoding = true
+	}
+}
+
 func fromEnd() testFactoryOpt {
 	return func(c *testFactoryCfg) {
 		c.fromBeginning = false
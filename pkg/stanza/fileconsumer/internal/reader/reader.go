@@ -9,8 +9,10 @@ import (
 	"context"
 	"errors"
 	"io"
+	"math"
 	"os"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
@@ -18,13 +20,17 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/textutils"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/compression"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/header"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/ratelimit"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/scanner"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/flush"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/tokenlen"
 )
 
+// gzipExtension is the FileType of gzip files. They are read incrementally from their offset
+// in the compressed file, since appended data is written as new gzip members.
 const gzipExtension = ".gz"
 
 type Metadata struct {
@@ -35,7 +41,20 @@ type Metadata struct {
 	HeaderFinalized bool
 	FlushState      flush.State
 	TokenLenState   tokenlen.State
-	FileType        string
+	// FileType is the compression format of the file, if any. The offset of compressed
+	// files other than gzip is tracked in the decompressed data.
+	FileType string
+	// CompressedSize is the size of a compressed file when it was last read to the end.
+	CompressedSize int64
+	// Member is the name of the file in an archive, if the file is read from one. Members
+	// are identified by the fingerprint of the archive along with their name.
+	Member string
+	// Encoding is the name of the encoding detected from the first bytes of the file, once it
+	// can't change anymore.
+	Encoding string
+	// RateLimits are the rate limits of the file itself, which are kept across polls
+	// but not persisted.
+	RateLimits ratelimit.Limits `json:"-"`
 }
 
 // Reader manages a single file
@@ -45,6 +64,8 @@ type Reader struct {
 	fileName               string
 	file                   *os.File
 	reader                 io.Reader
+	decompressor           io.ReadCloser
+	src                    io.Reader
 	fingerprintSize        int
 	bufPool                *sync.Pool
 	initialBufferSize      int
@@ -59,6 +80,17 @@ type Reader struct {
 	compression            string
 	acquireFSLock          bool
 	maxBatchSize           int
+	// atLeastOnce is set when the offset must only move past the tokens that were accepted
+	// downstream, so that rejected tokens are emitted again.
+	atLeastOnce bool
+	// stopped is set when reading stopped before the end of the file since ReadToEnd was called,
+	// because tokens were rejected downstream or a limit was reached.
+	stopped bool
+	// maxBytesPerRead is the number of bytes read before the file yields to the other files.
+	maxBytesPerRead int64
+	// yielded is set when reading stopped at maxBytesPerRead since ReadToEnd was called.
+	yielded bool
+	limits  ratelimit.Limits
 }
 
 // ReadToEnd will read until the end of the file
@@ -69,40 +101,51 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		}
 		defer r.unlockFile()
 	}
+	r.stopped = false
+	r.yielded = false
 
-	switch r.compression {
-	case "gzip":
+	switch r.FileType {
+	case "":
+	case gzipExtension:
 		currentEOF, err := r.createGzipReader()
 		if err != nil {
 			return
 		}
 		// Offset tracking in an uncompressed file is based on the length of emitted tokens, but in this case
-		// we need to set the offset to the end of the file.
+		// we need to set the offset to the end of the file. The positions of the tokens are in the
+		// decompressed data, so when reading stopped early the file is read again from where it started.
+		startOffset, startRecordNum := r.Offset, r.RecordNum
 		defer func() {
-			r.Offset = currentEOF
-		}()
-	case "auto":
-		if r.FileType == gzipExtension {
-			currentEOF, err := r.createGzipReader()
-			if err != nil {
-				return
-			}
-			// Offset tracking in an uncompressed file is based on the length of emitted tokens, but in this case
-			// we need to set the offset to the end of the file.
-			defer func() {
+			if r.stopped {
+				r.Offset, r.RecordNum = startOffset, startRecordNum
+			} else {
 				r.Offset = currentEOF
-			}()
-		} else {
-			r.reader = r.file
-		}
+			}
+		}()
 	default:
-		r.reader = r.file
+		info, err := r.file.Stat()
+		if err != nil {
+			r.set.Logger.Error("failed to stat", zap.Error(err))
+			return
+		}
+		// Compressed files are decompressed from the start, so they're only read again once they changed.
+		if info.Size() == r.CompressedSize {
+			return
+		}
+		defer func() {
+			if ctx.Err() == nil && !r.stopped {
+				r.CompressedSize = info.Size()
+			}
+		}()
 	}
 
-	if _, err := r.file.Seek(r.Offset, 0); err != nil {
-		r.set.Logger.Error("failed to seek", zap.Error(err))
+	if err := r.seek(); err != nil {
+		if !errors.Is(err, io.EOF) {
+			r.set.Logger.Error("failed to seek", zap.Error(err))
+		}
 		return
 	}
+	defer r.closeDecompressor()
 
 	defer func() {
 		if r.needsUpdateFingerprint {
@@ -142,6 +185,50 @@ func (r *Reader) createGzipReader() (int64, error) {
 	return currentEOF, nil
 }
 
+// seek positions the reader at the current offset. Compressed files are decompressed from
+// the start, since their offset is tracked in the decompressed data.
+func (r *Reader) seek() error {
+	if r.src != nil {
+		// Members of archives are read from a stream, which is only read once.
+		if _, err := io.CopyN(io.Discard, r.src, r.Offset); err != nil {
+			return err
+		}
+		r.reader, r.src = r.src, nil
+		return nil
+	}
+	switch r.FileType {
+	case "":
+		r.reader = r.file
+	case gzipExtension:
+		// The gzip reader already starts at the offset in the compressed file.
+	default:
+		r.closeDecompressor()
+		decompressor, err := compression.NewReader(r.FileType, io.NewSectionReader(r.file, 0, math.MaxInt64))
+		if err != nil {
+			return err
+		}
+		if _, err = io.CopyN(io.Discard, decompressor, r.Offset); err != nil {
+			_ = decompressor.Close()
+			return err
+		}
+		r.decompressor = decompressor
+		r.reader = decompressor
+		return nil
+	}
+	_, err := r.file.Seek(r.Offset, 0)
+	return err
+}
+
+func (r *Reader) closeDecompressor() {
+	if r.decompressor == nil {
+		return
+	}
+	if err := r.decompressor.Close(); err != nil {
+		r.set.Logger.Debug("Problem closing decompressor", zap.Error(err))
+	}
+	r.decompressor = nil
+}
+
 func (r *Reader) readHeader(ctx context.Context) (doneReadingFile bool) {
 	bufPtr := r.getBufPtrFromPool()
 	defer r.bufPool.Put(bufPtr)
@@ -196,7 +283,7 @@ func (r *Reader) readHeader(ctx context.Context) (doneReadingFile bool) {
 	r.HeaderFinalized = true
 
 	// Reset position in file to r.Offest after the header scanner might have moved it past a content token.
-	if _, err := r.file.Seek(r.Offset, 0); err != nil {
+	if err := r.seek(); err != nil {
 		r.set.Logger.Error("failed to seek post-header", zap.Error(err))
 		return true
 	}
@@ -223,6 +310,7 @@ func (r *Reader) readContents(ctx context.Context) {
 
 	numTokensBatched := 0
 	tokenOffsets[0] = r.Offset
+	startOffset, lastOffset := r.Offset, r.Offset
 	// Iterate over the contents of the file.
 	for {
 		select {
@@ -231,27 +319,40 @@ func (r *Reader) readContents(ctx context.Context) {
 		default:
 		}
 
+		// Gzip files can't stop in the middle, since they're only read again from the offset they were read from.
+		if r.FileType != gzipExtension && r.limitReached(s.Pos()-startOffset) {
+			r.stopped = true
+			if numTokensBatched > 0 && r.emit(ctx, tokenBodies[:numTokensBatched], tokenOffsets) {
+				r.Offset = s.Pos()
+			}
+			return
+		}
+
 		ok := s.Scan()
 		if !ok {
-			if err := s.Error(); err != nil {
-				r.set.Logger.Error("failed during scan", zap.Error(err))
-			} else if r.deleteAtEOF {
-				r.delete()
+			scanErr := s.Error()
+			if scanErr != nil {
+				r.set.Logger.Error("failed during scan", zap.Error(scanErr))
 			}
 
 			if numTokensBatched > 0 {
-				err := r.emitFunc(ctx, tokenBodies[:numTokensBatched], r.FileAttributes, r.RecordNum, tokenOffsets)
-				if err != nil {
-					r.set.Logger.Error("failed to emit token", zap.Error(err))
+				if !r.emit(ctx, tokenBodies[:numTokensBatched], tokenOffsets) {
+					return
 				}
 				r.Offset = s.Pos()
 			}
+
+			if scanErr == nil && r.deleteAtEOF {
+				r.delete()
+			}
 			return
 		}
 
 		var err error
 		tokenBodies[numTokensBatched], err = r.decoder.Bytes(s.Bytes())
 		tokenOffsets[numTokensBatched+1] = s.Pos()
+		r.limits.Take(s.Pos() - lastOffset)
+		lastOffset = s.Pos()
 		if err != nil {
 			r.set.Logger.Error("failed to decode token", zap.Error(err))
 			r.Offset = s.Pos() // move past the bad token or we may be stuck
@@ -261,8 +362,8 @@ func (r *Reader) readContents(ctx context.Context) {
 
 		r.RecordNum++
 		if r.maxBatchSize > 0 && numTokensBatched >= r.maxBatchSize {
-			if err = r.emitFunc(ctx, tokenBodies[:numTokensBatched], r.FileAttributes, r.RecordNum, tokenOffsets); err != nil {
-				r.set.Logger.Error("failed to emit token", zap.Error(err))
+			if !r.emit(ctx, tokenBodies[:numTokensBatched], tokenOffsets) {
+				return
 			}
 			numTokensBatched = 0
 			r.Offset, tokenOffsets[0] = s.Pos(), s.Pos()
@@ -270,6 +371,41 @@ func (r *Reader) readContents(ctx context.Context) {
 	}
 }
 
+// limitReached reports whether reading must stop before the next token, because maxBytesPerRead
+// bytes were read since ReadToEnd was called, or a rate limit of the file was reached.
+func (r *Reader) limitReached(read int64) bool {
+	if r.maxBytesPerRead > 0 && read >= r.maxBytesPerRead {
+		r.yielded = true
+		return true
+	}
+	return !r.limits.Allow()
+}
+
+// Yielded reports whether the last call to ReadToEnd stopped at the maximum number of bytes
+// per read, leaving the rest of the file to be read once the other files were read.
+func (r *Reader) Yielded() bool {
+	return r.yielded
+}
+
+// emit emits a batch of tokens, and reports whether the offset can move past them. When reading
+// at least once, the offset is moved back to the start of a batch that was rejected downstream,
+// so that it's emitted again when the file is read next. Gzip files are read again from the
+// offset they were read from instead, along with the batches emitted before.
+func (r *Reader) emit(ctx context.Context, tokens [][]byte, offsets []int64) bool {
+	err := r.emitFunc(ctx, tokens, r.FileAttributes, r.RecordNum, offsets)
+	if err == nil {
+		return true
+	}
+	r.set.Logger.Error("failed to emit token", zap.Error(err))
+	if !r.atLeastOnce {
+		return true
+	}
+	r.stopped = true
+	r.Offset = offsets[0]
+	r.RecordNum -= int64(len(tokens))
+	return false
+}
+
 // Delete will close and delete the file
 func (r *Reader) delete() {
 	r.close()
@@ -287,6 +423,7 @@ func (r *Reader) Close() *Metadata {
 }
 
 func (r *Reader) close() {
+	r.closeDecompressor()
 	if r.file != nil {
 		if err := r.file.Close(); err != nil {
 			r.set.Logger.Debug("Problem closing reader", zap.Error(err))
@@ -330,6 +467,27 @@ func (r *Reader) Validate() bool {
 	return refreshedFingerprint.StartsWith(r.Fingerprint)
 }
 
+// Lag is how far reading a file is behind its end.
+type Lag struct {
+	// Bytes is the number of bytes between the offset of the file and its end.
+	Bytes int64
+	// ModTime is the modification time of the file.
+	ModTime time.Time
+}
+
+// Lag returns how far reading the file is behind its end. It is only known for open files
+// which are not compressed.
+func (r *Reader) Lag() (Lag, bool) {
+	if r.file == nil || r.FileType != "" {
+		return Lag{}, false
+	}
+	info, err := r.file.Stat()
+	if err != nil {
+		return Lag{}, false
+	}
+	return Lag{Bytes: max(0, info.Size()-r.Offset), ModTime: info.ModTime()}, true
+}
+
 func (r *Reader) GetFileName() string {
 	return r.fileName
 }
@@ -19,7 +19,9 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/charset"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/ratelimit"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/scanner"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/internal/filetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/internal/stanzatime"
@@ -234,6 +236,101 @@ func TestFlushPeriodEOF(t *testing.T) {
 	sink.ExpectToken(t, []byte{'b'})
 }
 
+func TestMaxBytesPerRead(t *testing.T) {
+	tempDir := t.TempDir()
+	temp := filetest.OpenTemp(t, tempDir)
+	_, err := temp.WriteString("aaaa\nbbbb\ncccc\n")
+	require.NoError(t, err)
+
+	f, sink := testFactory(t)
+	f.MaxBytesPerRead = 5
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+	r, err := f.NewReader(temp, fp)
+	require.NoError(t, err)
+
+	for i, expected := range []string{"aaaa", "bbbb", "cccc"} {
+		r.ReadToEnd(t.Context())
+		sink.ExpectToken(t, []byte(expected))
+		assert.True(t, r.Yielded())
+		lag, ok := r.Lag()
+		require.True(t, ok)
+		assert.Equal(t, int64(10-5*i), lag.Bytes)
+	}
+
+	r.ReadToEnd(t.Context())
+	sink.ExpectNoCalls(t)
+	assert.False(t, r.Yielded())
+	assert.Equal(t, int64(15), r.Offset)
+}
+
+func TestRateLimit(t *testing.T) {
+	tempDir := t.TempDir()
+	temp := filetest.OpenTemp(t, tempDir)
+	line := strings.Repeat("a", 99)
+	_, err := temp.WriteString(line + "\n" + line + "\n")
+	require.NoError(t, err)
+
+	// The first line takes far more bytes than are refilled during the test
+	f, sink := testFactory(t)
+	f.RateLimiter = ratelimit.New(10, 0)
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+	r, err := f.NewReader(temp, fp)
+	require.NoError(t, err)
+	require.Len(t, r.RateLimits, 1)
+
+	r.ReadToEnd(t.Context())
+	sink.ExpectToken(t, []byte(line))
+	assert.False(t, r.Yielded())
+	assert.Equal(t, int64(100), r.Offset)
+
+	// The limits of the file are kept with its metadata
+	m := r.Close()
+	r, err = f.NewReaderFromMetadata(filetest.OpenFile(t, temp.Name()), m)
+	require.NoError(t, err)
+	r.ReadToEnd(t.Context())
+	sink.ExpectNoCalls(t)
+	assert.Equal(t, int64(100), r.Offset)
+}
+
+func TestDetectEncoding(t *testing.T) {
+	utf16le, err := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().String("log1\nlog2\n")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "UTF16LEWithBOM", content: "\xFF\xFE" + utf16le, expected: charset.UTF16LE},
+		{name: "UTF8WithBOM", content: "\xEF\xBB\xBFlog1\nlog2\n", expected: charset.UTF8},
+		{name: "Latin1", content: "log1\xE9\nlog2\n", expected: charset.Latin1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			temp := filetest.OpenTemp(t, tempDir)
+			_, err := temp.WriteString(tc.content)
+			require.NoError(t, err)
+
+			f, sink := testFactory(t, withDetectEncoding())
+			fp, err := f.NewFingerprint(temp)
+			require.NoError(t, err)
+			r, err := f.NewReader(temp, fp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, r.Encoding)
+
+			r.ReadToEnd(t.Context())
+			token, attributes := sink.NextCall(t)
+			assert.True(t, strings.HasPrefix(string(token), "log1"))
+			assert.Equal(t, tc.expected, attributes[attrs.LogFileEncoding])
+			sink.ExpectToken(t, []byte("log2"))
+		})
+	}
+}
+
 func TestUntermintedLongLogEntry(t *testing.T) {
 	tempDir := t.TempDir()
 	temp := filetest.OpenTemp(t, tempDir)
@@ -0,0 +1,247 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package watch // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/watch"
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// ErrNotSupported is returned when file system notifications are not available on the platform.
+var ErrNotSupported = errors.New("file system notifications are not supported on this platform")
+
+// Changes are the changes observed since they were last drained.
+type Changes struct {
+	// Paths are the files that were created, written to, renamed or whose permissions changed.
+	Paths []string
+	// Rescan is set when events may have been missed, or when directories appeared
+	// that may contain matching files. All files must be matched again.
+	Rescan bool
+}
+
+// Watcher watches the directories containing the files matched by the include
+// patterns, and records the files that change.
+//
+// Notifications are not recursive, so only the directories passed to Sync are watched,
+// along with the directories that are created in them and that may contain matching files.
+type Watcher struct {
+	logger   *zap.Logger
+	includes [][]string
+	fsw      *fsnotify.Watcher
+	notify   chan struct{}
+	done     chan struct{}
+
+	mu      sync.Mutex
+	watched map[string]struct{}
+	failed  map[string]struct{}
+	pending map[string]struct{}
+	rescan  bool
+}
+
+// New creates a Watcher for the given include patterns and starts listening for events.
+func New(logger *zap.Logger, includes []string) (*Watcher, error) {
+	fsw, err := newFSWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		logger:  logger,
+		fsw:     fsw,
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		watched: map[string]struct{}{},
+		failed:  map[string]struct{}{},
+		pending: map[string]struct{}{},
+	}
+	for _, include := range includes {
+		w.includes = append(w.includes, splitPath(include))
+	}
+	go w.run()
+	return w, nil
+}
+
+// Notify returns a channel that receives a value when there are changes to drain.
+func (w *Watcher) Notify() <-chan struct{} {
+	return w.notify
+}
+
+// Drain returns the changes observed since the last call and resets them.
+func (w *Watcher) Drain() Changes {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changes := Changes{Rescan: w.rescan}
+	for path := range w.pending {
+		changes.Paths = append(changes.Paths, path)
+	}
+	slices.Sort(changes.Paths)
+	clear(w.pending)
+	w.rescan = false
+	return changes
+}
+
+// Sync watches the base directories of the include patterns and the directories of the
+// given matched files. Directories that are already watched are skipped.
+func (w *Watcher) Sync(matches []string) {
+	dirs := make(map[string]struct{}, len(w.includes))
+	for _, include := range w.includes {
+		if base := baseDir(include); base != "" {
+			dirs[base] = struct{}{}
+		}
+	}
+	for _, match := range matches {
+		dirs[filepath.Dir(match)] = struct{}{}
+	}
+	for dir := range dirs {
+		w.watch(dir)
+	}
+}
+
+// Close stops watching and releases the underlying notification resources.
+func (w *Watcher) Close() error {
+	err := w.fsw.Close()
+	<-w.done
+	return err
+}
+
+func (w *Watcher) watch(dir string) {
+	w.mu.Lock()
+	_, watched := w.watched[dir]
+	w.mu.Unlock()
+	if watched {
+		return
+	}
+
+	if err := w.fsw.Add(dir); err != nil {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		// Directories may not exist yet, and the number of watches may be limited by the
+		// system. Either way, the directory is still covered by polling.
+		if _, seen := w.failed[dir]; !seen {
+			w.failed[dir] = struct{}{}
+			w.logger.Debug("Failed to watch directory", zap.String("path", dir), zap.Error(err))
+		}
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.failed, dir)
+	w.watched[dir] = struct{}{}
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			if !errors.Is(err, fsnotify.ErrEventOverflow) {
+				w.logger.Debug("File system notification error", zap.Error(err))
+			}
+			w.record(func() { w.rescan = true })
+		}
+	}
+}
+
+func (w *Watcher) handleEvent(event fsnotify.Event) {
+	switch {
+	case event.Has(fsnotify.Create):
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if w.mayContainMatches(event.Name) {
+				w.watch(event.Name)
+				w.record(func() { w.rescan = true })
+			}
+			return
+		}
+		w.record(func() { w.pending[event.Name] = struct{}{} })
+	case event.Has(fsnotify.Write), event.Has(fsnotify.Chmod):
+		w.record(func() { w.pending[event.Name] = struct{}{} })
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		w.mu.Lock()
+		_, watched := w.watched[event.Name]
+		w.mu.Unlock()
+		if watched {
+			// Watches of removed directories are dropped by the system, so the directory must
+			// be watched again if it's recreated.
+			_ = w.fsw.Remove(event.Name)
+			w.record(func() {
+				delete(w.watched, event.Name)
+				w.rescan = true
+			})
+		}
+	}
+}
+
+// record updates the pending changes and signals that there are changes to drain.
+func (w *Watcher) record(update func()) {
+	w.mu.Lock()
+	update()
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// mayContainMatches reports whether files in the directory, or in its subdirectories,
+// may be matched by any of the include patterns.
+func (w *Watcher) mayContainMatches(dir string) bool {
+	segments := splitPath(dir)
+	for _, include := range w.includes {
+		for i, pattern := range include {
+			if pattern == "**" {
+				return true
+			}
+			if i == len(segments) {
+				// The pattern has more segments than the directory, and its prefix matched.
+				return true
+			}
+			if matched, _ := doublestar.Match(pattern, segments[i]); !matched {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// baseDir returns the leading segments of the pattern that don't contain any meta characters.
+func baseDir(pattern []string) string {
+	var base []string
+	for _, segment := range pattern[:len(pattern)-1] {
+		if strings.ContainsAny(segment, "*?[{\\") {
+			break
+		}
+		base = append(base, segment)
+	}
+	if len(base) == 0 {
+		return ""
+	}
+	if base[0] == "" {
+		// The pattern is absolute.
+		return string(filepath.Separator) + filepath.Join(base[1:]...)
+	}
+	return filepath.Join(base...)
+}
+
+func splitPath(path string) []string {
+	return strings.Split(filepath.Clean(path), string(filepath.Separator))
+}
@@ -0,0 +1,13 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package watch // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/watch"
+
+import "github.com/fsnotify/fsnotify"
+
+// newFSWatcher creates an inotify based watcher.
+func newFSWatcher() (*fsnotify.Watcher, error) {
+	return fsnotify.NewWatcher()
+}
@@ -0,0 +1,14 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package watch // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/watch"
+
+import "github.com/fsnotify/fsnotify"
+
+// newFSWatcher returns ErrNotSupported, notifications are only used on Linux where
+// they are backed by inotify.
+func newFSWatcher() (*fsnotify.Watcher, error) {
+	return nil, ErrNotSupported
+}
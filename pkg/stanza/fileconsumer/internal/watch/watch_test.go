@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBaseDir(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		expected string
+	}{
+		{pattern: "*.log", expected: ""},
+		{pattern: filepath.Join("logs", "*.log"), expected: "logs"},
+		{pattern: filepath.Join("logs", "*", "app", "*.log"), expected: "logs"},
+		{pattern: filepath.Join("logs", "app.log"), expected: "logs"},
+		{pattern: string(filepath.Separator) + filepath.Join("var", "log", "**", "*.log"), expected: string(filepath.Separator) + filepath.Join("var", "log")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			assert.Equal(t, tc.expected, baseDir(splitPath(tc.pattern)))
+		})
+	}
+}
+
+func TestMayContainMatches(t *testing.T) {
+	w := &Watcher{includes: [][]string{
+		splitPath(filepath.Join("a", "*", "*.log")),
+		splitPath(filepath.Join("b", "**", "*.log")),
+	}}
+
+	assert.True(t, w.mayContainMatches(filepath.Join("a", "c")))
+	assert.False(t, w.mayContainMatches(filepath.Join("a", "c", "d")))
+	assert.True(t, w.mayContainMatches(filepath.Join("b", "c", "d")))
+	assert.False(t, w.mayContainMatches(filepath.Join("c", "d")))
+}
+
+func TestWatcher(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		_, err := New(zap.NewNop(), []string{"*.log"})
+		require.ErrorIs(t, err, ErrNotSupported)
+		return
+	}
+
+	dir := t.TempDir()
+	w, err := New(zap.NewNop(), []string{filepath.Join(dir, "*", "*.log")})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, w.Close())
+	}()
+	w.Sync(nil)
+
+	// Directories that may contain matching files are watched once they're created.
+	sub := filepath.Join(dir, "app")
+	require.NoError(t, os.Mkdir(sub, 0o700))
+	changes := waitForChanges(t, w)
+	assert.True(t, changes.Rescan)
+
+	path := filepath.Join(sub, "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("line\n"), 0o600))
+	require.Eventually(t, func() bool {
+		changes.Paths = append(changes.Paths, w.Drain().Paths...)
+		return assert.ObjectsAreEqual([]string{path}, slices.Compact(changes.Paths))
+	}, 5*time.Second, 10*time.Millisecond)
+	changes = Changes{}
+
+	// Removing a watched directory requires all files to be matched again.
+	require.NoError(t, os.RemoveAll(sub))
+	require.Eventually(t, func() bool {
+		return w.Drain().Rescan
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func waitForChanges(t *testing.T, w *Watcher) Changes {
+	select {
+	case <-w.Notify():
+		return w.Drain()
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no changes were observed")
+		return Changes{}
+	}
+}
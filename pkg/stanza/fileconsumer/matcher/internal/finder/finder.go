@@ -59,3 +59,13 @@ func FindFiles(includes, excludes []string) ([]string, error) {
 	slices.Sort(keys)
 	return keys, errs
 }
+
+// Match reports whether the path is matched by any of the include patterns and none of the exclude patterns.
+func Match(includes, excludes []string, path string) bool {
+	for _, include := range includes {
+		if itMatches, _ := doublestar.PathMatch(include, path); itMatches {
+			return !pathExcluded(excludes, path)
+		}
+	}
+	return false
+}
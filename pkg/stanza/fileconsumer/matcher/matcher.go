@@ -199,3 +199,16 @@ func (m Matcher) MatchFiles() ([]string, error) {
 
 	return result, errs
 }
+
+// MatchPath reports whether the path is matched by the include and exclude patterns.
+// Neither 'exclude_older_than' nor 'ordering_criteria' are applied, see Filtered.
+func (m Matcher) MatchPath(path string) bool {
+	return finder.Match(m.include, m.exclude, path)
+}
+
+// Filtered reports whether the matched files are filtered by 'exclude_older_than' or
+// 'ordering_criteria', in which case whether a file is matched depends on the other
+// files, and it can only be determined by MatchFiles.
+func (m Matcher) Filtered() bool {
+	return len(m.filterOpts) > 0
+}
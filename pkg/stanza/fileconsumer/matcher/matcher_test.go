@@ -854,6 +854,40 @@ func TestMatcher(t *testing.T) {
 	}
 }
 
+func TestMatchPath(t *testing.T) {
+	matcher, err := New(Criteria{
+		Include: []string{filepath.Join("a", "*.log"), filepath.Join("b", "**", "*.log")},
+		Exclude: []string{filepath.Join("**", "exclude.log")},
+	})
+	require.NoError(t, err)
+	assert.False(t, matcher.Filtered())
+
+	assert.True(t, matcher.MatchPath(filepath.Join("a", "1.log")))
+	assert.False(t, matcher.MatchPath(filepath.Join("a", "1.txt")))
+	assert.False(t, matcher.MatchPath(filepath.Join("a", "c", "1.log")))
+	assert.True(t, matcher.MatchPath(filepath.Join("b", "c", "d", "1.log")))
+	assert.False(t, matcher.MatchPath(filepath.Join("b", "exclude.log")))
+}
+
+func TestFiltered(t *testing.T) {
+	matcher, err := New(Criteria{
+		Include:          []string{"*.log"},
+		ExcludeOlderThan: time.Hour,
+	})
+	require.NoError(t, err)
+	assert.True(t, matcher.Filtered())
+
+	matcher, err = New(Criteria{
+		Include: []string{"*.log"},
+		OrderingCriteria: OrderingCriteria{
+			Regex:  `(?P<value>\d+)`,
+			SortBy: []Sort{{SortType: sortTypeNumeric, RegexKey: "value"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, matcher.Filtered())
+}
+
 func enableSortByMTimeFeature(t *testing.T) {
 	if !metadata.FilelogMtimeSortTypeFeatureGate.IsEnabled() {
 		require.NoError(t, featuregate.GlobalRegistry().Set(metadata.FilelogMtimeSortTypeFeatureGate.ID(), true))
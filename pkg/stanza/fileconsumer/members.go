@@ -0,0 +1,263 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/bundle"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/flush"
+)
+
+// knownMembersKey is the key under which the metadata of the files read from archives is saved.
+const knownMembersKey = "knownArchiveMembers"
+
+// memberTracker keeps the metadata of the files read from archives, which are tracked
+// separately from the files that are matched on disk.
+type memberTracker struct {
+	mu       sync.Mutex
+	metadata []*reader.Metadata
+	// touched are the metadata of the archives seen since the last poll.
+	touched map[*reader.Metadata]struct{}
+	// complete are the archives whose files were all read to the end, by path.
+	complete map[string]archiveState
+	seen     map[string]struct{}
+}
+
+type archiveState struct {
+	size    int64
+	modTime time.Time
+}
+
+func newMemberTracker() *memberTracker {
+	return &memberTracker{
+		touched:  map[*reader.Metadata]struct{}{},
+		complete: map[string]archiveState{},
+		seen:     map[string]struct{}{},
+	}
+}
+
+// lookup returns the metadata of the files read from the archive, by name, and
+// marks them as seen.
+func (t *memberTracker) lookup(fp *fingerprint.Fingerprint) map[string]*reader.Metadata {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	known := map[string]*reader.Metadata{}
+	for _, md := range t.metadata {
+		if fp.StartsWith(md.GetFingerprint()) {
+			known[md.Member] = md
+			t.touched[md] = struct{}{}
+		}
+	}
+	return known
+}
+
+// update replaces the metadata of a file read from an archive.
+func (t *memberTracker) update(old, md *reader.Metadata) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.touched, old)
+	t.touched[md] = struct{}{}
+	for i := range t.metadata {
+		if t.metadata[i] == old {
+			t.metadata[i] = md
+			return
+		}
+	}
+	t.metadata = append(t.metadata, md)
+}
+
+// isComplete reports whether all the files of the archive were read to the end, and the
+// archive didn't change since.
+func (t *memberTracker) isComplete(path string, info os.FileInfo) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seen[path] = struct{}{}
+	state, ok := t.complete[path]
+	return ok && state.size == info.Size() && state.modTime.Equal(info.ModTime())
+}
+
+func (t *memberTracker) setComplete(path string, info os.FileInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.complete[path] = archiveState{size: info.Size(), modTime: info.ModTime()}
+}
+
+// endPoll forgets the archives that weren't seen since the last poll.
+func (t *memberTracker) endPoll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	metadata := t.metadata[:0]
+	for _, md := range t.metadata {
+		if _, ok := t.touched[md]; ok {
+			metadata = append(metadata, md)
+		}
+	}
+	clear(t.metadata[len(metadata):])
+	t.metadata = metadata
+	clear(t.touched)
+
+	for path := range t.complete {
+		if _, ok := t.seen[path]; !ok {
+			delete(t.complete, path)
+		}
+	}
+	clear(t.seen)
+}
+
+func (t *memberTracker) getMetadata() []*reader.Metadata {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*reader.Metadata(nil), t.metadata...)
+}
+
+func (t *memberTracker) loadMetadata(metadata []*reader.Metadata) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.metadata = metadata
+	for _, md := range metadata {
+		t.touched[md] = struct{}{}
+	}
+}
+
+// consumeArchives reads the files of the archives among the paths, and returns the
+// paths of the other files.
+func (m *Manager) consumeArchives(ctx context.Context, paths []string) []string {
+	if m.archiveFormat == "" {
+		return paths
+	}
+
+	type archive struct {
+		file   *os.File
+		format string
+		fp     *fingerprint.Fingerprint
+	}
+	archives := make([]archive, 0, len(paths))
+	files := make([]string, 0, len(paths))
+OUTER:
+	for _, path := range paths {
+		file, err := openFile(path) // #nosec - operator must read in files defined by user
+		if err != nil {
+			// Errors are reported when the file is read as it is.
+			files = append(files, path)
+			continue
+		}
+		format := m.archiveFormat
+		if format == bundle.Auto {
+			format = bundle.Detect(file, m.set.Logger)
+		}
+		if format == "" {
+			m.closeFile(file)
+			files = append(files, path)
+			continue
+		}
+
+		fp, err := fingerprint.NewFromFile(file, m.readerFactory.FingerprintSize, false, m.set.Logger)
+		if err != nil || fp.Len() == 0 {
+			m.closeFile(file)
+			continue
+		}
+		// Copies of the same archive are only read once.
+		for _, a := range archives {
+			if a.fp.Equal(fp) {
+				m.set.Logger.Debug("Skipping duplicate archive", zap.String("path", path))
+				m.closeFile(file)
+				continue OUTER
+			}
+		}
+		archives = append(archives, archive{file: file, format: format, fp: fp})
+	}
+
+	for len(archives) > 0 {
+		batch := archives[:min(len(archives), m.maxBatchFiles)]
+		archives = archives[len(batch):]
+
+		var wg sync.WaitGroup
+		for _, a := range batch {
+			wg.Go(func() {
+				defer m.closeFile(a.file)
+				m.readArchive(ctx, a.file, a.format, a.fp)
+			})
+		}
+		wg.Wait()
+	}
+	return files
+}
+
+// readArchive reads the files of an archive to the end, starting from their known offsets.
+func (m *Manager) readArchive(ctx context.Context, file *os.File, format string, fp *fingerprint.Fingerprint) {
+	logger := m.set.Logger.With(zap.String("path", file.Name()))
+	info, err := file.Stat()
+	if err != nil {
+		logger.Error("Failed to stat archive", zap.Error(err))
+		return
+	}
+	known := m.members.lookup(fp)
+	if m.members.isComplete(file.Name(), info) {
+		return
+	}
+
+	complete := true
+	err = bundle.Walk(file, format, func(member bundle.Member, src io.Reader) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		old := known[member.Name]
+		md := old
+		if md == nil {
+			md = &reader.Metadata{
+				FlushState: flush.State{
+					LastDataChange: time.Now(),
+				},
+				Member: member.Name,
+			}
+			if !m.readerFactory.FromBeginning {
+				md.Offset = member.Size
+			}
+		}
+		md.Fingerprint = fp
+		if md.Offset >= member.Size {
+			m.members.update(old, md)
+			return nil
+		}
+
+		r, err := m.readerFactory.NewMemberReader(file, src, md)
+		if err != nil {
+			logger.Error("Failed to create reader", zap.String("member", member.Name), zap.Error(err))
+			complete = false
+			return nil
+		}
+		m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, 1)
+		r.ReadToEnd(ctx)
+		m.telemetryBuilder.FileconsumerReadingFiles.Add(ctx, -1)
+		md = r.Close()
+		if md.Offset < member.Size {
+			complete = false
+		}
+		m.members.update(old, md)
+		return nil
+	})
+	switch {
+	case err != nil:
+		if ctx.Err() == nil {
+			logger.Error("Failed to read archive", zap.Error(err))
+		}
+	case complete:
+		m.members.setComplete(file.Name(), info)
+	}
+}
+
+func (m *Manager) closeFile(file *os.File) {
+	if err := file.Close(); err != nil {
+		m.set.Logger.Debug("problem closing file", zap.Error(err))
+	}
+}
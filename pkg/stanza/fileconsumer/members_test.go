@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileconsumer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/emit"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/internal/filetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+type archiveEntry struct {
+	name    string
+	content string
+}
+
+func tarData(t *testing.T, entries ...archiveEntry) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: e.name, Mode: 0o600, Size: int64(len(e.content))}))
+		_, err := tw.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func zipData(t *testing.T, entries ...archiveEntry) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func writeArchive(t *testing.T, path string, data []byte) {
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+// TestReadArchives tests that the files of tar and zip archives are read as separate files
+// named after the archive, and that other files are read as they are.
+func TestReadArchives(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Archive = "auto"
+	operator, sink := testManager(t, cfg)
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, err := gw.Write(tarData(t,
+		archiveEntry{name: "app.log", content: "app log 1\napp log 2\n"},
+		archiveEntry{name: "db/db.log", content: "db log\n"},
+	))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	writeArchive(t, filepath.Join(tempDir, "bundle.tar.gz"), gz.Bytes())
+	writeArchive(t, filepath.Join(tempDir, "bundle.zip"), zipData(t,
+		archiveEntry{name: "web.log", content: "web log\n"},
+	))
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "plain log\n")
+
+	member := func(archive, name string) map[string]any {
+		return map[string]any{attrs.LogFileName: archive + attrs.MemberSeparator + name}
+	}
+	operator.poll(t.Context())
+	sink.ExpectCalls(t,
+		emit.NewToken([]byte("app log 1"), member("bundle.tar.gz", "app.log")),
+		emit.NewToken([]byte("app log 2"), member("bundle.tar.gz", "app.log")),
+		emit.NewToken([]byte("db log"), member("bundle.tar.gz", "db/db.log")),
+		emit.NewToken([]byte("web log"), member("bundle.zip", "web.log")),
+		emit.NewToken([]byte("plain log"), map[string]any{attrs.LogFileName: filepath.Base(temp.Name())}),
+	)
+
+	// The archives are not read again
+	operator.poll(t.Context())
+	sink.ExpectNoCalls(t)
+}
+
+// TestReadArchivesStartAtEnd tests that the files of archives which exist at startup are
+// skipped when starting at the end, while archives that appear afterward are read entirely.
+func TestReadArchivesStartAtEnd(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.Archive = "zip"
+	operator, sink := testManager(t, cfg)
+
+	writeArchive(t, filepath.Join(tempDir, "old.zip"), zipData(t, archiveEntry{name: "old.log", content: "old log\n"}))
+	operator.poll(t.Context())
+	sink.ExpectNoCalls(t)
+
+	writeArchive(t, filepath.Join(tempDir, "new.zip"), zipData(t, archiveEntry{name: "new.log", content: "new log\n"}))
+	operator.poll(t.Context())
+	sink.ExpectToken(t, []byte("new log"))
+	sink.ExpectNoCalls(t)
+}
+
+// TestReadArchiveResume tests that the offsets of the files of an archive are saved, so
+// that an archive which was partially read is resumed after a restart.
+func TestReadArchiveResume(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Archive = "tar"
+
+	data := tarData(t,
+		archiveEntry{name: "a.log", content: "a1\na2\n"},
+		archiveEntry{name: "b.log", content: "b1\nb2\n"},
+	)
+	// The archive is still being written, and ends after the first line of b.log, which follows
+	// the header of a.log, its padded contents, and the header of b.log.
+	cut := 512*3 + len("b1\n")
+	path := filepath.Join(tempDir, "bundle.tar")
+	writeArchive(t, path, data[:cut])
+
+	persister := testutil.NewUnscopedMockPersister()
+	operator, sink := testManager(t, cfg)
+	require.NoError(t, operator.Start(persister))
+	sink.ExpectTokens(t, []byte("a1"), []byte("a2"), []byte("b1"))
+	require.NoError(t, operator.Stop())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.Write(data[cut:])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	operator, sink = testManager(t, cfg)
+	require.NoError(t, operator.Start(persister))
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+	sink.ExpectToken(t, []byte("b2"))
+	sink.ExpectNoCalls(t)
+}
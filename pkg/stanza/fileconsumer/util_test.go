@@ -4,9 +4,11 @@
 package fileconsumer
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/emittest"
@@ -20,7 +22,17 @@ func testManager(t *testing.T, cfg *Config, opts ...Option) (*Manager, *emittest
 }
 
 func testManagerWithSink(t *testing.T, cfg *Config, sink *emittest.Sink, opts ...Option) *Manager {
-	set := componenttest.NewNopTelemetrySettings()
+	return testManagerWithSettings(t, cfg, sink, componenttest.NewNopTelemetrySettings(), opts...)
+}
+
+func testManagerWithTelemetry(t *testing.T, cfg *Config, opts ...Option) (*Manager, *emittest.Sink, *componenttest.Telemetry) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+	sink := emittest.NewSink()
+	return testManagerWithSettings(t, cfg, sink, tel.NewTelemetrySettings(), opts...), sink, tel
+}
+
+func testManagerWithSettings(t *testing.T, cfg *Config, sink *emittest.Sink, set component.TelemetrySettings, opts ...Option) *Manager {
 	input, err := cfg.Build(set, sink.Callback, opts...)
 	input.tracker = tracker.NewFileTracker(t.Context(), set, cfg.MaxBatches, cfg.PollsToArchive, testutil.NewUnscopedMockPersister())
 	require.NoError(t, err)
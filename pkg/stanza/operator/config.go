@@ -30,6 +30,14 @@ type Builder interface {
 	SetID(string)
 }
 
+// AcknowledgingBuilder is implemented by the builders of inputs which only consider their entries
+// delivered once writing them returned without error. The receivers running such inputs emit
+// entries synchronously, and return the errors of their consumers.
+type AcknowledgingBuilder interface {
+	Builder
+	RequiresAcknowledgement() bool
+}
+
 // UnmarshalJSON will unmarshal a config from JSON.
 func (c *Config) UnmarshalJSON(bytes []byte) error {
 	var typeUnmarshaller struct {
@@ -5,6 +5,7 @@ package helper // import "github.com/open-telemetry/opentelemetry-collector-cont
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -201,17 +202,53 @@ func (e *BatchingLogEmitter) makeNewBatch() []*entry.Entry {
 // SynchronousLogEmitter is a stanza operator that emits log entries to the consumer callback function `consumerFunc` synchronously
 type SynchronousLogEmitter struct {
 	OutputOperator
-	consumerFunc func(context.Context, []*entry.Entry)
+	consumerFunc func(context.Context, []*entry.Entry) error
 }
 
 func NewSynchronousLogEmitter(set component.TelemetrySettings, consumerFunc func(context.Context, []*entry.Entry)) *SynchronousLogEmitter {
+	return NewAcknowledgingLogEmitter(set, func(ctx context.Context, entries []*entry.Entry) error {
+		consumerFunc(ctx, entries)
+		return nil
+	})
+}
+
+// NewAcknowledgingLogEmitter creates a SynchronousLogEmitter which returns the errors of `consumerFunc`
+// as a *DeliveryError, so that inputs know whether the entries they wrote were accepted by the consumer.
+// `consumerFunc` should only return an error when the entries can be emitted again.
+func NewAcknowledgingLogEmitter(set component.TelemetrySettings, consumerFunc func(context.Context, []*entry.Entry) error) *SynchronousLogEmitter {
 	op, _ := NewOutputConfig("synchronous_log_emitter", "synchronous_log_emitter").Build(set)
 	return &SynchronousLogEmitter{
 		OutputOperator: op,
-		consumerFunc:   consumerFunc,
+		consumerFunc: func(ctx context.Context, entries []*entry.Entry) error {
+			if err := consumerFunc(ctx, entries); err != nil {
+				return &DeliveryError{Err: err}
+			}
+			return nil
+		},
 	}
 }
 
+// DeliveryError is returned when the consumer of a log emitter failed to accept entries, as opposed
+// to the errors of the operators processing them, which are returned once the entries were sent on.
+type DeliveryError struct {
+	Err error
+}
+
+func (e *DeliveryError) Error() string {
+	return "deliver entries: " + e.Err.Error()
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// IsDeliveryError reports whether err holds a *DeliveryError, in which case the entries written
+// weren't accepted by the consumer and should be written again.
+func IsDeliveryError(err error) bool {
+	var deliveryErr *DeliveryError
+	return errors.As(err, &deliveryErr)
+}
+
 func (*SynchronousLogEmitter) Start(operator.Persister) error {
 	return nil
 }
@@ -221,11 +258,9 @@ func (*SynchronousLogEmitter) Stop() error {
 }
 
 func (e *SynchronousLogEmitter) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
-	e.consumerFunc(ctx, entries)
-	return nil
+	return e.consumerFunc(ctx, entries)
 }
 
 func (e *SynchronousLogEmitter) Process(ctx context.Context, ent *entry.Entry) error {
-	e.consumerFunc(ctx, []*entry.Entry{ent})
-	return nil
+	return e.consumerFunc(ctx, []*entry.Entry{ent})
 }
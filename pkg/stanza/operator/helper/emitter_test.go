@@ -5,6 +5,7 @@ package helper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -247,3 +248,29 @@ func complexEntriesForNDifferentHosts(count, n int) []*entry.Entry {
 	}
 	return ret
 }
+
+func TestAcknowledgingLogEmitter(t *testing.T) {
+	consumeErr := errors.New("rejected")
+	emitter := NewAcknowledgingLogEmitter(
+		componenttest.NewNopTelemetrySettings(),
+		func(_ context.Context, entries []*entry.Entry) error {
+			if len(entries) > 1 {
+				return consumeErr
+			}
+			return nil
+		},
+	)
+
+	require.NoError(t, emitter.Start(nil))
+	defer func() {
+		require.NoError(t, emitter.Stop())
+	}()
+
+	assert.NoError(t, emitter.Process(t.Context(), entry.New()))
+	assert.NoError(t, emitter.ProcessBatch(t.Context(), []*entry.Entry{entry.New()}))
+	err := emitter.ProcessBatch(t.Context(), []*entry.Entry{entry.New(), entry.New()})
+	assert.ErrorIs(t, err, consumeErr)
+	assert.True(t, IsDeliveryError(err))
+	assert.True(t, IsDeliveryError(fmt.Errorf("failed to send entry after error: %w", err)))
+	assert.False(t, IsDeliveryError(consumeErr))
+}
@@ -37,6 +37,12 @@ type Config struct {
 	fileconsumer.Config `mapstructure:",squash"`
 }
 
+// RequiresAcknowledgement reports whether entries must be accepted downstream before the
+// offsets of the files move past them.
+func (c Config) RequiresAcknowledgement() bool {
+	return c.AtLeastOnce
+}
+
 // Build will build a file input operator from the supplied configuration
 func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error) {
 	inputOperator, err := c.InputConfig.Build(set)
@@ -40,18 +40,22 @@ func (i *Input) Stop() error {
 	return i.fileConsumer.Stop()
 }
 
+// emitBatch writes the entries of the tokens, and only returns an error if they weren't accepted
+// by the consumer. The errors of converting tokens and processing entries are reported here,
+// since reading the tokens again when reading at least once would fail the same way.
 func (i *Input) emitBatch(ctx context.Context, tokens [][]byte, attributes map[string]any, lastRecordNumber int64, offsets []int64) error {
-	var errs error
 	entries, err := i.convertTokens(tokens, attributes, lastRecordNumber, offsets)
 	if err != nil {
-		errs = multierr.Append(errs, fmt.Errorf("convert tokens: %w", err))
+		i.Logger().Error("convert tokens", zap.Error(err))
 	}
 
 	if err = i.WriteBatch(ctx, entries); err != nil {
-		errs = multierr.Append(errs, fmt.Errorf("consume entries: %w", err))
+		if helper.IsDeliveryError(err) {
+			return fmt.Errorf("consume entries: %w", err)
+		}
+		i.Logger().Error("process entries", zap.Error(err))
 	}
-
-	return errs
+	return nil
 }
 
 func (i *Input) convertTokens(tokens [][]byte, attributes map[string]any, lastRecordNumber int64, offsets []int64) ([]*entry.Entry, error) {
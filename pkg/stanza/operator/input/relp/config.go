@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package relp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/relp"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jpillora/backoff"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtls"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/textutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const (
+	operatorType = "relp_input"
+
+	// DefaultMaxLogSize is the max size of the data of a frame
+	// if MaxLogSize is not set
+	DefaultMaxLogSize = 128 * 1024
+)
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
+}
+
+// NewConfig creates a new RELP input config with default values
+func NewConfig() *Config {
+	return NewConfigWithID(operatorType)
+}
+
+// NewConfigWithID creates a new RELP input config with default values
+func NewConfigWithID(operatorID string) *Config {
+	return &Config{
+		InputConfig: helper.NewInputConfig(operatorID, operatorType),
+		BaseConfig: BaseConfig{
+			Encoding: "utf-8",
+		},
+	}
+}
+
+// Config is the configuration of a relp input operator.
+type Config struct {
+	helper.InputConfig `mapstructure:",squash"`
+	BaseConfig         `mapstructure:",squash"`
+}
+
+// BaseConfig is the detailed configuration of a relp input operator.
+type BaseConfig struct {
+	MaxLogSize    helper.ByteSize         `mapstructure:"max_log_size,omitempty"`
+	ListenAddress string                  `mapstructure:"listen_address,omitempty"`
+	TLS           *configtls.ServerConfig `mapstructure:"tls,omitempty"`
+	AddAttributes bool                    `mapstructure:"add_attributes,omitempty"`
+	Encoding      string                  `mapstructure:"encoding,omitempty"`
+}
+
+// RequiresAcknowledgement always returns true, since frames are only acknowledged
+// to the client once their entry was written without error.
+func (Config) RequiresAcknowledgement() bool {
+	return true
+}
+
+// Build will build a relp input operator.
+func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error) {
+	inputOperator, err := c.InputConfig.Build(set)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.MaxLogSize == 0 {
+		c.MaxLogSize = DefaultMaxLogSize
+	}
+
+	if c.MaxLogSize < 0 || c.MaxLogSize > maxDataLen {
+		return nil, fmt.Errorf("invalid value for parameter 'max_log_size', must be between 1 and %d bytes", maxDataLen)
+	}
+
+	if c.ListenAddress == "" {
+		return nil, errors.New("missing required parameter 'listen_address'")
+	}
+
+	// validate the input address
+	if _, err = net.ResolveTCPAddr("tcp", c.ListenAddress); err != nil {
+		return nil, fmt.Errorf("failed to resolve listen_address: %w", err)
+	}
+
+	enc, err := textutils.LookupEncoding(c.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolver *helper.IPResolver
+	if c.AddAttributes {
+		resolver = helper.NewIPResolver()
+	}
+
+	relpInput := &Input{
+		InputOperator: inputOperator,
+		address:       c.ListenAddress,
+		maxLogSize:    int(c.MaxLogSize),
+		addAttributes: c.AddAttributes,
+		encoding:      enc,
+		backoff: backoff.Backoff{
+			Max: 3 * time.Second,
+		},
+		resolver: resolver,
+	}
+
+	if c.TLS != nil {
+		relpInput.tls, err = c.TLS.LoadTLSConfig(context.Background())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return relpInput, nil
+}
@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package relp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/relp"
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RELP frames are made of a transaction number, a command, the length of the data,
+// and the data itself, separated by a space and terminated by a newline:
+//
+//	TXNR SP COMMAND SP DATALEN [SP DATA] LF
+const (
+	commandOpen        = "open"
+	commandClose       = "close"
+	commandSyslog      = "syslog"
+	commandRsp         = "rsp"
+	commandServerClose = "serverclose"
+
+	maxTxnrLen    = 9
+	maxCommandLen = 32
+	maxDataLen    = 999999999
+)
+
+var errDataTooLarge = errors.New("frame data exceeds max_log_size")
+
+type frame struct {
+	txnr    int
+	command string
+	data    []byte
+}
+
+// readFrame reads the next frame from r. It returns io.EOF if r ended before a new frame.
+// If the data of the frame is longer than maxLogSize, the data is skipped and errDataTooLarge
+// is returned along with the transaction number and command of the frame.
+func readFrame(r *bufio.Reader, maxLogSize int) (frame, error) {
+	var f frame
+
+	txnr, _, err := readField(r, maxTxnrLen, false)
+	if err != nil {
+		if errors.Is(err, io.EOF) && txnr == "" {
+			return f, io.EOF
+		}
+		return f, fmt.Errorf("read transaction number: %w", err)
+	}
+	if f.txnr, err = parseNumber(txnr); err != nil {
+		return f, fmt.Errorf("invalid transaction number: %w", err)
+	}
+
+	if f.command, _, err = readField(r, maxCommandLen, false); err != nil {
+		return f, fmt.Errorf("read command: %w", err)
+	}
+	if f.command == "" || strings.ContainsFunc(f.command, func(c rune) bool { return c < 'a' || c > 'z' }) {
+		return f, fmt.Errorf("invalid command %q", f.command)
+	}
+
+	dataLenField, end, err := readField(r, maxTxnrLen, true)
+	if err != nil {
+		return f, fmt.Errorf("read data length: %w", err)
+	}
+	dataLen, err := parseNumber(dataLenField)
+	if err != nil {
+		return f, fmt.Errorf("invalid data length: %w", err)
+	}
+	if end == '\n' {
+		if dataLen != 0 {
+			return f, fmt.Errorf("missing data of %d bytes", dataLen)
+		}
+		return f, nil
+	}
+
+	if dataLen > maxLogSize {
+		if _, err = r.Discard(dataLen); err != nil {
+			return f, fmt.Errorf("skip data: %w", noEOF(err))
+		}
+		if err = readTrailer(r); err != nil {
+			return f, err
+		}
+		return f, errDataTooLarge
+	}
+
+	f.data = make([]byte, dataLen)
+	if _, err = io.ReadFull(r, f.data); err != nil {
+		return f, fmt.Errorf("read data: %w", noEOF(err))
+	}
+	return f, readTrailer(r)
+}
+
+// readField reads a field up to the next space, or newline if allowed.
+// It returns the field, and the byte which terminated it.
+func readField(r *bufio.Reader, maxLen int, allowNewline bool) (string, byte, error) {
+	var field []byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			if len(field) > 0 {
+				err = noEOF(err)
+			}
+			return string(field), 0, err
+		}
+		switch {
+		case c == ' ':
+			return string(field), c, nil
+		case c == '\n' && allowNewline:
+			return string(field), c, nil
+		case len(field) == maxLen:
+			return string(field), 0, fmt.Errorf("field %q is longer than %d bytes", field, maxLen)
+		}
+		field = append(field, c)
+	}
+}
+
+func readTrailer(r *bufio.Reader) error {
+	c, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("read trailer: %w", noEOF(err))
+	}
+	if c != '\n' {
+		return fmt.Errorf("invalid trailer %q", c)
+	}
+	return nil
+}
+
+func parseNumber(field string) (int, error) {
+	if field == "" || strings.ContainsFunc(field, func(c rune) bool { return c < '0' || c > '9' }) {
+		return 0, fmt.Errorf("%q is not a number", field)
+	}
+	return strconv.Atoi(field)
+}
+
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// writeFrame writes a frame to w and flushes it.
+func writeFrame(w *bufio.Writer, f frame) error {
+	w.WriteString(strconv.Itoa(f.txnr))
+	w.WriteByte(' ')
+	w.WriteString(f.command)
+	w.WriteByte(' ')
+	w.WriteString(strconv.Itoa(len(f.data)))
+	if len(f.data) > 0 {
+		w.WriteByte(' ')
+		w.Write(f.data)
+	}
+	w.WriteByte('\n')
+	return w.Flush()
+}
+
+// response returns the response frame to the frame with the transaction number txnr.
+// The data of responses starts with a status code and a message, which may be followed
+// by further lines.
+func response(txnr, code int, msg string, lines ...string) frame {
+	data := strconv.Itoa(code) + " " + msg
+	for _, line := range lines {
+		data += "\n" + line
+	}
+	return frame{txnr: txnr, command: commandRsp, data: []byte(data)}
+}
+
+// parseOffers parses the offers of the data of an open command, which are newline separated
+// NAME=VALUE pairs.
+func parseOffers(data []byte) map[string]string {
+	offers := make(map[string]string)
+	for line := range bytes.Lines(data) {
+		name, value, _ := strings.Cut(strings.TrimRight(string(line), "\r\n"), "=")
+		if name != "" {
+			offers[name] = value
+		}
+	}
+	return offers
+}
@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package relp
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFrame(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expected    []frame
+		expectedErr string
+	}{
+		{
+			name:  "Open",
+			input: "1 open 30 relp_version=0\ncommands=syslog\n",
+			expected: []frame{
+				{txnr: 1, command: "open", data: []byte("relp_version=0\ncommands=syslog")},
+			},
+		},
+		{
+			name:  "Syslog",
+			input: "2 syslog 15 <13>1 - - - - -\n3 syslog 5 hello\n",
+			expected: []frame{
+				{txnr: 2, command: "syslog", data: []byte("<13>1 - - - - -")},
+				{txnr: 3, command: "syslog", data: []byte("hello")},
+			},
+		},
+		{
+			name:  "NoData",
+			input: "4 close 0\n",
+			expected: []frame{
+				{txnr: 4, command: "close"},
+			},
+		},
+		{
+			name:  "DataWithNewlines",
+			input: "5 syslog 11 line1\nline2\n",
+			expected: []frame{
+				{txnr: 5, command: "syslog", data: []byte("line1\nline2")},
+			},
+		},
+		{
+			name:        "InvalidTransactionNumber",
+			input:       "a syslog 5 hello\n",
+			expectedErr: "invalid transaction number",
+		},
+		{
+			name:        "TransactionNumberTooLong",
+			input:       "1234567890 syslog 5 hello\n",
+			expectedErr: "read transaction number",
+		},
+		{
+			name:        "InvalidCommand",
+			input:       "1 Syslog 5 hello\n",
+			expectedErr: `invalid command "Syslog"`,
+		},
+		{
+			name:        "InvalidDataLength",
+			input:       "1 syslog -5 hello\n",
+			expectedErr: "invalid data length",
+		},
+		{
+			name:        "MissingData",
+			input:       "1 syslog 5\n",
+			expectedErr: "missing data of 5 bytes",
+		},
+		{
+			name:        "InvalidTrailer",
+			input:       "1 syslog 4 hello\n",
+			expectedErr: `invalid trailer 'o'`,
+		},
+		{
+			name:        "Truncated",
+			input:       "1 syslog 5 hel",
+			expectedErr: "read data: unexpected EOF",
+		},
+		{
+			name:        "TruncatedHeader",
+			input:       "1 sys",
+			expectedErr: "read command: unexpected EOF",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tc.input))
+			for _, expected := range tc.expected {
+				f, err := readFrame(r, DefaultMaxLogSize)
+				require.NoError(t, err)
+				assert.Equal(t, expected, f)
+			}
+			_, err := readFrame(r, DefaultMaxLogSize)
+			if tc.expectedErr == "" {
+				require.ErrorIs(t, err, io.EOF)
+				return
+			}
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestReadFrameDataTooLarge(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("1 syslog 11 hello world\n2 syslog 5 hello\n"))
+
+	f, err := readFrame(r, 5)
+	require.ErrorIs(t, err, errDataTooLarge)
+	assert.Equal(t, 1, f.txnr)
+	assert.Equal(t, "syslog", f.command)
+
+	f, err = readFrame(r, 5)
+	require.NoError(t, err)
+	assert.Equal(t, frame{txnr: 2, command: "syslog", data: []byte("hello")}, f)
+}
+
+func TestWriteFrame(t *testing.T) {
+	testCases := []struct {
+		name     string
+		frame    frame
+		expected string
+	}{
+		{
+			name:     "Response",
+			frame:    response(2, 200, "OK"),
+			expected: "2 rsp 6 200 OK\n",
+		},
+		{
+			name:     "ResponseWithLines",
+			frame:    response(1, 200, "OK", "relp_version=0", "commands=syslog"),
+			expected: "1 rsp 37 200 OK\nrelp_version=0\ncommands=syslog\n",
+		},
+		{
+			name:     "NoData",
+			frame:    frame{command: commandServerClose},
+			expected: "0 serverclose 0\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, writeFrame(bufio.NewWriter(&buf), tc.frame))
+			assert.Equal(t, tc.expected, buf.String())
+
+			f, err := readFrame(bufio.NewReader(&buf), DefaultMaxLogSize)
+			require.NoError(t, err)
+			assert.Equal(t, tc.frame.txnr, f.txnr)
+			assert.Equal(t, tc.frame.command, f.command)
+			assert.Equal(t, string(tc.frame.data), string(f.data))
+		})
+	}
+}
+
+func TestParseOffers(t *testing.T) {
+	offers := parseOffers([]byte("relp_version=0\r\nrelp_software=librelp,1.2.13,http://librelp.adiscon.com\ncommands=syslog"))
+	assert.Equal(t, map[string]string{
+		"relp_version":  "0",
+		"relp_software": "librelp,1.2.13,http://librelp.adiscon.com",
+		"commands":      "syslog",
+	}, offers)
+}
@@ -0,0 +1,289 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package relp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/relp"
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpillora/backoff"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/textutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const (
+	relpVersion  = "0"
+	relpSoftware = "opentelemetry-collector-contrib,relp_input"
+)
+
+// Input is an operator that receives log entries from RELP clients, such as rsyslog's omrelp.
+// Each syslog frame is only acknowledged once its entry was delivered, or failed in a way
+// that would happen again if the client sent it again.
+type Input struct {
+	helper.InputOperator
+	address       string
+	maxLogSize    int
+	addAttributes bool
+
+	listener net.Listener
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	tls      *tls.Config
+	backoff  backoff.Backoff
+
+	encoding encoding.Encoding
+	resolver *helper.IPResolver
+}
+
+// session is a RELP session over a single connection.
+type session struct {
+	conn net.Conn
+
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closed bool
+}
+
+// respond writes a frame to the client, unless the session is already closed.
+func (s *session) respond(f frame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return net.ErrClosed
+	}
+	return writeFrame(s.w, f)
+}
+
+// close marks the session as closed. If notify is set, the client is told that the
+// server closes the session with a serverclose frame.
+func (s *session) close(notify bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if notify {
+		return writeFrame(s.w, frame{command: commandServerClose})
+	}
+	return nil
+}
+
+// Start will start listening for RELP connections.
+func (i *Input) Start(_ operator.Persister) error {
+	if err := i.configureListener(); err != nil {
+		return fmt.Errorf("failed to listen on interface: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i.cancel = cancel
+	i.goListen(ctx)
+	return nil
+}
+
+func (i *Input) configureListener() error {
+	if i.tls == nil {
+		listener, err := net.Listen("tcp", i.address)
+		if err != nil {
+			return fmt.Errorf("failed to configure tcp listener: %w", err)
+		}
+		i.listener = listener
+		return nil
+	}
+
+	i.tls.Time = time.Now
+	i.tls.Rand = rand.Reader
+
+	listener, err := tls.Listen("tcp", i.address, i.tls)
+	if err != nil {
+		return fmt.Errorf("failed to configure tls listener: %w", err)
+	}
+
+	i.listener = listener
+	return nil
+}
+
+// goListen will listen for RELP connections.
+func (i *Input) goListen(ctx context.Context) {
+	i.wg.Go(func() {
+		for {
+			conn, err := i.listener.Accept()
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+					i.Logger().Debug("Listener accept error", zap.Error(err))
+					time.Sleep(i.backoff.Duration())
+					continue
+				}
+			}
+			i.backoff.Reset()
+
+			i.Logger().Debug("Received connection", zap.String("address", conn.RemoteAddr().String()))
+			s := &session{conn: conn, w: bufio.NewWriter(conn)}
+			subctx, cancel := context.WithCancel(ctx)
+			i.goHandleClose(subctx, s)
+			i.goHandleFrames(subctx, s, cancel)
+		}
+	})
+}
+
+// goHandleClose will wait for the context to finish before closing a connection.
+// Sessions which are still open at that time are closed by the server.
+func (i *Input) goHandleClose(ctx context.Context, s *session) {
+	i.wg.Go(func() {
+		<-ctx.Done()
+		i.Logger().Debug("Closing connection", zap.String("address", s.conn.RemoteAddr().String()))
+		if err := s.close(true); err != nil {
+			i.Logger().Debug("Failed to send serverclose", zap.Error(err))
+		}
+		if err := s.conn.Close(); err != nil {
+			i.Logger().Error("Failed to close connection", zap.Error(err))
+		}
+	})
+}
+
+// goHandleFrames will handle the frames of a RELP session.
+func (i *Input) goHandleFrames(ctx context.Context, s *session, cancel context.CancelFunc) {
+	i.wg.Go(func() {
+		defer cancel()
+		if err := i.handleFrames(ctx, s); err != nil {
+			i.Logger().Error("Closing RELP session", zap.String("address", s.conn.RemoteAddr().String()), zap.Error(err))
+		}
+	})
+}
+
+func (i *Input) handleFrames(ctx context.Context, s *session) error {
+	// The session ends without a serverclose frame once the client closed the connection
+	// or the session, or the connection broke.
+	defer func() { _ = s.close(false) }()
+
+	r := bufio.NewReader(s.conn)
+	dec := i.encoding.NewDecoder()
+	open := false
+	for {
+		f, err := readFrame(r, i.maxLogSize)
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case errors.Is(err, errDataTooLarge):
+			i.Logger().Error("Rejected frame", zap.Int("txnr", f.txnr), zap.Error(err))
+			if err = s.respond(response(f.txnr, 500, err.Error())); err != nil {
+				return err
+			}
+			continue
+		case err != nil:
+			return fmt.Errorf("read frame: %w", err)
+		}
+
+		switch f.command {
+		case commandOpen:
+			offers := parseOffers(f.data)
+			if !slices.Contains(strings.Split(offers["commands"], ","), commandSyslog) {
+				_ = s.respond(response(f.txnr, 500, "required command syslog not offered"))
+				return fmt.Errorf("client does not offer the %s command", commandSyslog)
+			}
+			open = true
+			if err = s.respond(response(f.txnr, 200, "OK",
+				"relp_version="+relpVersion,
+				"relp_software="+relpSoftware,
+				"commands="+commandSyslog,
+			)); err != nil {
+				return err
+			}
+		case commandSyslog:
+			if !open {
+				_ = s.respond(response(f.txnr, 500, "session not open"))
+				return errors.New("syslog frame received before open")
+			}
+			rsp := response(f.txnr, 200, "OK")
+			if err = i.handleMessage(ctx, s.conn, dec, f.data); err != nil {
+				i.Logger().Error("Failed to write entry", zap.Int("txnr", f.txnr), zap.Error(err))
+				// The client only sends the frame again when it wasn't delivered, as the
+				// other errors would happen again.
+				if helper.IsDeliveryError(err) {
+					rsp = response(f.txnr, 500, err.Error())
+				}
+			}
+			if err = s.respond(rsp); err != nil {
+				return err
+			}
+		case commandClose:
+			return s.respond(frame{txnr: f.txnr, command: commandRsp})
+		default:
+			if err = s.respond(response(f.txnr, 500, "unknown command "+f.command)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleMessage writes the entry of a syslog frame. The frame may only be acknowledged
+// if no *helper.DeliveryError is returned.
+func (i *Input) handleMessage(ctx context.Context, conn net.Conn, dec *encoding.Decoder, data []byte) error {
+	decoded, err := textutils.DecodeAsString(dec, data)
+	if err != nil {
+		return fmt.Errorf("decode data: %w", err)
+	}
+
+	entry, err := i.NewEntry(decoded)
+	if err != nil {
+		return fmt.Errorf("create entry: %w", err)
+	}
+
+	if i.addAttributes {
+		entry.AddAttribute("net.transport", "IP.TCP")
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+			ip := addr.IP.String()
+			entry.AddAttribute("net.peer.ip", ip)
+			entry.AddAttribute("net.peer.port", strconv.FormatInt(int64(addr.Port), 10))
+			entry.AddAttribute("net.peer.name", i.resolver.GetHostFromIP(ip))
+		}
+
+		if addr, ok := conn.LocalAddr().(*net.TCPAddr); ok {
+			ip := addr.IP.String()
+			entry.AddAttribute("net.host.ip", ip)
+			entry.AddAttribute("net.host.port", strconv.FormatInt(int64(addr.Port), 10))
+			entry.AddAttribute("net.host.name", i.resolver.GetHostFromIP(ip))
+		}
+	}
+
+	return i.Write(ctx, entry)
+}
+
+// Stop will stop listening for RELP connections.
+func (i *Input) Stop() error {
+	if i.cancel == nil {
+		return nil
+	}
+	i.cancel()
+
+	if i.listener != nil {
+		if err := i.listener.Close(); err != nil {
+			i.Logger().Error("failed to close RELP listener", zap.Error(err))
+		}
+	}
+
+	i.wg.Wait()
+	if i.resolver != nil {
+		i.resolver.Stop()
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cef
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func newTestParser(t *testing.T) *Parser {
+	config := NewConfigWithID("test")
+	set := componenttest.NewNopTelemetrySettings()
+	op, err := config.Build(set)
+	require.NoError(t, err)
+	return op.(*Parser)
+}
+
+func TestInit(t *testing.T) {
+	builder, ok := operator.DefaultRegistry.Lookup("cef_parser")
+	require.True(t, ok, "expected cef_parser to be registered")
+	require.Equal(t, "cef_parser", builder().Type())
+}
+
+func TestConfigBuildFailure(t *testing.T) {
+	config := NewConfigWithID("test")
+	config.OnError = "invalid_on_error"
+	set := componenttest.NewNopTelemetrySettings()
+	_, err := config.Build(set)
+	require.ErrorContains(t, err, "invalid `on_error` field")
+}
+
+func TestParserInvalidType(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parse([]int{})
+	require.ErrorContains(t, err, "type '[]int' cannot be parsed as CEF")
+}
+
+func TestParserEmptyInput(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parse("")
+	require.ErrorContains(t, err, "parse from field body is empty")
+}
+
+func TestParser(t *testing.T) {
+	cases := []struct {
+		name        string
+		configure   func(*Config)
+		input       *entry.Entry
+		expect      *entry.Entry
+		expectError bool
+	}{
+		{
+			"simple",
+			func(_ *Config) {},
+			&entry.Entry{
+				Body: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 msg=stopped by rule a\\=b",
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"version":               "0",
+					"device_vendor":         "Security",
+					"device_product":        "threatmanager",
+					"device_version":        "1.0",
+					"device_event_class_id": "100",
+					"name":                  "worm successfully stopped",
+					"severity":              "10",
+					"extensions": map[string]any{
+						"src": "10.0.0.1",
+						"msg": "stopped by rule a=b",
+					},
+				},
+				Body: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 msg=stopped by rule a\\=b",
+			},
+			false,
+		},
+		{
+			"parse-to-body",
+			func(cfg *Config) {
+				cfg.ParseTo = entry.RootableField{Field: entry.NewBodyField()}
+			},
+			&entry.Entry{
+				Body: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 msg=stopped by rule a\\=b",
+			},
+			&entry.Entry{
+				Body: map[string]any{
+					"version":               "0",
+					"device_vendor":         "Security",
+					"device_product":        "threatmanager",
+					"device_version":        "1.0",
+					"device_event_class_id": "100",
+					"name":                  "worm successfully stopped",
+					"severity":              "10",
+					"extensions": map[string]any{
+						"src": "10.0.0.1",
+						"msg": "stopped by rule a=b",
+					},
+				},
+			},
+			false,
+		},
+		{
+			"invalid",
+			func(_ *Config) {},
+			&entry.Entry{
+				Body: "not a CEF message",
+			},
+			&entry.Entry{
+				Body: "not a CEF message",
+			},
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfigWithID("test")
+			cfg.OutputIDs = []string{"fake"}
+			tc.configure(cfg)
+
+			set := componenttest.NewNopTelemetrySettings()
+			op, err := cfg.Build(set)
+			require.NoError(t, err)
+
+			fake := testutil.NewFakeOutput(t)
+			require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+
+			ots := time.Now()
+			tc.input.ObservedTimestamp = ots
+			tc.expect.ObservedTimestamp = ots
+
+			err = op.Process(t.Context(), tc.input)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			fake.ExpectEntry(t, tc.expect)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package w3c // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/w3c"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const operatorType = "w3c_parser"
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
+}
+
+// NewConfig creates a new W3C extended log format parser config with default values.
+func NewConfig() *Config {
+	return NewConfigWithID(operatorType)
+}
+
+// NewConfigWithID creates a new W3C extended log format parser config with default values.
+func NewConfigWithID(operatorID string) *Config {
+	return &Config{
+		ParserConfig: helper.NewParserConfig(operatorID, operatorType),
+	}
+}
+
+// Config is the configuration of a W3C extended log format parser operator.
+type Config struct {
+	helper.ParserConfig `mapstructure:",squash"`
+
+	// Fields is either a `#Fields:` directive or the space separated list of field names it declares.
+	Fields string `mapstructure:"fields"`
+	// FieldsAttribute is the name of an attribute holding the `#Fields:` directive of the entry's file.
+	FieldsAttribute string `mapstructure:"fields_attribute"`
+}
+
+// Build will build a W3C extended log format parser operator.
+func (c Config) Build(set component.TelemetrySettings) (operator.Operator, error) {
+	parserOperator, err := c.ParserConfig.Build(set)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Fields == "" && c.FieldsAttribute == "" {
+		return nil, errors.New("missing required field 'fields' or 'fields_attribute'")
+	}
+
+	if c.Fields != "" && c.FieldsAttribute != "" {
+		return nil, errors.New("only one fields parameter can be set: 'fields' or 'fields_attribute'")
+	}
+
+	p := &Parser{
+		ParserOperator:  parserOperator,
+		fieldsAttribute: c.FieldsAttribute,
+	}
+
+	if c.Fields != "" {
+		fields, err := parseutils.ParseW3CFields(c.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'fields': %w", err)
+		}
+		p.parse = generateParseFunc(fields)
+	}
+
+	return p, nil
+}
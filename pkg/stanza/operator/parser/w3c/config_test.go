@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package w3c
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/operatortest"
+)
+
+func TestConfig(t *testing.T) {
+	operatortest.ConfigUnmarshalTests{
+		DefaultConfig: NewConfig(),
+		TestsFile:     filepath.Join(".", "testdata", "config.yaml"),
+		Tests: []operatortest.ConfigUnmarshalTest{
+			{
+				Name: "fields",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.Fields = "#Fields: date time cs-method cs-uri-stem sc-status"
+					return cfg
+				}(),
+			},
+			{
+				Name: "fields_attribute",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.FieldsAttribute = "w3c.fields"
+					return cfg
+				}(),
+			},
+			{
+				Name: "on_error_drop_quiet",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.Fields = "date time"
+					cfg.OnError = "drop_quiet"
+					return cfg
+				}(),
+			},
+			{
+				Name: "parse_to_attributes",
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.Fields = "date time"
+					cfg.ParseTo = entry.RootableField{Field: entry.NewAttributeField()}
+					return cfg
+				}(),
+			},
+		},
+	}.Run(t)
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package w3c // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/w3c"
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/parseutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// Parser is an operator that parses W3C extended log format entries.
+type Parser struct {
+	helper.ParserOperator
+	fieldsAttribute string
+	parse           parseFunc
+}
+
+type parseFunc func(any) (any, error)
+
+func (p *Parser) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.TransformerOperator.ProcessBatchWith(ctx, entries, p.Process)
+}
+
+// Process will parse an entry as a W3C extended log format entry.
+func (p *Parser) Process(ctx context.Context, e *entry.Entry) error {
+	// Static parse function
+	if p.parse != nil {
+		return p.ProcessWith(ctx, e, p.parse)
+	}
+
+	// Dynamically generate the parse function based on a fields attribute
+	f, ok := e.Attributes[p.fieldsAttribute]
+	if !ok {
+		p.Logger().Error("read dynamic fields attribute", zap.String("attribute", p.fieldsAttribute))
+		return fmt.Errorf("failed to read dynamic fields attribute %s", p.fieldsAttribute)
+	}
+	fieldsString, ok := f.(string)
+	if !ok {
+		p.Logger().Error("fields must be string", zap.String("type", fmt.Sprintf("%T", f)))
+		return fmt.Errorf("fields are expected to be a string but is %T", f)
+	}
+	fields, err := parseutils.ParseW3CFields(fieldsString)
+	if err != nil {
+		return fmt.Errorf("invalid dynamic fields attribute %s: %w", p.fieldsAttribute, err)
+	}
+	return p.ProcessWith(ctx, e, generateParseFunc(fields))
+}
+
+// generateParseFunc returns a parse function for the given field names, allowing
+// each entry to have a potentially unique set of fields when using dynamic
+// field names retrieved from an entry's attribute.
+func generateParseFunc(fields []string) parseFunc {
+	return func(value any) (any, error) {
+		var line string
+		switch t := value.(type) {
+		case string:
+			line = t
+		case []byte:
+			line = string(t)
+		default:
+			return nil, fmt.Errorf("type '%T' cannot be parsed as W3C", value)
+		}
+		return parseutils.ParseW3CEntry(fields, line)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package w3c
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func TestInit(t *testing.T) {
+	builder, ok := operator.DefaultRegistry.Lookup("w3c_parser")
+	require.True(t, ok, "expected w3c_parser to be registered")
+	require.Equal(t, "w3c_parser", builder().Type())
+}
+
+func TestBuild(t *testing.T) {
+	cases := []struct {
+		name      string
+		configure func(*Config)
+		expectErr string
+	}{
+		{
+			"fields",
+			func(cfg *Config) {
+				cfg.Fields = "date time"
+			},
+			"",
+		},
+		{
+			"fields-attribute",
+			func(cfg *Config) {
+				cfg.FieldsAttribute = "w3c.fields"
+			},
+			"",
+		},
+		{
+			"missing-fields",
+			func(_ *Config) {},
+			"missing required field 'fields' or 'fields_attribute'",
+		},
+		{
+			"both-fields",
+			func(cfg *Config) {
+				cfg.Fields = "date time"
+				cfg.FieldsAttribute = "w3c.fields"
+			},
+			"only one fields parameter can be set",
+		},
+		{
+			"invalid-fields",
+			func(cfg *Config) {
+				cfg.Fields = "#Version: 1.0"
+			},
+			"invalid 'fields'",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfigWithID("test")
+			tc.configure(cfg)
+			_, err := cfg.Build(componenttest.NewNopTelemetrySettings())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParser(t *testing.T) {
+	cases := []struct {
+		name        string
+		configure   func(*Config)
+		input       *entry.Entry
+		expect      *entry.Entry
+		expectError bool
+	}{
+		{
+			"static-fields",
+			func(cfg *Config) {
+				cfg.Fields = "#Fields: date time cs-method cs-uri-stem cs-uri-query sc-status cs(User-Agent)"
+			},
+			&entry.Entry{
+				Body: "2024-01-02 03:04:05 GET /index.html - 200 Mozilla/5.0+(Windows+NT+10.0)",
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"date":           "2024-01-02",
+					"time":           "03:04:05",
+					"cs-method":      "GET",
+					"cs-uri-stem":    "/index.html",
+					"sc-status":      "200",
+					"cs(User-Agent)": "Mozilla/5.0+(Windows+NT+10.0)",
+				},
+				Body: "2024-01-02 03:04:05 GET /index.html - 200 Mozilla/5.0+(Windows+NT+10.0)",
+			},
+			false,
+		},
+		{
+			"dynamic-fields",
+			func(cfg *Config) {
+				cfg.FieldsAttribute = "w3c.fields"
+				cfg.ParseTo = entry.RootableField{Field: entry.NewBodyField()}
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"w3c.fields": "#Fields: c-ip cs(User-Agent)",
+				},
+				Body: `10.0.0.1 "Mozilla/5.0 (X11; Linux x86_64)"`,
+			},
+			&entry.Entry{
+				Attributes: map[string]any{
+					"w3c.fields": "#Fields: c-ip cs(User-Agent)",
+				},
+				Body: map[string]any{
+					"c-ip":           "10.0.0.1",
+					"cs(User-Agent)": "Mozilla/5.0 (X11; Linux x86_64)",
+				},
+			},
+			false,
+		},
+		{
+			"missing-fields-attribute",
+			func(cfg *Config) {
+				cfg.FieldsAttribute = "w3c.fields"
+			},
+			&entry.Entry{
+				Body: "2024-01-02",
+			},
+			&entry.Entry{
+				Body: "2024-01-02",
+			},
+			true,
+		},
+		{
+			"directive-line",
+			func(cfg *Config) {
+				cfg.Fields = "date time"
+			},
+			&entry.Entry{
+				Body: "#Software: Microsoft Internet Information Services 10.0",
+			},
+			&entry.Entry{
+				Body: "#Software: Microsoft Internet Information Services 10.0",
+			},
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfigWithID("test")
+			cfg.OutputIDs = []string{"fake"}
+			tc.configure(cfg)
+
+			set := componenttest.NewNopTelemetrySettings()
+			op, err := cfg.Build(set)
+			require.NoError(t, err)
+
+			fake := testutil.NewFakeOutput(t)
+			require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+
+			ots := time.Now()
+			tc.input.ObservedTimestamp = ots
+			tc.expect.ObservedTimestamp = ots
+
+			err = op.Process(t.Context(), tc.input)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			fake.ExpectEntry(t, tc.expect)
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 )
 
 const (
@@ -52,6 +53,7 @@ type Config struct {
 	helper.TransformerConfig `mapstructure:",squash"`
 	IsFirstEntry             string          `mapstructure:"is_first_entry"`
 	IsLastEntry              string          `mapstructure:"is_last_entry"`
+	Preset                   string          `mapstructure:"preset"`
 	MaxBatchSize             int             `mapstructure:"max_batch_size"`
 	MaxUnmatchedBatchSize    int             `mapstructure:"max_unmatched_batch_size"`
 	CombineField             entry.Field     `mapstructure:"combine_field"`
@@ -70,23 +72,34 @@ func (c *Config) Build(set component.TelemetrySettings) (operator.Operator, erro
 		return nil, fmt.Errorf("failed to build transformer config: %w", err)
 	}
 
+	if c.Preset != "" && (c.IsLastEntry != "" || c.IsFirstEntry != "") {
+		return nil, errors.New("preset cannot be set along with is_first_entry or is_last_entry")
+	}
+
 	if c.IsLastEntry != "" && c.IsFirstEntry != "" {
 		return nil, errors.New("only one of is_first_entry and is_last_entry can be set")
 	}
 
-	if c.IsLastEntry == "" && c.IsFirstEntry == "" {
-		return nil, errors.New("one of is_first_entry and is_last_entry must be set")
+	if c.IsLastEntry == "" && c.IsFirstEntry == "" && c.Preset == "" {
+		return nil, errors.New("one of is_first_entry, is_last_entry and preset must be set")
 	}
 
 	var matchesFirst bool
 	var prog *vm.Program
-	if c.IsFirstEntry != "" {
+	switch {
+	case c.Preset != "":
+		// Presets recognize the entries that continue a batch, so that the others are first entries.
+		matchesFirst = true
+		if _, err = split.NewPresetMatcher(c.Preset); err != nil {
+			return nil, fmt.Errorf("invalid preset: %w", err)
+		}
+	case c.IsFirstEntry != "":
 		matchesFirst = true
 		prog, err = helper.ExprCompileBool(c.IsFirstEntry)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile is_first_entry: %w", err)
 		}
-	} else {
+	default:
 		matchesFirst = false
 		prog, err = helper.ExprCompileBool(c.IsLastEntry)
 		if err != nil {
@@ -116,6 +129,7 @@ func (c *Config) Build(set component.TelemetrySettings) (operator.Operator, erro
 		TransformerOperator:   transformer,
 		matchFirstLine:        matchesFirst,
 		prog:                  prog,
+		preset:                c.Preset,
 		maxBatchSize:          c.MaxBatchSize,
 		maxUnmatchedBatchSize: c.MaxUnmatchedBatchSize,
 		maxSources:            c.MaxSources,
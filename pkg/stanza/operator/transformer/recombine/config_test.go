@@ -93,6 +93,15 @@ func TestUnmarshal(t *testing.T) {
 					return cfg
 				}(),
 			},
+			{
+				Name:               "preset",
+				ExpectUnmarshalErr: false,
+				Expect: func() *Config {
+					cfg := NewConfig()
+					cfg.Preset = "java"
+					return cfg
+				}(),
+			},
 			{
 				Name:               "on_error_drop",
 				ExpectUnmarshalErr: false,
@@ -18,6 +18,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
 )
 
 const DefaultSourceIdentifier = "DefaultSourceIdentifier"
@@ -27,6 +28,7 @@ type Transformer struct {
 	helper.TransformerOperator
 	matchFirstLine        bool
 	prog                  *vm.Program
+	preset                string
 	maxBatchSize          int
 	maxUnmatchedBatchSize int
 	maxSources            int
@@ -51,6 +53,8 @@ type sourceBatch struct {
 	recombined             *bytes.Buffer
 	firstEntryObservedTime time.Time
 	matchDetected          bool
+	// matcher recognizes the entries that continue the batch when a preset is used.
+	matcher split.ContinuationMatcher
 }
 
 func (t *Transformer) Start(_ operator.Persister) error {
@@ -110,28 +114,13 @@ func (t *Transformer) ProcessBatch(ctx context.Context, entries []*entry.Entry)
 	}
 
 	for _, e := range entries {
-		// Get the environment for executing the expression
-		env := helper.GetExprEnv(e)
-
-		m, err := expr.Run(t.prog, env)
-		helper.PutExprEnv(env)
+		s := t.source(e)
+		matches, err := t.matches(e, s)
 		if err != nil {
 			errs = append(errs, t.HandleEntryErrorWithWrite(ctx, e, err, collectWrite))
 			continue
 		}
 
-		matches := m.(bool)
-		var s string
-		err = e.Read(t.sourceIdentifier, &s)
-		if err != nil {
-			t.Logger().Warn("entry does not contain the source_identifier, so it may be pooled with other sources")
-			s = DefaultSourceIdentifier
-		}
-
-		if s == "" {
-			s = DefaultSourceIdentifier
-		}
-
 		switch {
 		case matches && t.matchFirstLine:
 			// Flush the existing batch
@@ -164,31 +153,12 @@ func (t *Transformer) Process(ctx context.Context, e *entry.Entry) error {
 	t.Lock()
 	defer t.Unlock()
 
-	// Get the environment for executing the expression.
-	// In the future, we may want to provide access to the currently
-	// batched entries so users can do comparisons to other entries
-	// rather than just use absolute rules.
-	env := helper.GetExprEnv(e)
-	defer helper.PutExprEnv(env)
-
-	m, err := expr.Run(t.prog, env)
+	s := t.source(e)
+	matches, err := t.matches(e, s)
 	if err != nil {
 		return t.HandleEntryError(ctx, e, err)
 	}
 
-	// this is guaranteed to be a boolean because of expr.AsBool
-	matches := m.(bool)
-	var s string
-	err = e.Read(t.sourceIdentifier, &s)
-	if err != nil {
-		t.Logger().Warn("entry does not contain the source_identifier, so it may be pooled with other sources")
-		s = DefaultSourceIdentifier
-	}
-
-	if s == "" {
-		s = DefaultSourceIdentifier
-	}
-
 	switch {
 	// This is the first entry in the next batch
 	case matches && t.matchFirstLine:
@@ -212,6 +182,52 @@ func (t *Transformer) Process(ctx context.Context, e *entry.Entry) error {
 	return nil
 }
 
+// source returns the source identifier of the entry.
+func (t *Transformer) source(e *entry.Entry) string {
+	var s string
+	err := e.Read(t.sourceIdentifier, &s)
+	if err != nil {
+		t.Logger().Warn("entry does not contain the source_identifier, so it may be pooled with other sources")
+		return DefaultSourceIdentifier
+	}
+	if s == "" {
+		return DefaultSourceIdentifier
+	}
+	return s
+}
+
+// matches reports whether the entry is the first entry of a batch, or the last one
+// when is_last_entry is set.
+func (t *Transformer) matches(e *entry.Entry, source string) (bool, error) {
+	if t.preset != "" {
+		batch, ok := t.batchMap[source]
+		if !ok {
+			return true, nil
+		}
+		var s string
+		if err := e.Read(t.combineField, &s); err != nil {
+			// The entry is reported when it's added to the batch.
+			return false, nil
+		}
+		return !batch.matcher.Continues([]byte(s)), nil
+	}
+
+	// Get the environment for executing the expression.
+	// In the future, we may want to provide access to the currently
+	// batched entries so users can do comparisons to other entries
+	// rather than just use absolute rules.
+	env := helper.GetExprEnv(e)
+	defer helper.PutExprEnv(env)
+
+	m, err := expr.Run(t.prog, env)
+	if err != nil {
+		return false, err
+	}
+
+	// this is guaranteed to be a boolean because of expr.AsBool
+	return m.(bool), nil
+}
+
 // addToBatch adds the current entry to the current batch of entries that will be combined
 func (t *Transformer) addToBatch(ctx context.Context, e *entry.Entry, source string, matches bool, write helper.WriteFunction) {
 	batch, ok := t.batchMap[source]
@@ -241,6 +257,10 @@ func (t *Transformer) addToBatch(ctx context.Context, e *entry.Entry, source str
 		t.Logger().Error("entry does not contain the combine_field")
 		return
 	}
+	if !ok && batch.matcher != nil {
+		// The first entry of the batch is matched as well, since matchers keep track of the entry.
+		batch.matcher.Continues([]byte(s))
+	}
 	if batch.recombined.Len() > 0 {
 		batch.recombined.WriteString(t.combineWith)
 	}
@@ -299,6 +319,11 @@ func (t *Transformer) addNewBatch(source string, e *entry.Entry) *sourceBatch {
 	batch.recombined.Reset()
 	batch.firstEntryObservedTime = e.ObservedTimestamp
 	batch.matchDetected = false
+	batch.matcher = nil
+	if t.preset != "" {
+		// The preset is validated when the operator is built.
+		batch.matcher, _ = split.NewPresetMatcher(t.preset)
+	}
 	t.batchMap[source] = batch
 	return batch
 }
@@ -736,6 +736,76 @@ func TestTransformer(t *testing.T) {
 				entryWithBody(t1, "test6\ntest7\ntest1"),
 			},
 		},
+		{
+			"PresetJava",
+			func() *Config {
+				cfg := NewConfig()
+				cfg.CombineField = entry.NewBodyField()
+				cfg.Preset = "java"
+				cfg.OutputIDs = []string{"fake"}
+				return cfg
+			}(),
+			[]*entry.Entry{
+				entryWithBody(t1, "INFO started"),
+				entryWithBody(t1, "ERROR failed"),
+				entryWithBody(t1, "java.lang.IllegalStateException: closed"),
+				entryWithBody(t1, "\tat com.example.Main.main(Main.java:5)"),
+				entryWithBody(t1, "Caused by: java.io.IOException: broken pipe"),
+				entryWithBody(t1, "\t... 1 more"),
+				entryWithBody(t2, "INFO retrying"),
+				entryWithBody(t2, "INFO stopped"),
+			},
+			[]*entry.Entry{
+				entryWithBody(t1, "INFO started"),
+				entryWithBody(t1, "ERROR failed\njava.lang.IllegalStateException: closed\n\tat com.example.Main.main(Main.java:5)\nCaused by: java.io.IOException: broken pipe\n\t... 1 more"),
+				entryWithBody(t2, "INFO retrying"),
+			},
+		},
+		{
+			"PresetPythonTracebackStartsEntry",
+			func() *Config {
+				cfg := NewConfig()
+				cfg.CombineField = entry.NewBodyField()
+				cfg.Preset = "python"
+				cfg.OutputIDs = []string{"fake"}
+				return cfg
+			}(),
+			[]*entry.Entry{
+				entryWithBody(t1, "Traceback (most recent call last):"),
+				entryWithBody(t1, "  File \"main.py\", line 1, in <module>"),
+				entryWithBody(t1, "ZeroDivisionError: division by zero"),
+				entryWithBody(t2, "INFO done"),
+				entryWithBody(t2, "INFO stopped"),
+			},
+			[]*entry.Entry{
+				entryWithBody(t1, "Traceback (most recent call last):\n  File \"main.py\", line 1, in <module>\nZeroDivisionError: division by zero"),
+				entryWithBody(t2, "INFO done"),
+			},
+		},
+		{
+			"PresetGoPerSource",
+			func() *Config {
+				cfg := NewConfig()
+				cfg.CombineField = entry.NewBodyField()
+				cfg.Preset = "go"
+				cfg.OutputIDs = []string{"fake"}
+				return cfg
+			}(),
+			[]*entry.Entry{
+				entryWithBodyAttr(t1, "panic: boom", map[string]string{attrs.LogFilePath: "file1"}),
+				entryWithBodyAttr(t2, "panic: bang", map[string]string{attrs.LogFilePath: "file2"}),
+				entryWithBodyAttr(t1, "", map[string]string{attrs.LogFilePath: "file1"}),
+				entryWithBodyAttr(t1, "goroutine 1 [running]:", map[string]string{attrs.LogFilePath: "file1"}),
+				entryWithBodyAttr(t1, "main.main()", map[string]string{attrs.LogFilePath: "file1"}),
+				entryWithBodyAttr(t1, "\t/app/main.go:10 +0x45", map[string]string{attrs.LogFilePath: "file1"}),
+				entryWithBodyAttr(t2, "server restarted", map[string]string{attrs.LogFilePath: "file2"}),
+				entryWithBodyAttr(t1, "server restarted", map[string]string{attrs.LogFilePath: "file1"}),
+			},
+			[]*entry.Entry{
+				entryWithBodyAttr(t2, "panic: bang", map[string]string{attrs.LogFilePath: "file2"}),
+				entryWithBodyAttr(t1, "panic: boom\n\ngoroutine 1 [running]:\nmain.main()\n\t/app/main.go:10 +0x45", map[string]string{attrs.LogFilePath: "file1"}),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -800,6 +870,26 @@ func TestTransformer(t *testing.T) {
 	})
 }
 
+func TestBuildPreset(t *testing.T) {
+	set := componenttest.NewNopTelemetrySettings()
+
+	cfg := NewConfig()
+	cfg.CombineField = entry.NewBodyField()
+	cfg.Preset = "ruby"
+	_, err := cfg.Build(set)
+	require.ErrorContains(t, err, `invalid preset: unknown preset "ruby"`)
+
+	cfg.Preset = "java"
+	cfg.IsFirstEntry = MatchAll
+	_, err = cfg.Build(set)
+	require.EqualError(t, err, "preset cannot be set along with is_first_entry or is_last_entry")
+
+	cfg.Preset = ""
+	cfg.IsFirstEntry = ""
+	_, err = cfg.Build(set)
+	require.EqualError(t, err, "one of is_first_entry, is_last_entry and preset must be set")
+}
+
 func BenchmarkRecombine(b *testing.B) {
 	cfg := NewConfig()
 	cfg.CombineField = entry.NewBodyField()
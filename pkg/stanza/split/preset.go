@@ -0,0 +1,203 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package split // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split"
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+)
+
+// Presets recognizing the stack traces and exceptions of a language, which are grouped
+// with the log line that reports them.
+const (
+	PresetJava   = "java"
+	PresetPython = "python"
+	PresetGo     = "go"
+	PresetDotNet = "dotnet"
+	PresetNodeJS = "nodejs"
+)
+
+// ContinuationMatcher recognizes the lines of multiline entries.
+type ContinuationMatcher interface {
+	// Continues reports whether the line continues the entry of the previous lines,
+	// and records the line as part of the entry. The first line of an entry is passed
+	// to it as well, and whether it continues a previous entry is then ignored.
+	Continues(line []byte) bool
+}
+
+// NewPresetMatcher returns a matcher of the entries of the named preset.
+func NewPresetMatcher(preset string) (ContinuationMatcher, error) {
+	switch preset {
+	case PresetJava:
+		return &javaMatcher{}, nil
+	case PresetPython:
+		return &pythonMatcher{}, nil
+	case PresetGo:
+		return &goMatcher{}, nil
+	case PresetDotNet:
+		return &dotNetMatcher{}, nil
+	case PresetNodeJS:
+		return &nodeJSMatcher{}, nil
+	default:
+		return nil, fmt.Errorf("unknown preset %q, must be one of %q, %q, %q, %q or %q",
+			preset, PresetJava, PresetPython, PresetGo, PresetDotNet, PresetNodeJS)
+	}
+}
+
+// PresetSplitFunc creates a bufio.SplitFunc that splits an incoming stream into tokens made of a
+// line and the lines that continue it, as recognized by the named preset. Lines are UTF-8 encoded.
+func PresetSplitFunc(preset string, flushAtEOF bool) (bufio.SplitFunc, error) {
+	if _, err := NewPresetMatcher(preset); err != nil {
+		return nil, err
+	}
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		// The matcher is stateful, so the entry is matched from its first line on each call.
+		matcher, _ := NewPresetMatcher(preset)
+		for start := 0; start < len(data); {
+			end := bytes.IndexByte(data[start:], '\n')
+			if end < 0 {
+				break
+			}
+			line := dropCR(data[start : start+end])
+			if !matcher.Continues(line) && start > 0 {
+				return start, dropCR(data[:start-1]), nil
+			}
+			start += end + 1
+		}
+
+		// Flush if no more data is expected
+		if len(data) != 0 && atEOF && flushAtEOF {
+			return len(data), bytes.TrimSuffix(dropCR(data), []byte("\n")), nil
+		}
+		return 0, nil, nil // read more data and try again
+	}, nil
+}
+
+func dropCR(data []byte) []byte {
+	return bytes.TrimSuffix(data, []byte("\r"))
+}
+
+var (
+	// javaExceptionRegex matches the first line of a Java exception, i.e. a qualified class name
+	// followed by the message, which may follow the log line that reports it.
+	javaExceptionRegex = regexp.MustCompile(`^(?:[a-zA-Z_$][\w$]*\.)+[a-zA-Z_$][\w$]*(?:Exception|Error|Throwable)(?::|$)`)
+	javaFrameRegex     = regexp.MustCompile(`^\s+(?:at |\.\.\. \d+ (?:more|common frames omitted))`)
+	javaCauseRegex     = regexp.MustCompile(`^\s*(?:Caused by|Suppressed): `)
+)
+
+// javaMatcher matches Java exceptions, along with their "Caused by:" and "Suppressed:" chains.
+type javaMatcher struct{}
+
+func (*javaMatcher) Continues(line []byte) bool {
+	return javaFrameRegex.Match(line) || javaCauseRegex.Match(line) || javaExceptionRegex.Match(line)
+}
+
+var (
+	pythonTracebackRegex = regexp.MustCompile(`^\s*Traceback \(most recent call last\):`)
+	pythonChainRegex     = regexp.MustCompile(`^(?:During handling of the above exception, another exception occurred:|The above exception was the direct cause of the following exception:)`)
+	// pythonExceptionRegex matches the last line of a traceback, i.e. the exception and its message.
+	pythonExceptionRegex = regexp.MustCompile(`^[a-zA-Z_][\w.]*(?::|$)`)
+)
+
+// pythonMatcher matches Python tracebacks, including chained exceptions.
+type pythonMatcher struct {
+	inTraceback bool
+	afterFrame  bool
+}
+
+func (m *pythonMatcher) Continues(line []byte) bool {
+	switch {
+	case pythonTracebackRegex.Match(line):
+		m.inTraceback, m.afterFrame = true, false
+		return true
+	case !m.inTraceback:
+		return false
+	case isIndented(line):
+		m.afterFrame = true
+		return true
+	case len(bytes.TrimSpace(line)) == 0, pythonChainRegex.Match(line):
+		return true
+	case m.afterFrame && pythonExceptionRegex.Match(line):
+		m.afterFrame = false
+		return true
+	default:
+		m.inTraceback = false
+		return false
+	}
+}
+
+var (
+	goPanicRegex     = regexp.MustCompile(`^(?:panic: |fatal error: |SIGQUIT: )`)
+	goGoroutineRegex = regexp.MustCompile(`^goroutine \d+ \[.*\]:`)
+	goTraceRegex     = regexp.MustCompile(`^(?:created by |\[signal |exit status \d+)`)
+	// goFuncRegex matches the function calls of a goroutine trace, e.g. "main.(*T).run(...)".
+	goFuncRegex = regexp.MustCompile(`^[\w./\-]+(?:\.\(\*?[\w.\[\], ]+\))?\.[\w.\[\]\-]+\(.*\)$`)
+)
+
+// goMatcher matches Go panics and the goroutine traces that follow them. Since they're
+// printed by the runtime rather than logged, panics start entries of their own.
+type goMatcher struct {
+	inPanic bool
+}
+
+func (m *goMatcher) Continues(line []byte) bool {
+	switch {
+	case goPanicRegex.Match(line), !m.inPanic && goGoroutineRegex.Match(line):
+		m.inPanic = true
+		return false
+	case !m.inPanic:
+		return false
+	case isIndented(line), len(bytes.TrimSpace(line)) == 0, goGoroutineRegex.Match(line), goTraceRegex.Match(line), goFuncRegex.Match(line):
+		return true
+	default:
+		m.inPanic = false
+		return false
+	}
+}
+
+var (
+	// dotNetExceptionRegex matches the first line of a .NET exception, which may follow the log line that reports it.
+	dotNetExceptionRegex = regexp.MustCompile(`^(?:[a-zA-Z_][\w` + "`" + `]*\.)+[a-zA-Z_][\w` + "`" + `]*Exception(?::|$)`)
+	dotNetFrameRegex     = regexp.MustCompile(`^(?:\s+at |\s*---> |\s*--- End of )`)
+)
+
+// dotNetMatcher matches .NET exceptions, including inner exceptions.
+type dotNetMatcher struct{}
+
+func (*dotNetMatcher) Continues(line []byte) bool {
+	return dotNetFrameRegex.Match(line) || dotNetExceptionRegex.Match(line)
+}
+
+var (
+	// nodeJSErrorRegex matches the first line of an error, which may follow the log line that reports it.
+	nodeJSErrorRegex = regexp.MustCompile(`^(?:[A-Z]\w*)?(?:Error|Exception)(?: \[[\w-]+\])?(?::|$)`)
+	nodeJSFrameRegex = regexp.MustCompile(`^\s+(?:at |\.\.\. \d+ (?:more )?lines? matching|\[cause\]: |\[errors\]: )`)
+)
+
+// nodeJSMatcher matches Node.js errors, including their causes and properties.
+type nodeJSMatcher struct {
+	inError bool
+}
+
+func (m *nodeJSMatcher) Continues(line []byte) bool {
+	switch {
+	case nodeJSFrameRegex.Match(line), nodeJSErrorRegex.Match(line):
+		m.inError = true
+		return true
+	case !m.inError:
+		return false
+	case isIndented(line), bytes.Equal(bytes.TrimSpace(line), []byte("}")):
+		// The properties of errors are printed after their stack trace.
+		return true
+	default:
+		m.inError = false
+		return false
+	}
+}
+
+func isIndented(line []byte) bool {
+	return len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(bytes.TrimSpace(line)) > 0
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package split
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split/splittest"
+)
+
+// TestPresetSplitFuncCorpus splits the logs of testdata/preset/<preset>/input.log, and expects
+// the tokens of testdata/preset/<preset>/expected.json.
+func TestPresetSplitFuncCorpus(t *testing.T) {
+	for _, preset := range []string{PresetJava, PresetPython, PresetGo, PresetDotNet, PresetNodeJS} {
+		input, err := os.ReadFile(filepath.Join("testdata", "preset", preset, "input.log"))
+		require.NoError(t, err)
+		expectedJSON, err := os.ReadFile(filepath.Join("testdata", "preset", preset, "expected.json"))
+		require.NoError(t, err)
+		var expected []string
+		require.NoError(t, json.Unmarshal(expectedJSON, &expected))
+
+		splitFunc, err := PresetSplitFunc(preset, true)
+		require.NoError(t, err)
+
+		steps := make([]splittest.Step, 0, len(expected))
+		for _, token := range expected {
+			steps = append(steps, splittest.ExpectAdvanceToken(len(token)+1, token))
+		}
+		t.Run(preset, splittest.New(splitFunc, input, steps...))
+	}
+}
+
+func TestPresetSplitFunc(t *testing.T) {
+	testCases := []struct {
+		name       string
+		preset     string
+		flushAtEOF bool
+		input      []byte
+		steps      []splittest.Step
+	}{
+		{
+			name:   "NoStackTrace",
+			preset: PresetJava,
+			input:  []byte("log1\nlog2\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\n"), "log1"),
+			},
+		},
+		{
+			name:   "WaitForNextLine",
+			preset: PresetJava,
+			input:  []byte("log1\n\tat com.example.Main.main(Main.java:5)\n"),
+		},
+		{
+			name:       "FlushAtEOF",
+			preset:     PresetJava,
+			flushAtEOF: true,
+			input:      []byte("log1\n\tat com.example.Main.main(Main.java:5)"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\n\tat com.example.Main.main(Main.java:5)"), "log1\n\tat com.example.Main.main(Main.java:5)"),
+			},
+		},
+		{
+			name:   "CarriageReturn",
+			preset: PresetDotNet,
+			input:  []byte("log1\r\n   at App.Program.Main()\r\nlog2\r\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("log1\r\n   at App.Program.Main()\r\n"), "log1\r\n   at App.Program.Main()"),
+			},
+		},
+		{
+			name:   "LeadingStackTrace",
+			preset: PresetJava,
+			input:  []byte("\tat com.example.Main.main(Main.java:5)\nlog1\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(len("\tat com.example.Main.main(Main.java:5)\n"), "\tat com.example.Main.main(Main.java:5)"),
+			},
+		},
+		{
+			name:   "PythonTracebackEnd",
+			preset: PresetPython,
+			input:  []byte("Traceback (most recent call last):\n  File \"main.py\", line 1, in <module>\nZeroDivisionError: division by zero\nINFO: done\nlog2\n"),
+			steps: []splittest.Step{
+				splittest.ExpectAdvanceToken(
+					len("Traceback (most recent call last):\n  File \"main.py\", line 1, in <module>\nZeroDivisionError: division by zero\n"),
+					"Traceback (most recent call last):\n  File \"main.py\", line 1, in <module>\nZeroDivisionError: division by zero",
+				),
+				splittest.ExpectAdvanceToken(len("INFO: done\n"), "INFO: done"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		splitFunc, err := PresetSplitFunc(tc.preset, tc.flushAtEOF)
+		require.NoError(t, err)
+		t.Run(tc.name, splittest.New(splitFunc, tc.input, tc.steps...))
+	}
+}
+
+func TestNewPresetMatcher(t *testing.T) {
+	for _, preset := range []string{PresetJava, PresetPython, PresetGo, PresetDotNet, PresetNodeJS} {
+		_, err := NewPresetMatcher(preset)
+		assert.NoError(t, err)
+	}
+	_, err := NewPresetMatcher("ruby")
+	assert.EqualError(t, err, `unknown preset "ruby", must be one of "java", "python", "go", "dotnet" or "nodejs"`)
+
+	_, err = PresetSplitFunc("ruby", false)
+	assert.Error(t, err)
+}
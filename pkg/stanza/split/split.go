@@ -35,6 +35,9 @@ type Config struct {
 	LineStartPattern string `mapstructure:"line_start_pattern"`
 	LineEndPattern   string `mapstructure:"line_end_pattern"`
 	OmitPattern      bool   `mapstructure:"omit_pattern"`
+	// Preset is the name of a built-in multiline preset, which groups stack traces and
+	// exceptions with the log line that precedes them.
+	Preset string `mapstructure:"preset"`
 }
 
 // Func will return a bufio.SplitFunc based on the config
@@ -51,9 +54,22 @@ func (c Config) FuncWithLogger(enc encoding.Encoding, flushAtEOF bool, maxLogSiz
 		if c.LineStartPattern != "" {
 			return nil, errors.New("line_start_pattern should not be set when using nop encoding")
 		}
+		if c.Preset != "" {
+			return nil, errors.New("preset should not be set when using nop encoding")
+		}
 		return NoSplitFunc(maxLogSize), nil
 	}
 
+	if c.Preset != "" {
+		if c.LineEndPattern != "" || c.LineStartPattern != "" {
+			return nil, errors.New("preset cannot be set along with line_start_pattern or line_end_pattern")
+		}
+		if enc != unicode.UTF8 {
+			return nil, errors.New("preset requires utf-8 encoding")
+		}
+		return PresetSplitFunc(c.Preset, flushAtEOF)
+	}
+
 	if c.LineEndPattern == "" && c.LineStartPattern == "" {
 		return NewlineSplitFunc(enc, flushAtEOF)
 	}
@@ -71,6 +71,31 @@ func TestConfigFunc(t *testing.T) {
 		_, err := cfg.Func(unicode.UTF8, false, maxLogSize)
 		assert.EqualError(t, err, "compile line end regex: error parsing regexp: missing closing ]: `[`")
 	})
+
+	t.Run("Preset", func(t *testing.T) {
+		cfg := Config{Preset: PresetJava}
+		f, err := cfg.Func(unicode.UTF8, false, maxLogSize)
+		assert.NoError(t, err)
+
+		advance, token, err := f([]byte("foo\n\tat com.example.Main.main(Main.java:5)\nbar\n"), false)
+		assert.NoError(t, err)
+		assert.Equal(t, len("foo\n\tat com.example.Main.main(Main.java:5)\n"), advance)
+		assert.Equal(t, []byte("foo\n\tat com.example.Main.main(Main.java:5)"), token)
+	})
+
+	t.Run("PresetErrors", func(t *testing.T) {
+		_, err := Config{Preset: PresetJava, LineStartPattern: "foo"}.Func(unicode.UTF8, false, maxLogSize)
+		assert.EqualError(t, err, "preset cannot be set along with line_start_pattern or line_end_pattern")
+
+		_, err = Config{Preset: PresetJava}.Func(unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), false, maxLogSize)
+		assert.EqualError(t, err, "preset requires utf-8 encoding")
+
+		_, err = Config{Preset: PresetJava}.Func(encoding.Nop, false, maxLogSize)
+		assert.EqualError(t, err, "preset should not be set when using nop encoding")
+
+		_, err = Config{Preset: "ruby"}.Func(unicode.UTF8, false, maxLogSize)
+		assert.ErrorContains(t, err, `unknown preset "ruby"`)
+	})
 }
 
 func TestLineStartSplitFunc(t *testing.T) {
@@ -25,6 +25,7 @@ require (
 )
 
 require (
+	github.com/klauspost/compress v1.18.4 // indirect
 	go.opentelemetry.io/collector/component/componenttest v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/consumer/consumertest v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/processor/processortest v0.145.1-0.20260212054546-f0da990367b6
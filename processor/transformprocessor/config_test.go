@@ -17,6 +17,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -31,6 +32,7 @@ func TestLoadConfig(t *testing.T) {
 			id: component.NewIDWithName(metadata.Type, ""),
 			expected: &Config{
 				ErrorMode: ottl.PropagateError,
+				State:     state.NewDefaultConfig(),
 				TraceStatements: []common.ContextStatements{
 					{
 						Context: "span",
@@ -96,6 +98,7 @@ func TestLoadConfig(t *testing.T) {
 			id: component.NewIDWithName(metadata.Type, "with_conditions"),
 			expected: &Config{
 				ErrorMode: ottl.PropagateError,
+				State:     state.NewDefaultConfig(),
 				TraceStatements: []common.ContextStatements{
 					{
 						Context:    "span",
@@ -138,6 +141,7 @@ func TestLoadConfig(t *testing.T) {
 			id: component.NewIDWithName(metadata.Type, "ignore_errors"),
 			expected: &Config{
 				ErrorMode: ottl.IgnoreError,
+				State:     state.NewDefaultConfig(),
 				TraceStatements: []common.ContextStatements{
 					{
 						Context: "resource",
@@ -187,6 +191,7 @@ func TestLoadConfig(t *testing.T) {
 			id: component.NewIDWithName(metadata.Type, "structured_configuration_with_path_context"),
 			expected: &Config{
 				ErrorMode: ottl.PropagateError,
+				State:     state.NewDefaultConfig(),
 				TraceStatements: []common.ContextStatements{
 					{
 						Context:    "span",
@@ -217,6 +222,7 @@ func TestLoadConfig(t *testing.T) {
 			id: component.NewIDWithName(metadata.Type, "structured_configuration_with_inferred_context"),
 			expected: &Config{
 				ErrorMode: ottl.PropagateError,
+				State:     state.NewDefaultConfig(),
 				TraceStatements: []common.ContextStatements{
 					{
 						Statements: []string{
@@ -255,6 +261,7 @@ func TestLoadConfig(t *testing.T) {
 			id: component.NewIDWithName(metadata.Type, "flat_configuration"),
 			expected: &Config{
 				ErrorMode: ottl.PropagateError,
+				State:     state.NewDefaultConfig(),
 				TraceStatements: []common.ContextStatements{
 					{
 						Statements: []string{
@@ -293,6 +300,7 @@ func TestLoadConfig(t *testing.T) {
 			id: component.NewIDWithName(metadata.Type, "context_statements_error_mode"),
 			expected: &Config{
 				ErrorMode: ottl.IgnoreError,
+				State:     state.NewDefaultConfig(),
 				TraceStatements: []common.ContextStatements{
 					{
 						Statements: []string{`set(resource.attributes["name"], "propagate")`},
@@ -10,6 +10,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/xconsumer"
+	"go.opentelemetry.io/collector/pipeline"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 	"go.opentelemetry.io/collector/processor/processorhelper/xprocessorhelper"
@@ -28,6 +29,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metrics"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/profiles"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/traces"
 )
 
@@ -212,6 +214,7 @@ func (f *transformProcessorFactory) createDefaultConfig() component.Config {
 		MetricStatements:   []common.ContextStatements{},
 		LogStatements:      []common.ContextStatements{},
 		ProfileStatements:  []common.ContextStatements{},
+		State:              state.NewDefaultConfig(),
 		dataPointFunctions: f.dataPointFunctions,
 		logFunctions:       f.logFunctions,
 		metricFunctions:    f.metricFunctions,
@@ -231,7 +234,8 @@ func (f *transformProcessorFactory) createLogsProcessor(
 	if f.defaultLogFunctionsOverridden {
 		set.Logger.Debug("non-default OTTL log functions have been registered in the \"transform\" processor", zap.Bool("log", f.defaultLogFunctionsOverridden))
 	}
-	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.FlattenData, set.TelemetrySettings, f.logFunctions)
+	registry := state.NewRegistry(oCfg.State, set.ID, pipeline.SignalLogs.String(), set.Logger)
+	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.FlattenData, set.TelemetrySettings, state.BindFunctions(f.logFunctions, registry))
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -241,7 +245,9 @@ func (f *transformProcessorFactory) createLogsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessLogs,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(registry.Start),
+		processorhelper.WithShutdown(registry.Shutdown))
 }
 
 func (f *transformProcessorFactory) createTracesProcessor(
@@ -257,7 +263,8 @@ func (f *transformProcessorFactory) createTracesProcessor(
 			zap.Bool("spanevent", f.defaultSpanEventFunctionsOverridden),
 		)
 	}
-	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, set.TelemetrySettings, f.spanFunctions, f.spanEventFunctions)
+	registry := state.NewRegistry(oCfg.State, set.ID, pipeline.SignalTraces.String(), set.Logger)
+	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, set.TelemetrySettings, state.BindFunctions(f.spanFunctions, registry), state.BindFunctions(f.spanEventFunctions, registry))
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -267,7 +274,9 @@ func (f *transformProcessorFactory) createTracesProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessTraces,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(registry.Start),
+		processorhelper.WithShutdown(registry.Shutdown))
 }
 
 func (f *transformProcessorFactory) createMetricsProcessor(
@@ -284,7 +293,8 @@ func (f *transformProcessorFactory) createMetricsProcessor(
 			zap.Bool("metric", f.defaultMetricFunctionsOverridden),
 		)
 	}
-	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, set.TelemetrySettings, f.metricFunctions, f.dataPointFunctions)
+	registry := state.NewRegistry(oCfg.State, set.ID, pipeline.SignalMetrics.String(), set.Logger)
+	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, set.TelemetrySettings, f.metricFunctions, state.BindFunctions(f.dataPointFunctions, registry))
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -294,7 +304,9 @@ func (f *transformProcessorFactory) createMetricsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessMetrics,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(registry.Start),
+		processorhelper.WithShutdown(registry.Shutdown))
 }
 
 func (f *transformProcessorFactory) createProfilesProcessor(
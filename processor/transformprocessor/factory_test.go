@@ -30,6 +30,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pprofiletest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
 )
 
 func assertConfigContainsDefaultFunctions(t *testing.T, config Config) {
@@ -68,6 +69,7 @@ func TestFactory_CreateDefaultConfig(t *testing.T) {
 		MetricStatements:  []common.ContextStatements{},
 		LogStatements:     []common.ContextStatements{},
 		ProfileStatements: []common.ContextStatements{},
+		State:             state.NewDefaultConfig(),
 	}, cfg)
 	assertConfigContainsDefaultFunctions(t, *cfg.(*Config))
 	require.NoError(t, componenttest.CheckConfigStruct(cfg))
@@ -15,6 +15,7 @@ require (
 	go.opentelemetry.io/collector/consumer v1.51.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/featuregate v1.51.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/pdata v1.51.1-0.20260212054546-f0da990367b6
+	go.opentelemetry.io/collector/pipeline v1.51.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/processor v1.51.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/otel/metric v1.40.0 // indirect
 	go.opentelemetry.io/otel/trace v1.40.0 // indirect
@@ -24,10 +25,12 @@ require (
 )
 
 require (
+	github.com/klauspost/compress v1.18.4 // indirect
 	go.opentelemetry.io/collector/component/componenttest v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/confmap/xconfmap v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/consumer/consumertest v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/consumer/xconsumer v0.145.1-0.20260212054546-f0da990367b6
+	go.opentelemetry.io/collector/extension/xextension v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/pdata/pprofile v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/processor/processorhelper v0.145.1-0.20260212054546-f0da990367b6
 	go.opentelemetry.io/collector/processor/processorhelper/xprocessorhelper v0.145.1-0.20260212054546-f0da990367b6
@@ -74,9 +77,9 @@ require (
 	github.com/zeebo/xxh3 v1.1.0 // indirect
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/collector/component/componentstatus v0.145.1-0.20260212054546-f0da990367b6 // indirect
+	go.opentelemetry.io/collector/extension v1.51.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/internal/componentalias v0.145.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/collector/pdata/testdata v0.145.1-0.20260212054546-f0da990367b6 // indirect
-	go.opentelemetry.io/collector/pipeline v1.51.1-0.20260212054546-f0da990367b6 // indirect
 	go.opentelemetry.io/otel/sdk v1.40.0 // indirect
 	go.opentelemetry.io/otel/sdk/metric v1.40.0 // indirect
 	go.yaml.in/yaml/v3 v3.0.4 // indirect
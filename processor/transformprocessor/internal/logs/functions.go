@@ -4,12 +4,16 @@
 package logs // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/logs"
 
 import (
+	"maps"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
 )
 
 func LogFunctions() map[string]ottl.Factory[*ottllog.TransformContext] {
-	// No logs-only functions yet.
-	return ottlfuncs.StandardFuncs[*ottllog.TransformContext]()
+	functions := ottlfuncs.StandardFuncs[*ottllog.TransformContext]()
+	maps.Copy(functions, state.Functions[*ottllog.TransformContext](nil))
+	return functions
 }
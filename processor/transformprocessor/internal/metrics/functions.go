@@ -12,6 +12,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
 )
 
 var UseConvertBetweenSumAndGaugeMetricContext = featuregate.GlobalRegistry().MustRegister(
@@ -31,6 +32,7 @@ func DataPointFunctions() map[string]ottl.Factory[*ottldatapoint.TransformContex
 	)
 
 	maps.Copy(functions, datapointFunctions)
+	maps.Copy(functions, state.Functions[*ottldatapoint.TransformContext](nil))
 
 	return functions
 }
@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Entry is the state recorded for a key.
+type Entry struct {
+	// Value is the last value recorded for the key.
+	Value any `json:"value"`
+	// Time is the time associated with the value, e.g. the timestamp of the record it was taken from.
+	Time time.Time `json:"time"`
+	// Updated is the wall clock time the entry was last written, used to expire the entry.
+	Updated time.Time `json:"updated"`
+}
+
+type cacheItem struct {
+	key   string
+	entry Entry
+}
+
+// cache is a bounded key value store whose entries expire once they haven't been
+// updated for the configured TTL. Entries are kept in the order they were updated
+// so that both expired entries and, when the cache is full, the least recently
+// updated entries are evicted from the front of the list.
+type cache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	items      map[string]*list.Element
+	order      *list.List
+	now        func() time.Time
+}
+
+func newCache(maxEntries int, ttl time.Duration) *cache {
+	return &cache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		items:      map[string]*list.Element{},
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// Update calls fn with the current entry for the key, if any, and stores the entry
+// returned by fn. The whole operation is atomic, so concurrent updates of a key are
+// never lost.
+func (c *cache) Update(key string, fn func(prev Entry, found bool) Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictExpired(now)
+
+	var prev Entry
+	elem, found := c.items[key]
+	if found {
+		prev = elem.Value.(*cacheItem).entry
+	}
+
+	next := fn(prev, found)
+	next.Updated = now
+	if found {
+		elem.Value.(*cacheItem).entry = next
+		c.order.MoveToBack(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushBack(&cacheItem{key: key, entry: next})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Front())
+	}
+}
+
+// Len returns the number of entries, including expired entries that haven't been evicted yet.
+func (c *cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// snapshot returns the entries that haven't expired, from the least to the most recently updated.
+func (c *cache) snapshot() []cacheItem {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictExpired(c.now())
+	items := make([]cacheItem, 0, c.order.Len())
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		items = append(items, *elem.Value.(*cacheItem))
+	}
+	return items
+}
+
+// restore adds the given entries, which must be ordered from the least to the most recently updated.
+func (c *cache) restore(items []cacheItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, item := range items {
+		if elem, ok := c.items[item.key]; ok {
+			c.remove(elem)
+		}
+		c.items[item.key] = c.order.PushBack(&cacheItem{key: item.key, entry: item.entry})
+	}
+	c.evictExpired(c.now())
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Front())
+	}
+}
+
+func (c *cache) evictExpired(now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	for elem := c.order.Front(); elem != nil; elem = c.order.Front() {
+		if now.Sub(elem.Value.(*cacheItem).entry.Updated) < c.ttl {
+			return
+		}
+		c.remove(elem)
+	}
+}
+
+func (c *cache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*cacheItem).key)
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a controllable time source for caches.
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.now = f.now.Add(d)
+}
+
+func newTestCache(maxEntries int, ttl time.Duration) (*cache, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := newCache(maxEntries, ttl)
+	c.now = clock.Now
+	return c, clock
+}
+
+func put(c *cache, key string, value any) {
+	c.Update(key, func(Entry, bool) Entry {
+		return Entry{Value: value}
+	})
+}
+
+func get(c *cache, key string) (any, bool) {
+	var value any
+	var found bool
+	c.Update(key, func(prev Entry, ok bool) Entry {
+		value, found = prev.Value, ok
+		return prev
+	})
+	return value, found
+}
+
+func Test_cache_Update(t *testing.T) {
+	c, _ := newTestCache(10, time.Minute)
+
+	_, found := get(c, "a")
+	assert.False(t, found)
+
+	put(c, "a", 1)
+	value, found := get(c, "a")
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+
+	put(c, "a", 2)
+	value, found = get(c, "a")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+}
+
+func Test_cache_MaxEntries(t *testing.T) {
+	c, _ := newTestCache(2, time.Minute)
+
+	put(c, "a", 1)
+	put(c, "b", 2)
+	// Updating a makes b the least recently updated key.
+	put(c, "a", 3)
+	put(c, "c", 4)
+	require.Equal(t, 2, c.Len())
+
+	_, found := get(c, "b")
+	assert.False(t, found)
+	value, found := get(c, "a")
+	assert.True(t, found)
+	assert.Equal(t, 3, value)
+}
+
+func Test_cache_TTL(t *testing.T) {
+	c, clock := newTestCache(10, time.Minute)
+
+	put(c, "a", 1)
+	clock.Advance(30 * time.Second)
+	put(c, "b", 2)
+	clock.Advance(40 * time.Second)
+
+	_, found := get(c, "a")
+	assert.False(t, found)
+	_, found = get(c, "b")
+	assert.True(t, found)
+}
+
+func Test_cache_SnapshotRestore(t *testing.T) {
+	c, clock := newTestCache(10, time.Minute)
+	put(c, "a", 1)
+	clock.Advance(time.Second)
+	put(c, "b", 2)
+
+	items := c.snapshot()
+	require.Len(t, items, 2)
+	assert.Equal(t, "a", items[0].key)
+	assert.Equal(t, "b", items[1].key)
+
+	restored, restoredClock := newTestCache(1, time.Minute)
+	restoredClock.now = clock.now
+	restored.restore(items)
+	// Only the most recently updated entry fits.
+	require.Equal(t, 1, restored.Len())
+	value, found := get(restored, "b")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+}
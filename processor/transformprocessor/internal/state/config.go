@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	defaultMaxEntries = 10000
+	defaultTTL        = 10 * time.Minute
+)
+
+// Config defines the state kept by the stateful OTTL converters.
+type Config struct {
+	// MaxEntries is the maximum number of keys tracked by each stateful converter of a statement.
+	// The least recently updated keys are evicted once the limit is reached.
+	MaxEntries int `mapstructure:"max_entries"`
+	// TTL is the duration after which a key that hasn't been updated is evicted.
+	TTL time.Duration `mapstructure:"ttl"`
+	// StorageID is the optional storage extension used to persist the state across restarts.
+	StorageID *component.ID `mapstructure:"storage"`
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// NewDefaultConfig returns the default state configuration.
+func NewDefaultConfig() Config {
+	return Config{
+		MaxEntries: defaultMaxEntries,
+		TTL:        defaultTTL,
+	}
+}
+
+func (c Config) Validate() error {
+	if c.MaxEntries <= 0 {
+		return errors.New("max_entries must be greater than 0")
+	}
+	if c.TTL <= 0 {
+		return errors.New("ttl must be greater than 0")
+	}
+	return nil
+}
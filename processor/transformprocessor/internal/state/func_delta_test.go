@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_delta(t *testing.T) {
+	c, _ := newTestCache(10, time.Hour)
+	exprFunc := delta[record](
+		c,
+		field[ottl.StandardStringLikeGetter[record]]("key"),
+		field[ottl.StandardFloatLikeGetter[record]]("value"),
+	)
+
+	tests := []struct {
+		name     string
+		record   record
+		expected any
+	}{
+		{
+			name:     "first observation",
+			record:   record{"key": "a", "value": int64(10)},
+			expected: nil,
+		},
+		{
+			name:     "increase",
+			record:   record{"key": "a", "value": 12.5},
+			expected: 2.5,
+		},
+		{
+			name:     "decrease",
+			record:   record{"key": "a", "value": "7.5"},
+			expected: -5.0,
+		},
+		{
+			name:     "other key",
+			record:   record{"key": "b", "value": int64(1)},
+			expected: nil,
+		},
+		{
+			name:     "missing value",
+			record:   record{"key": "a"},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := exprFunc(t.Context(), tt.record)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
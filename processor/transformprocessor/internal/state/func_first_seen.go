@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type FirstSeenArguments[K any] struct {
+	Key    ottl.StringLikeGetter[K]
+	Window ottl.Optional[ottl.DurationGetter[K]]
+}
+
+func NewFirstSeenFactory[K any](registry *Registry) ottl.Factory[K] {
+	return ottl.NewFactory("FirstSeen", &FirstSeenArguments[K]{}, createFirstSeenFunction[K](registry))
+}
+
+func createFirstSeenFunction[K any](registry *Registry) ottl.CreateFunctionFunc[K] {
+	return func(_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+		args, ok := oArgs.(*FirstSeenArguments[K])
+		if !ok {
+			return nil, errors.New("FirstSeenFactory args must be of type *FirstSeenArguments[K]")
+		}
+
+		// Keys must be kept for at least the window when it is known upfront.
+		var ttl time.Duration
+		if !args.Window.IsEmpty() {
+			if window, isLiteral := ottl.GetLiteralValue(args.Window.Get()); isLiteral {
+				if window <= 0 {
+					return nil, errors.New("window must be greater than 0")
+				}
+				ttl = window
+			}
+		}
+
+		return firstSeen(registry.newCache("FirstSeen", ttl), args.Key, args.Window), nil
+	}
+}
+
+func firstSeen[K any](c *cache, key ottl.StringLikeGetter[K], windowGetter ottl.Optional[ottl.DurationGetter[K]]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		k, err := key.Get(ctx, tCtx)
+		if err != nil || k == nil {
+			return nil, err
+		}
+		// Without a window a key is only seen for the first time again once it has expired.
+		var window time.Duration
+		if !windowGetter.IsEmpty() {
+			if window, err = windowGetter.Get().Get(ctx, tCtx); err != nil {
+				return nil, err
+			}
+		}
+
+		now := c.now()
+		first := false
+		c.Update(*k, func(prev Entry, found bool) Entry {
+			if found && (window <= 0 || now.Sub(prev.Time) < window) {
+				return prev
+			}
+			first = true
+			return Entry{Time: now}
+		})
+		return first, nil
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_firstSeen(t *testing.T) {
+	c, clock := newTestCache(10, time.Hour)
+	exprFunc := firstSeen[record](
+		c,
+		field[ottl.StandardStringLikeGetter[record]]("key"),
+		ottl.NewTestingOptional[ottl.DurationGetter[record]](field[ottl.StandardDurationGetter[record]]("window")),
+	)
+	window := 10 * time.Minute
+
+	steps := []struct {
+		advance  time.Duration
+		key      string
+		expected bool
+	}{
+		{key: "a", expected: true},
+		{key: "a", advance: time.Minute, expected: false},
+		{key: "b", expected: true},
+		{key: "a", advance: 8 * time.Minute, expected: false},
+		// The window starts at the first occurrence, later occurrences don't extend it.
+		{key: "a", advance: time.Minute, expected: true},
+		{key: "a", advance: time.Minute, expected: false},
+	}
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		result, err := exprFunc(t.Context(), record{"key": step.key, "window": window})
+		require.NoError(t, err)
+		assert.Equal(t, step.expected, result, "key %q at %s", step.key, clock.now)
+	}
+}
+
+func Test_firstSeen_noWindow(t *testing.T) {
+	c, clock := newTestCache(10, time.Minute)
+	exprFunc := firstSeen[record](
+		c,
+		field[ottl.StandardStringLikeGetter[record]]("key"),
+		ottl.Optional[ottl.DurationGetter[record]]{},
+	)
+
+	result, err := exprFunc(t.Context(), record{"key": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, true, result)
+
+	clock.Advance(50 * time.Second)
+	result, err = exprFunc(t.Context(), record{"key": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, false, result)
+
+	// Without a window, the key is only seen for the first time again once it expired.
+	clock.Advance(time.Minute)
+	result, err = exprFunc(t.Context(), record{"key": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, true, result)
+}
+
+func Test_createFirstSeenFunction_invalidWindow(t *testing.T) {
+	window, err := ottl.NewTestingLiteralGetter[record, time.Duration](true, ottl.StandardDurationGetter[record]{
+		Getter: func(context.Context, record) (any, error) {
+			return -time.Minute, nil
+		},
+	})
+	require.NoError(t, err)
+
+	factory := NewFirstSeenFactory[record](nil)
+	_, err = factory.CreateFunction(ottl.FunctionContext{}, &FirstSeenArguments[record]{
+		Key:    field[ottl.StandardStringLikeGetter[record]]("key"),
+		Window: ottl.NewTestingOptional[ottl.DurationGetter[record]](window),
+	})
+	assert.ErrorContains(t, err, "window must be greater than 0")
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
+
+import (
+	"context"
+	"errors"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type PreviousArguments[K any] struct {
+	Key   ottl.StringLikeGetter[K]
+	Value ottl.Getter[K]
+}
+
+func NewPreviousFactory[K any](registry *Registry) ottl.Factory[K] {
+	return ottl.NewFactory("Previous", &PreviousArguments[K]{}, createPreviousFunction[K](registry))
+}
+
+func createPreviousFunction[K any](registry *Registry) ottl.CreateFunctionFunc[K] {
+	return func(_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+		args, ok := oArgs.(*PreviousArguments[K])
+		if !ok {
+			return nil, errors.New("PreviousFactory args must be of type *PreviousArguments[K]")
+		}
+
+		return previous(registry.newCache("Previous", 0), args.Key, args.Value), nil
+	}
+}
+
+func previous[K any](c *cache, key ottl.StringLikeGetter[K], value ottl.Getter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		k, err := key.Get(ctx, tCtx)
+		if err != nil || k == nil {
+			return nil, err
+		}
+		v, err := value.Get(ctx, tCtx)
+		if err != nil || v == nil {
+			return nil, err
+		}
+
+		var result any
+		c.Update(*k, func(prev Entry, found bool) Entry {
+			if found {
+				result = prev.Value
+			}
+			return Entry{Value: toRaw(v)}
+		})
+		return result, nil
+	}
+}
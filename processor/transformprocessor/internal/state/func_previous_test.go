@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_previous(t *testing.T) {
+	c, _ := newTestCache(10, time.Hour)
+	exprFunc := previous[record](
+		c,
+		field[ottl.StandardStringLikeGetter[record]]("key"),
+		ottl.StandardGetSetter[record]{Getter: func(_ context.Context, tCtx record) (any, error) {
+			return tCtx["value"], nil
+		}},
+	)
+
+	result, err := exprFunc(t.Context(), record{"key": "a", "value": "first"})
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	result, err = exprFunc(t.Context(), record{"key": "a", "value": int64(2)})
+	require.NoError(t, err)
+	assert.Equal(t, "first", result)
+
+	m := pcommon.NewMap()
+	m.PutStr("k", "v")
+	result, err = exprFunc(t.Context(), record{"key": "a", "value": m})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result)
+
+	// The stored map must not change when the original map is modified.
+	m.PutStr("k", "changed")
+	result, err = exprFunc(t.Context(), record{"key": "a", "value": true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"k": "v"}, result)
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
+
+import (
+	"context"
+	"errors"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type RateArguments[K any] struct {
+	Key   ottl.StringLikeGetter[K]
+	Value ottl.FloatLikeGetter[K]
+	Time  ottl.Optional[ottl.TimeGetter[K]]
+}
+
+func NewRateFactory[K any](registry *Registry) ottl.Factory[K] {
+	return ottl.NewFactory("Rate", &RateArguments[K]{}, createRateFunction[K](registry))
+}
+
+func createRateFunction[K any](registry *Registry) ottl.CreateFunctionFunc[K] {
+	return func(_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+		args, ok := oArgs.(*RateArguments[K])
+		if !ok {
+			return nil, errors.New("RateFactory args must be of type *RateArguments[K]")
+		}
+
+		return rate(registry.newCache("Rate", 0), args.Key, args.Value, args.Time), nil
+	}
+}
+
+func rate[K any](c *cache, key ottl.StringLikeGetter[K], value ottl.FloatLikeGetter[K], timeGetter ottl.Optional[ottl.TimeGetter[K]]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		k, err := key.Get(ctx, tCtx)
+		if err != nil || k == nil {
+			return nil, err
+		}
+		v, err := value.Get(ctx, tCtx)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		ts := c.now()
+		if !timeGetter.IsEmpty() {
+			if ts, err = timeGetter.Get().Get(ctx, tCtx); err != nil {
+				return nil, err
+			}
+		}
+
+		var result any
+		c.Update(*k, func(prev Entry, found bool) Entry {
+			if !found {
+				return Entry{Value: *v, Time: ts}
+			}
+			elapsed := ts.Sub(prev.Time).Seconds()
+			if elapsed <= 0 {
+				// Out of order or duplicate observations don't contribute to the rate.
+				return prev
+			}
+			delta := *v - asFloat(prev.Value)
+			if delta < 0 {
+				// The counter was reset, so it increased by its current value since.
+				delta = *v
+			}
+			result = delta / elapsed
+			return Entry{Value: *v, Time: ts}
+		})
+		return result, nil
+	}
+}
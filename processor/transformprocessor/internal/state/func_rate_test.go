@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// record is the transform context used by the tests of the stateful converters.
+type record map[string]any
+
+func field[T any](name string) T {
+	return T{Getter: func(_ context.Context, tCtx record) (any, error) {
+		return tCtx[name], nil
+	}}
+}
+
+func Test_rate(t *testing.T) {
+	c, _ := newTestCache(10, time.Hour)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	exprFunc := rate[record](
+		c,
+		field[ottl.StandardStringLikeGetter[record]]("key"),
+		field[ottl.StandardFloatLikeGetter[record]]("value"),
+		ottl.NewTestingOptional[ottl.TimeGetter[record]](field[ottl.StandardTimeGetter[record]]("time")),
+	)
+
+	tests := []struct {
+		name     string
+		record   record
+		expected any
+	}{
+		{
+			name:     "first observation",
+			record:   record{"key": "a", "value": int64(10), "time": start},
+			expected: nil,
+		},
+		{
+			name:     "other key",
+			record:   record{"key": "b", "value": int64(100), "time": start},
+			expected: nil,
+		},
+		{
+			name:     "increase",
+			record:   record{"key": "a", "value": int64(30), "time": start.Add(10 * time.Second)},
+			expected: 2.0,
+		},
+		{
+			name:     "out of order",
+			record:   record{"key": "a", "value": int64(20), "time": start.Add(5 * time.Second)},
+			expected: nil,
+		},
+		{
+			name:     "counter reset",
+			record:   record{"key": "a", "value": 5.0, "time": start.Add(20 * time.Second)},
+			expected: 0.5,
+		},
+		{
+			name:     "missing value",
+			record:   record{"key": "a", "time": start.Add(30 * time.Second)},
+			expected: nil,
+		},
+		{
+			name:     "missing key",
+			record:   record{"value": int64(50), "time": start.Add(30 * time.Second)},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := exprFunc(t.Context(), tt.record)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_rate_defaultTime(t *testing.T) {
+	c, clock := newTestCache(10, time.Hour)
+	exprFunc := rate[record](
+		c,
+		field[ottl.StandardStringLikeGetter[record]]("key"),
+		field[ottl.StandardFloatLikeGetter[record]]("value"),
+		ottl.Optional[ottl.TimeGetter[record]]{},
+	)
+
+	result, err := exprFunc(t.Context(), record{"key": "a", "value": 1.0})
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	clock.Advance(2 * time.Second)
+	result, err = exprFunc(t.Context(), record{"key": "a", "value": 11.0})
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, result)
+}
+
+func Test_rate_invalidValue(t *testing.T) {
+	c, _ := newTestCache(10, time.Hour)
+	exprFunc := rate[record](
+		c,
+		field[ottl.StandardStringLikeGetter[record]]("key"),
+		field[ottl.StandardFloatLikeGetter[record]]("value"),
+		ottl.Optional[ottl.TimeGetter[record]]{},
+	)
+
+	_, err := exprFunc(t.Context(), record{"key": "a", "value": []int{1}})
+	assert.Error(t, err)
+	assert.Zero(t, c.Len())
+}
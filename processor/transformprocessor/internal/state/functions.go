@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
+
+import (
+	"maps"
+	"reflect"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// Functions returns the stateful converters backed by the given registry.
+// A nil registry keeps the state in memory with the default limits.
+func Functions[K any](registry *Registry) map[string]ottl.Factory[K] {
+	return ottl.CreateFactoryMap(
+		NewRateFactory[K](registry),
+		NewDeltaFactory[K](registry),
+		NewPreviousFactory[K](registry),
+		NewFirstSeenFactory[K](registry),
+	)
+}
+
+// BindFunctions returns a copy of functions in which the stateful converters are
+// backed by the given registry. Functions that were overridden with a different
+// implementation of the same name are left untouched.
+func BindFunctions[K any](functions map[string]ottl.Factory[K], registry *Registry) map[string]ottl.Factory[K] {
+	bound := maps.Clone(functions)
+	for name, f := range Functions[K](registry) {
+		existing, ok := bound[name]
+		if !ok || reflect.TypeOf(existing.CreateDefaultArguments()) != reflect.TypeOf(f.CreateDefaultArguments()) {
+			continue
+		}
+		bound[name] = f
+	}
+	return bound
+}
+
+func asFloat(v any) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case int64:
+		return float64(val)
+	default:
+		return 0
+	}
+}
+
+// toRaw copies pdata values into their raw representation, so that the stored
+// state doesn't share memory with the data that is being processed.
+func toRaw(v any) any {
+	switch val := v.(type) {
+	case pcommon.Value:
+		return val.AsRaw()
+	case pcommon.Map:
+		return val.AsRaw()
+	case pcommon.Slice:
+		return val.AsRaw()
+	case []byte:
+		return append([]byte(nil), val...)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.uber.org/zap"
+)
+
+// Registry owns the caches of the stateful converters of a processor, and persists
+// them through a storage extension when one is configured.
+//
+// A cache is created for every stateful converter invocation when the statements are
+// parsed. Caches are identified by the converter name and the order in which they were
+// created, which is stable as long as the statements of the processor don't change.
+type Registry struct {
+	cfg         Config
+	componentID component.ID
+	name        string
+	logger      *zap.Logger
+
+	mu     sync.Mutex
+	caches []namedCache
+	client storage.Client
+}
+
+type namedCache struct {
+	key   string
+	cache *cache
+}
+
+// NewRegistry creates a Registry for the processor with the given ID. The name
+// distinguishes the state of the processors of different signals sharing the same ID.
+func NewRegistry(cfg Config, componentID component.ID, name string, logger *zap.Logger) *Registry {
+	return &Registry{
+		cfg:         cfg,
+		componentID: componentID,
+		name:        name,
+		logger:      logger,
+	}
+}
+
+// newCache returns a new cache for the given converter. A nil Registry returns a
+// cache with the default limits that is never persisted, so that the converters can
+// also be used outside of the processor.
+func (r *Registry) newCache(function string, ttl time.Duration) *cache {
+	if r == nil {
+		return newCache(defaultMaxEntries, max(ttl, defaultTTL))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := newCache(r.cfg.MaxEntries, max(ttl, r.cfg.TTL))
+	r.caches = append(r.caches, namedCache{key: function + "/" + strconv.Itoa(len(r.caches)), cache: c})
+	return c
+}
+
+// Start restores the persisted state when a storage extension is configured.
+func (r *Registry) Start(ctx context.Context, host component.Host) error {
+	if r.cfg.StorageID == nil {
+		return nil
+	}
+
+	ext, ok := host.GetExtensions()[*r.cfg.StorageID]
+	if !ok {
+		return fmt.Errorf("storage extension '%s' not found", r.cfg.StorageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("non-storage extension '%s' found", r.cfg.StorageID)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindProcessor, r.componentID, r.name)
+	if err != nil {
+		return fmt.Errorf("failed to get storage client: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.client = client
+	for _, nc := range r.caches {
+		data, err := client.Get(ctx, nc.key)
+		if err != nil {
+			return fmt.Errorf("failed to read state %q: %w", nc.key, err)
+		}
+		if data == nil {
+			continue
+		}
+		items, err := unmarshalItems(data)
+		if err != nil {
+			// Corrupted or incompatible state must not prevent the processor from starting.
+			r.logger.Warn("discarding persisted state", zap.String("key", nc.key), zap.Error(err))
+			continue
+		}
+		nc.cache.restore(items)
+	}
+	return nil
+}
+
+// Shutdown persists the state when a storage extension is configured.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		return nil
+	}
+
+	var errs error
+	for _, nc := range r.caches {
+		data, err := marshalItems(nc.cache.snapshot())
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to encode state %q: %w", nc.key, err))
+			continue
+		}
+		if err := r.client.Set(ctx, nc.key, data); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to write state %q: %w", nc.key, err))
+		}
+	}
+	errs = errors.Join(errs, r.client.Close(ctx))
+	r.client = nil
+	return errs
+}
+
+type persistedItem struct {
+	Key   string `json:"key"`
+	Entry Entry  `json:"entry"`
+}
+
+func marshalItems(items []cacheItem) ([]byte, error) {
+	persisted := make([]persistedItem, len(items))
+	for i, item := range items {
+		persisted[i] = persistedItem{Key: item.key, Entry: item.entry}
+	}
+	return json.Marshal(persisted)
+}
+
+func unmarshalItems(data []byte) ([]cacheItem, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are decoded as json.Number so that integers keep their type.
+	dec.UseNumber()
+	var persisted []persistedItem
+	if err := dec.Decode(&persisted); err != nil {
+		return nil, err
+	}
+
+	items := make([]cacheItem, len(persisted))
+	for i, p := range persisted {
+		p.Entry.Value = fromJSONNumbers(p.Entry.Value)
+		items[i] = cacheItem{key: p.Key, entry: p.Entry}
+	}
+	slices.SortStableFunc(items, func(a, b cacheItem) int {
+		return a.entry.Updated.Compare(b.entry.Updated)
+	})
+	return items, nil
+}
+
+func fromJSONNumbers(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case map[string]any:
+		for k, item := range val {
+			val[k] = fromJSONNumbers(item)
+		}
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = fromJSONNumbers(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
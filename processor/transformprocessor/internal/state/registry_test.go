@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/xextension/storage"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type mockStorageClient struct {
+	mu     sync.Mutex
+	data   map[string][]byte
+	closed bool
+}
+
+func (m *mockStorageClient) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data[key], nil
+}
+
+func (m *mockStorageClient) Set(_ context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = value
+	return nil
+}
+
+func (m *mockStorageClient) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	return nil
+}
+
+func (*mockStorageClient) Batch(context.Context, ...*storage.Operation) error {
+	return nil
+}
+
+func (m *mockStorageClient) Close(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+type mockStorageExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	client *mockStorageClient
+}
+
+func (m *mockStorageExtension) GetClient(context.Context, component.Kind, component.ID, string) (storage.Client, error) {
+	m.client.closed = false
+	return m.client, nil
+}
+
+type mockHost struct {
+	extensions map[component.ID]component.Component
+}
+
+func (h *mockHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+var storageID = component.MustNewID("file_storage")
+
+func newTestRegistry(t *testing.T, client *mockStorageClient) (*Registry, component.Host) {
+	cfg := NewDefaultConfig()
+	cfg.StorageID = &storageID
+	registry := NewRegistry(cfg, component.MustNewID("transform"), "logs", zap.NewNop())
+	host := &mockHost{extensions: map[component.ID]component.Component{
+		storageID: &mockStorageExtension{client: client},
+	}}
+	t.Cleanup(func() {
+		assert.NoError(t, registry.Shutdown(context.Background()))
+	})
+	return registry, host
+}
+
+func Test_Registry_persistence(t *testing.T) {
+	client := &mockStorageClient{data: map[string][]byte{}}
+
+	registry, host := newTestRegistry(t, client)
+	deltaCache := registry.newCache("Delta", 0)
+	previousCache := registry.newCache("Previous", 0)
+	require.NoError(t, registry.Start(t.Context(), host))
+	put(deltaCache, "a", 1.5)
+	put(previousCache, "a", map[string]any{"count": int64(1), "ratio": 0.5})
+	require.NoError(t, registry.Shutdown(t.Context()))
+	assert.True(t, client.closed)
+	assert.Contains(t, client.data, "Delta/0")
+	assert.Contains(t, client.data, "Previous/1")
+
+	restored, host := newTestRegistry(t, client)
+	deltaCache = restored.newCache("Delta", 0)
+	previousCache = restored.newCache("Previous", 0)
+	require.NoError(t, restored.Start(t.Context(), host))
+
+	value, found := get(deltaCache, "a")
+	assert.True(t, found)
+	assert.Equal(t, 1.5, value)
+	value, found = get(previousCache, "a")
+	assert.True(t, found)
+	assert.Equal(t, map[string]any{"count": int64(1), "ratio": 0.5}, value)
+}
+
+func Test_Registry_corruptedState(t *testing.T) {
+	client := &mockStorageClient{data: map[string][]byte{"Delta/0": []byte("not json")}}
+
+	registry, host := newTestRegistry(t, client)
+	c := registry.newCache("Delta", 0)
+	require.NoError(t, registry.Start(t.Context(), host))
+	assert.Zero(t, c.Len())
+}
+
+func Test_Registry_missingStorage(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.StorageID = &storageID
+	registry := NewRegistry(cfg, component.MustNewID("transform"), "logs", zap.NewNop())
+	assert.ErrorContains(t, registry.Start(t.Context(), componenttest.NewNopHost()), "storage extension 'file_storage' not found")
+}
+
+func Test_Registry_newCache(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.MaxEntries = 2
+	cfg.TTL = time.Minute
+	registry := NewRegistry(cfg, component.MustNewID("transform"), "logs", zap.NewNop())
+
+	c := registry.newCache("FirstSeen", time.Hour)
+	assert.Equal(t, 2, c.maxEntries)
+	assert.Equal(t, time.Hour, c.ttl)
+
+	c = registry.newCache("Rate", 0)
+	assert.Equal(t, time.Minute, c.ttl)
+
+	var nilRegistry *Registry
+	c = nilRegistry.newCache("Rate", 0)
+	assert.Equal(t, defaultMaxEntries, c.maxEntries)
+	assert.Equal(t, defaultTTL, c.ttl)
+}
+
+func Test_BindFunctions(t *testing.T) {
+	override := ottl.NewFactory("Delta", nil, func(ottl.FunctionContext, ottl.Arguments) (ottl.ExprFunc[any], error) {
+		return nil, nil
+	})
+	functions := Functions[any](nil)
+	functions["Delta"] = override
+
+	registry := NewRegistry(NewDefaultConfig(), component.MustNewID("transform"), "logs", zap.NewNop())
+	bound := BindFunctions(functions, registry)
+
+	assert.Len(t, bound, len(functions))
+	assert.Nil(t, bound["Delta"].CreateDefaultArguments())
+	_, err := bound["Rate"].CreateFunction(ottl.FunctionContext{}, &RateArguments[any]{})
+	require.NoError(t, err)
+	assert.Len(t, registry.caches, 1)
+	// The original functions must not be bound to the registry.
+	_, err = functions["Rate"].CreateFunction(ottl.FunctionContext{}, &RateArguments[any]{})
+	require.NoError(t, err)
+	assert.Len(t, registry.caches, 1)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
 )
 
 func SpanFunctions() map[string]ottl.Factory[*ottlspan.TransformContext] {
@@ -21,11 +22,13 @@ func SpanFunctions() map[string]ottl.Factory[*ottlspan.TransformContext] {
 	)
 
 	maps.Copy(functions, spanFunctions)
+	maps.Copy(functions, state.Functions[*ottlspan.TransformContext](nil))
 
 	return functions
 }
 
 func SpanEventFunctions() map[string]ottl.Factory[*ottlspanevent.TransformContext] {
-	// No trace-only functions yet.
-	return ottlfuncs.StandardFuncs[*ottlspanevent.TransformContext]()
+	functions := ottlfuncs.StandardFuncs[*ottlspanevent.TransformContext]()
+	maps.Copy(functions, state.Functions[*ottlspanevent.TransformContext](nil))
+	return functions
 }
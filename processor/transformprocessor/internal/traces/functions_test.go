@@ -4,6 +4,7 @@
 package traces
 
 import (
+	"maps"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,12 +13,14 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/state"
 )
 
 func Test_SpanFunctions(t *testing.T) {
 	expected := ottlfuncs.StandardFuncs[*ottlspan.TransformContext]()
 	expected["IsRootSpan"] = ottlfuncs.NewIsRootSpanFactoryNew()
 	expected["set_semconv_span_name"] = NewSetSemconvSpanNameFactory()
+	maps.Copy(expected, state.Functions[*ottlspan.TransformContext](nil))
 
 	actual := SpanFunctions()
 	require.Len(t, actual, len(expected))
@@ -28,6 +31,7 @@ func Test_SpanFunctions(t *testing.T) {
 
 func Test_SpanEventFunctions(t *testing.T) {
 	expected := ottlfuncs.StandardFuncs[*ottlspanevent.TransformContext]()
+	maps.Copy(expected, state.Functions[*ottlspanevent.TransformContext](nil))
 	actual := SpanEventFunctions()
 	require.Len(t, actual, len(expected))
 	for k := range actual {
@@ -4,11 +4,15 @@
 package k8slogreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8slogreceiver"
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"go.uber.org/multierr"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
 )
 
 const (
@@ -16,9 +20,13 @@ const (
 )
 
 const (
-	DefaultMode        = ModeDaemonSetStdout
-	DefaultHostRoot    = "/host_root"
-	DefaultNodeFromEnv = "KUBE_NODE_NAME"
+	DefaultMode               = ModeDaemonSetStdout
+	DefaultHostRoot           = "/host_root"
+	DefaultNodeFromEnv        = "KUBE_NODE_NAME"
+	DefaultStartAt            = "end"
+	DefaultPollInterval       = 200 * time.Millisecond
+	DefaultMaxConcurrentFiles = 1024
+	DefaultMaxLogSize         = 1024 * 1024
 )
 
 // Config is the configuration of a k8slog receiver
@@ -26,13 +34,38 @@ type Config struct {
 	Discovery SourceConfig  `mapstructure:"discovery"`
 	Extract   ExtractConfig `mapstructure:"extract"`
 
-	// TODO: refactor fileconsumer and add it's config of k8s implementation here.
+	// StartAt represents where to start reading the logs of a container which were not read before.
+	// Valid values are "beginning" and "end" (default).
+	StartAt string `mapstructure:"start_at"`
+
+	// PollInterval represents how often the log files of the containers are scanned for new logs and rotations.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+
+	// MaxConcurrentFiles represents the maximum number of log files which are read at the same time.
+	MaxConcurrentFiles int `mapstructure:"max_concurrent_files"`
+
+	// MaxLogSize represents the maximum size of a log, including the logs recombined from partial lines.
+	MaxLogSize helper.ByteSize `mapstructure:"max_log_size"`
+
+	adapter.BaseConfig `mapstructure:",squash"`
 }
 
 // ExtractConfig allows specifying how to extract resource attributes from pod.
 type ExtractConfig struct {
 	// Metadata represents the list of metadata fields to extract from pod.
-	// TODO: supported metadata fields and default values.
+	// Supported fields are:
+	// - k8s.namespace.name
+	// - k8s.pod.name
+	// - k8s.pod.uid
+	// - k8s.pod.start_time
+	// - k8s.node.name
+	// - k8s.container.name
+	// - k8s.container.restart_count
+	// - container.id
+	// - container.image.name
+	// - container.image.tag
+	// By default, k8s.namespace.name, k8s.pod.name, k8s.pod.uid, k8s.container.name
+	// and k8s.container.restart_count are extracted.
 	Metadata []string `mapstructure:"metadata"`
 
 	// Annotations represents the rules to extract from pod annotations.
@@ -111,7 +144,25 @@ type FieldExtractConfig struct {
 }
 
 func (c Config) Validate() error {
-	return c.Discovery.Validate()
+	err := multierr.Append(c.Discovery.Validate(), c.Extract.Validate())
+	if c.StartAt != "beginning" && c.StartAt != "end" {
+		err = multierr.Append(err, fmt.Errorf("invalid start_at %q", c.StartAt))
+	}
+	if c.PollInterval <= 0 {
+		err = multierr.Append(err, errors.New("poll_interval must be positive"))
+	}
+	if c.MaxConcurrentFiles < 1 {
+		err = multierr.Append(err, errors.New("max_concurrent_files must be positive"))
+	}
+	if c.MaxLogSize <= 0 {
+		err = multierr.Append(err, errors.New("max_log_size must be positive"))
+	}
+	return err
+}
+
+func (c ExtractConfig) Validate() error {
+	_, err := newExtractor(c)
+	return err
 }
 
 // SourceConfig allows specifying how to discover containers to collect logs from.
@@ -152,11 +203,17 @@ func (c SourceConfig) Validate() error {
 	for _, r := range c.RuntimeAPIs {
 		err = multierr.Append(err, r.Validate())
 	}
+	for _, f := range c.Filter {
+		_, filterErr := newContainerFilter(f)
+		err = multierr.Append(err, filterErr)
+	}
 	return err
 }
 
 // FilterConfig allows specifying how to filter containers to collect logs from.
 // By default, all containers are collected from.
+// A container is collected from if it matches any of the filters,
+// and it matches a filter if it matches all the rules of the filter.
 type FilterConfig struct {
 	// Annotations represents the rules to filter containers based on pod annotations.
 	Annotations []MapFilterConfig `mapstructure:"annotations"`
@@ -179,7 +236,6 @@ type FilterConfig struct {
 
 // ValueFilterConfig allows specifying a filter rule to filter containers based on string values,
 // such as pod names, namespaces, container names or pod UIDs.
-// If any of the values match, this rule is considered to match.
 type ValueFilterConfig struct {
 	// Op represents how to compare the value.
 	// Valid values are:
@@ -212,6 +268,5 @@ type MapFilterConfig struct {
 
 	// Value represents the value to compare against.
 	// If Op is "exists" or "not-exists", this field is ignored.
-	// If any of the values match, this rule is considered to match.
 	Value string `mapstructure:"value"`
 }
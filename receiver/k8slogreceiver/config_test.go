@@ -6,6 +6,7 @@ package k8slogreceiver
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,7 +14,10 @@ import (
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/confmap/xconfmap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/consumerretry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8slogreceiver/internal/metadata"
 )
 
@@ -84,6 +88,14 @@ func TestLoadConfig(t *testing.T) {
 						"k8s.container.name",
 					},
 				},
+				StartAt:            "beginning",
+				PollInterval:       time.Second,
+				MaxConcurrentFiles: DefaultMaxConcurrentFiles,
+				MaxLogSize:         DefaultMaxLogSize,
+				BaseConfig: adapter.BaseConfig{
+					Operators:      []operator.Config{},
+					RetryOnFailure: consumerretry.NewDefaultConfig(),
+				},
 			},
 		},
 	}
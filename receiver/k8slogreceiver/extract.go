@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package k8slogreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8slogreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	attrNamespaceName         = "k8s.namespace.name"
+	attrPodName               = "k8s.pod.name"
+	attrPodUID                = "k8s.pod.uid"
+	attrPodStartTime          = "k8s.pod.start_time"
+	attrNodeName              = "k8s.node.name"
+	attrContainerName         = "k8s.container.name"
+	attrContainerRestartCount = "k8s.container.restart_count"
+	attrContainerID           = "container.id"
+	attrContainerImageName    = "container.image.name"
+	attrContainerImageTag     = "container.image.tag"
+)
+
+// pathAttributes are the attributes which the container parser extracts from the log file path.
+var pathAttributes = []string{
+	attrNamespaceName,
+	attrPodName,
+	attrPodUID,
+	attrContainerName,
+	attrContainerRestartCount,
+}
+
+var supportedMetadata = map[string]bool{
+	attrNamespaceName:         true,
+	attrPodName:               true,
+	attrPodUID:                true,
+	attrPodStartTime:          true,
+	attrNodeName:              true,
+	attrContainerName:         true,
+	attrContainerRestartCount: true,
+	attrContainerID:           true,
+	attrContainerImageName:    true,
+	attrContainerImageTag:     true,
+}
+
+const (
+	fromPod = "pod"
+
+	annotationsPrefix = "k8s.pod.annotations."
+	labelsPrefix      = "k8s.pod.labels."
+	envPrefix         = "k8s.pod.env."
+)
+
+// fieldRule is the compiled form of a FieldExtractConfig.
+type fieldRule struct {
+	tagName  string
+	prefix   string
+	key      string
+	keyRegex *regexp.Regexp
+	regex    *regexp.Regexp
+}
+
+func newFieldRules(cfgs []FieldExtractConfig, prefix string) ([]fieldRule, error) {
+	rules := make([]fieldRule, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.From != "" && cfg.From != fromPod {
+			return nil, fmt.Errorf("unsupported from %q", cfg.From)
+		}
+		if (cfg.Key == "") == (cfg.KeyRegex == "") {
+			return nil, errors.New("exactly one of key or key_regex must be specified")
+		}
+		rule := fieldRule{tagName: cfg.TagName, prefix: prefix, key: cfg.Key}
+		if cfg.KeyRegex != "" {
+			re, err := regexp.Compile("^(?:" + cfg.KeyRegex + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid key_regex %q: %w", cfg.KeyRegex, err)
+			}
+			rule.keyRegex = re
+		}
+		if cfg.Regex != "" {
+			re, err := regexp.Compile(cfg.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q: %w", cfg.Regex, err)
+			}
+			if re.SubexpIndex("value") < 0 {
+				return nil, fmt.Errorf("regex %q must contain a named subexpression \"value\"", cfg.Regex)
+			}
+			rule.regex = re
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// value extracts the value of the attribute from the value of the field.
+func (r fieldRule) value(v string) (string, bool) {
+	if r.regex == nil {
+		return v, true
+	}
+	matches := r.regex.FindStringSubmatch(v)
+	if matches == nil {
+		return "", false
+	}
+	return matches[r.regex.SubexpIndex("value")], true
+}
+
+func (r fieldRule) extract(m map[string]string, attrs map[string]string) {
+	if r.keyRegex == nil {
+		v, ok := m[r.key]
+		if !ok {
+			return
+		}
+		if v, ok = r.value(v); !ok {
+			return
+		}
+		name := r.tagName
+		if name == "" {
+			name = r.prefix + r.key
+		}
+		attrs[name] = v
+		return
+	}
+	for k, v := range m {
+		match := r.keyRegex.FindStringSubmatchIndex(k)
+		if match == nil {
+			continue
+		}
+		value, ok := r.value(v)
+		if !ok {
+			continue
+		}
+		name := r.prefix + k
+		if r.tagName != "" {
+			name = string(r.keyRegex.ExpandString(nil, r.tagName, k, match))
+		}
+		attrs[name] = value
+	}
+}
+
+// extractor is the compiled form of an ExtractConfig.
+type extractor struct {
+	metadata    map[string]bool
+	annotations []fieldRule
+	labels      []fieldRule
+	env         []fieldRule
+}
+
+func newExtractor(cfg ExtractConfig) (*extractor, error) {
+	e := &extractor{metadata: map[string]bool{}}
+	metadata := cfg.Metadata
+	if len(metadata) == 0 {
+		metadata = pathAttributes
+	}
+	for _, field := range metadata {
+		if !supportedMetadata[field] {
+			return nil, fmt.Errorf("unsupported metadata %q", field)
+		}
+		e.metadata[field] = true
+	}
+
+	var err error
+	if e.annotations, err = newFieldRules(cfg.Annotations, annotationsPrefix); err != nil {
+		return nil, fmt.Errorf("annotations: %w", err)
+	}
+	if e.labels, err = newFieldRules(cfg.Labels, labelsPrefix); err != nil {
+		return nil, fmt.Errorf("labels: %w", err)
+	}
+	if e.env, err = newFieldRules(cfg.Env, envPrefix); err != nil {
+		return nil, fmt.Errorf("env: %w", err)
+	}
+	return e, nil
+}
+
+func (e *extractor) needsEnv() bool {
+	return len(e.env) > 0
+}
+
+// attributes returns the resource attributes of a container.
+// The attributes which depend on the log file, i.e. the restart count and the container ID, are not included.
+func (e *extractor) attributes(c *containerInfo, env map[string]string) map[string]string {
+	attrs := map[string]string{}
+	metadata := map[string]string{
+		attrNamespaceName: c.namespace,
+		attrPodName:       c.podName,
+		attrPodUID:        c.podUID,
+		attrNodeName:      c.nodeName,
+		attrContainerName: c.containerName,
+	}
+	if !c.startTime.IsZero() {
+		metadata[attrPodStartTime] = c.startTime.UTC().Format(time.RFC3339)
+	}
+	if c.image != "" {
+		name, tag := parseImage(c.image)
+		metadata[attrContainerImageName] = name
+		if tag != "" {
+			metadata[attrContainerImageTag] = tag
+		}
+	}
+	for k, v := range metadata {
+		if e.metadata[k] && v != "" {
+			attrs[k] = v
+		}
+	}
+
+	for _, rule := range e.annotations {
+		rule.extract(c.annotations, attrs)
+	}
+	for _, rule := range e.labels {
+		rule.extract(c.labels, attrs)
+	}
+	for _, rule := range e.env {
+		rule.extract(env, attrs)
+	}
+	return attrs
+}
+
+// parseImage splits an image reference such as "registry:5000/app:v1@sha256:..." into its name and tag.
+func parseImage(image string) (name, tag string) {
+	name, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
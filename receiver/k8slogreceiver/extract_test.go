@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package k8slogreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractorAttributes(t *testing.T) {
+	c := &containerInfo{
+		namespace:     "default",
+		podName:       "web-0",
+		podUID:        "8a1c1ecb-5b77-4e4b-9c6d-2c5c6fbd3f0e",
+		nodeName:      "node-1",
+		containerName: "nginx",
+		image:         "registry:5000/nginx:1.27@sha256:0123",
+		startTime:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		labels: map[string]string{
+			"app.kubernetes.io/component": "proxy",
+			"app.kubernetes.io/version":   "1.27",
+		},
+		annotations: map[string]string{
+			"kubernetes.io/change-cause": "APP_NAME=web GIT_SHA=58a1e39",
+		},
+	}
+	env := map[string]string{"REGION": "eu"}
+
+	tests := []struct {
+		name     string
+		cfg      ExtractConfig
+		expected map[string]string
+	}{
+		{
+			name: "default",
+			expected: map[string]string{
+				"k8s.namespace.name": "default",
+				"k8s.pod.name":       "web-0",
+				"k8s.pod.uid":        "8a1c1ecb-5b77-4e4b-9c6d-2c5c6fbd3f0e",
+				"k8s.container.name": "nginx",
+			},
+		},
+		{
+			name: "metadata",
+			cfg: ExtractConfig{
+				Metadata: []string{"k8s.node.name", "k8s.pod.start_time", "container.image.name", "container.image.tag"},
+			},
+			expected: map[string]string{
+				"k8s.node.name":        "node-1",
+				"k8s.pod.start_time":   "2024-01-02T03:04:05Z",
+				"container.image.name": "registry:5000/nginx",
+				"container.image.tag":  "1.27",
+			},
+		},
+		{
+			name: "fields",
+			cfg: ExtractConfig{
+				Metadata: []string{"k8s.pod.name"},
+				Annotations: []FieldExtractConfig{
+					{TagName: "git.sha", Key: "kubernetes.io/change-cause", Regex: `GIT_SHA=(?P<value>\w+)`},
+					{TagName: "ci.build", Key: "kubernetes.io/change-cause", Regex: `CI_BUILD=(?P<value>\w+)`},
+				},
+				Labels: []FieldExtractConfig{
+					{TagName: "$1", KeyRegex: `app\.kubernetes\.io/(.*)`},
+				},
+				Env: []FieldExtractConfig{
+					{Key: "REGION"},
+				},
+			},
+			expected: map[string]string{
+				"k8s.pod.name":       "web-0",
+				"git.sha":            "58a1e39",
+				"component":          "proxy",
+				"version":            "1.27",
+				"k8s.pod.env.REGION": "eu",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := newExtractor(tt.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, e.attributes(c, env))
+		})
+	}
+}
+
+func TestExtractorErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ExtractConfig
+	}{
+		{
+			name: "unsupported metadata",
+			cfg:  ExtractConfig{Metadata: []string{"k8s.cluster.name"}},
+		},
+		{
+			name: "key and key_regex",
+			cfg:  ExtractConfig{Labels: []FieldExtractConfig{{Key: "app", KeyRegex: "app.*"}}},
+		},
+		{
+			name: "regex without value",
+			cfg:  ExtractConfig{Annotations: []FieldExtractConfig{{Key: "app", Regex: "(.*)"}}},
+		},
+		{
+			name: "from namespace",
+			cfg:  ExtractConfig{Labels: []FieldExtractConfig{{Key: "app", From: "namespace"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newExtractor(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
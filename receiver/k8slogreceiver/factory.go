@@ -10,7 +10,10 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/consumerretry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8slogreceiver/internal/metadata"
 )
 
@@ -42,6 +45,14 @@ func createDefaultConfig() component.Config {
 				},
 			},
 		},
+		StartAt:            DefaultStartAt,
+		PollInterval:       DefaultPollInterval,
+		MaxConcurrentFiles: DefaultMaxConcurrentFiles,
+		MaxLogSize:         DefaultMaxLogSize,
+		BaseConfig: adapter.BaseConfig{
+			Operators:      []operator.Config{},
+			RetryOnFailure: consumerretry.NewDefaultConfig(),
+		},
 	}
 }
 
@@ -51,7 +62,6 @@ func createLogsReceiver(
 	cfg component.Config,
 	consumer consumer.Logs,
 ) (receiver.Logs, error) {
-	settings.Logger.Error("k8slogreceiver is not yet implemented")
 	rCfg, ok := cfg.(*Config)
 	if !ok {
 		return nil, errors.New("failed to cast config to k8slogreceiver.Config")
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package k8slogreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8slogreceiver"
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	opEquals     = "equals"
+	opNotEquals  = "not-equals"
+	opExists     = "exists"
+	opNotExists  = "not-exists"
+	opMatches    = "matches"
+	opNotMatches = "not-matches"
+)
+
+// matchFunc reports whether a value matches a rule. ok is false when the value doesn't exist.
+type matchFunc func(value string, ok bool) bool
+
+func newMatchFunc(op, value string) (matchFunc, error) {
+	switch op {
+	case "", opEquals:
+		return func(v string, ok bool) bool { return ok && v == value }, nil
+	case opNotEquals:
+		return func(v string, ok bool) bool { return !ok || v != value }, nil
+	case opExists:
+		return func(_ string, ok bool) bool { return ok }, nil
+	case opNotExists:
+		return func(_ string, ok bool) bool { return !ok }, nil
+	case opMatches, opNotMatches:
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", value, err)
+		}
+		if op == opMatches {
+			return func(v string, ok bool) bool { return ok && re.MatchString(v) }, nil
+		}
+		return func(v string, ok bool) bool { return !ok || !re.MatchString(v) }, nil
+	default:
+		return nil, fmt.Errorf("invalid op %q", op)
+	}
+}
+
+type mapRule struct {
+	key   string
+	match matchFunc
+}
+
+func newMapRules(cfgs []MapFilterConfig) ([]mapRule, error) {
+	rules := make([]mapRule, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Key == "" {
+			return nil, fmt.Errorf("missing key in filter with op %q", cfg.Op)
+		}
+		match, err := newMatchFunc(cfg.Op, cfg.Value)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, mapRule{key: cfg.Key, match: match})
+	}
+	return rules, nil
+}
+
+func newValueRules(cfgs []ValueFilterConfig) ([]matchFunc, error) {
+	rules := make([]matchFunc, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Op == opExists || cfg.Op == opNotExists {
+			return nil, fmt.Errorf("op %q is not supported for values", cfg.Op)
+		}
+		match, err := newMatchFunc(cfg.Op, cfg.Value)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, match)
+	}
+	return rules, nil
+}
+
+func matchMap(rules []mapRule, m map[string]string) bool {
+	for _, rule := range rules {
+		v, ok := m[rule.key]
+		if !rule.match(v, ok) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchValue(rules []matchFunc, v string) bool {
+	for _, match := range rules {
+		if !match(v, true) {
+			return false
+		}
+	}
+	return true
+}
+
+// containerFilter is the compiled form of a FilterConfig.
+type containerFilter struct {
+	annotations []mapRule
+	labels      []mapRule
+	env         []mapRule
+	namespaces  []matchFunc
+	containers  []matchFunc
+	pods        []matchFunc
+}
+
+func newContainerFilter(cfg FilterConfig) (*containerFilter, error) {
+	var (
+		f   containerFilter
+		err error
+	)
+	if f.annotations, err = newMapRules(cfg.Annotations); err != nil {
+		return nil, fmt.Errorf("annotations: %w", err)
+	}
+	if f.labels, err = newMapRules(cfg.Labels); err != nil {
+		return nil, fmt.Errorf("labels: %w", err)
+	}
+	if f.env, err = newMapRules(cfg.Env); err != nil {
+		return nil, fmt.Errorf("env: %w", err)
+	}
+	if f.namespaces, err = newValueRules(cfg.Namespaces); err != nil {
+		return nil, fmt.Errorf("namespaces: %w", err)
+	}
+	if f.containers, err = newValueRules(cfg.Containers); err != nil {
+		return nil, fmt.Errorf("containers: %w", err)
+	}
+	if f.pods, err = newValueRules(cfg.Pods); err != nil {
+		return nil, fmt.Errorf("pods: %w", err)
+	}
+	return &f, nil
+}
+
+func (f *containerFilter) match(c *containerInfo, env map[string]string) bool {
+	return matchValue(f.namespaces, c.namespace) &&
+		matchValue(f.pods, c.podName) &&
+		matchValue(f.containers, c.containerName) &&
+		matchMap(f.labels, c.labels) &&
+		matchMap(f.annotations, c.annotations) &&
+		matchMap(f.env, env)
+}
+
+// containerFilters selects the containers matching any of the filters, or all the containers if there are none.
+type containerFilters []*containerFilter
+
+func newContainerFilters(cfgs []FilterConfig) (containerFilters, error) {
+	filters := make(containerFilters, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		f, err := newContainerFilter(cfg)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+func (fs containerFilters) needsEnv() bool {
+	for _, f := range fs {
+		if len(f.env) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (fs containerFilters) match(c *containerInfo, env map[string]string) bool {
+	if len(fs) == 0 {
+		return true
+	}
+	for _, f := range fs {
+		if f.match(c, env) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package k8slogreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerFilters(t *testing.T) {
+	c := &containerInfo{
+		namespace:     "default",
+		podName:       "web-0",
+		containerName: "nginx",
+		labels:        map[string]string{"app": "web"},
+		annotations:   map[string]string{"io.opentelemetry.collectlog": "true"},
+	}
+	env := map[string]string{"LOG_LEVEL": "debug"}
+
+	tests := []struct {
+		name     string
+		filters  []FilterConfig
+		expected bool
+	}{
+		{
+			name:     "no filters",
+			expected: true,
+		},
+		{
+			name: "all rules match",
+			filters: []FilterConfig{{
+				Annotations: []MapFilterConfig{{Op: "exists", Key: "io.opentelemetry.collectlog"}},
+				Labels:      []MapFilterConfig{{Key: "app", Value: "web"}},
+				Env:         []MapFilterConfig{{Op: "matches", Key: "LOG_LEVEL", Value: "^(debug|info)$"}},
+				Namespaces:  []ValueFilterConfig{{Op: "not-equals", Value: "kube-system"}},
+				Containers:  []ValueFilterConfig{{Value: "nginx"}},
+				Pods:        []ValueFilterConfig{{Op: "matches", Value: "^web-"}},
+			}},
+			expected: true,
+		},
+		{
+			name: "one rule doesn't match",
+			filters: []FilterConfig{{
+				Labels:     []MapFilterConfig{{Key: "app", Value: "web"}},
+				Namespaces: []ValueFilterConfig{{Op: "not-matches", Value: "^def"}},
+			}},
+			expected: false,
+		},
+		{
+			name: "missing key",
+			filters: []FilterConfig{{
+				Labels: []MapFilterConfig{{Op: "not-exists", Key: "app"}},
+			}},
+			expected: false,
+		},
+		{
+			name: "any filter matches",
+			filters: []FilterConfig{
+				{Namespaces: []ValueFilterConfig{{Value: "kube-system"}}},
+				{Containers: []ValueFilterConfig{{Value: "nginx"}}},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := newContainerFilters(tt.filters)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filters.match(c, env))
+		})
+	}
+}
+
+func TestContainerFilterErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter FilterConfig
+	}{
+		{
+			name:   "invalid op",
+			filter: FilterConfig{Pods: []ValueFilterConfig{{Op: "contains", Value: "web"}}},
+		},
+		{
+			name:   "exists on values",
+			filter: FilterConfig{Namespaces: []ValueFilterConfig{{Op: "exists"}}},
+		},
+		{
+			name:   "invalid regex",
+			filter: FilterConfig{Labels: []MapFilterConfig{{Op: "matches", Key: "app", Value: "("}}},
+		},
+		{
+			name:   "missing key",
+			filter: FilterConfig{Annotations: []MapFilterConfig{{Op: "exists"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newContainerFilter(tt.filter)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -5,26 +5,105 @@ package k8slogreceiver // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"context"
+	"path/filepath"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/file"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/container"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8slogreceiver/internal/metadata"
 )
 
-type k8slogReceiver struct{}
+// podLogsPattern matches the log files which the kubelet writes for the containers:
+// /var/log/pods/<namespace>_<pod name>_<pod uid>/<container name>/<restart count>.log
+// The rotated files are renamed by the kubelet, so they're read to their end as lost files.
+var podLogsPattern = filepath.Join("var", "log", "pods", "*", "*", "*.log")
+
+type k8slogReceiver struct {
+	source *podSource
+	stanza receiver.Logs
+}
 
-func (*k8slogReceiver) Start(context.Context, component.Host) error {
-	return nil
+func (r *k8slogReceiver) Start(ctx context.Context, host component.Host) error {
+	if err := r.source.Start(ctx); err != nil {
+		return err
+	}
+	return r.stanza.Start(ctx, host)
 }
 
-func (*k8slogReceiver) Shutdown(context.Context) error {
-	return nil
+func (r *k8slogReceiver) Shutdown(ctx context.Context) error {
+	err := r.stanza.Shutdown(ctx)
+	r.source.Shutdown()
+	return err
 }
 
 func newReceiver(
-	receiver.Settings,
-	*Config,
-	consumer.Logs,
+	set receiver.Settings,
+	cfg *Config,
+	next consumer.Logs,
 ) (receiver.Logs, error) {
-	return &k8slogReceiver{}, nil
+	source, err := newPodSource(set.Logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	enrich, err := consumer.NewLogs(func(ctx context.Context, ld plog.Logs) error {
+		ld.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+			return !source.enrich(ctx, rl.Resource().Attributes())
+		})
+		if ld.ResourceLogs().Len() == 0 {
+			return nil
+		}
+		return next.ConsumeLogs(ctx, ld)
+	}, consumer.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+	if err != nil {
+		return nil, err
+	}
+
+	stanza, err := adapter.NewFactory(stanzaReceiverType{}, metadata.LogsStability).
+		CreateLogs(context.Background(), set, cfg, enrich)
+	if err != nil {
+		return nil, err
+	}
+	return &k8slogReceiver{source: source, stanza: stanza}, nil
+}
+
+// stanzaReceiverType reads the container logs with a file input followed by a container parser,
+// so that the resources of the logs identify the containers they come from.
+type stanzaReceiverType struct{}
+
+func (stanzaReceiverType) Type() component.Type {
+	return metadata.Type
+}
+
+func (stanzaReceiverType) CreateDefaultConfig() component.Config {
+	return createDefaultConfig()
+}
+
+func (stanzaReceiverType) BaseConfig(cfg component.Config) adapter.BaseConfig {
+	c := cfg.(*Config)
+	base := c.BaseConfig
+
+	parser := container.NewConfig()
+	parser.MaxLogSize = c.MaxLogSize
+	base.Operators = append([]operator.Config{operator.NewConfig(parser)}, c.Operators...)
+	return base
+}
+
+func (stanzaReceiverType) InputConfig(cfg component.Config) operator.Config {
+	c := cfg.(*Config)
+	input := file.NewConfig()
+	input.Include = []string{filepath.Join(c.Discovery.HostRoot, podLogsPattern)}
+	input.StartAt = c.StartAt
+	input.PollInterval = c.PollInterval
+	input.MaxConcurrentFiles = c.MaxConcurrentFiles
+	input.MaxLogSize = c.MaxLogSize
+	input.IncludeFileName = false
+	input.IncludeFilePath = true
+	return operator.NewConfig(input)
 }
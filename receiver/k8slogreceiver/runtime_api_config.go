@@ -2,9 +2,18 @@
 // SPDX-License-Identifier: Apache-2.0
 
 package k8slogreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8slogreceiver"
+
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"go.opentelemetry.io/collector/confmap"
 	"go.uber.org/zap"
@@ -52,7 +61,27 @@ func (c *RuntimeAPIConfig) Unmarshal(component *confmap.Conf) error {
 type runtimeAPIBuilder interface {
 	Validate() error
 	Type() string
-	NewClient(logger *zap.Logger, hostRoot string) (any, error)
+	NewClient(logger *zap.Logger, hostRoot string) (runtimeClient, error)
+}
+
+// runtimeClient gets the information about containers which isn't available from the k8s API.
+type runtimeClient interface {
+	// Runtime returns the runtime of the containers the client knows about,
+	// as found in the scheme of the container IDs, e.g. "docker" for "docker://<id>".
+	Runtime() string
+
+	// ContainerEnv returns the environment variables of a container.
+	ContainerEnv(ctx context.Context, containerID string) (map[string]string, error)
+}
+
+// parseEnv converts a list of KEY=VALUE pairs to a map.
+func parseEnv(env []string) map[string]string {
+	m := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		m[k] = v
+	}
+	return m
 }
 
 type baseRuntimeAPIConfig struct {
@@ -65,6 +94,7 @@ type criConfig struct {
 
 	// Addr represents the address of the CRI endpoint.
 	// By default, it is set to <HOST_ROOT>/run/containerd/containerd.sock.
+	// It's currently unused, the containers are inspected through ContainerdState.
 	Addr string `mapstructure:"addr"`
 
 	// ContainerdState represents the path to the containerd state directory.
@@ -74,7 +104,10 @@ type criConfig struct {
 	ContainerdState string `mapstructure:"containerd_state"`
 }
 
-func (*criConfig) Validate() error {
+func (c *criConfig) Validate() error {
+	if c.Addr != "" && !strings.HasPrefix(c.Addr, "unix://") {
+		return fmt.Errorf("invalid cri addr %q, only unix sockets are supported", c.Addr)
+	}
 	return nil
 }
 
@@ -82,11 +115,46 @@ func (*criConfig) Type() string {
 	return "cri"
 }
 
-func (*criConfig) NewClient(logger *zap.Logger, hostRoot string) (any, error) {
-	_ = logger
-	_ = hostRoot
-	// TODO implement me
-	panic("implement me")
+func (c *criConfig) NewClient(_ *zap.Logger, hostRoot string) (runtimeClient, error) {
+	stateDirs := []string{c.ContainerdState}
+	if c.ContainerdState == "" {
+		stateDirs = []string{
+			filepath.Join(hostRoot, "run", "containerd"),
+			filepath.Join(hostRoot, "var", "run", "containerd"),
+		}
+	}
+	return &containerdClient{stateDirs: stateDirs}, nil
+}
+
+// containerdClient inspects containerd containers through the OCI bundles in the containerd state directory.
+type containerdClient struct {
+	stateDirs []string
+}
+
+func (*containerdClient) Runtime() string {
+	return "containerd"
+}
+
+func (c *containerdClient) ContainerEnv(_ context.Context, containerID string) (map[string]string, error) {
+	var errs error
+	for _, dir := range c.stateDirs {
+		path := filepath.Join(dir, "io.containerd.runtime.v2.task", "k8s.io", containerID, "config.json")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		var spec struct {
+			Process struct {
+				Env []string `json:"env"`
+			} `json:"process"`
+		}
+		if err := json.Unmarshal(data, &spec); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return parseEnv(spec.Process.Env), nil
+	}
+	return nil, fmt.Errorf("failed to find the bundle of container %s: %w", containerID, errs)
 }
 
 // dockerConfig allows specifying how to connect to the Docker daemon.
@@ -98,6 +166,7 @@ type dockerConfig struct {
 	Addr string `mapstructure:"addr"`
 
 	// ContainerdAddr represents the address of the containerd daemon.
+	// It's currently unused, the containers are inspected through the Docker daemon.
 	// By default, directories below are tried in order:
 	// - <HOST_ROOT>/run/docker/containerd/containerd.sock
 	// - <HOST_ROOT>/run/containerd/containerd.sock
@@ -105,7 +174,10 @@ type dockerConfig struct {
 	ContainerdAddr string `mapstructure:"containerd_addr"`
 }
 
-func (*dockerConfig) Validate() error {
+func (c *dockerConfig) Validate() error {
+	if c.Addr != "" && !strings.HasPrefix(c.Addr, "unix://") && !strings.HasPrefix(c.Addr, "tcp://") {
+		return fmt.Errorf("invalid docker addr %q, only unix and tcp addresses are supported", c.Addr)
+	}
 	return nil
 }
 
@@ -113,9 +185,65 @@ func (*dockerConfig) Type() string {
 	return "docker"
 }
 
-func (*dockerConfig) NewClient(logger *zap.Logger, hostRoot string) (any, error) {
-	_ = logger
-	_ = hostRoot
-	// TODO implement me
-	panic("implement me")
+func (c *dockerConfig) NewClient(_ *zap.Logger, hostRoot string) (runtimeClient, error) {
+	addr := c.Addr
+	if addr == "" {
+		addr = "unix://" + filepath.Join(hostRoot, "var", "run", "docker.sock")
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker addr %q: %w", addr, err)
+	}
+
+	client := &dockerClient{endpoint: "http://docker"}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+	case "tcp":
+		client.endpoint = "http://" + u.Host
+	default:
+		return nil, fmt.Errorf("unsupported docker addr %q", addr)
+	}
+	client.client = &http.Client{Transport: transport}
+	return client, nil
+}
+
+// dockerClient inspects containers through the Docker Engine API.
+type dockerClient struct {
+	client   *http.Client
+	endpoint string
+}
+
+func (*dockerClient) Runtime() string {
+	return "docker"
+}
+
+func (c *dockerClient) ContainerEnv(ctx context.Context, containerID string) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/containers/"+url.PathEscape(containerID)+"/json", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %s: %w", containerID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to inspect container %s: %s", containerID, resp.Status)
+	}
+
+	var container struct {
+		Config struct {
+			Env []string `json:"Env"`
+		} `json:"Config"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&container); err != nil {
+		return nil, fmt.Errorf("failed to decode container %s: %w", containerID, err)
+	}
+	return parseEnv(container.Config.Env), nil
 }
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package k8slogreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestContainerdClientEnv(t *testing.T) {
+	hostRoot := t.TempDir()
+	bundle := filepath.Join(hostRoot, "var", "run", "containerd", "io.containerd.runtime.v2.task", "k8s.io", "abc")
+	require.NoError(t, os.MkdirAll(bundle, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(bundle, "config.json"),
+		[]byte(`{"process":{"env":["PATH=/usr/bin","REGION=eu","EMPTY="]}}`), 0o600))
+
+	client, err := (&criConfig{}).NewClient(zap.NewNop(), hostRoot)
+	require.NoError(t, err)
+	assert.Equal(t, "containerd", client.Runtime())
+
+	env, err := client.ContainerEnv(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"PATH": "/usr/bin", "REGION": "eu", "EMPTY": ""}, env)
+
+	_, err = client.ContainerEnv(context.Background(), "missing")
+	assert.Error(t, err)
+}
+
+func TestDockerClientEnv(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/containers/abc/json", func(w http.ResponseWriter, _ *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"Config": map[string]any{"Env": []string{"REGION=eu"}},
+		}))
+	})
+	server := &http.Server{Handler: mux}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		assert.NoError(t, server.Close())
+	})
+
+	client, err := (&dockerConfig{Addr: "unix://" + socket}).NewClient(zap.NewNop(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "docker", client.Runtime())
+
+	env, err := client.ContainerEnv(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"REGION": "eu"}, env)
+
+	_, err = client.ContainerEnv(context.Background(), "missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,389 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package k8slogreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8slogreceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+)
+
+const (
+	// deletedPodRetention is how long the metadata of deleted pods is kept,
+	// so that the logs which are read after the deletion can still be enriched.
+	deletedPodRetention = 5 * time.Minute
+	cleanupInterval     = time.Minute
+	cacheSyncTimeout    = time.Minute
+	runtimeAPITimeout   = 5 * time.Second
+)
+
+// containerInfo is the metadata of a container of a pod running on the node.
+type containerInfo struct {
+	namespace     string
+	podName       string
+	podUID        string
+	nodeName      string
+	containerName string
+	image         string
+	startTime     time.Time
+	labels        map[string]string
+	annotations   map[string]string
+	// specEnv is the environment of the container which is set by value in the pod spec.
+	specEnv map[string]string
+	// containerID is the ID of the current instance of the container, prefixed with the runtime, e.g. "containerd://<id>".
+	containerID string
+	// containerIDs maps the restart counts of the container to the IDs of its instances, without the runtime prefix.
+	containerIDs map[string]string
+	deleted      time.Time
+
+	resolve    sync.Once
+	selected   bool
+	attributes map[string]string
+}
+
+// podSource watches the pods running on the node, and uses their metadata to filter and enrich the logs of their containers.
+type podSource struct {
+	logger    *zap.Logger
+	cfg       SourceConfig
+	filters   containerFilters
+	extractor *extractor
+	runtimes  map[string]runtimeClient
+	needsEnv  bool
+
+	mu         sync.RWMutex
+	containers map[string]*containerInfo
+	envs       map[string]map[string]string
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newPodSource(logger *zap.Logger, cfg *Config) (*podSource, error) {
+	filters, err := newContainerFilters(cfg.Discovery.Filter)
+	if err != nil {
+		return nil, err
+	}
+	ext, err := newExtractor(cfg.Extract)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimes := map[string]runtimeClient{}
+	for _, r := range cfg.Discovery.RuntimeAPIs {
+		client, err := r.NewClient(logger, cfg.Discovery.HostRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s client: %w", r.Type(), err)
+		}
+		runtimes[client.Runtime()] = client
+	}
+
+	return &podSource{
+		logger:     logger,
+		cfg:        cfg.Discovery,
+		filters:    filters,
+		extractor:  ext,
+		runtimes:   runtimes,
+		needsEnv:   filters.needsEnv() || ext.needsEnv(),
+		containers: map[string]*containerInfo{},
+		envs:       map[string]map[string]string{},
+		stopCh:     make(chan struct{}),
+	}, nil
+}
+
+// Start watches the pods of the node, and waits until the pods which already exist are known.
+func (s *podSource) Start(ctx context.Context) error {
+	nodeName := os.Getenv(s.cfg.NodeFromEnv)
+	if nodeName == "" {
+		return fmt.Errorf("node name not found in environment variable %q", s.cfg.NodeFromEnv)
+	}
+	client, err := k8sconfig.MakeClient(s.cfg.K8sAPI)
+	if err != nil {
+		return fmt.Errorf("failed to create k8s client: %w", err)
+	}
+
+	informer := newPodInformer(client, nodeName)
+	if _, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				s.upsert(pod)
+			}
+		},
+		UpdateFunc: func(_, obj any) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				s.upsert(pod)
+			}
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*corev1.Pod); ok {
+				s.remove(pod, time.Now())
+			}
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to watch pods: %w", err)
+	}
+
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		informer.Run(s.stopCh)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.cleanupLoop()
+	}()
+
+	syncCtx, cancel := context.WithTimeout(ctx, cacheSyncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+		return errors.New("timed out waiting for the pods of the node to be listed")
+	}
+	return nil
+}
+
+// Shutdown stops watching the pods.
+func (s *podSource) Shutdown() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func newPodInformer(client kubernetes.Interface, nodeName string) cache.SharedInformer {
+	selector := fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
+	return cache.NewSharedInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = selector
+				return client.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = selector
+				return client.CoreV1().Pods(metav1.NamespaceAll).Watch(context.Background(), opts)
+			},
+		},
+		&corev1.Pod{},
+		0,
+	)
+}
+
+func containerKey(podUID, containerName string) string {
+	return podUID + "/" + containerName
+}
+
+// trimRuntime removes the runtime prefix from a container ID.
+func trimRuntime(containerID string) (rt, id string) {
+	rt, id, ok := strings.Cut(containerID, "://")
+	if !ok {
+		return "", containerID
+	}
+	return rt, id
+}
+
+func (s *podSource) upsert(pod *corev1.Pod) {
+	specs := map[string]corev1.Container{}
+	for _, c := range pod.Spec.InitContainers {
+		specs[c.Name] = c
+	}
+	for _, c := range pod.Spec.Containers {
+		specs[c.Name] = c
+	}
+
+	var startTime time.Time
+	if pod.Status.StartTime != nil {
+		startTime = pod.Status.StartTime.Time
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, status := range statuses {
+		key := containerKey(string(pod.UID), status.Name)
+		c := &containerInfo{
+			namespace:     pod.Namespace,
+			podName:       pod.Name,
+			podUID:        string(pod.UID),
+			nodeName:      pod.Spec.NodeName,
+			containerName: status.Name,
+			image:         status.Image,
+			startTime:     startTime,
+			labels:        pod.Labels,
+			annotations:   pod.Annotations,
+			specEnv:       map[string]string{},
+			containerID:   status.ContainerID,
+			containerIDs:  map[string]string{},
+		}
+		for _, env := range specs[status.Name].Env {
+			if env.ValueFrom == nil {
+				c.specEnv[env.Name] = env.Value
+			}
+		}
+		// Keep the IDs of the previous instances, whose logs may still be read.
+		if old, ok := s.containers[key]; ok {
+			for restartCount, id := range old.containerIDs {
+				c.containerIDs[restartCount] = id
+			}
+		}
+		if last := status.LastTerminationState.Terminated; last != nil && last.ContainerID != "" && status.RestartCount > 0 {
+			_, id := trimRuntime(last.ContainerID)
+			c.containerIDs[strconv.Itoa(int(status.RestartCount-1))] = id
+		}
+		if status.ContainerID != "" {
+			_, id := trimRuntime(status.ContainerID)
+			c.containerIDs[strconv.Itoa(int(status.RestartCount))] = id
+		}
+		s.containers[key] = c
+	}
+}
+
+func (s *podSource) remove(pod *corev1.Pod, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.containers {
+		if c.podUID == string(pod.UID) && c.deleted.IsZero() {
+			c.deleted = now
+		}
+	}
+}
+
+func (s *podSource) cleanupLoop() {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case now := <-ticker.C:
+			s.cleanup(now)
+		}
+	}
+}
+
+// cleanup forgets the containers of the pods which were deleted long enough ago.
+func (s *podSource) cleanup(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, c := range s.containers {
+		if c.deleted.IsZero() || now.Sub(c.deleted) < deletedPodRetention {
+			continue
+		}
+		delete(s.containers, key)
+		for _, id := range c.containerIDs {
+			delete(s.envs, id)
+		}
+	}
+}
+
+func (s *podSource) lookup(podUID, containerName string) *containerInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.containers[containerKey(podUID, containerName)]
+}
+
+// env returns the environment of a container. The environment set in the pod spec is used
+// when the runtime of the container can't be inspected.
+func (s *podSource) env(ctx context.Context, c *containerInfo) map[string]string {
+	if !s.needsEnv || c.containerID == "" {
+		return c.specEnv
+	}
+	rt, id := trimRuntime(c.containerID)
+
+	s.mu.RLock()
+	env, ok := s.envs[id]
+	s.mu.RUnlock()
+	if ok {
+		return env
+	}
+
+	client, ok := s.runtimes[rt]
+	if !ok {
+		return c.specEnv
+	}
+	ctx, cancel := context.WithTimeout(ctx, runtimeAPITimeout)
+	defer cancel()
+	env, err := client.ContainerEnv(ctx, id)
+	if err != nil {
+		s.logger.Warn("Failed to get the environment of the container, using the pod spec instead",
+			zap.String("container.id", c.containerID), zap.Error(err))
+		return c.specEnv
+	}
+
+	s.mu.Lock()
+	s.envs[id] = env
+	s.mu.Unlock()
+	return env
+}
+
+// enrich checks whether the logs of a resource are collected, and adds the metadata of the container to the resource.
+// The resource is expected to have the attributes which the container parser extracts from the log file path.
+func (s *podSource) enrich(ctx context.Context, attrs pcommon.Map) bool {
+	var podUID, containerName, restartCount string
+	if v, ok := attrs.Get(attrPodUID); ok {
+		podUID = v.AsString()
+	}
+	if v, ok := attrs.Get(attrContainerName); ok {
+		containerName = v.AsString()
+	}
+	if v, ok := attrs.Get(attrContainerRestartCount); ok {
+		restartCount = v.AsString()
+	}
+
+	c := s.lookup(podUID, containerName)
+	if c == nil {
+		// The pod isn't known, so it can only be collected when everything is.
+		if len(s.filters) > 0 {
+			return false
+		}
+	} else {
+		c.resolve.Do(func() {
+			env := s.env(ctx, c)
+			c.selected = s.filters.match(c, env)
+			if c.selected {
+				c.attributes = s.extractor.attributes(c, env)
+			}
+		})
+		if !c.selected {
+			return false
+		}
+	}
+
+	for _, k := range pathAttributes {
+		if !s.extractor.metadata[k] {
+			attrs.Remove(k)
+		}
+	}
+	if c == nil {
+		return true
+	}
+	for k, v := range c.attributes {
+		attrs.PutStr(k, v)
+	}
+	if s.extractor.metadata[attrContainerID] {
+		if id, ok := c.containerIDs[restartCount]; ok {
+			attrs.PutStr(attrContainerID, id)
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package k8slogreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testPodUID = "8a1c1ecb-5b77-4e4b-9c6d-2c5c6fbd3f0e"
+
+func testPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "web-0",
+			UID:         testPodUID,
+			Labels:      map[string]string{"app": "web"},
+			Annotations: map[string]string{"io.opentelemetry.collectlog": "true"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node-1",
+			Containers: []corev1.Container{{
+				Name: "nginx",
+				Env:  []corev1.EnvVar{{Name: "REGION", Value: "eu"}},
+			}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "nginx",
+				Image:        "nginx:1.27",
+				ContainerID:  "containerd://bbb",
+				RestartCount: 1,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ContainerID: "containerd://aaa"},
+				},
+			}},
+		},
+	}
+}
+
+// testPathAttributes returns the resource attributes which the container parser extracts from the log file path.
+func testPathAttributes(podUID, restartCount string) pcommon.Map {
+	attrs := pcommon.NewMap()
+	attrs.PutStr(attrNamespaceName, "default")
+	attrs.PutStr(attrPodName, "web-0")
+	attrs.PutStr(attrPodUID, podUID)
+	attrs.PutStr(attrContainerName, "nginx")
+	attrs.PutStr(attrContainerRestartCount, restartCount)
+	return attrs
+}
+
+func newTestPodSource(t *testing.T, cfg *Config) *podSource {
+	cfg.Discovery.RuntimeAPIs = nil
+	s, err := newPodSource(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	return s
+}
+
+func TestPodSourceEnrich(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Extract = ExtractConfig{
+		Metadata: []string{attrPodName, attrContainerID, attrContainerImageName},
+		Labels:   []FieldExtractConfig{{Key: "app"}},
+		Env:      []FieldExtractConfig{{Key: "REGION"}},
+	}
+	s := newTestPodSource(t, cfg)
+	s.upsert(testPod())
+
+	attrs := testPathAttributes(testPodUID, "0")
+	require.True(t, s.enrich(context.Background(), attrs))
+	assert.Equal(t, map[string]any{
+		attrPodName:            "web-0",
+		attrContainerID:        "aaa",
+		attrContainerImageName: "nginx",
+		"k8s.pod.labels.app":   "web",
+		"k8s.pod.env.REGION":   "eu",
+	}, attrs.AsRaw())
+
+	attrs = testPathAttributes(testPodUID, "1")
+	require.True(t, s.enrich(context.Background(), attrs))
+	v, ok := attrs.Get(attrContainerID)
+	require.True(t, ok)
+	assert.Equal(t, "bbb", v.Str())
+}
+
+func TestPodSourceFilter(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Discovery.Filter = []FilterConfig{{
+		Annotations: []MapFilterConfig{{Op: "exists", Key: "io.opentelemetry.collectlog"}},
+	}}
+	s := newTestPodSource(t, cfg)
+	s.upsert(testPod())
+
+	excluded := testPod()
+	excluded.UID = "0c2f7e2a-6b1e-4c55-8f60-6f0b6f6bd1b9"
+	excluded.Annotations = nil
+	s.upsert(excluded)
+
+	assert.True(t, s.enrich(context.Background(), testPathAttributes(testPodUID, "1")))
+	assert.False(t, s.enrich(context.Background(), testPathAttributes(string(excluded.UID), "0")))
+	// The logs of unknown pods are dropped, since they can't be checked against the filters.
+	assert.False(t, s.enrich(context.Background(), testPathAttributes("5d3b3c44-8f0e-4f4a-b1bc-6f8f3ce0f9a4", "0")))
+}
+
+func TestPodSourceUnknownPod(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Extract.Metadata = []string{attrPodName, attrContainerName}
+	s := newTestPodSource(t, cfg)
+
+	attrs := testPathAttributes(testPodUID, "0")
+	require.True(t, s.enrich(context.Background(), attrs))
+	assert.Equal(t, map[string]any{
+		attrPodName:       "web-0",
+		attrContainerName: "nginx",
+	}, attrs.AsRaw())
+}
+
+func TestPodSourceCleanup(t *testing.T) {
+	s := newTestPodSource(t, createDefaultConfig().(*Config))
+	s.upsert(testPod())
+
+	now := time.Now()
+	s.remove(testPod(), now)
+	s.cleanup(now.Add(deletedPodRetention / 2))
+	assert.NotNil(t, s.lookup(testPodUID, "nginx"))
+
+	s.cleanup(now.Add(deletedPodRetention))
+	assert.Nil(t, s.lookup(testPodUID, "nginx"))
+}
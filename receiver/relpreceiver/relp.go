@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package relpreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/relpreceiver"
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/receiver"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/relp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/relpreceiver/internal/metadata"
+)
+
+// NewFactory creates a factory for relp receiver
+func NewFactory() receiver.Factory {
+	return adapter.NewFactory(ReceiverType{}, metadata.LogsStability)
+}
+
+// ReceiverType implements adapter.LogReceiverType
+// to create a relp receiver
+type ReceiverType struct{}
+
+// Type is the receiver type
+func (ReceiverType) Type() component.Type {
+	return metadata.Type
+}
+
+// CreateDefaultConfig creates a config with type and version
+func (ReceiverType) CreateDefaultConfig() component.Config {
+	return &RELPConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: *relp.NewConfig(),
+	}
+}
+
+// BaseConfig gets the base config from config, for now
+func (ReceiverType) BaseConfig(cfg component.Config) adapter.BaseConfig {
+	return cfg.(*RELPConfig).BaseConfig
+}
+
+// RELPConfig defines configuration for the relp receiver
+type RELPConfig struct {
+	InputConfig        relp.Config `mapstructure:",squash"`
+	adapter.BaseConfig `mapstructure:",squash"`
+
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
+
+// InputConfig unmarshals the input operator
+func (ReceiverType) InputConfig(cfg component.Config) operator.Config {
+	return operator.NewConfig(&cfg.(*RELPConfig).InputConfig)
+}
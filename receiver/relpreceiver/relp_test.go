@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package relpreceiver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/confmap/xconfmap"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/relp"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/relpreceiver/internal/metadata"
+)
+
+func TestRELP(t *testing.T) {
+	numLogs := 5
+
+	cfg := testdataConfigYaml()
+	sink := new(consumertest.LogsSink)
+	conn := startReceiver(t, cfg, sink)
+
+	r := bufio.NewReader(conn)
+	require.Equal(t, "1 rsp", sendFrame(t, conn, r, 1, "open", "relp_version=0\ncommands=syslog")[:5])
+	for i := range numLogs {
+		msg := fmt.Sprintf("<86>1 2021-02-28T00:0%d:02.003Z test msg %d", i, i)
+		require.Equal(t, fmt.Sprintf("%d rsp 6 200 OK", i+2), sendFrame(t, conn, r, i+2, "syslog", msg))
+	}
+	require.Equal(t, "7 rsp 0", sendFrame(t, conn, r, 7, "close", ""))
+
+	// Frames are acknowledged once the sink received their logs.
+	require.Equal(t, numLogs, sink.LogRecordCount())
+	require.Len(t, sink.AllLogs(), numLogs)
+
+	for i := range numLogs {
+		log := sink.AllLogs()[i].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		require.Equal(t, fmt.Sprintf("<86>1 2021-02-28T00:0%d:02.003Z test msg %d", i, i), log.Body().Str())
+	}
+}
+
+func TestRELPConsumerError(t *testing.T) {
+	cfg := testdataConfigYaml()
+	conn := startReceiver(t, cfg, consumertest.NewErr(errors.New("not ready")))
+
+	r := bufio.NewReader(conn)
+	sendFrame(t, conn, r, 1, "open", "relp_version=0\ncommands=syslog")
+	rsp := sendFrame(t, conn, r, 2, "syslog", "<86>1 2021-02-28T00:00:02.003Z test msg")
+	assert.True(t, strings.HasPrefix(rsp, "2 rsp "), rsp)
+	assert.Contains(t, rsp, " 500 ")
+	assert.Contains(t, rsp, "not ready")
+}
+
+// startReceiver starts a relp receiver, and returns a connection to it.
+func startReceiver(t *testing.T, cfg *RELPConfig, next consumer.Logs) net.Conn {
+	f := NewFactory()
+	rcvr, err := f.CreateLogs(t.Context(), receivertest.NewNopSettings(metadata.Type), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(t.Context(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, rcvr.Shutdown(t.Context()))
+	})
+
+	conn, err := net.Dial("tcp", cfg.InputConfig.ListenAddress)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return conn
+}
+
+// sendFrame sends a RELP frame, and returns the response frame without its trailer.
+func sendFrame(t *testing.T, conn net.Conn, r *bufio.Reader, txnr int, command, data string) string {
+	frame := strconv.Itoa(txnr) + " " + command + " " + strconv.Itoa(len(data))
+	if data != "" {
+		frame += " " + data
+	}
+	_, err := conn.Write([]byte(frame + "\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var rsp []byte
+	for {
+		// The open response spans multiple lines, read it up to the announced data length.
+		line, err := r.ReadBytes('\n')
+		require.NoError(t, err)
+		rsp = append(rsp, line...)
+		fields := strings.SplitN(string(rsp), " ", 4)
+		require.GreaterOrEqual(t, len(fields), 3)
+		dataLen, err := strconv.Atoi(strings.TrimSuffix(fields[2], "\n"))
+		require.NoError(t, err)
+		if dataLen == 0 || len(fields) == 4 && len(fields[3]) > dataLen {
+			return strings.TrimSuffix(string(rsp), "\n")
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub("relp")
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	assert.NoError(t, xconfmap.Validate(cfg))
+	assert.Equal(t, testdataConfigYaml(), cfg)
+}
+
+func testdataConfigYaml() *RELPConfig {
+	return &RELPConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: func() relp.Config {
+			c := relp.NewConfig()
+			c.ListenAddress = "127.0.0.1:29019"
+			return *c
+		}(),
+	}
+}
+
+func TestDecodeInputConfigFailure(t *testing.T) {
+	factory := NewFactory()
+	badCfg := &RELPConfig{
+		BaseConfig: adapter.BaseConfig{
+			Operators: []operator.Config{},
+		},
+		InputConfig: func() relp.Config {
+			c := relp.NewConfig()
+			c.Encoding = "fake"
+			return *c
+		}(),
+	}
+	receiver, err := factory.CreateLogs(t.Context(), receivertest.NewNopSettings(metadata.Type), badCfg, consumertest.NewNop())
+	require.Error(t, err, "receiver creation should fail if input config isn't valid")
+	require.Nil(t, receiver, "receiver creation should fail if input config isn't valid")
+}